@@ -178,6 +178,8 @@ type AKOConfigSpec struct {
 	PersistentVolumeClaim string `json:"pvc,omitempty"`
 	MountPath             string `json:"mountPath,omitempty"`
 	LogFile               string `json:"logFile,omitempty"`
+	// ReplicaCount is the number of AKO replicas, AKO runs with leader election if it is more than 1
+	ReplicaCount int `json:"replicaCount,omitempty"`
 }
 
 // AKOConfigStatus defines the observed state of AKOConfig
@@ -33,7 +33,10 @@ import (
 )
 
 func SetIfRebootRequired(oldCm corev1.ConfigMap, newCm corev1.ConfigMap) {
-	skipList := []string{DeleteConfig, LogLevel}
+	// these settings are picked up by AKO without a restart
+	skipList := []string{DeleteConfig, LogLevel, Layer7Only, NoPGForSni, ShardVSSize, PassthroughShardSize,
+		ServiceEngineGroupName, VipNetworkList, NodeNetworkList, BgpPeerLabels, EnableRHI, DefaultDomain,
+		AutoFQDN, DefaultIngController}
 	oldCksum := getChecksum(oldCm, skipList)
 	newCksum := getChecksum(newCm, skipList)
 
@@ -98,6 +98,11 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 				Resources: []string{"ingressclasses"},
 				Verbs:     []string{"get", "watch", "list"},
 			},
+			{
+				APIGroups: []string{"discovery.k8s.io"},
+				Resources: []string{"endpointslices"},
+				Verbs:     []string{"get", "watch", "list"},
+			},
 			{
 				APIGroups: []string{""},
 				Resources: []string{"services/status"},
@@ -120,14 +125,24 @@ func BuildClusterrole(ako akov1alpha1.AKOConfig, r *AKOConfigReconciler, log log
 			},
 			{
 				APIGroups: []string{"ako.vmware.com"},
-				Resources: []string{"hostrules", "hostrules/status", "httprules", "httprules/status", "aviinfrasettings", "aviinfrasettings/status"},
+				Resources: []string{"hostrules", "hostrules/status", "httprules", "httprules/status", "aviinfrasettings", "aviinfrasettings/status", "l4rules", "l4rules/status"},
 				Verbs:     []string{"get", "watch", "list", "patch", "update"},
 			},
 			{
 				APIGroups: []string{"networking.x-k8s.io"},
-				Resources: []string{"gateways", "gateways/status", "gatewayclasses", "gatewayclasses/status"},
+				Resources: []string{"gateways", "gateways/status", "gatewayclasses", "gatewayclasses/status", "httproutes", "httproutes/status", "tcproutes", "tcproutes/status", "udproutes", "udproutes/status", "tlsroutes", "tlsroutes/status"},
 				Verbs:     []string{"get", "watch", "list", "patch", "update"},
 			},
+			{
+				APIGroups: []string{"coordination.k8s.io"},
+				Resources: []string{"leases"},
+				Verbs:     []string{"get", "create", "update"},
+			},
+			{
+				APIGroups: []string{""},
+				Resources: []string{"events"},
+				Verbs:     []string{"create", "patch", "update"},
+			},
 		},
 	}
 
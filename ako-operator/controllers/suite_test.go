@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/ako-operator/api/v1alpha1"
@@ -193,3 +194,24 @@ func TestStatefulset(t *testing.T) {
 	akoConfig.Spec.AKOSettings.APIServerPort = 9090
 	buildStatefulSetAndVerify(sfRes, akoConfig, true, false, t)
 }
+
+func TestStatefulsetReplicas(t *testing.T) {
+	// Test for:
+	// 1. Whether the replica count in akoConfig is set in the statefulset, along with leader election
+	// 2. Whether an update is required for the statefulsets when the replica count changes
+	g := gomega.NewGomegaWithT(t)
+	akoConfig := getTestDefaultAKOConfig()
+	sf, err := BuildStatefulSet(akoConfig, corev1.Secret{})
+	g.Expect(err).To(gomega.BeNil())
+	g.Expect(*sf.Spec.Replicas).To(gomega.Equal(int32(1)))
+	_, ok := getListOfEnvVars(sf.Spec.Template.Spec.Containers[0])["LEADER_ELECTION"]
+	g.Expect(ok).To(gomega.BeFalse())
+
+	t.Log("updating ako replica count and verifying statefulset update")
+	akoConfig.Spec.ReplicaCount = 2
+	sfReplicas := buildStatefulSetAndVerify(sf, akoConfig, true, false, t)
+	g.Expect(*sfReplicas.Spec.Replicas).To(gomega.Equal(int32(2)))
+	leaderElectionEnv := getListOfEnvVars(sfReplicas.Spec.Template.Spec.Containers[0])["LEADER_ELECTION"]
+	g.Expect(leaderElectionEnv.Value).To(gomega.Equal("true"))
+	buildStatefulSetAndVerify(sfReplicas, akoConfig, false, false, t)
+}
@@ -181,7 +181,7 @@ func isSfUpdateRequired(existingSf appsv1.StatefulSet, newSf appsv1.StatefulSet)
 	newContainer := newSf.Spec.Template.Spec.Containers[0]
 
 	// update to the statefulset required?
-	if existingSf.Spec.Replicas != nil && *existingSf.Spec.Replicas == 1 {
+	if existingSf.Spec.Replicas != nil && *existingSf.Spec.Replicas == *newSf.Spec.Replicas {
 		if len(existingSf.Spec.Template.Spec.Containers) != 1 {
 			return true
 		}
@@ -282,5 +282,12 @@ func getEnvVars(ako akov1alpha1.AKOConfig, aviSecret v1.Secret) []v1.EnvVar {
 		Name:  "LOG_FILE_NAME",
 		Value: ako.Spec.LogFile,
 	})
+
+	if ako.Spec.ReplicaCount > 1 {
+		envVars = append(envVars, v1.EnvVar{
+			Name:  "LEADER_ELECTION",
+			Value: "true",
+		})
+	}
 	return envVars
 }
@@ -19,6 +19,8 @@ import (
 	"fmt"
 
 	"os"
+	"path/filepath"
+	"strconv"
 	"sync"
 	"time"
 
@@ -59,10 +61,24 @@ func InitializeAKOApi() {
 	lib.SetApiServerInstance(akoApi)
 }
 
+// InitializeAKOWebhook starts the TLS server serving the admission reviews of the validating webhook,
+// using the certificate mounted from the webhook secret.
+func InitializeAKOWebhook() {
+	webhookApi := api.NewTLSServer(lib.GetAkoWebhookPort(),
+		filepath.Join(lib.AKO_WEBHOOK_CERT_DIR, "tls.crt"),
+		filepath.Join(lib.AKO_WEBHOOK_CERT_DIR, "tls.key"),
+		[]models.ApiModel{k8s.NewValidatingWebhookModel()})
+	webhookApi.InitApi()
+}
+
 func InitializeAKC() {
 	var err error
 	kubeCluster := false
 	utils.AviLog.Info("AKO is running with version: ", version)
+	if dryRun, _ := strconv.ParseBool(os.Getenv(lib.AKO_DRY_RUN)); dryRun {
+		utils.AviLog.Warnf("AKO is running in dry run mode, no changes would be made on the Avi controller")
+		lib.SetDryRun(true)
+	}
 	// Check if we are running inside kubernetes. Hence try authenticating with service token
 	cfg, err := rest.InClusterConfig()
 	if err != nil {
@@ -169,6 +185,11 @@ func InitializeAKC() {
 		utils.AviLog.Fatalf("Avi Controller Cluster state is not Active, shutting down AKO")
 	}
 
+	if lib.IsLeaderElectionEnabled() {
+		// Start as a standby, the replica would take over the sync once it is elected as the leader.
+		lib.SetAKOIsLeader(false)
+	}
+
 	informers := k8s.K8sinformers{Cs: kubeClient, DynamicClient: dynamicClient, OshiftClient: oshiftClient}
 	c := k8s.SharedAviController()
 	stopCh := utils.SetupSignalHandler()
@@ -184,6 +205,10 @@ func InitializeAKC() {
 		utils.AviLog.Fatalf("Error in getting VIP network %s, shutting down AKO", err)
 	}
 
+	if lib.IsValidatingWebhookEnabled() {
+		InitializeAKOWebhook()
+	}
+
 	c.InitializeNamespaceSync()
 	k8s.PopulateNodeCache(kubeClient)
 	waitGroupMap := make(map[string]*sync.WaitGroup)
@@ -16,6 +16,7 @@ package cache
 import (
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 )
@@ -39,6 +40,16 @@ type AviPoolCache struct {
 	LastModified         string
 	InvalidData          bool
 	HasReference         bool
+	// Servers holds the servers of the pool on the controller, keyed by the server IP.
+	Servers map[string]AviPoolServerCache
+}
+
+// AviPoolServerCache is a server of a pool. The servers being drained by AKO are disabled, and carry the
+// deadline after which they are deleted from the pool.
+type AviPoolServerCache struct {
+	ServerNode    string
+	Enabled       bool
+	DrainDeadline time.Time
 }
 
 type ServiceMetadataObj struct {
@@ -88,6 +99,7 @@ type AviVsCache struct {
 	Tenant               string
 	Uuid                 string
 	Vip                  string
+	V6Vip                string
 	CloudConfigCksum     string
 	PGKeyCollection      []NamespaceName
 	VSVipKeyCollection   []NamespaceName
@@ -96,6 +108,7 @@ type AviVsCache struct {
 	HTTPKeyCollection    []NamespaceName
 	SSLKeyCertCollection []NamespaceName
 	L4PolicyCollection   []NamespaceName
+	NSPolicyCollection   []NamespaceName
 	SNIChildCollection   []string
 	ParentVSRef          NamespaceName
 	PassthroughParentRef NamespaceName
@@ -265,6 +278,22 @@ func (v *AviVsCache) RemoveFromL4PolicyCollection(k NamespaceName) {
 	v.L4PolicyCollection = RemoveNamespaceName(v.L4PolicyCollection, k)
 }
 
+func (v *AviVsCache) AddToNSPolicyCollection(k NamespaceName) {
+	if v.NSPolicyCollection == nil {
+		v.NSPolicyCollection = []NamespaceName{k}
+	}
+	if !utils.HasElem(v.NSPolicyCollection, k) {
+		v.NSPolicyCollection = append(v.NSPolicyCollection, k)
+	}
+}
+
+func (v *AviVsCache) RemoveFromNSPolicyCollection(k NamespaceName) {
+	if v.NSPolicyCollection == nil {
+		return
+	}
+	v.NSPolicyCollection = RemoveNamespaceName(v.NSPolicyCollection, k)
+}
+
 func (v *AviVsCache) AddToSNIChildCollection(k string) {
 	if v.SNIChildCollection == nil {
 		v.SNIChildCollection = []string{k}
@@ -308,6 +337,38 @@ type AviPkiProfileCache struct {
 	HasReference     bool
 }
 
+type AviPersistenceProfileCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+}
+
+type AviAppProfileCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+}
+
+type AviHealthMonitorCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+}
+
+type AviStringGroupCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum uint32
+	LastModified     string
+}
+
 type NextPage struct {
 	Next_uri   string
 	Collection interface{}
@@ -333,6 +394,7 @@ type AviVSVIPCache struct {
 	LastModified     string
 	InvalidData      bool
 	Vips             []string
+	V6IPs            []string
 	NetworkNames     []string
 	HasReference     bool
 }
@@ -344,6 +406,7 @@ type AviHTTPPolicyCache struct {
 	CloudConfigCksum string
 	PoolGroups       []string
 	Pools            []string
+	StringGroups     []string
 	LastModified     string
 	InvalidData      bool
 	HasReference     bool
@@ -359,6 +422,15 @@ type AviL4PolicyCache struct {
 	HasReference     bool
 }
 
+type AviNSPolicyCache struct {
+	Name             string
+	Tenant           string
+	Uuid             string
+	CloudConfigCksum string
+	LastModified     string
+	HasReference     bool
+}
+
 type AviVrfCache struct {
 	Name             string
 	Uuid             string
@@ -488,6 +560,10 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			if value.(*AviHTTPPolicyCache).Uuid == uuid {
 				return value.(*AviHTTPPolicyCache).Name, true
 			}
+		case *AviNSPolicyCache:
+			if value.(*AviNSPolicyCache).Uuid == uuid {
+				return value.(*AviNSPolicyCache).Name, true
+			}
 		case *AviPGCache:
 			if value.(*AviPGCache).Uuid == uuid {
 				return value.(*AviPGCache).Name, true
@@ -496,6 +572,18 @@ func (c *AviCache) AviCacheGetNameByUuid(uuid string) (interface{}, bool) {
 			if value.(*AviPkiProfileCache).Uuid == uuid {
 				return value.(*AviPkiProfileCache).Name, true
 			}
+		case *AviPersistenceProfileCache:
+			if value.(*AviPersistenceProfileCache).Uuid == uuid {
+				return value.(*AviPersistenceProfileCache).Name, true
+			}
+		case *AviHealthMonitorCache:
+			if value.(*AviHealthMonitorCache).Uuid == uuid {
+				return value.(*AviHealthMonitorCache).Name, true
+			}
+		case *AviStringGroupCache:
+			if value.(*AviStringGroupCache).Uuid == uuid {
+				return value.(*AviStringGroupCache).Name, true
+			}
 		}
 	}
 	return nil, false
@@ -44,13 +44,19 @@ type AviObjCache struct {
 	CloudKeyCache      *AviCache
 	HTTPPolicyCache    *AviCache
 	L4PolicyCache      *AviCache
+	NSPolicyCache      *AviCache
 	SSLKeyCache        *AviCache
 	PKIProfileCache    *AviCache
+	PersistenceCache   *AviCache
+	HealthMonitorCache *AviCache
+	StringGroupCache   *AviCache
+	AppProfileCache    *AviCache
 	VSVIPCache         *AviCache
 	VrfCache           *AviCache
 	VsCacheMeta        *AviCache
 	VsCacheLocal       *AviCache
 	ClusterStatusCache *AviCache
+	OperStatusCache    *AviCache
 }
 
 func NewAviObjCache() *AviObjCache {
@@ -64,10 +70,16 @@ func NewAviObjCache() *AviObjCache {
 	c.CloudKeyCache = NewAviCache()
 	c.HTTPPolicyCache = NewAviCache()
 	c.L4PolicyCache = NewAviCache()
+	c.NSPolicyCache = NewAviCache()
 	c.VSVIPCache = NewAviCache()
 	c.VrfCache = NewAviCache()
 	c.PKIProfileCache = NewAviCache()
+	c.PersistenceCache = NewAviCache()
+	c.HealthMonitorCache = NewAviCache()
+	c.StringGroupCache = NewAviCache()
+	c.AppProfileCache = NewAviCache()
 	c.ClusterStatusCache = NewAviCache()
+	c.OperStatusCache = NewAviCache()
 	return &c
 }
 
@@ -83,12 +95,17 @@ func SharedAviObjCache() *AviObjCache {
 
 func (c *AviObjCache) AviRefreshObjectCache(client *clients.AviClient, cloud string) {
 	c.PopulatePkiProfilesToCache(client)
+	c.PopulatePersistenceProfilesToCache(client)
+	c.PopulateHealthMonitorsToCache(client)
+	c.PopulateStringGroupsToCache(client)
+	c.PopulateAppProfilesToCache(client)
 	c.PopulatePoolsToCache(client, cloud)
 	c.PopulatePgDataToCache(client, cloud)
 	c.PopulateDSDataToCache(client, cloud)
 	c.PopulateSSLKeyToCache(client, cloud)
 	c.PopulateHttpPolicySetToCache(client, cloud)
 	c.PopulateL4PolicySetToCache(client, cloud)
+	c.PopulateNSPolicyToCache(client, cloud)
 	c.PopulateVsVipDataToCache(client, cloud)
 }
 
@@ -239,6 +256,14 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 		}
 	}
 
+	for _, objKey := range vsCacheObj.NSPolicyCollection {
+		if intf, found := c.NSPolicyCache.AviCacheGet(objKey); found {
+			if obj, ok := intf.(*AviNSPolicyCache); ok {
+				obj.HasReference = true
+			}
+		}
+	}
+
 	for _, objKey := range vsCacheObj.PGKeyCollection {
 		if intf, found := c.PgCache.AviCacheGet(objKey); found {
 			if obj, ok := intf.(*AviPGCache); ok {
@@ -277,7 +302,7 @@ func (c *AviObjCache) MarkReference(vsCacheObj *AviVsCache) {
 func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 
 	var dsKeys, vsVipKeys, httpKeys, sslKeys []NamespaceName
-	var pgKeys, poolKeys, l4Keys []NamespaceName
+	var pgKeys, poolKeys, l4Keys, nspKeys []NamespaceName
 	for _, objkey := range c.DSCache.AviGetAllKeys() {
 		intf, _ := c.DSCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviDSCache); ok {
@@ -308,6 +333,16 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		}
 	}
 
+	for _, objkey := range c.NSPolicyCache.AviGetAllKeys() {
+		intf, _ := c.NSPolicyCache.AviCacheGet(objkey)
+		if obj, ok := intf.(*AviNSPolicyCache); ok {
+			if obj.HasReference == false {
+				utils.AviLog.Infof("Reference Not found for network security policy: %s", objkey)
+				nspKeys = append(nspKeys, objkey)
+			}
+		}
+	}
+
 	for _, objkey := range c.PgCache.AviGetAllKeys() {
 		intf, _ := c.PgCache.AviCacheGet(objkey)
 		if obj, ok := intf.(*AviPGCache); ok {
@@ -359,6 +394,7 @@ func (c *AviObjCache) DeleteUnmarked(childCollection []string) {
 		PGKeyCollection:      pgKeys,
 		PoolKeyCollection:    poolKeys,
 		L4PolicyCollection:   l4Keys,
+		NSPolicyCollection:   nspKeys,
 		SNIChildCollection:   childCollection,
 	}
 	vsKey := NamespaceName{
@@ -585,6 +621,7 @@ func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud strin
 			PkiProfileCollection: pkiKey,
 			ServiceMetadataObj:   svc_mdata_obj,
 			LastModified:         *pool.LastModified,
+			Servers:              GetPoolServersCache(pool.Servers),
 		}
 		*poolData = append(*poolData, poolCacheObj)
 	}
@@ -604,6 +641,39 @@ func (c *AviObjCache) AviPopulateAllPools(client *clients.AviClient, cloud strin
 	return poolData, result.Count, nil
 }
 
+// GetPoolServersCache returns the servers of the pool keyed by the server IP. The drain deadline of the servers
+// being drained by AKO is read back from the description of the disabled servers.
+func GetPoolServersCache(servers []*models.Server) map[string]AviPoolServerCache {
+	poolServers := make(map[string]AviPoolServerCache, len(servers))
+	for _, server := range servers {
+		if server == nil || server.IP == nil || server.IP.Addr == nil {
+			continue
+		}
+		serverCache := AviPoolServerCache{Enabled: server.Enabled == nil || *server.Enabled}
+		if server.ServerNode != nil {
+			serverCache.ServerNode = *server.ServerNode
+		}
+		if !serverCache.Enabled && server.Description != nil {
+			serverCache.DrainDeadline, _ = lib.GetServerDrainDeadline(*server.Description)
+		}
+		poolServers[*server.IP.Addr] = serverCache
+	}
+	return poolServers
+}
+
+// GetPoolServers returns the servers of the pool in the cache, nil is returned if the pool is not in the cache.
+func (c *AviObjCache) GetPoolServers(poolKey NamespaceName) map[string]AviPoolServerCache {
+	poolCache, ok := c.PoolCache.AviCacheGet(poolKey)
+	if !ok {
+		return nil
+	}
+	poolCacheObj, ok := poolCache.(*AviPoolCache)
+	if !ok {
+		return nil
+	}
+	return poolCacheObj.Servers
+}
+
 func (c *AviObjCache) PopulatePkiProfilesToCache(client *clients.AviClient, override_uri ...NextPage) {
 	var pkiProfData []AviPkiProfileCache
 	c.AviPopulateAllPkiPRofiles(client, &pkiProfData)
@@ -702,10 +772,15 @@ func (c *AviObjCache) AviPopulateAllVSVips(client *clients.AviClient, cloud stri
 		for _, dnsinfo := range vsvip.DNSInfo {
 			fqdns = append(fqdns, *dnsinfo.Fqdn)
 		}
-		var vips []string
+		var vips, v6vips []string
 		var networkNames []string
 		for _, vip := range vsvip.Vip {
-			vips = append(vips, *vip.IPAddress.Addr)
+			if vip.IPAddress != nil && vip.IPAddress.Addr != nil {
+				vips = append(vips, *vip.IPAddress.Addr)
+			}
+			if vip.Ip6Address != nil && vip.Ip6Address.Addr != nil {
+				v6vips = append(v6vips, *vip.Ip6Address.Addr)
+			}
 			if ipamNetworkSubnet := vip.IPAMNetworkSubnet; ipamNetworkSubnet != nil {
 				if networkRef := *ipamNetworkSubnet.NetworkRef; networkRef != "" {
 					if networkRefName := strings.Split(networkRef, "#"); len(networkRefName) == 2 {
@@ -730,6 +805,7 @@ func (c *AviObjCache) AviPopulateAllVSVips(client *clients.AviClient, cloud stri
 			NetworkNames:     networkNames,
 			LastModified:     *vsvip.LastModified,
 			Vips:             vips,
+			V6IPs:            v6vips,
 			CloudConfigCksum: checksum,
 		}
 		*vsVipData = append(*vsVipData, vsVipCacheObj)
@@ -1116,6 +1192,7 @@ func (c *AviObjCache) AviPopulateOnePoolCache(client *clients.AviClient,
 			PkiProfileCollection: pkiKey,
 			ServiceMetadataObj:   svc_mdata_obj,
 			LastModified:         *pool.LastModified,
+			Servers:              GetPoolServersCache(pool.Servers),
 		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *pool.Name}
 		c.PoolCache.AviCacheAdd(k, &poolCacheObj)
@@ -1282,10 +1359,15 @@ func (c *AviObjCache) AviPopulateOneVsVipCache(client *clients.AviClient,
 			fqdns = append(fqdns, *dnsinfo.Fqdn)
 		}
 
-		var vips []string
+		var vips, v6vips []string
 		var networkNames []string
 		for _, vip := range vsvip.Vip {
-			vips = append(vips, *vip.IPAddress.Addr)
+			if vip.IPAddress != nil && vip.IPAddress.Addr != nil {
+				vips = append(vips, *vip.IPAddress.Addr)
+			}
+			if vip.Ip6Address != nil && vip.Ip6Address.Addr != nil {
+				v6vips = append(v6vips, *vip.Ip6Address.Addr)
+			}
 			if ipamNetworkSubnet := vip.IPAMNetworkSubnet; ipamNetworkSubnet != nil {
 				if networkRef := *ipamNetworkSubnet.NetworkRef; networkRef != "" {
 					if networkRefName := strings.Split(networkRef, "#"); len(networkRefName) == 2 {
@@ -1308,6 +1390,7 @@ func (c *AviObjCache) AviPopulateOneVsVipCache(client *clients.AviClient,
 			FQDNs:            fqdns,
 			LastModified:     *vsvip.LastModified,
 			Vips:             vips,
+			V6IPs:            v6vips,
 			NetworkNames:     networkNames,
 			CloudConfigCksum: checksum,
 		}
@@ -1381,6 +1464,7 @@ func (c *AviObjCache) AviPopulateOneVsHttpPolCache(client *clients.AviClient,
 			CloudConfigCksum: *httppol.CloudConfigCksum,
 			PoolGroups:       poolGroups,
 			Pools:            pools,
+			StringGroups:     c.httpSecurityStringGroups(httppol.HTTPSecurityPolicy),
 			LastModified:     *httppol.LastModified,
 		}
 		k := NamespaceName{Namespace: lib.GetTenant(), Name: *httppol.Name}
@@ -1430,7 +1514,7 @@ func (c *AviObjCache) AviPopulateOneVsL4PolCache(client *clients.AviClient,
 		if l4pol.L4ConnectionPolicy != nil {
 			for _, rule := range l4pol.L4ConnectionPolicy.Rules {
 				protocol = *rule.Match.Protocol.Protocol
-				if rule.Action != nil {
+				if rule.Action != nil && rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolRef != nil {
 					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
 					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
 					if found {
@@ -1487,6 +1571,27 @@ func (c *AviObjCache) PopulateSSLKeyToCache(client *clients.AviClient, cloud str
 	}
 }
 
+// httpSecurityStringGroups returns the names of the stringgroups used by the rules of the http security policy.
+func (c *AviObjCache) httpSecurityStringGroups(securityPolicy *models.HttpsecurityPolicy) []string {
+	var stringGroups []string
+	if securityPolicy == nil {
+		return stringGroups
+	}
+	for _, rule := range securityPolicy.Rules {
+		if rule.Match == nil || rule.Match.Path == nil {
+			continue
+		}
+		for _, sgRef := range rule.Match.Path.StringGroupRefs {
+			sgUuid := ExtractUuid(sgRef, "stringgroup-.*.#")
+			sgName, found := c.StringGroupCache.AviCacheGetNameByUuid(sgUuid)
+			if found {
+				stringGroups = append(stringGroups, sgName.(string))
+			}
+		}
+	}
+	return stringGroups
+}
+
 func (c *AviObjCache) AviPopulateAllHttpPolicySets(client *clients.AviClient, cloud string, httpPolicyData *[]AviHTTPPolicyCache, nextPage ...NextPage) (*[]AviHTTPPolicyCache, int, error) {
 	var uri string
 	akoUser := lib.AKOUser
@@ -1550,6 +1655,7 @@ func (c *AviObjCache) AviPopulateAllHttpPolicySets(client *clients.AviClient, cl
 			CloudConfigCksum: *httppol.CloudConfigCksum,
 			PoolGroups:       poolGroups,
 			Pools:            pools,
+			StringGroups:     c.httpSecurityStringGroups(httppol.HTTPSecurityPolicy),
 			LastModified:     *httppol.LastModified,
 		}
 		*httpPolicyData = append(*httpPolicyData, httpPolCacheObj)
@@ -1643,6 +1749,8 @@ func (c *AviObjCache) AviPopulateAllL4PolicySets(client *clients.AviClient, clou
 			for _, rule := range l4pol.L4ConnectionPolicy.Rules {
 				if rule.Action != nil {
 					protocol = *rule.Match.Protocol.Protocol
+				}
+				if rule.Action != nil && rule.Action.SelectPool != nil && rule.Action.SelectPool.PoolRef != nil {
 					poolUuid := ExtractUuid(*rule.Action.SelectPool.PoolRef, "pool-.*.#")
 					poolName, found := c.PoolCache.AviCacheGetNameByUuid(poolUuid)
 					if found {
@@ -1707,6 +1815,510 @@ func (c *AviObjCache) PopulateL4PolicySetToCache(client *clients.AviClient, clou
 	}
 }
 
+func (c *AviObjCache) AviPopulateAllNSPolicies(client *clients.AviClient, cloud string, nspData *[]AviNSPolicyCache, nextPage ...NextPage) (*[]AviNSPolicyCache, int, error) {
+	var uri string
+	akoUser := lib.AKOUser
+	if len(nextPage) == 1 {
+		uri = nextPage[0].Next_uri
+	} else {
+		uri = "/api/networksecuritypolicy/?" + "&include_name=true" + "&created_by=" + akoUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for networksecuritypolicy %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		nsp := models.NetworkSecurityPolicy{}
+		err = json.Unmarshal(elems[i], &nsp)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal networksecuritypolicy data, err: %v", err)
+			continue
+		}
+		if nsp.Name == nil || nsp.UUID == nil {
+			utils.AviLog.Warnf("Incomplete networksecuritypolicy data unmarshalled, %s", utils.Stringify(nsp))
+			continue
+		}
+		nspCacheObj := AviNSPolicyCache{
+			Name:   *nsp.Name,
+			Tenant: lib.GetTenant(),
+			Uuid:   *nsp.UUID,
+		}
+		if nsp.CloudConfigCksum != nil {
+			nspCacheObj.CloudConfigCksum = *nsp.CloudConfigCksum
+		}
+		if nsp.LastModified != nil {
+			nspCacheObj.LastModified = *nsp.LastModified
+		}
+		*nspData = append(*nspData, nspCacheObj)
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/networksecuritypolicy")
+		if len(next_uri) > 1 {
+			override_uri := "/api/networksecuritypolicy" + next_uri[1]
+			nextPage := NextPage{Next_uri: override_uri}
+			_, _, err := c.AviPopulateAllNSPolicies(client, cloud, nspData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return nspData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateNSPolicyToCache(client *clients.AviClient, cloud string) {
+	var nspData []AviNSPolicyCache
+	_, count, err := c.AviPopulateAllNSPolicies(client, cloud, &nspData)
+	if err != nil || len(nspData) != count {
+		return
+	}
+	nspCacheData := c.NSPolicyCache.ShallowCopy()
+	for i, nspCacheObj := range nspData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: nspCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to networksecuritypolicy cache :%s", utils.Stringify(nspCacheObj))
+		c.NSPolicyCache.AviCacheAdd(k, &nspData[i])
+		delete(nspCacheData, k)
+	}
+	// The data that is left in nspCacheData should be explicitly removed
+	for key := range nspCacheData {
+		utils.AviLog.Debugf("Deleting key from networksecuritypolicy cache :%s", key)
+		c.NSPolicyCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneNSPolicyCache(client *clients.AviClient, cloud string, objName string) error {
+	uri := "/api/networksecuritypolicy?name=" + objName + "&created_by=" + lib.AKOUser
+	var nspData []AviNSPolicyCache
+	_, _, err := c.AviPopulateAllNSPolicies(client, cloud, &nspData, NextPage{Next_uri: uri})
+	if err != nil {
+		return err
+	}
+	for i, nspCacheObj := range nspData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: nspCacheObj.Name}
+		c.NSPolicyCache.AviCacheAdd(k, &nspData[i])
+		utils.AviLog.Infof("Adding networksecuritypolicy to Cache during refresh %s", utils.Stringify(nspCacheObj))
+	}
+	return nil
+}
+
+func (c *AviObjCache) AviPopulateAllPersistenceProfiles(client *clients.AviClient, persistenceData *[]AviPersistenceProfileCache, nextPage ...NextPage) (*[]AviPersistenceProfileCache, int, error) {
+	// The applicationpersistenceprofile objects do not have a created_by field, the ones created by AKO are identified with the name prefix.
+	var uri string
+	if len(nextPage) == 1 {
+		uri = nextPage[0].Next_uri
+	} else {
+		uri = "/api/applicationpersistenceprofile/?" + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationpersistenceprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationpersistenceprofile data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		persistence := models.ApplicationPersistenceProfile{}
+		err = json.Unmarshal(elems[i], &persistence)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationpersistenceprofile data, err: %v", err)
+			continue
+		}
+		if persistence.Name == nil || persistence.UUID == nil {
+			utils.AviLog.Warnf("Incomplete applicationpersistenceprofile data unmarshalled, %s", utils.Stringify(persistence))
+			continue
+		}
+		if !strings.HasPrefix(*persistence.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		var timeout int32
+		if persistence.IPPersistenceProfile != nil && persistence.IPPersistenceProfile.IPPersistentTimeout != nil {
+			timeout = *persistence.IPPersistenceProfile.IPPersistentTimeout
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		persistenceCacheObj := AviPersistenceProfileCache{
+			Name:             *persistence.Name,
+			Tenant:           lib.GetTenant(),
+			Uuid:             *persistence.UUID,
+			CloudConfigCksum: lib.PersistenceProfileChecksum(timeout, emptyIngestionMarkers, persistence.Markers, true),
+		}
+		if persistence.LastModified != nil {
+			persistenceCacheObj.LastModified = *persistence.LastModified
+		}
+		*persistenceData = append(*persistenceData, persistenceCacheObj)
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/applicationpersistenceprofile")
+		if len(next_uri) > 1 {
+			override_uri := "/api/applicationpersistenceprofile" + next_uri[1]
+			nextPage := NextPage{Next_uri: override_uri}
+			_, _, err := c.AviPopulateAllPersistenceProfiles(client, persistenceData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return persistenceData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulatePersistenceProfilesToCache(client *clients.AviClient) {
+	var persistenceData []AviPersistenceProfileCache
+	_, count, err := c.AviPopulateAllPersistenceProfiles(client, &persistenceData)
+	if err != nil || len(persistenceData) != count {
+		return
+	}
+	persistenceCacheData := c.PersistenceCache.ShallowCopy()
+	for i, persistenceCacheObj := range persistenceData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: persistenceCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to applicationpersistenceprofile cache :%s", utils.Stringify(persistenceCacheObj))
+		c.PersistenceCache.AviCacheAdd(k, &persistenceData[i])
+		delete(persistenceCacheData, k)
+	}
+	// The data that is left in persistenceCacheData should be explicitly removed
+	for key := range persistenceCacheData {
+		utils.AviLog.Debugf("Deleting key from applicationpersistenceprofile cache :%s", key)
+		c.PersistenceCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOnePersistenceProfileCache(client *clients.AviClient, cloud string, objName string) error {
+	uri := "/api/applicationpersistenceprofile?name=" + objName
+	var persistenceData []AviPersistenceProfileCache
+	_, _, err := c.AviPopulateAllPersistenceProfiles(client, &persistenceData, NextPage{Next_uri: uri})
+	if err != nil {
+		return err
+	}
+	for i, persistenceCacheObj := range persistenceData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: persistenceCacheObj.Name}
+		c.PersistenceCache.AviCacheAdd(k, &persistenceData[i])
+		utils.AviLog.Infof("Adding applicationpersistenceprofile to Cache during refresh %s", utils.Stringify(persistenceCacheObj))
+	}
+	return nil
+}
+
+func (c *AviObjCache) AviPopulateAllHealthMonitors(client *clients.AviClient, hmData *[]AviHealthMonitorCache, nextPage ...NextPage) (*[]AviHealthMonitorCache, int, error) {
+	// The healthmonitor objects do not have a created_by field, the ones created by AKO are identified with the name prefix.
+	var uri string
+	if len(nextPage) == 1 {
+		uri = nextPage[0].Next_uri
+	} else {
+		uri = "/api/healthmonitor/?" + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for healthmonitor %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		hm := models.HealthMonitor{}
+		err = json.Unmarshal(elems[i], &hm)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal healthmonitor data, err: %v", err)
+			continue
+		}
+		if hm.Name == nil || hm.UUID == nil {
+			utils.AviLog.Warnf("Incomplete healthmonitor data unmarshalled, %s", utils.Stringify(hm))
+			continue
+		}
+		if !strings.HasPrefix(*hm.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		var monitorPort int32
+		if hm.MonitorPort != nil {
+			monitorPort = *hm.MonitorPort
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		hmCacheObj := AviHealthMonitorCache{
+			Name:             *hm.Name,
+			Tenant:           lib.GetTenant(),
+			Uuid:             *hm.UUID,
+			CloudConfigCksum: lib.HealthMonitorChecksum(monitorPort, emptyIngestionMarkers, hm.Markers, true),
+		}
+		if hm.LastModified != nil {
+			hmCacheObj.LastModified = *hm.LastModified
+		}
+		*hmData = append(*hmData, hmCacheObj)
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/healthmonitor")
+		if len(next_uri) > 1 {
+			override_uri := "/api/healthmonitor" + next_uri[1]
+			nextPage := NextPage{Next_uri: override_uri}
+			_, _, err := c.AviPopulateAllHealthMonitors(client, hmData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return hmData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateHealthMonitorsToCache(client *clients.AviClient) {
+	var hmData []AviHealthMonitorCache
+	_, count, err := c.AviPopulateAllHealthMonitors(client, &hmData)
+	if err != nil || len(hmData) != count {
+		return
+	}
+	hmCacheData := c.HealthMonitorCache.ShallowCopy()
+	for i, hmCacheObj := range hmData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: hmCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to healthmonitor cache :%s", utils.Stringify(hmCacheObj))
+		c.HealthMonitorCache.AviCacheAdd(k, &hmData[i])
+		delete(hmCacheData, k)
+	}
+	// The data that is left in hmCacheData should be explicitly removed
+	for key := range hmCacheData {
+		utils.AviLog.Debugf("Deleting key from healthmonitor cache :%s", key)
+		c.HealthMonitorCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneHealthMonitorCache(client *clients.AviClient, cloud string, objName string) error {
+	uri := "/api/healthmonitor?name=" + objName
+	var hmData []AviHealthMonitorCache
+	_, _, err := c.AviPopulateAllHealthMonitors(client, &hmData, NextPage{Next_uri: uri})
+	if err != nil {
+		return err
+	}
+	for i, hmCacheObj := range hmData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: hmCacheObj.Name}
+		c.HealthMonitorCache.AviCacheAdd(k, &hmData[i])
+		utils.AviLog.Infof("Adding healthmonitor to Cache during refresh %s", utils.Stringify(hmCacheObj))
+	}
+	return nil
+}
+
+func (c *AviObjCache) AviPopulateAllStringGroups(client *clients.AviClient, sgData *[]AviStringGroupCache, nextPage ...NextPage) (*[]AviStringGroupCache, int, error) {
+	// The stringgroup objects do not have a created_by field, the ones created by AKO are identified with the name prefix.
+	var uri string
+	if len(nextPage) == 1 {
+		uri = nextPage[0].Next_uri
+	} else {
+		uri = "/api/stringgroup/?" + "&include_name=true" + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for stringgroup %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal stringgroup data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		sg := models.StringGroup{}
+		err = json.Unmarshal(elems[i], &sg)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal stringgroup data, err: %v", err)
+			continue
+		}
+		if sg.Name == nil || sg.UUID == nil {
+			utils.AviLog.Warnf("Incomplete stringgroup data unmarshalled, %s", utils.Stringify(sg))
+			continue
+		}
+		if !strings.HasPrefix(*sg.Name, lib.GetNamePrefix()) {
+			continue
+		}
+		var values []string
+		for _, kv := range sg.Kv {
+			if kv.Key != nil {
+				values = append(values, *kv.Key)
+			}
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		sgCacheObj := AviStringGroupCache{
+			Name:             *sg.Name,
+			Tenant:           lib.GetTenant(),
+			Uuid:             *sg.UUID,
+			CloudConfigCksum: lib.StringGroupChecksum(values, emptyIngestionMarkers, sg.Markers, true),
+		}
+		if sg.LastModified != nil {
+			sgCacheObj.LastModified = *sg.LastModified
+		}
+		*sgData = append(*sgData, sgCacheObj)
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/stringgroup")
+		if len(next_uri) > 1 {
+			override_uri := "/api/stringgroup" + next_uri[1]
+			nextPage := NextPage{Next_uri: override_uri}
+			_, _, err := c.AviPopulateAllStringGroups(client, sgData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return sgData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateStringGroupsToCache(client *clients.AviClient) {
+	var sgData []AviStringGroupCache
+	_, count, err := c.AviPopulateAllStringGroups(client, &sgData)
+	if err != nil || len(sgData) != count {
+		return
+	}
+	sgCacheData := c.StringGroupCache.ShallowCopy()
+	for i, sgCacheObj := range sgData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: sgCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to stringgroup cache :%s", utils.Stringify(sgCacheObj))
+		c.StringGroupCache.AviCacheAdd(k, &sgData[i])
+		delete(sgCacheData, k)
+	}
+	// The data that is left in sgCacheData should be explicitly removed
+	for key := range sgCacheData {
+		utils.AviLog.Debugf("Deleting key from stringgroup cache :%s", key)
+		c.StringGroupCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneStringGroupCache(client *clients.AviClient, cloud string, objName string) error {
+	uri := "/api/stringgroup?name=" + objName
+	var sgData []AviStringGroupCache
+	_, _, err := c.AviPopulateAllStringGroups(client, &sgData, NextPage{Next_uri: uri})
+	if err != nil {
+		return err
+	}
+	for i, sgCacheObj := range sgData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: sgCacheObj.Name}
+		c.StringGroupCache.AviCacheAdd(k, &sgData[i])
+		utils.AviLog.Infof("Adding stringgroup to Cache during refresh %s", utils.Stringify(sgCacheObj))
+	}
+	return nil
+}
+
+func (c *AviObjCache) AviPopulateAllAppProfiles(client *clients.AviClient, appProfData *[]AviAppProfileCache, nextPage ...NextPage) (*[]AviAppProfileCache, int, error) {
+	// Only the applicationprofile objects created by AKO, to validate the client certificates, are cached.
+	var uri string
+	if len(nextPage) == 1 {
+		uri = nextPage[0].Next_uri
+	} else {
+		uri = "/api/applicationprofile/?" + "&include_name=true" + "&created_by=" + lib.AKOUser + "&page_size=100"
+	}
+
+	result, err := lib.AviGetCollectionRaw(client, uri)
+	if err != nil {
+		utils.AviLog.Warnf("Get uri %v returned err for applicationprofile %v", uri, err)
+		return nil, 0, err
+	}
+	elems := make([]json.RawMessage, result.Count)
+	err = json.Unmarshal(result.Results, &elems)
+	if err != nil {
+		utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+		return nil, 0, err
+	}
+	for i := 0; i < len(elems); i++ {
+		appProf := models.ApplicationProfile{}
+		err = json.Unmarshal(elems[i], &appProf)
+		if err != nil {
+			utils.AviLog.Warnf("Failed to unmarshal applicationprofile data, err: %v", err)
+			continue
+		}
+		if appProf.Name == nil || appProf.UUID == nil {
+			utils.AviLog.Warnf("Incomplete applicationprofile data unmarshalled, %s", utils.Stringify(appProf))
+			continue
+		}
+		var mode, dnHeader string
+		if appProf.HTTPProfile != nil {
+			if appProf.HTTPProfile.SslClientCertificateMode != nil {
+				mode = *appProf.HTTPProfile.SslClientCertificateMode
+			}
+			if appProf.HTTPProfile.SslClientCertificateAction != nil && len(appProf.HTTPProfile.SslClientCertificateAction.Headers) > 0 &&
+				appProf.HTTPProfile.SslClientCertificateAction.Headers[0].RequestHeader != nil {
+				dnHeader = *appProf.HTTPProfile.SslClientCertificateAction.Headers[0].RequestHeader
+			}
+		}
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		appProfCacheObj := AviAppProfileCache{
+			Name:             *appProf.Name,
+			Tenant:           lib.GetTenant(),
+			Uuid:             *appProf.UUID,
+			CloudConfigCksum: lib.ClientCertProfileChecksum(mode, dnHeader, emptyIngestionMarkers, appProf.Markers, true),
+		}
+		if appProf.LastModified != nil {
+			appProfCacheObj.LastModified = *appProf.LastModified
+		}
+		*appProfData = append(*appProfData, appProfCacheObj)
+	}
+
+	if result.Next != "" {
+		// It has a next page, let's recursively call the same method.
+		next_uri := strings.Split(result.Next, "/api/applicationprofile")
+		if len(next_uri) > 1 {
+			override_uri := "/api/applicationprofile" + next_uri[1]
+			nextPage := NextPage{Next_uri: override_uri}
+			_, _, err := c.AviPopulateAllAppProfiles(client, appProfData, nextPage)
+			if err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+	return appProfData, result.Count, nil
+}
+
+func (c *AviObjCache) PopulateAppProfilesToCache(client *clients.AviClient) {
+	var appProfData []AviAppProfileCache
+	_, count, err := c.AviPopulateAllAppProfiles(client, &appProfData)
+	if err != nil || len(appProfData) != count {
+		return
+	}
+	appProfCacheData := c.AppProfileCache.ShallowCopy()
+	for i, appProfCacheObj := range appProfData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: appProfCacheObj.Name}
+		utils.AviLog.Debugf("Adding key to applicationprofile cache :%s", utils.Stringify(appProfCacheObj))
+		c.AppProfileCache.AviCacheAdd(k, &appProfData[i])
+		delete(appProfCacheData, k)
+	}
+	// The data that is left in appProfCacheData should be explicitly removed
+	for key := range appProfCacheData {
+		utils.AviLog.Debugf("Deleting key from applicationprofile cache :%s", key)
+		c.AppProfileCache.AviCacheDelete(key)
+	}
+}
+
+func (c *AviObjCache) AviPopulateOneAppProfileCache(client *clients.AviClient, cloud string, objName string) error {
+	uri := "/api/applicationprofile?name=" + objName + "&created_by=" + lib.AKOUser
+	var appProfData []AviAppProfileCache
+	_, _, err := c.AviPopulateAllAppProfiles(client, &appProfData, NextPage{Next_uri: uri})
+	if err != nil {
+		return err
+	}
+	for i, appProfCacheObj := range appProfData {
+		k := NamespaceName{Namespace: lib.GetTenant(), Name: appProfCacheObj.Name}
+		c.AppProfileCache.AviCacheAdd(k, &appProfData[i])
+		utils.AviLog.Infof("Adding applicationprofile to Cache during refresh %s", utils.Stringify(appProfCacheObj))
+	}
+	return nil
+}
+
 func (c *AviObjCache) AviObjVrfCachePopulate(client *clients.AviClient, cloud string) error {
 	if lib.GetDisableStaticRoute() {
 		utils.AviLog.Debugf("Static route sync disabled, skipping vrf cache population")
@@ -1820,12 +2432,13 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 			if vs["cloud_config_cksum"] != nil {
 				k := NamespaceName{Namespace: lib.GetTenant(), Name: vs["name"].(string)}
 				*vsCacheCopy = RemoveNamespaceName(*vsCacheCopy, k)
-				var vip string
+				var vip, v6vip string
 				var vsVipKey []NamespaceName
 				var sslKeys []NamespaceName
 				var dsKeys []NamespaceName
 				var httpKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var nspKeys []NamespaceName
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var sharedVsOrL4 bool
@@ -1846,6 +2459,9 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 								if len(vsVipData.Vips) > 0 {
 									vip = vsVipData.Vips[0]
 								}
+								if len(vsVipData.V6IPs) > 0 {
+									v6vip = vsVipData.V6IPs[0]
+								}
 							}
 						}
 					}
@@ -1936,6 +2552,13 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 						}
 					}
 				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					nspName, foundnsp := c.NSPolicyCache.AviCacheGetNameByUuid(nspUuid)
+					if foundnsp {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
 				if vs["http_policies"] != nil {
 					for _, http_intf := range vs["http_policies"].([]interface{}) {
 						httpmap, ok := http_intf.(map[string]interface{})
@@ -1979,11 +2602,13 @@ func (c *AviObjCache) AviObjVSCachePopulate(client *clients.AviClient, cloud str
 					PGKeyCollection:      poolgroupKeys,
 					PoolKeyCollection:    poolKeys,
 					Vip:                  vip,
+					V6Vip:                v6vip,
 					CloudConfigCksum:     vs["cloud_config_cksum"].(string),
 					SNIChildCollection:   sni_child_collection,
 					ParentVSRef:          parentVSKey,
 					ServiceMetadataObj:   svc_mdata_obj,
 					L4PolicyCollection:   l4Keys,
+					NSPolicyCollection:   nspKeys,
 					LastModified:         vs["_last_modified"].(string),
 				}
 				if val, ok := vs["enable_rhi"]; ok {
@@ -2075,7 +2700,7 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 
 			}
 			if vs["cloud_config_cksum"] != nil {
-				var vip string
+				var vip, v6vip string
 				var vsVipKey []NamespaceName
 				var sslKeys []NamespaceName
 				var dsKeys []NamespaceName
@@ -2083,6 +2708,7 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 				var poolgroupKeys []NamespaceName
 				var poolKeys []NamespaceName
 				var l4Keys []NamespaceName
+				var nspKeys []NamespaceName
 
 				// Populate the VSVIP cache
 				if vs["vsvip_ref"] != nil {
@@ -2098,6 +2724,9 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 							if len(vsVipData.Vips) > 0 {
 								vip = vsVipData.Vips[0]
 							}
+							if len(vsVipData.V6IPs) > 0 {
+								v6vip = vsVipData.V6IPs[0]
+							}
 						}
 					}
 				}
@@ -2184,6 +2813,13 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 						}
 					}
 				}
+				if vs["network_security_policy_ref"] != nil {
+					nspUuid := ExtractUuid(vs["network_security_policy_ref"].(string), "networksecuritypolicy-.*.#")
+					nspName, foundnsp := c.NSPolicyCache.AviCacheGetNameByUuid(nspUuid)
+					if foundnsp {
+						nspKeys = append(nspKeys, NamespaceName{Namespace: lib.GetTenant(), Name: nspName.(string)})
+					}
+				}
 				if vs["http_policies"] != nil {
 					for _, http_intf := range vs["http_policies"].([]interface{}) {
 						// find the sslkey name from the ssl key cache
@@ -2218,10 +2854,12 @@ func (c *AviObjCache) AviObjOneVSCachePopulate(client *clients.AviClient, cloud
 					PGKeyCollection:      poolgroupKeys,
 					PoolKeyCollection:    poolKeys,
 					Vip:                  vip,
+					V6Vip:                v6vip,
 					CloudConfigCksum:     vs["cloud_config_cksum"].(string),
 					SNIChildCollection:   sni_child_collection,
 					ParentVSRef:          parentVSKey,
 					L4PolicyCollection:   l4Keys,
+					NSPolicyCollection:   nspKeys,
 					ServiceMetadataObj:   svc_mdata_obj,
 				}
 				if val, ok := vs["enable_rhi"]; ok {
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/vmware/alb-sdk/go/clients"
+)
+
+const (
+	OperStateUp = "OPER_UP"
+)
+
+// AviOperStatusCache is the operational status of a virtualservice or a pool, as reported in the
+// runtime of the object on the Avi controller. The servers are reported only for the pools.
+type AviOperStatusCache struct {
+	Name         string
+	State        string
+	Reason       []string
+	NumServers   int32
+	NumServersUp int32
+}
+
+type aviInventory struct {
+	Config struct {
+		Name string `json:"name"`
+		UUID string `json:"uuid"`
+	} `json:"config"`
+	Runtime struct {
+		OperStatus struct {
+			State  string   `json:"state"`
+			Reason []string `json:"reason"`
+		} `json:"oper_status"`
+		NumServers   int32 `json:"num_servers"`
+		NumServersUp int32 `json:"num_servers_up"`
+	} `json:"runtime"`
+}
+
+// AviOperStatusPopulate fetches the runtime of the virtualservices and pools created by AKO from the
+// inventory APIs, and replaces the contents of the OperStatusCache, which is keyed by the object uuids.
+func (c *AviObjCache) AviOperStatusPopulate(client *clients.AviClient, cloud string) error {
+	operStatuses := make(map[string]*AviOperStatusCache)
+	for _, object := range []string{"virtualservice-inventory", "pool-inventory"} {
+		uri := "/api/" + object + "/?include_name=true&cloud_ref.name=" + cloud + "&created_by=" + lib.AKOUser + "&page_size=100"
+		if err := c.aviPopulateOperStatuses(client, uri, operStatuses); err != nil {
+			utils.AviLog.Warnf("Get uri %v returned err for %s: %v", uri, object, err)
+			return err
+		}
+	}
+
+	for uuid := range c.OperStatusCache.ShallowCopy() {
+		if _, ok := operStatuses[uuid.(string)]; !ok {
+			c.OperStatusCache.AviCacheDelete(uuid)
+		}
+	}
+	for uuid, operStatus := range operStatuses {
+		c.OperStatusCache.AviCacheAdd(uuid, operStatus)
+	}
+	utils.AviLog.Debugf("Populated the oper status of %d objects", len(operStatuses))
+	return nil
+}
+
+func (c *AviObjCache) aviPopulateOperStatuses(client *clients.AviClient, uri string, operStatuses map[string]*AviOperStatusCache) error {
+	for uri != "" {
+		result, err := lib.AviGetCollectionRaw(client, uri)
+		if err != nil {
+			return err
+		}
+		var elems []json.RawMessage
+		if err := json.Unmarshal(result.Results, &elems); err != nil {
+			return err
+		}
+		for _, elem := range elems {
+			inventory := aviInventory{}
+			if err := json.Unmarshal(elem, &inventory); err != nil {
+				utils.AviLog.Warnf("Failed to unmarshal inventory data, err: %v", err)
+				continue
+			}
+			if inventory.Config.UUID == "" {
+				continue
+			}
+			operStatuses[inventory.Config.UUID] = &AviOperStatusCache{
+				Name:         inventory.Config.Name,
+				State:        inventory.Runtime.OperStatus.State,
+				Reason:       inventory.Runtime.OperStatus.Reason,
+				NumServers:   inventory.Runtime.NumServers,
+				NumServersUp: inventory.Runtime.NumServersUp,
+			}
+		}
+
+		uri = ""
+		if nextURI := strings.SplitN(result.Next, "/api/", 2); len(nextURI) > 1 {
+			uri = "/api/" + nextURI[1]
+		}
+	}
+	return nil
+}
+
+// GetOperStatus returns the oper status of the virtualservice or pool with the uuid.
+func (c *AviObjCache) GetOperStatus(uuid string) (*AviOperStatusCache, bool) {
+	operStatus, ok := c.OperStatusCache.AviCacheGet(uuid)
+	if !ok {
+		return nil, false
+	}
+	operStatusObj, ok := operStatus.(*AviOperStatusCache)
+	return operStatusObj, ok
+}
@@ -19,11 +19,13 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/vmware/alb-sdk/go/clients"
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
@@ -59,10 +61,22 @@ func PopulateCache() error {
 			utils.AviLog.Warnf("Failed to set the controller cluster uuid with error: %v", err)
 		}
 		// once the l3 cache is populated, we can call the updatestatus functions from here
-		restlayer := rest.NewRestOperations(avi_obj_cache, avi_rest_client_pool)
-		restlayer.SyncObjectStatuses()
+		if lib.AKOIsLeader() {
+			restlayer := rest.NewRestOperations(avi_obj_cache, avi_rest_client_pool)
+			restlayer.SyncObjectStatuses()
+		}
 	}
 
+	if lib.AKOIsLeader() {
+		deleteStaleObjects()
+	} else {
+		utils.AviLog.Infof("AKO is not the leader, skipping clean up of stale objects")
+	}
+	return nil
+}
+
+func deleteStaleObjects() {
+	avi_obj_cache := avicache.SharedAviObjCache()
 	// Delete Stale objects by deleting model for dummy VS
 	aviclient := avicache.SharedAVIClients()
 	restlayer := rest.NewRestOperations(avi_obj_cache, aviclient)
@@ -76,7 +90,6 @@ func PopulateCache() error {
 		}
 		avi_obj_cache.VsCacheMeta.AviCacheDelete(staleCacheKey)
 	}
-	return nil
 }
 
 func PopulateNodeCache(cs *kubernetes.Clientset) {
@@ -114,6 +127,150 @@ func deleteConfigFromConfigmap(cs kubernetes.Interface) bool {
 	return true
 }
 
+// configMapEnvKeys maps the configmap keys, that are passed to AKO as environment variables,
+// to the environment variables that are read by AKO.
+var configMapEnvKeys = map[string]string{
+	"shardVSSize":            "SHARD_VS_SIZE",
+	"passthroughShardSize":   "PASSTHROUGH_SHARD_SIZE",
+	"serviceEngineGroupName": lib.SEG_NAME,
+	"vipNetworkList":         lib.VIP_NETWORK_LIST,
+	"nodeNetworkList":        lib.NODE_NETWORK_LIST,
+	"bgpPeerLabels":          lib.BGP_PEER_LABELS,
+	"enableRHI":              lib.ENABLE_RHI,
+	"defaultDomain":          lib.DEFAULT_DOMAIN,
+	"autoFQDN":               "AUTO_L4_FQDN",
+	"defaultIngController":   "DEFAULT_ING_CONTROLLER",
+}
+
+// configMapFlagSetters holds the setters for the configmap keys that are read by AKO from the configmap data.
+var configMapFlagSetters = map[string]func(string){
+	lib.LAYER7_ONLY:   lib.SetLayer7Only,
+	lib.NO_PG_FOR_SNI: lib.SetNoPGForSNI,
+	lib.GRBAC:         lib.SetGRBACSupport,
+}
+
+// applyConfigMapChanges diffs the old and new configmap data, and applies the changed settings which can be
+// edited while AKO is running. The keys of the applied settings are returned. logLevel and deleteConfig are
+// handled separately, and a change in any other setting would take effect only after a restart of AKO.
+func applyConfigMapChanges(oldcm, cm *corev1.ConfigMap) []string {
+	keys := make(map[string]struct{})
+	for key := range oldcm.Data {
+		keys[key] = struct{}{}
+	}
+	for key := range cm.Data {
+		keys[key] = struct{}{}
+	}
+
+	var changedKeys []string
+	for key := range keys {
+		if key == lib.LOG_LEVEL || key == lib.DeleteConfig || oldcm.Data[key] == cm.Data[key] {
+			continue
+		}
+		if env, ok := configMapEnvKeys[key]; ok {
+			os.Setenv(env, cm.Data[key])
+		} else if setter, ok := configMapFlagSetters[key]; ok {
+			setter(cm.Data[key])
+		} else {
+			utils.AviLog.Warnf("configmap setting %s changed, AKO needs to be restarted for the change to take effect", key)
+			continue
+		}
+		utils.AviLog.Infof("configmap setting %s changed from %q to %q", key, oldcm.Data[key], cm.Data[key])
+		changedKeys = append(changedKeys, key)
+	}
+	sort.Strings(changedKeys)
+	return changedKeys
+}
+
+// reconcileConfigMapChanges validates the user input against the changed settings, and removes the models whose
+// VS names, shard layout or placement are affected by the changed settings from the graph layer. The virtualservices
+// of the removed models are deleted from the Avi controller right away, so that the full sync, for which true is
+// returned, rebuilds the models from scratch with the changed settings. It is called with the config lock held,
+// so that the graph and rest layers pick up the changed settings only after the affected models and the derived
+// caches are reset.
+func (c *AviController) reconcileConfigMapChanges(changedKeys []string, cm *corev1.ConfigMap, aviclient *clients.AviClient) bool {
+	isValidUserInput := avicache.ValidateUserInput(aviclient)
+	c.DisableSync = !isValidUserInput || delConfigFromData(cm.Data)
+	lib.SetDisableSync(c.DisableSync)
+	if c.DisableSync {
+		utils.AviLog.Warnf("sync is disabled, changed configmap settings %v would be synced once it is enabled", changedKeys)
+		return false
+	}
+
+	restlayer := rest.NewRestOperations(avicache.SharedAviObjCache(), avicache.SharedAVIClients())
+	modelNames, resetHostNames := getModelsAffectedByConfigMapChange(changedKeys)
+	for _, modelName := range modelNames {
+		utils.AviLog.Infof("Removing model %s from the graph layer, it would be rebuilt with the changed configmap settings", modelName)
+		objects.SharedAviGraphLister().Save(modelName, nil)
+		restlayer.DequeueNodes(modelName)
+	}
+	if resetHostNames {
+		// the hostname mappings of the removed L7 models are rebuilt by the full sync
+		utils.AviLog.Infof("Resetting the hostname mappings of the L7 virtualservices for the changed configmap settings %v", changedKeys)
+		nodes.SharedHostNameLister().Reset()
+	}
+	return true
+}
+
+// getModelsAffectedByConfigMapChange returns the models whose VS names or shard layout change with the changed
+// configmap settings, these are the shared and dedicated L7 virtualservices for changes in shardVSSize, noPGForSNI
+// and defaultIngController, the passthrough virtualservices for changes in passthroughShardSize and
+// defaultIngController, and the L4 virtualservices of Services of type LoadBalancer for changes in layer7Only.
+// All the models other than the vrf model are returned for changes in serviceEngineGroupName, vipNetworkList,
+// bgpPeerLabels and enableRHI, as the SE group and the vip placement of a virtualservice can not be updated in
+// place. A change in nodeNetworkList only updates the placement networks of the pools, which are updated in
+// place by the full sync. It also returns whether the hostname mappings of the L7 virtualservices are to be reset.
+func getModelsAffectedByConfigMapChange(changedKeys []string) ([]string, bool) {
+	var rebuildL7, rebuildPassthrough, rebuildL4, rebuildAll bool
+	for _, key := range changedKeys {
+		switch key {
+		case "shardVSSize", lib.NO_PG_FOR_SNI:
+			rebuildL7 = true
+		case "passthroughShardSize":
+			rebuildPassthrough = true
+		case "defaultIngController":
+			rebuildL7, rebuildPassthrough = true, true
+		case lib.LAYER7_ONLY:
+			rebuildL4 = true
+		case "serviceEngineGroupName", "vipNetworkList", "bgpPeerLabels", "enableRHI":
+			rebuildAll = true
+		}
+	}
+
+	var modelNames []string
+	if !rebuildL7 && !rebuildPassthrough && !rebuildL4 && !rebuildAll {
+		return modelNames, false
+	}
+	allModels := objects.SharedAviGraphLister().GetAll()
+	for modelName, avimodelIntf := range allModels.(map[string]interface{}) {
+		if avimodelIntf == nil {
+			continue
+		}
+		avimodel := avimodelIntf.(*nodes.AviObjectGraph)
+		if avimodel.IsVrf {
+			continue
+		}
+		if evhNodes := avimodel.GetAviEvhVS(); len(evhNodes) > 0 {
+			if rebuildAll || (rebuildL7 && evhNodes[0].SharedVS) {
+				modelNames = append(modelNames, modelName)
+			}
+			continue
+		}
+		vsNodes := avimodel.GetAviVS()
+		if len(vsNodes) == 0 {
+			continue
+		}
+		isPassthroughVS := vsNodes[0].SharedVS && vsNodes[0].ApplicationProfile == utils.DEFAULT_L4_APP_PROFILE
+		isL4VS := !vsNodes[0].SharedVS && vsNodes[0].ServiceMetadata.Gateway == ""
+		if rebuildAll ||
+			(rebuildPassthrough && isPassthroughVS) ||
+			(rebuildL7 && vsNodes[0].SharedVS && !isPassthroughVS) ||
+			(rebuildL4 && isL4VS) {
+			modelNames = append(modelNames, modelName)
+		}
+	}
+	return modelNames, rebuildL7 || rebuildAll
+}
+
 // HandleConfigMap : initialise the controller, start informer for configmap and wait for the akc configmap to be created.
 // When the configmap is created, enable sync for other k8s objects. When the configmap is disabled, disable sync.
 func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct{}, stopCh <-chan struct{}, quickSyncCh chan struct{}) error {
@@ -146,7 +303,7 @@ func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct
 			}
 			utils.AviLog.Infof("avi k8s configmap created")
 			utils.AviLog.SetLevel(cm.Data[lib.LOG_LEVEL])
-			// Check if AKO is configured to only use Ingress. This value can be only set during bootup and can't be edited dynamically.
+			// Check if AKO is configured to only use Ingress.
 			lib.SetLayer7Only(cm.Data[lib.LAYER7_ONLY])
 			// Check if we need to use PGs for SNIs or not.
 			lib.SetNoPGForSNI(cm.Data[lib.NO_PG_FOR_SNI])
@@ -172,7 +329,22 @@ func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct
 				utils.AviLog.SetLevel(cm.Data[lib.LOG_LEVEL])
 			}
 
+			// apply the rest of the changed settings, the kubernetes objects are re-synced with the new
+			// values below, or once the sync is enabled again via deleteConfig. The graph and rest layers
+			// are blocked until the changed settings are applied, and the affected models are removed.
+			lib.LockConfig()
+			changedKeys := applyConfigMapChanges(oldcm, cm)
+			var resync bool
+			if oldcm.Data[lib.DeleteConfig] == cm.Data[lib.DeleteConfig] && len(changedKeys) != 0 {
+				resync = c.reconcileConfigMapChanges(changedKeys, cm, aviclient)
+			}
+			lib.UnlockConfig()
+
 			if oldcm.Data[lib.DeleteConfig] == cm.Data[lib.DeleteConfig] {
+				if resync {
+					utils.AviLog.Infof("Triggering a full sync for the changed configmap settings %v", changedKeys)
+					quickSyncCh <- struct{}{}
+				}
 				return
 			}
 			// if DeleteConfig value has changed, then check if we need to enable/disable sync
@@ -181,6 +353,10 @@ func (c *AviController) HandleConfigMap(k8sinfo K8sinformers, ctrlCh chan struct
 			lib.SetDisableSync(c.DisableSync)
 			if isValidUserInput {
 				if delConfigFromData(cm.Data) {
+					if !lib.AKOIsLeader() {
+						utils.AviLog.Infof("AKO is not the leader, deleteConfig would be handled by the leader")
+						return
+					}
 					c.DeleteModels()
 					if lib.GetServiceType() == "ClusterIP" {
 						avicache.DeConfigureSeGroupLabels()
@@ -215,6 +391,7 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	// set up signals so we handle the first shutdown signal gracefully
 	var worker *utils.FullSyncThread
 	var tokenWorker *utils.FullSyncThread
+	var operStatusWorker *utils.FullSyncThread
 	informersArg := make(map[string]interface{})
 	informersArg[utils.INFORMERS_OPENSHIFT_CLIENT] = informers.OshiftClient
 	if lib.GetNamespaceToSync() != "" {
@@ -313,6 +490,12 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 		}
 	}
 
+	if operStatusInterval := lib.GetOperStatusSyncInterval(); operStatusInterval != 0 && !lib.GetAdvancedL4() {
+		operStatusWorker = utils.NewFullSyncThread(operStatusInterval)
+		operStatusWorker.SyncFunction = c.OperStatusSync
+		go operStatusWorker.Run()
+	}
+
 	ingestionQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	ingestionQueue.SyncFunc = SyncFromIngestionLayer
 	ingestionQueue.Run(stopCh, ingestionwg)
@@ -329,11 +512,21 @@ func (c *AviController) InitController(informers K8sinformers, registeredInforme
 	statusQueue.SyncFunc = SyncFromStatusQueue
 	statusQueue.Run(stopCh, statusWG)
 
+	if lib.IsLeaderElectionEnabled() {
+		// Contest the leader election only once the models are built, so that the leader can take over
+		// the sync to the Avi controller without a full sync of the kubernetes objects.
+		go c.RunLeaderElection(informers.Cs, stopCh)
+	}
+
 LABEL:
 	for {
 		select {
 		case <-quickSyncCh:
-			worker.QuickSync()
+			if worker != nil {
+				worker.QuickSync()
+			} else if err := c.FullSyncK8s(); err != nil {
+				utils.AviLog.Warnf("Quick sync of the kubernetes objects failed: %v", err)
+			}
 		case <-ctrlCh:
 			break LABEL
 		}
@@ -341,6 +534,10 @@ LABEL:
 	if worker != nil {
 		worker.Shutdown()
 	}
+	if operStatusWorker != nil {
+		operStatusWorker.Shutdown()
+	}
+	utils.FlushSpans()
 
 	ingestionQueue.StopWorkers(stopCh)
 	graphQueue.StopWorkers(stopCh)
@@ -353,7 +550,27 @@ func (c *AviController) RefreshAuthToken() {
 	lib.RefreshAuthToken(c.informers.KubeClientIntf.ClientSet)
 }
 
+// OperStatusSync fetches the oper status of the virtualservices and pools from the Avi controller, and publishes it
+// on the kubernetes objects. Only the leader updates the kubernetes objects.
+func (c *AviController) OperStatusSync() {
+	if !lib.AKOIsLeader() {
+		return
+	}
+	avi_rest_client_pool := avicache.SharedAVIClients()
+	avi_obj_cache := avicache.SharedAviObjCache()
+	if len(avi_rest_client_pool.AviClient) == 0 {
+		return
+	}
+	if err := avi_obj_cache.AviOperStatusPopulate(avi_rest_client_pool.AviClient[0], utils.CloudName); err != nil {
+		utils.AviLog.Warnf("Failed to fetch the oper status of the virtualservices: %v", err)
+		return
+	}
+	restlayer := rest.NewRestOperations(avi_obj_cache, avi_rest_client_pool)
+	restlayer.SyncObjectOperStatuses()
+}
+
 func (c *AviController) FullSync() {
+	defer utils.ObserveFullSyncDuration("cache", time.Now())
 
 	avi_rest_client_pool := avicache.SharedAVIClients()
 	avi_obj_cache := avicache.SharedAviObjCache()
@@ -367,6 +584,13 @@ func (c *AviController) FullSync() {
 			restlayer := rest.NewRestOperations(avi_obj_cache, avi_rest_client_pool)
 			restlayer.SyncObjectStatuses()
 		}
+		if !lib.AKOIsLeader() {
+			// Standby replicas keep the object cache in sync with the changes done by the leader.
+			_, _, err := avi_obj_cache.AviObjCachePopulate(avi_rest_client_pool.AviClient[0], utils.CtrlVersion, utils.CloudName)
+			if err != nil {
+				utils.AviLog.Warnf("failed to refresh avi cache on standby with error: %v", err)
+			}
+		}
 		allModelsMap := objects.SharedAviGraphLister().GetAll()
 		var allModels []string
 		for modelName := range allModelsMap.(map[string]interface{}) {
@@ -393,6 +617,9 @@ func (c *AviController) FullSyncK8s() error {
 		utils.AviLog.Infof("Sync disabled, skipping full sync")
 		return nil
 	}
+	defer utils.ObserveFullSyncDuration("k8s", time.Now())
+	lib.RLockConfig()
+	defer lib.RUnlockConfig()
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	var vrfModelName string
 	if lib.GetDisableStaticRoute() && !lib.IsNodePortMode() {
@@ -413,12 +640,16 @@ func (c *AviController) FullSyncK8s() error {
 			time.Sleep(20 * time.Second)
 			timeout <- true
 		}()
+		// The config lock is released while waiting, since the rest layer needs it to process the vrf model,
+		// and a configmap edit waiting for the lock would otherwise block the rest layer till the timeout.
+		lib.RUnlockConfig()
 		select {
 		case <-lib.StaticRouteSyncChan:
 			utils.AviLog.Infof("Processing done for VRF")
 		case <-timeout:
 			utils.AviLog.Warnf("Timed out while waiting for rest layer to respond, moving on with bootup")
 		}
+		lib.RLockConfig()
 	}
 
 	svcObjs, err := utils.GetInformers().ServiceInformer.Lister().Services(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
@@ -494,6 +725,16 @@ func (c *AviController) FullSyncK8s() error {
 			}
 		}
 
+		l4RuleObjs, err := lib.GetCRDInformers().L4RuleInformer.Lister().L4Rules(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the l4rules during full sync: %s", err)
+		} else {
+			for _, l4RuleObj := range l4RuleObjs {
+				key := lib.L4Rule + "/" + utils.ObjKey(l4RuleObj)
+				nodes.DequeueIngestion(key, true)
+			}
+		}
+
 		// Ingress Section
 		if utils.GetInformers().IngressInformer != nil {
 			ingObjs, err := utils.GetInformers().IngressInformer.Lister().Ingresses(metav1.NamespaceAll).List(labels.Set(nil).AsSelector())
@@ -581,6 +822,14 @@ func (c *AviController) FullSyncK8s() error {
 		}
 	}
 
+	publishAllModelsToRestLayer(vrfModelName)
+	return nil
+}
+
+// publishAllModelsToRestLayer publishes the models for all the parent VSes present in the avi object cache,
+// followed by the models that are present only in the graph layer. The model for skipModelName is not published.
+func publishAllModelsToRestLayer(skipModelName string) {
+	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	cache := avicache.SharedAviObjCache()
 	vsKeys := cache.VsCacheMeta.AviCacheGetAllParentVSKeys()
 	utils.AviLog.Debugf("Got the VS keys: %s", vsKeys)
@@ -588,7 +837,7 @@ func (c *AviController) FullSyncK8s() error {
 	var allModels []string
 	for modelName := range allModelsMap.(map[string]interface{}) {
 		// ignore vrf model, as it has been published already
-		if modelName != vrfModelName {
+		if modelName != skipModelName {
 			allModels = append(allModels, modelName)
 		}
 	}
@@ -634,7 +883,6 @@ func (c *AviController) FullSyncK8s() error {
 			nodes.PublishKeyToRestLayer(modelName, "fullsync", sharedQueue)
 		}
 	}
-	return nil
 }
 
 // DeleteModels : Delete models and add the model name in the queue.
@@ -726,7 +974,13 @@ func SyncFromIngestionLayer(key interface{}, wg *sync.WaitGroup) error {
 		utils.AviLog.Warnf("Unexpected object type: expected string, got %T", key)
 		return nil
 	}
+	span := utils.StartKeySpan(utils.ObjectIngestionLayer, keyStr, "DequeueIngestion")
+	defer span.End()
+	start := time.Now()
+	lib.RLockConfig()
+	defer lib.RUnlockConfig()
 	nodes.DequeueIngestion(keyStr, false)
+	lib.IngestionLogger(keyStr).Debugf("key: %s, msg: graph sync completed in %v", keyStr, time.Since(start))
 	return nil
 }
 
@@ -736,6 +990,8 @@ func SyncFromFastRetryLayer(key interface{}, wg *sync.WaitGroup) error {
 		utils.AviLog.Warnf("Unexpected object type: expected string, got %T", key)
 		return nil
 	}
+	span := utils.StartKeySpan(lib.FAST_RETRY_LAYER, keyStr, "DequeueFastRetry")
+	defer span.End()
 	retry.DequeueFastRetry(keyStr)
 	return nil
 }
@@ -746,6 +1002,8 @@ func SyncFromSlowRetryLayer(key interface{}, wg *sync.WaitGroup) error {
 		utils.AviLog.Warnf("Unexpected object type: expected string, got %T", key)
 		return nil
 	}
+	span := utils.StartKeySpan(lib.SLOW_RETRY_LAYER, keyStr, "DequeueSlowRetry")
+	defer span.End()
 	retry.DequeueSlowRetry(keyStr)
 	return nil
 }
@@ -759,11 +1017,21 @@ func SyncFromNodesLayer(key interface{}, wg *sync.WaitGroup) error {
 	cache := avicache.SharedAviObjCache()
 	aviclient := avicache.SharedAVIClients()
 	restlayer := rest.NewRestOperations(cache, aviclient)
+	span := utils.StartKeySpan(utils.GraphLayer, keyStr, "DequeueNodes")
+	defer span.End()
+	start := time.Now()
+	lib.RLockConfig()
+	defer lib.RUnlockConfig()
 	restlayer.DequeueNodes(keyStr)
+	lib.ModelLogger(keyStr, utils.RestLayer).Debugf("key: %s, msg: rest layer sync completed in %v", keyStr, time.Since(start))
 	return nil
 }
 
 func SyncFromStatusQueue(key interface{}, wg *sync.WaitGroup) error {
+	if !lib.AKOIsLeader() {
+		utils.AviLog.Debugf("AKO is not the leader, skipping status update for key: %v", key)
+		return nil
+	}
 	status.DequeueStatus(key)
 	return nil
 }
@@ -26,12 +26,14 @@ import (
 	routev1 "github.com/openshift/api/route/v1"
 	oshiftclient "github.com/openshift/client-go/route/clientset/versioned"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
@@ -45,15 +47,20 @@ var ctrlonce sync.Once
 
 // +kubebuilder:rbac:groups=networking.x-k8s.io,resources=gateways;gateways/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=networking.x-k8s.io,resources=gatewayclasses;gatewayclasses/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=networking.x-k8s.io,resources=httproutes;httproutes/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=networking.x-k8s.io,resources=tcproutes;tcproutes/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=networking.x-k8s.io,resources=udproutes;udproutes/status,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=networking.x-k8s.io,resources=tlsroutes;tlsroutes/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=services;services/status,verbs=get;list;watch;update;patch
 // +kubebuilder:rbac:groups=core,resources=endpoints,verbs=get;list;watch
+// +kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=get;list;watch
 // +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
 type AviController struct {
-	worker_id uint32
-	//recorder        record.EventRecorder
+	worker_id        uint32
+	recorder         record.EventRecorder
 	informers        *utils.Informers
 	dynamicInformers *lib.DynamicInformers
 	workqueue        []workqueue.RateLimitingInterface
@@ -69,8 +76,7 @@ type K8sinformers struct {
 func SharedAviController() *AviController {
 	ctrlonce.Do(func() {
 		controllerInstance = &AviController{
-			worker_id: (uint32(1) << utils.NumWorkersIngestion) - 1,
-			//recorder:  recorder,
+			worker_id:        (uint32(1) << utils.NumWorkersIngestion) - 1,
 			informers:        utils.GetInformers(),
 			dynamicInformers: lib.GetDynamicInformers(),
 			DisableSync:      true,
@@ -119,15 +125,15 @@ func isNodeUpdated(oldNode, newNode *corev1.Node) bool {
 }
 
 // Consider an ingress has been updated only if spec/annotation is updated
-func isIngressUpdated(oldIngress, newIngress *networkingv1beta1.Ingress) bool {
+func isIngressUpdated(oldIngress, newIngress *networkingv1.Ingress) bool {
 	if oldIngress.ResourceVersion == newIngress.ResourceVersion {
 		return false
 	}
 
 	oldSpecHash := utils.Hash(utils.Stringify(oldIngress.Spec))
-	oldAnnotationHash := utils.Hash(utils.Stringify(oldIngress.Annotations))
+	oldAnnotationHash := utils.Hash(utils.Stringify(withoutOperStatusAnnotation(oldIngress.Annotations)))
 	newSpecHash := utils.Hash(utils.Stringify(newIngress.Spec))
-	newAnnotationHash := utils.Hash(utils.Stringify(newIngress.Annotations))
+	newAnnotationHash := utils.Hash(utils.Stringify(withoutOperStatusAnnotation(newIngress.Annotations)))
 
 	if oldSpecHash != newSpecHash || oldAnnotationHash != newAnnotationHash {
 		return true
@@ -136,6 +142,39 @@ func isIngressUpdated(oldIngress, newIngress *networkingv1beta1.Ingress) bool {
 	return false
 }
 
+// withoutOperStatusAnnotation returns the annotations without the oper status annotation, which is set by AKO
+// and does not affect the translation of the object.
+func withoutOperStatusAnnotation(annotations map[string]string) map[string]string {
+	if len(annotations) == 0 {
+		return nil
+	}
+	if _, ok := annotations[status.OperStatusAnnotation]; !ok {
+		return annotations
+	}
+	filtered := make(map[string]string, len(annotations)-1)
+	for k, v := range annotations {
+		if k != status.OperStatusAnnotation {
+			filtered[k] = v
+		}
+	}
+	if len(filtered) == 0 {
+		return nil
+	}
+	return filtered
+}
+
+// isOperStatusOnlyUpdate returns true if the oper status annotation is the only change in the Service.
+func isOperStatusOnlyUpdate(oldSvc, newSvc *corev1.Service) bool {
+	if oldSvc.Annotations[status.OperStatusAnnotation] == newSvc.Annotations[status.OperStatusAnnotation] {
+		return false
+	}
+	oldCopy, newCopy := oldSvc.DeepCopy(), newSvc.DeepCopy()
+	oldCopy.Annotations = withoutOperStatusAnnotation(oldCopy.Annotations)
+	newCopy.Annotations = withoutOperStatusAnnotation(newCopy.Annotations)
+	oldCopy.ResourceVersion, oldCopy.ManagedFields = newCopy.ResourceVersion, newCopy.ManagedFields
+	return reflect.DeepEqual(oldCopy, newCopy)
+}
+
 // Consider a route has been updated only if spec/annotation is updated
 func isRouteUpdated(oldRoute, newRoute *routev1.Route) bool {
 	if oldRoute.ResourceVersion == newRoute.ResourceVersion {
@@ -234,6 +273,29 @@ func AddGatewaysFromNSToIngestionQueue(numWorkers uint32, c *AviController, name
 		c.workqueue[bkt].AddRateLimited(key)
 		utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
 	}
+
+	routeInformers := []struct {
+		kind     string
+		informer cache.SharedIndexInformer
+	}{
+		{lib.HTTPRoute, lib.GetSvcAPIInformers().HTTPRouteInformer.Informer()},
+		{lib.TCPRoute, lib.GetSvcAPIInformers().TCPRouteInformer.Informer()},
+		{lib.UDPRoute, lib.GetSvcAPIInformers().UDPRouteInformer.Informer()},
+		{lib.TLSRoute, lib.GetSvcAPIInformers().TLSRouteInformer.Informer()},
+	}
+	for _, routeInformer := range routeInformers {
+		routeObjs, err := routeInformer.informer.GetIndexer().ByIndex(cache.NamespaceIndex, namespace)
+		if err != nil {
+			utils.AviLog.Errorf("Unable to retrieve the %s objects during namespace sync: %s", routeInformer.kind, err)
+			return
+		}
+		for _, routeObj := range routeObjs {
+			key := routeInformer.kind + "/" + utils.ObjKey(routeObj)
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: %s for namespace: %s", key, msg, namespace)
+		}
+	}
 }
 
 /*
@@ -454,12 +516,59 @@ func AddPodEventHandler(numWorkers uint32, c *AviController) cache.ResourceEvent
 	return podEventHandler
 }
 
+// AddPodReadinessGateEventHandler enqueues the Endpoints of the Services selecting a pod, once the pod is
+// waiting only for the readiness gate managed by AKO. The pod is present in the not ready addresses of the
+// Endpoints, which are not updated when the containers of the pod become ready.
+func AddPodReadinessGateEventHandler(numWorkers uint32, c *AviController) cache.ResourceEventHandler {
+	enqueueEndpoints := func(pod *corev1.Pod) {
+		svcs, err := utils.GetInformers().ServiceInformer.Lister().Services(pod.Namespace).List(labels.Everything())
+		if err != nil {
+			utils.AviLog.Warnf("Error in listing services in namespace %s: %v", pod.Namespace, err)
+			return
+		}
+		for _, svc := range svcs {
+			if len(svc.Spec.Selector) == 0 || !labels.SelectorFromSet(svc.Spec.Selector).Matches(labels.Set(pod.Labels)) {
+				continue
+			}
+			key := utils.Endpoints + "/" + utils.ObjKey(svc)
+			bkt := utils.Bkt(pod.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: pod %s waiting for readiness gate", key, pod.Name)
+		}
+	}
+
+	podEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			pod := obj.(*corev1.Pod)
+			if lib.IsPodWaitingForReadinessGate(pod) {
+				enqueueEndpoints(pod)
+			}
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldPod := old.(*corev1.Pod)
+			newPod := cur.(*corev1.Pod)
+			if lib.IsPodWaitingForReadinessGate(newPod) && !lib.IsPodWaitingForReadinessGate(oldPod) {
+				enqueueEndpoints(newPod)
+			}
+		},
+	}
+	return podEventHandler
+}
+
 func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 	cs := k8sinfo.Cs
 	utils.AviLog.Debugf("Creating event broadcaster")
 	eventBroadcaster := record.NewBroadcaster()
 	eventBroadcaster.StartLogging(utils.AviLog.Debugf)
 	eventBroadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: cs.CoreV1().Events("")})
+	c.recorder = eventBroadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: lib.AKOEventComponent})
+	lib.SetEventRecorder(c.recorder)
 	mcpQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
 	c.workqueue = mcpQueue.Workqueue
 	numWorkers := mcpQueue.NumWorkers
@@ -516,6 +625,70 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		},
 	}
 
+	// The EndpointSlices of a Service are enqueued with the Endpoints key of the Service, so that the
+	// pool servers are evaluated the same way for both the APIs.
+	epSliceEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice := obj.(*discoveryv1beta1.EndpointSlice)
+			svcName, ok := epSlice.Labels[discoveryv1beta1.LabelServiceName]
+			if !ok {
+				return
+			}
+			key := utils.Endpoints + "/" + epSlice.Namespace + "/" + svcName
+			bkt := utils.Bkt(epSlice.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: ADD EndpointSlice %s", key, epSlice.Name)
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			epSlice, ok := obj.(*discoveryv1beta1.EndpointSlice)
+			if !ok {
+				// endpointslice was deleted but its final state is unrecorded.
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				epSlice, ok = tombstone.Obj.(*discoveryv1beta1.EndpointSlice)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not an EndpointSlice: %#v", obj)
+					return
+				}
+			}
+			svcName, ok := epSlice.Labels[discoveryv1beta1.LabelServiceName]
+			if !ok {
+				return
+			}
+			key := utils.Endpoints + "/" + epSlice.Namespace + "/" + svcName
+			bkt := utils.Bkt(epSlice.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: DELETE EndpointSlice %s", key, epSlice.Name)
+		},
+		UpdateFunc: func(old, cur interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oepSlice := old.(*discoveryv1beta1.EndpointSlice)
+			cepSlice := cur.(*discoveryv1beta1.EndpointSlice)
+			if reflect.DeepEqual(cepSlice.Endpoints, oepSlice.Endpoints) && reflect.DeepEqual(cepSlice.Ports, oepSlice.Ports) {
+				return
+			}
+			svcName, ok := cepSlice.Labels[discoveryv1beta1.LabelServiceName]
+			if !ok {
+				return
+			}
+			key := utils.Endpoints + "/" + cepSlice.Namespace + "/" + svcName
+			bkt := utils.Bkt(cepSlice.Namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+			utils.AviLog.Debugf("key: %s, msg: UPDATE EndpointSlice %s", key, cepSlice.Name)
+		},
+	}
+
 	svcEventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: func(obj interface{}) {
 			if c.DisableSync {
@@ -591,6 +764,9 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			}
 			oldobj := old.(*corev1.Service)
 			svc := cur.(*corev1.Service)
+			if isOperStatusOnlyUpdate(oldobj, svc) {
+				return
+			}
 			if oldobj.ResourceVersion != svc.ResourceVersion || !reflect.DeepEqual(svc.Annotations, oldobj.Annotations) {
 				// Only add the key if the resource versions have changed.
 				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(svc))
@@ -622,7 +798,11 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		},
 	}
 
-	c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	if utils.GetEndpointSliceEnabled() {
+		c.informers.EpSliceInformer.Informer().AddEventHandler(epSliceEventHandler)
+	} else {
+		c.informers.EpInformer.Informer().AddEventHandler(epEventHandler)
+	}
 
 	c.informers.ServiceInformer.Informer().AddEventHandler(svcEventHandler)
 	c.informers.ServiceInformer.Informer().AddIndexers(
@@ -639,6 +819,18 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				}
 				return []string{}, nil
 			},
+			lib.L4RuleServicesIndex: func(obj interface{}) ([]string, error) {
+				service, ok := obj.(*corev1.Service)
+				if !ok {
+					return []string{}, nil
+				}
+				if service.Spec.Type == corev1.ServiceTypeLoadBalancer {
+					if val, ok := service.Annotations[lib.L4RuleNameAnnotation]; ok && val != "" {
+						return []string{service.Namespace + "/" + val}, nil
+					}
+				}
+				return []string{}, nil
+			},
 		},
 	)
 
@@ -792,7 +984,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			if c.DisableSync {
 				return
 			}
-			ingress := obj.(*networkingv1beta1.Ingress)
+			ingress := obj.(*networkingv1.Ingress)
 			namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(ingress))
 			if !utils.CheckIfNamespaceAccepted(namespace) {
 				utils.AviLog.Debugf("Ingress add event: Namespace: %s didn't qualify filter. Not adding ingress", namespace)
@@ -807,7 +999,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			if c.DisableSync {
 				return
 			}
-			ingress, ok := obj.(*networkingv1beta1.Ingress)
+			ingress, ok := obj.(*networkingv1.Ingress)
 			if !ok {
 				// ingress was deleted but its final state is unrecorded.
 				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
@@ -815,7 +1007,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
 					return
 				}
-				ingress, ok = tombstone.Obj.(*networkingv1beta1.Ingress)
+				ingress, ok = tombstone.Obj.(*networkingv1.Ingress)
 				if !ok {
 					utils.AviLog.Errorf("Tombstone contained object that is not an Ingress: %#v", obj)
 					return
@@ -835,8 +1027,8 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 			if c.DisableSync {
 				return
 			}
-			oldobj := old.(*networkingv1beta1.Ingress)
-			ingress := cur.(*networkingv1beta1.Ingress)
+			oldobj := old.(*networkingv1.Ingress)
+			ingress := cur.(*networkingv1.Ingress)
 			if isIngressUpdated(oldobj, ingress) {
 				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(ingress))
 				if !utils.CheckIfNamespaceAccepted(namespace) {
@@ -911,7 +1103,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				if c.DisableSync {
 					return
 				}
-				ingClass := obj.(*networkingv1beta1.IngressClass)
+				ingClass := obj.(*networkingv1.IngressClass)
 				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(ingClass))
 				key := utils.IngressClass + "/" + utils.ObjKey(ingClass)
 				bkt := utils.Bkt(namespace, numWorkers)
@@ -922,14 +1114,14 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				if c.DisableSync {
 					return
 				}
-				ingClass, ok := obj.(*networkingv1beta1.IngressClass)
+				ingClass, ok := obj.(*networkingv1.IngressClass)
 				if !ok {
 					tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
 					if !ok {
 						utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
 						return
 					}
-					ingClass, ok = tombstone.Obj.(*networkingv1beta1.IngressClass)
+					ingClass, ok = tombstone.Obj.(*networkingv1.IngressClass)
 					if !ok {
 						utils.AviLog.Errorf("Tombstone contained object that is not an IngressClass: %#v", obj)
 						return
@@ -945,8 +1137,8 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 				if c.DisableSync {
 					return
 				}
-				oldobj := old.(*networkingv1beta1.IngressClass)
-				ingClass := cur.(*networkingv1beta1.IngressClass)
+				oldobj := old.(*networkingv1.IngressClass)
+				ingClass := cur.(*networkingv1.IngressClass)
 				if oldobj.ResourceVersion != ingClass.ResourceVersion {
 					// Only add the key if the resource versions have changed.
 					namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(ingClass))
@@ -962,7 +1154,7 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		c.informers.IngressClassInformer.Informer().AddIndexers(
 			cache.Indexers{
 				lib.AviSettingIngClassIndex: func(obj interface{}) ([]string, error) {
-					ingclass, ok := obj.(*networkingv1beta1.IngressClass)
+					ingclass, ok := obj.(*networkingv1.IngressClass)
 					if !ok {
 						return []string{}, nil
 					}
@@ -1017,6 +1209,29 @@ func (c *AviController) SetupEventHandlers(k8sinfo K8sinformers) {
 		podEventHandler := AddPodEventHandler(numWorkers, c)
 		c.informers.PodInformer.Informer().AddEventHandler(podEventHandler)
 	}
+
+	if lib.IsPodReadinessGateEnabled() {
+		podEventHandler := AddPodReadinessGateEventHandler(numWorkers, c)
+		c.informers.PodInformer.Informer().AddEventHandler(podEventHandler)
+		c.informers.PodInformer.Informer().AddIndexers(
+			cache.Indexers{
+				lib.PodIPIndex: func(obj interface{}) ([]string, error) {
+					pod, ok := obj.(*corev1.Pod)
+					if !ok {
+						return []string{}, nil
+					}
+					var podIPs []string
+					for _, podIP := range pod.Status.PodIPs {
+						podIPs = append(podIPs, podIP.IP)
+					}
+					if len(podIPs) == 0 && pod.Status.PodIP != "" {
+						podIPs = append(podIPs, pod.Status.PodIP)
+					}
+					return podIPs, nil
+				},
+			},
+		)
+	}
 }
 
 func validateAviConfigMap(obj interface{}) (*corev1.ConfigMap, bool) {
@@ -1041,16 +1256,22 @@ func validateAviSecret(secret *corev1.Secret) bool {
 
 func (c *AviController) Start(stopCh <-chan struct{}) {
 	go c.informers.ServiceInformer.Informer().Run(stopCh)
-	go c.informers.EpInformer.Informer().Run(stopCh)
 	go c.informers.SecretInformer.Informer().Run(stopCh)
 
 	informersList := []cache.InformerSynced{
-		c.informers.EpInformer.Informer().HasSynced,
 		c.informers.ServiceInformer.Informer().HasSynced,
 		c.informers.SecretInformer.Informer().HasSynced,
 	}
 
-	if lib.GetServiceType() == lib.NodePortLocal {
+	if utils.GetEndpointSliceEnabled() {
+		go c.informers.EpSliceInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpSliceInformer.Informer().HasSynced)
+	} else {
+		go c.informers.EpInformer.Informer().Run(stopCh)
+		informersList = append(informersList, c.informers.EpInformer.Informer().HasSynced)
+	}
+
+	if lib.GetServiceType() == lib.NodePortLocal || lib.IsPodReadinessGateEnabled() {
 		go c.informers.PodInformer.Informer().Run(stopCh)
 		informersList = append(informersList, c.informers.PodInformer.Informer().HasSynced)
 	}
@@ -1075,6 +1296,14 @@ func (c *AviController) Start(stopCh <-chan struct{}) {
 			informersList = append(informersList, lib.GetSvcAPIInformers().GatewayClassInformer.Informer().HasSynced)
 			go lib.GetSvcAPIInformers().GatewayInformer.Informer().Run(stopCh)
 			informersList = append(informersList, lib.GetSvcAPIInformers().GatewayInformer.Informer().HasSynced)
+			go lib.GetSvcAPIInformers().HTTPRouteInformer.Informer().Run(stopCh)
+			informersList = append(informersList, lib.GetSvcAPIInformers().HTTPRouteInformer.Informer().HasSynced)
+			go lib.GetSvcAPIInformers().TCPRouteInformer.Informer().Run(stopCh)
+			informersList = append(informersList, lib.GetSvcAPIInformers().TCPRouteInformer.Informer().HasSynced)
+			go lib.GetSvcAPIInformers().UDPRouteInformer.Informer().Run(stopCh)
+			informersList = append(informersList, lib.GetSvcAPIInformers().UDPRouteInformer.Informer().HasSynced)
+			go lib.GetSvcAPIInformers().TLSRouteInformer.Informer().Run(stopCh)
+			informersList = append(informersList, lib.GetSvcAPIInformers().TLSRouteInformer.Informer().HasSynced)
 		}
 		if c.informers.IngressInformer != nil {
 			go c.informers.IngressInformer.Informer().Run(stopCh)
@@ -1113,6 +1342,11 @@ func (c *AviController) Start(stopCh <-chan struct{}) {
 			go lib.GetCRDInformers().HTTPRuleInformer.Informer().Run(stopCh)
 			informersList = append(informersList, lib.GetCRDInformers().HTTPRuleInformer.Informer().HasSynced)
 		}
+
+		if lib.GetL4RuleEnabled() {
+			go lib.GetCRDInformers().L4RuleInformer.Informer().Run(stopCh)
+			informersList = append(informersList, lib.GetCRDInformers().L4RuleInformer.Informer().HasSynced)
+		}
 	}
 
 	if !cache.WaitForCacheSync(stopCh, informersList...) {
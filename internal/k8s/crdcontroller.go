@@ -17,8 +17,10 @@ package k8s
 import (
 	"encoding/json"
 	"fmt"
+	"net"
 	"reflect"
 	"regexp"
+	"strings"
 	"time"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
@@ -41,11 +43,13 @@ func NewCRDInformers(cs akocrd.Interface) {
 	hostRuleInformer := akoInformerFactory.Ako().V1alpha1().HostRules()
 	httpRuleInformer := akoInformerFactory.Ako().V1alpha1().HTTPRules()
 	aviSettingsInformer := akoInformerFactory.Ako().V1alpha1().AviInfraSettings()
+	l4RuleInformer := akoInformerFactory.Ako().V1alpha1().L4Rules()
 
 	lib.SetCRDInformers(&lib.AKOCrdInformers{
 		HostRuleInformer:        hostRuleInformer,
 		HTTPRuleInformer:        httpRuleInformer,
 		AviInfraSettingInformer: aviSettingsInformer,
+		L4RuleInformer:          l4RuleInformer,
 	})
 }
 
@@ -282,17 +286,79 @@ func (c *AviController) SetupAKOCRDEventHandlers(numWorkers uint32) {
 		)
 	}
 
+	if lib.GetL4RuleEnabled() {
+		l4RuleEventHandler := cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				if c.DisableSync {
+					return
+				}
+				l4Rule := obj.(*akov1alpha1.L4Rule)
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l4Rule))
+				key := lib.L4Rule + "/" + utils.ObjKey(l4Rule)
+				if err := validateL4RuleObj(key, l4Rule); err != nil {
+					utils.AviLog.Warnf("Error retrieved during validation of L4Rule: %v", err)
+				}
+				utils.AviLog.Debugf("key: %s, msg: ADD", key)
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+			},
+			UpdateFunc: func(old, new interface{}) {
+				if c.DisableSync {
+					return
+				}
+				oldObj := old.(*akov1alpha1.L4Rule)
+				l4Rule := new.(*akov1alpha1.L4Rule)
+				// the Services are synced again once the status is updated post validation,
+				// since only Accepted L4Rules are applied.
+				if !reflect.DeepEqual(oldObj.Spec, l4Rule.Spec) || oldObj.Status.Status != l4Rule.Status.Status {
+					namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l4Rule))
+					key := lib.L4Rule + "/" + utils.ObjKey(l4Rule)
+					if !reflect.DeepEqual(oldObj.Spec, l4Rule.Spec) {
+						if err := validateL4RuleObj(key, l4Rule); err != nil {
+							utils.AviLog.Warnf("Error retrieved during validation of L4Rule: %v", err)
+						}
+					}
+					utils.AviLog.Debugf("key: %s, msg: UPDATE", key)
+					bkt := utils.Bkt(namespace, numWorkers)
+					c.workqueue[bkt].AddRateLimited(key)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				if c.DisableSync {
+					return
+				}
+				l4Rule, ok := obj.(*akov1alpha1.L4Rule)
+				if !ok {
+					tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+					if !ok {
+						utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+						return
+					}
+					l4Rule, ok = tombstone.Obj.(*akov1alpha1.L4Rule)
+					if !ok {
+						utils.AviLog.Errorf("Tombstone contained object that is not an L4Rule: %#v", obj)
+						return
+					}
+				}
+				key := lib.L4Rule + "/" + utils.ObjKey(l4Rule)
+				namespace, _, _ := cache.SplitMetaNamespaceKey(utils.ObjKey(l4Rule))
+				utils.AviLog.Debugf("key: %s, msg: DELETE", key)
+				// no need to validate for delete handler
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+			},
+		}
+
+		informer.L4RuleInformer.Informer().AddEventHandler(l4RuleEventHandler)
+	}
+
 	return
 }
 
 // validateHostRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
-	var err error
-	fqdn := hostrule.Spec.VirtualHost.Fqdn
-	foundHost, foundHR := objects.SharedCRDLister().GetFQDNToHostruleMapping(fqdn)
-	if foundHost && foundHR != hostrule.Namespace+"/"+hostrule.Name {
-		err = fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundHR)
+	if err := checkHostRuleObj(key, hostrule); err != nil {
 		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{
 			Status: lib.StatusRejected,
 			Error:  err.Error(),
@@ -300,6 +366,58 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 		return err
 	}
 
+	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+	return nil
+}
+
+// checkHostRuleObj checks the HostRule for duplicate fqdns, invalid source ranges
+// and refs that are not present on the controller.
+func checkHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
+	fqdn := hostrule.Spec.VirtualHost.Fqdn
+	fqdnType := lib.GetHostRuleFqdnType(hostrule)
+	var foundHost bool
+	var foundHR string
+	switch fqdnType {
+	case akov1alpha1.FqdnTypeExact:
+		foundHost, foundHR = objects.SharedCRDLister().GetExactFQDNToHostruleMapping(fqdn)
+	case akov1alpha1.FqdnTypeWildcard:
+		if !strings.HasPrefix(fqdn, "*.") || strings.Count(fqdn, "*") > 1 {
+			return fmt.Errorf("wildcard fqdn %s must start with *. and have no other *", fqdn)
+		}
+		foundHost, foundHR = objects.SharedCRDLister().GetHostruleForFQDNMatch(fqdn, fqdnType)
+	case akov1alpha1.FqdnTypeRegex:
+		if _, err := objects.NewHostRuleFQDNMatch(fqdn, fqdnType); err != nil {
+			return fmt.Errorf("invalid regex fqdn %s: %v", fqdn, err)
+		}
+		foundHost, foundHR = objects.SharedCRDLister().GetHostruleForFQDNMatch(fqdn, fqdnType)
+	default:
+		return fmt.Errorf("invalid fqdnType %s, supported types are %s, %s and %s", fqdnType,
+			akov1alpha1.FqdnTypeExact, akov1alpha1.FqdnTypeWildcard, akov1alpha1.FqdnTypeRegex)
+	}
+	if foundHost && foundHR != hostrule.Namespace+"/"+hostrule.Name {
+		return fmt.Errorf("duplicate fqdn %s found in %s", fqdn, foundHR)
+	}
+
+	for _, sourceRange := range hostrule.Spec.VirtualHost.SourceRanges {
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			return fmt.Errorf("invalid sourceRange %s: %v", sourceRange, err)
+		}
+	}
+
+	clientCert := hostrule.Spec.VirtualHost.TLS.ClientCertificate
+	if clientCert.CASecret != "" {
+		if hostrule.Spec.VirtualHost.ApplicationProfile != "" {
+			return fmt.Errorf("clientCertificate can not be used along with applicationProfile")
+		}
+		if clientCert.Mode != "" && clientCert.Mode != lib.ClientCertModeRequire && clientCert.Mode != lib.ClientCertModeRequest {
+			return fmt.Errorf("invalid clientCertificate mode %s, supported modes are %s and %s", clientCert.Mode,
+				lib.ClientCertModeRequire, lib.ClientCertModeRequest)
+		}
+	}
+
 	refData := map[string]string{
 		hostrule.Spec.VirtualHost.WAFPolicy:                  "WafPolicy",
 		hostrule.Spec.VirtualHost.ApplicationProfile:         "AppProfile",
@@ -317,19 +435,7 @@ func validateHostRuleObj(key string, hostrule *akov1alpha1.HostRule) error {
 		refData[script] = "VsDatascript"
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
-	}
-
-	status.UpdateHostRuleStatus(key, hostrule, status.UpdateCRDStatusOptions{
-		Status: lib.StatusAccepted,
-		Error:  "",
-	})
-	return nil
+	return checkRefsOnController(key, refData)
 }
 
 func checkRefsOnController(key string, refMap map[string]string) error {
@@ -351,6 +457,8 @@ var refModelMap = map[string]string{
 	"HttpPolicySet":          "httppolicyset",
 	"SslProfile":             "sslprofile",
 	"AppProfile":             "applicationprofile",
+	"L4AppProfile":           "applicationprofile",
+	"NetworkProfile":         "networkprofile",
 	"AnalyticsProfile":       "analyticsprofile",
 	"ErrorPageProfile":       "errorpageprofile",
 	"VsDatascript":           "vsdatascriptset",
@@ -422,6 +530,12 @@ func checkRefOnController(key, refKey, refValue string) error {
 			return fmt.Errorf("%s \"%s\" found on controller is invalid, must be of type: %s",
 				refModelMap[refKey], refValue, lib.AllowedApplicationProfile)
 		}
+	case "L4AppProfile":
+		if appProfType, ok := item["type"].(string); ok && appProfType != lib.AllowedL4ApplicationProfile {
+			utils.AviLog.Warnf("key: %s, msg: applicationProfile: %s must be of type %s", key, refValue, lib.AllowedL4ApplicationProfile)
+			return fmt.Errorf("%s \"%s\" found on controller is invalid, must be of type: %s",
+				refModelMap[refKey], refValue, lib.AllowedL4ApplicationProfile)
+		}
 	case "ServiceEngineGroup":
 		if seGroupLabels, ok := item["labels"].([]map[string]string); ok {
 			if len(seGroupLabels) == 0 {
@@ -449,8 +563,28 @@ func checkRefOnController(key, refKey, refValue string) error {
 // validateHTTPRuleObj would do validation checks
 // update internal CRD caches, and push relevant ingresses to ingestion
 func validateHTTPRuleObj(key string, httprule *akov1alpha1.HTTPRule) error {
+	if err := checkHTTPRuleObj(key, httprule); err != nil {
+		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+			Status: lib.StatusRejected,
+			Error:  err.Error(),
+		})
+		return err
+	}
+
+	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+		Status: lib.StatusAccepted,
+		Error:  "",
+	})
+	return nil
+}
+
+// checkHTTPRuleObj checks the HTTPRule for refs that are not present on the controller.
+func checkHTTPRuleObj(key string, httprule *akov1alpha1.HTTPRule) error {
 	refData := make(map[string]string)
 	for _, path := range httprule.Spec.Paths {
+		if err := checkHTTPRulePathActions(path); err != nil {
+			return err
+		}
 		refData[path.TLS.SSLProfile] = "SslProfile"
 		refData[path.ApplicationPersistence] = "ApplicationPersistence"
 
@@ -459,55 +593,121 @@ func validateHTTPRuleObj(key string, httprule *akov1alpha1.HTTPRule) error {
 		}
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
-		status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+	return checkRefsOnController(key, refData)
+}
+
+// checkHTTPRulePathActions checks the header, rewrite, redirect and rate limit settings of a HTTPRule path.
+func checkHTTPRulePathActions(path akov1alpha1.HTTPRulePaths) error {
+	for _, headers := range []akov1alpha1.HTTPRuleHeaders{path.RequestHeaders, path.ResponseHeaders} {
+		names := append([]string{}, headers.Remove...)
+		for _, header := range headers.Add {
+			names = append(names, header.Name)
+		}
+		for _, header := range headers.Replace {
+			names = append(names, header.Name)
+		}
+		for _, name := range names {
+			if name == "" {
+				return fmt.Errorf("header name is missing for the path %s", path.Target)
+			}
+		}
+	}
+
+	if path.Rewrite.Prefix != "" && !strings.HasPrefix(path.Rewrite.Prefix, "/") {
+		return fmt.Errorf("rewrite prefix %s of the path %s must start with /", path.Rewrite.Prefix, path.Target)
+	}
+
+	if path.Redirect != nil {
+		if path.Rewrite.Prefix != "" || len(path.RequestHeaders.Add) > 0 || len(path.RequestHeaders.Replace) > 0 || len(path.RequestHeaders.Remove) > 0 {
+			return fmt.Errorf("redirect can not be used along with rewrite or requestHeaders for the path %s", path.Target)
+		}
+		if err := checkHTTPRuleRedirect(path.Redirect, path.Target); err != nil {
+			return err
+		}
+	}
+
+	if path.MaxConcurrentConnectionsPerServer < 0 {
+		return fmt.Errorf("invalid maxConcurrentConnectionsPerServer %d for the path %s", path.MaxConcurrentConnectionsPerServer, path.Target)
+	}
+
+	rateLimit := path.RateLimit
+	if rateLimit == nil {
+		return nil
+	}
+	if rateLimit.Count <= 0 || rateLimit.Period < 0 || rateLimit.Burst < 0 {
+		return fmt.Errorf("invalid rateLimit for the path %s, count must be positive and period and burst can not be negative", path.Target)
+	}
+	switch rateLimit.Action {
+	case "", lib.RateLimitActionDrop, lib.RateLimitActionTooManyRequests:
+		if rateLimit.Redirect != nil {
+			return fmt.Errorf("rateLimit redirect is only applicable for the action %s for the path %s", lib.RateLimitActionRedirect, path.Target)
+		}
+	case lib.RateLimitActionRedirect:
+		if rateLimit.Redirect == nil {
+			return fmt.Errorf("rateLimit redirect is missing for the action %s for the path %s", lib.RateLimitActionRedirect, path.Target)
+		}
+		return checkHTTPRuleRedirect(rateLimit.Redirect, path.Target)
+	default:
+		return fmt.Errorf("invalid rateLimit action %s for the path %s, supported actions are %s, %s and %s", rateLimit.Action, path.Target,
+			lib.RateLimitActionDrop, lib.RateLimitActionTooManyRequests, lib.RateLimitActionRedirect)
+	}
+	return nil
+}
+
+func checkHTTPRuleRedirect(redirect *akov1alpha1.HTTPRuleRedirect, target string) error {
+	if redirect.Protocol != "" && redirect.Protocol != "HTTP" && redirect.Protocol != "HTTPS" {
+		return fmt.Errorf("invalid redirect protocol %s for the path %s, supported protocols are HTTP and HTTPS", redirect.Protocol, target)
+	}
+	if redirect.StatusCode != 0 && redirect.StatusCode != 301 && redirect.StatusCode != 302 && redirect.StatusCode != 307 {
+		return fmt.Errorf("invalid redirect statusCode %d for the path %s, supported status codes are 301, 302 and 307", redirect.StatusCode, target)
+	}
+	if redirect.Path != "" && !strings.HasPrefix(redirect.Path, "/") {
+		return fmt.Errorf("redirect path %s of the path %s must start with /", redirect.Path, target)
+	}
+	return nil
+}
+
+// validateL4RuleObj would do validation checks on the ingested L4Rule objects
+func validateL4RuleObj(key string, l4Rule *akov1alpha1.L4Rule) error {
+	if err := checkL4RuleObj(key, l4Rule); err != nil {
+		status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
 			Status: lib.StatusRejected,
 			Error:  err.Error(),
 		})
 		return err
 	}
 
-	status.UpdateHTTPRuleStatus(key, httprule, status.UpdateCRDStatusOptions{
+	status.UpdateL4RuleStatus(key, l4Rule, status.UpdateCRDStatusOptions{
 		Status: lib.StatusAccepted,
 		Error:  "",
 	})
 	return nil
 }
 
-// validateAviInfraSetting would do validaion checks on the
-// ingested AviInfraSetting objects
-func validateAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetting) error {
-	if ((infraSetting.Spec.Network.EnableRhi != nil && !*infraSetting.Spec.Network.EnableRhi) || infraSetting.Spec.Network.EnableRhi == nil) &&
-		len(infraSetting.Spec.Network.BgpPeerLabels) > 0 {
-		err := fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
-		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-			Status: lib.StatusRejected,
-			Error:  err.Error(),
-		})
-		return err
+// checkL4RuleObj checks the L4Rule for an invalid loadBalancerPolicy and refs that are not present on the controller.
+func checkL4RuleObj(key string, l4Rule *akov1alpha1.L4Rule) error {
+	lbPolicy := l4Rule.Spec.LoadBalancerPolicy
+	if lbPolicy.Hash != "" && lbPolicy.Algorithm != lib.LB_ALGORITHM_CONSISTENT_HASH {
+		return fmt.Errorf("loadBalancerPolicy hash %s is only applicable for algorithm %s", lbPolicy.Hash, lib.LB_ALGORITHM_CONSISTENT_HASH)
 	}
 
-	refData := make(map[string]string)
-	for _, vipNetwork := range infraSetting.Spec.Network.VipNetworks {
-		if vipNetwork.Cidr != "" {
-			re := regexp.MustCompile(lib.IPCIDRRegex)
-			if !re.MatchString(vipNetwork.Cidr) {
-				err := fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
-				status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
-					Status: lib.StatusRejected,
-					Error:  err.Error(),
-				})
-				return err
-			}
-		}
-		refData[vipNetwork.NetworkName] = "Network"
+	refData := map[string]string{
+		l4Rule.Spec.ApplicationProfile: "L4AppProfile",
+		l4Rule.Spec.NetworkProfile:     "NetworkProfile",
+		l4Rule.Spec.AnalyticsProfile:   "AnalyticsProfile",
 	}
 
-	if infraSetting.Spec.SeGroup.Name != "" {
-		refData[infraSetting.Spec.SeGroup.Name] = "ServiceEngineGroup"
+	for _, hm := range l4Rule.Spec.HealthMonitors {
+		refData[hm] = "HealthMonitor"
 	}
 
-	if err := checkRefsOnController(key, refData); err != nil {
+	return checkRefsOnController(key, refData)
+}
+
+// validateAviInfraSetting would do validaion checks on the
+// ingested AviInfraSetting objects
+func validateAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetting) error {
+	if err := checkAviInfraSetting(key, infraSetting); err != nil {
 		status.UpdateAviInfraSettingStatus(key, infraSetting, status.UpdateCRDStatusOptions{
 			Status: lib.StatusRejected,
 			Error:  err.Error(),
@@ -529,6 +729,38 @@ func validateAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetti
 	return nil
 }
 
+// checkAviInfraSetting checks the AviInfraSetting for invalid network settings
+// and refs that are not present on the controller.
+func checkAviInfraSetting(key string, infraSetting *akov1alpha1.AviInfraSetting) error {
+	if ((infraSetting.Spec.Network.EnableRhi != nil && !*infraSetting.Spec.Network.EnableRhi) || infraSetting.Spec.Network.EnableRhi == nil) &&
+		len(infraSetting.Spec.Network.BgpPeerLabels) > 0 {
+		return fmt.Errorf("BGPPeerLabels cannot be set if EnableRhi is false.")
+	}
+
+	refData := make(map[string]string)
+	for _, vipNetwork := range infraSetting.Spec.Network.VipNetworks {
+		if vipNetwork.Cidr != "" {
+			re := regexp.MustCompile(lib.IPCIDRRegex)
+			if !re.MatchString(vipNetwork.Cidr) {
+				return fmt.Errorf("invalid CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.Cidr, vipNetwork.NetworkName)
+			}
+		}
+		if vipNetwork.V6Cidr != "" {
+			ip, _, err := net.ParseCIDR(vipNetwork.V6Cidr)
+			if err != nil || ip.To4() != nil {
+				return fmt.Errorf("invalid IPv6 CIDR configuration %s detected for networkName %s in vipNetworkList", vipNetwork.V6Cidr, vipNetwork.NetworkName)
+			}
+		}
+		refData[vipNetwork.NetworkName] = "Network"
+	}
+
+	if infraSetting.Spec.SeGroup.Name != "" {
+		refData[infraSetting.Spec.SeGroup.Name] = "ServiceEngineGroup"
+	}
+
+	return checkRefsOnController(key, refData)
+}
+
 // addSeGroupLabel configures SEGroup with appropriate labels, during AviInfraSetting
 // creation/updates after ingestion
 func addSeGroupLabel(key, segName string) {
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"context"
+	"os"
+	"time"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// RunLeaderElection participates in the Lease based leader election among the AKO replicas, and
+// blocks till stopCh is closed. A replica that loses the leadership goes back to being a standby
+// and contests the election again.
+func (c *AviController) RunLeaderElection(cs kubernetes.Interface, stopCh <-chan struct{}) {
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		identity, _ = os.Hostname()
+	}
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      lib.AKOLeaseName,
+			Namespace: utils.GetAKONamespace(),
+		},
+		Client: cs.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{
+			Identity: identity,
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-stopCh
+		cancel()
+	}()
+
+	for {
+		utils.AviLog.Infof("Starting leader election for AKO with identity %s", identity)
+		leaderelection.RunOrDie(ctx, leaderelection.LeaderElectionConfig{
+			Lock:            lock,
+			ReleaseOnCancel: true,
+			LeaseDuration:   leaseDuration,
+			RenewDeadline:   renewDeadline,
+			RetryPeriod:     retryPeriod,
+			Callbacks: leaderelection.LeaderCallbacks{
+				OnStartedLeading: func(ctx context.Context) {
+					c.onStartedLeading()
+				},
+				OnStoppedLeading: func() {
+					utils.AviLog.Warnf("AKO lost the leadership, running as a standby")
+					lib.SetAKOIsLeader(false)
+				},
+				OnNewLeader: func(leader string) {
+					utils.AviLog.Infof("Current AKO leader is %s", leader)
+				},
+			},
+		})
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// onStartedLeading refreshes the avi object cache, since the previous leader could have changed the objects
+// in the controller, and publishes the models that were built while this replica was a standby.
+func (c *AviController) onStartedLeading() {
+	utils.AviLog.Infof("AKO became the leader, taking over the sync to the Avi controller")
+	aviClientPool := avicache.SharedAVIClients()
+	aviObjCache := avicache.SharedAviObjCache()
+	if aviClientPool != nil && len(aviClientPool.AviClient) > 0 {
+		_, _, err := aviObjCache.AviObjCachePopulate(aviClientPool.AviClient[0], utils.CtrlVersion, utils.CloudName)
+		if err != nil {
+			utils.AviLog.Warnf("failed to refresh avi cache with error: %v", err)
+		}
+	}
+	lib.SetAKOIsLeader(true)
+
+	if c.DisableSync {
+		// deleteConfig could have been set while this replica was a standby.
+		cm, err := c.informers.ConfigMapInformer.Lister().ConfigMaps(utils.GetAKONamespace()).Get(lib.AviConfigMap)
+		if err == nil && delConfigFromData(cm.Data) {
+			c.DeleteModels()
+		}
+		return
+	}
+	restlayer := rest.NewRestOperations(aviObjCache, aviClientPool)
+	restlayer.SyncObjectStatuses()
+	deleteStaleObjects()
+	publishAllModelsToRestLayer("")
+}
@@ -42,9 +42,17 @@ func NewSvcApiInformers(cs svccrd.Interface) {
 	svcApiInfomerFactory := svcapiinformers.NewSharedInformerFactory(cs, time.Second*30)
 	gwClassInformer := svcApiInfomerFactory.Networking().V1alpha1().GatewayClasses()
 	gwInformer := svcApiInfomerFactory.Networking().V1alpha1().Gateways()
+	httpRouteInformer := svcApiInfomerFactory.Networking().V1alpha1().HTTPRoutes()
+	tcpRouteInformer := svcApiInfomerFactory.Networking().V1alpha1().TCPRoutes()
+	udpRouteInformer := svcApiInfomerFactory.Networking().V1alpha1().UDPRoutes()
+	tlsRouteInformer := svcApiInfomerFactory.Networking().V1alpha1().TLSRoutes()
 	lib.SetSvcAPIsInformers(&lib.ServicesAPIInformers{
 		GatewayInformer:      gwInformer,
 		GatewayClassInformer: gwClassInformer,
+		HTTPRouteInformer:    httpRouteInformer,
+		TCPRouteInformer:     tcpRouteInformer,
+		UDPRouteInformer:     udpRouteInformer,
+		TLSRouteInformer:     tlsRouteInformer,
 	})
 }
 
@@ -66,6 +74,10 @@ func InformerStatusUpdatesForSvcApiGateway(key string, gateway *servicesapi.Gate
 	}
 
 	for _, listener := range gateway.Spec.Listeners {
+		if lib.IsSvcApiRouteListener(listener) {
+			// The routes are selected by the kind of the listener, not by the gateway labels.
+			continue
+		}
 		gwName, nameOk := listener.Routes.Selector.MatchLabels[lib.SvcApiGatewayNameLabelKey]
 		gwNamespace, nsOk := listener.Routes.Selector.MatchLabels[lib.SvcApiGatewayNamespaceLabelKey]
 		if !nameOk || !nsOk ||
@@ -90,6 +102,13 @@ func InformerStatusUpdatesForSvcApiGateway(key string, gateway *servicesapi.Gate
 			Message: fmt.Sprintf("Unable to identify controller %s", gwClassObj.Spec.Controller),
 			Reason:  "UnidentifiedController",
 		})
+		return
+	}
+
+	if lib.IsSvcApiL7Gateway(gateway) {
+		status.UpdateSvcApiL7GatewayListenerConditions(key, gateway, gwStatus)
+	} else {
+		status.UpdateSvcApiL4GatewayListenerConditions(key, gateway, gwStatus)
 	}
 }
 
@@ -321,6 +340,11 @@ func (c *AviController) SetupSvcApiEventHandlers(numWorkers uint32) {
 		},
 	)
 
+	informer.HTTPRouteInformer.Informer().AddEventHandler(c.svcApiRouteEventHandler(lib.HTTPRoute, numWorkers))
+	informer.TCPRouteInformer.Informer().AddEventHandler(c.svcApiRouteEventHandler(lib.TCPRoute, numWorkers))
+	informer.UDPRouteInformer.Informer().AddEventHandler(c.svcApiRouteEventHandler(lib.UDPRoute, numWorkers))
+	informer.TLSRouteInformer.Informer().AddEventHandler(c.svcApiRouteEventHandler(lib.TLSRoute, numWorkers))
+
 	informer.GatewayClassInformer.Informer().AddEventHandler(gatewayClassEventHandler)
 	informer.GatewayClassInformer.Informer().AddIndexers(
 		cache.Indexers{
@@ -341,3 +365,85 @@ func (c *AviController) SetupSvcApiEventHandlers(numWorkers uint32) {
 
 	return
 }
+
+// svcApiRouteEventHandler returns the event handler of the routes of the kind, which enqueues the routes with
+// the key kind/namespace/name.
+func (c *AviController) svcApiRouteEventHandler(kind string, numWorkers uint32) cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			route := obj.(metav1.Object)
+			namespace := route.GetNamespace()
+			if !utils.CheckIfNamespaceAccepted(namespace) {
+				utils.AviLog.Debugf("%s add event. Namespace %s didn't qualify filter. Not adding route.", kind, namespace)
+				return
+			}
+			key := kind + "/" + namespace + "/" + route.GetName()
+			utils.AviLog.Infof("key: %s, msg: ADD", key)
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+		},
+		UpdateFunc: func(old, new interface{}) {
+			if c.DisableSync {
+				return
+			}
+			oldObj := old.(metav1.Object)
+			route := new.(metav1.Object)
+			// The routes are selected by the gateway listeners using labels.
+			if !reflect.DeepEqual(getSvcApiRouteSpec(old), getSvcApiRouteSpec(new)) || !reflect.DeepEqual(oldObj.GetLabels(), route.GetLabels()) {
+				namespace := route.GetNamespace()
+				if !utils.CheckIfNamespaceAccepted(namespace) {
+					utils.AviLog.Debugf("%s update event. Namespace %s didn't qualify filter. Not updating route.", kind, namespace)
+					return
+				}
+				key := kind + "/" + namespace + "/" + route.GetName()
+				utils.AviLog.Infof("key: %s, msg: UPDATE", key)
+				bkt := utils.Bkt(namespace, numWorkers)
+				c.workqueue[bkt].AddRateLimited(key)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if c.DisableSync {
+				return
+			}
+			route, ok := obj.(metav1.Object)
+			if !ok {
+				tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+				if !ok {
+					utils.AviLog.Errorf("couldn't get object from tombstone %#v", obj)
+					return
+				}
+				route, ok = tombstone.Obj.(metav1.Object)
+				if !ok {
+					utils.AviLog.Errorf("Tombstone contained object that is not a %s: %#v", kind, obj)
+					return
+				}
+			}
+			namespace := route.GetNamespace()
+			if !utils.CheckIfNamespaceAccepted(namespace) {
+				utils.AviLog.Debugf("%s delete event. Namespace %s didn't qualify filter. Not deleting route.", kind, namespace)
+				return
+			}
+			key := kind + "/" + namespace + "/" + route.GetName()
+			utils.AviLog.Infof("key: %s, msg: DELETE", key)
+			bkt := utils.Bkt(namespace, numWorkers)
+			c.workqueue[bkt].AddRateLimited(key)
+		},
+	}
+}
+
+func getSvcApiRouteSpec(obj interface{}) interface{} {
+	switch route := obj.(type) {
+	case *servicesapi.HTTPRoute:
+		return route.Spec
+	case *servicesapi.TCPRoute:
+		return route.Spec
+	case *servicesapi.UDPRoute:
+		return route.Spec
+	case *servicesapi.TLSRoute:
+		return route.Spec
+	}
+	return nil
+}
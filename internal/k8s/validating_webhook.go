@@ -0,0 +1,169 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// NewValidatingWebhookModel returns the api model serving the admission reviews for the AKO CRDs and Ingresses.
+// The objects are validated with the same checks that are run by the ingestion layer, so that invalid objects
+// are denied at apply time instead of being marked Rejected later.
+func NewValidatingWebhookModel() *models.ValidatingWebhookModel {
+	return &models.ValidatingWebhookModel{
+		Validators: map[string]models.AdmissionValidator{
+			lib.HostRule:        validateHostRuleAdmission,
+			lib.HTTPRule:        validateHTTPRuleAdmission,
+			lib.AviInfraSetting: validateAviInfraSettingAdmission,
+			lib.L4Rule:          validateL4RuleAdmission,
+			utils.Ingress:       validateIngressAdmission,
+		},
+	}
+}
+
+func admissionKey(req *admissionv1beta1.AdmissionRequest) string {
+	return "admission/" + req.Kind.Kind + "/" + req.Namespace + "/" + req.Name
+}
+
+func validateHostRuleAdmission(req *admissionv1beta1.AdmissionRequest) error {
+	if req.Operation == admissionv1beta1.Delete {
+		return nil
+	}
+	hostrule := &akov1alpha1.HostRule{}
+	if err := json.Unmarshal(req.Object.Raw, hostrule); err != nil {
+		return fmt.Errorf("unable to decode HostRule: %v", err)
+	}
+	return checkHostRuleObj(admissionKey(req), hostrule)
+}
+
+func validateHTTPRuleAdmission(req *admissionv1beta1.AdmissionRequest) error {
+	if req.Operation == admissionv1beta1.Delete {
+		return nil
+	}
+	httprule := &akov1alpha1.HTTPRule{}
+	if err := json.Unmarshal(req.Object.Raw, httprule); err != nil {
+		return fmt.Errorf("unable to decode HTTPRule: %v", err)
+	}
+	return checkHTTPRuleObj(admissionKey(req), httprule)
+}
+
+func validateAviInfraSettingAdmission(req *admissionv1beta1.AdmissionRequest) error {
+	if req.Operation == admissionv1beta1.Delete {
+		return nil
+	}
+	infraSetting := &akov1alpha1.AviInfraSetting{}
+	if err := json.Unmarshal(req.Object.Raw, infraSetting); err != nil {
+		return fmt.Errorf("unable to decode AviInfraSetting: %v", err)
+	}
+	return checkAviInfraSetting(admissionKey(req), infraSetting)
+}
+
+func validateL4RuleAdmission(req *admissionv1beta1.AdmissionRequest) error {
+	if req.Operation == admissionv1beta1.Delete {
+		return nil
+	}
+	l4Rule := &akov1alpha1.L4Rule{}
+	if err := json.Unmarshal(req.Object.Raw, l4Rule); err != nil {
+		return fmt.Errorf("unable to decode L4Rule: %v", err)
+	}
+	return checkL4RuleObj(admissionKey(req), l4Rule)
+}
+
+// validateIngressAdmission denies an Ingress handled by AKO if any of its host/paths is already
+// used by another Ingress, as found in the HostNamePathStore. This is done only if
+// webhookDenyDuplicateHostPath is set, since AKO otherwise only warns about duplicate host/paths.
+func validateIngressAdmission(req *admissionv1beta1.AdmissionRequest) error {
+	if req.Operation == admissionv1beta1.Delete || !lib.IsWebhookDuplicateHostPathDenied() {
+		return nil
+	}
+	var ingress *networkingv1.Ingress
+	if req.Kind.Version == "v1beta1" {
+		ingressV1beta1 := &networkingv1beta1.Ingress{}
+		if err := json.Unmarshal(req.Object.Raw, ingressV1beta1); err != nil {
+			return fmt.Errorf("unable to decode Ingress: %v", err)
+		}
+		ingress = utils.IngressV1beta1ToV1(ingressV1beta1)
+	} else {
+		ingress = &networkingv1.Ingress{}
+		if err := json.Unmarshal(req.Object.Raw, ingress); err != nil {
+			return fmt.Errorf("unable to decode Ingress: %v", err)
+		}
+	}
+	if ingress.Namespace == "" {
+		ingress.Namespace = req.Namespace
+	}
+
+	key := admissionKey(req)
+	if !utils.CheckIfNamespaceAccepted(ingress.Namespace) || !lib.ValidateIngressForClass(key, ingress) {
+		return nil
+	}
+
+	nsIngress := ingress.Namespace + "/" + ingress.Name
+	for _, rule := range ingress.Spec.Rules {
+		if rule.IngressRuleValue.HTTP == nil {
+			continue
+		}
+		for _, svcPath := range rule.IngressRuleValue.HTTP.Paths {
+			found, ingresses := nodes.SharedHostNameLister().GetHostPathStoreIngresses(rule.Host, svcPath.Path)
+			if !found {
+				continue
+			}
+			for _, ing := range ingresses {
+				if ing != nsIngress && ingressUsesHostPath(key, ing, rule.Host, svcPath.Path) {
+					return fmt.Errorf("hostpath %s%s is already used by ingress %s", rule.Host, svcPath.Path, ing)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ingressUsesHostPath checks the HostNamePathStore entry of an Ingress against the informer cache, since
+// the store is updated only after the Ingress is processed in the graph layer, and can still hold the
+// host/paths of an Ingress that has been deleted or updated.
+func ingressUsesHostPath(key, nsIngress, host, path string) bool {
+	namespace, name, err := cache.SplitMetaNamespaceKey(nsIngress)
+	if err != nil {
+		return false
+	}
+	ingObj, err := utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: ingress %s in the hostpath store not found: %v", key, nsIngress, err)
+		return false
+	}
+	for _, rule := range ingObj.Spec.Rules {
+		if rule.Host != host || rule.IngressRuleValue.HTTP == nil {
+			continue
+		}
+		for _, svcPath := range rule.IngressRuleValue.HTTP.Paths {
+			if svcPath.Path == path {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -101,6 +101,11 @@ func AviPut(client *clients.AviClient, uri string, payload interface{}, response
 		}
 	}
 
+	if IsDryRun() {
+		utils.AviLog.Infof("msg: dry run, method: PUT, path: %s, object: %s", uri, utils.Stringify(payload))
+		return nil
+	}
+
 	err := client.AviSession.Put(uri, payload, &response)
 	if err != nil {
 		utils.AviLog.Warnf("msg: Unable to execute Put on uri %s %v", uri, err)
@@ -176,6 +176,12 @@ func GetPodCIDR(node *v1.Node) ([]string, error) {
 		}
 
 		podCIDRs = append(podCIDRs, node.Spec.PodCIDR)
+		// dual-stack nodes carry one pod CIDR per IP family.
+		for _, cidr := range node.Spec.PodCIDRs {
+			if !utils.HasElem(podCIDRs, cidr) {
+				podCIDRs = append(podCIDRs, cidr)
+			}
+		}
 	}
 
 	return podCIDRs, nil
@@ -18,6 +18,16 @@ const (
 	DISABLE_STATIC_ROUTE_SYNC = "DISABLE_STATIC_ROUTE_SYNC"
 	ENABLE_RHI                = "ENABLE_RHI"
 	ENABLE_EVH                = "ENABLE_EVH"
+	LEADER_ELECTION           = "LEADER_ELECTION"
+	AKO_DRY_RUN               = "AKO_DRY_RUN"
+	ENABLE_VALIDATING_WEBHOOK = "ENABLE_VALIDATING_WEBHOOK"
+	AKO_WEBHOOK_PORT          = "AKO_WEBHOOK_PORT"
+	WEBHOOK_DENY_DUP_HOSTPATH = "WEBHOOK_DENY_DUP_HOSTPATH"
+	AKO_WEBHOOK_CERT_DIR      = "/etc/ako/webhook"
+	ENABLE_POD_READINESS_GATE = "ENABLE_POD_READINESS_GATE"
+	SERVER_DRAIN_TIMEOUT      = "SERVER_DRAIN_TIMEOUT"
+	ZONE_SERVER_RATIO         = "ZONE_SERVER_RATIO"
+	OPER_STATUS_SYNC_INTERVAL = "OPER_STATUS_SYNC_INTERVAL"
 	CNI_PLUGIN                = "CNI_PLUGIN"
 	CALICO_CNI                = "calico"
 	ANTREA_CNI                = "antrea"
@@ -57,9 +67,30 @@ const (
 	FAST_RETRY_LAYER                           = "FastRetryLayer"
 	NOT_FOUND                                  = "HTTP code: 404"
 	STATUS_REDIRECT                            = "HTTP_REDIRECT_STATUS_CODE_302"
+	STATUS_NOT_FOUND                           = "HTTP_LOCAL_RESPONSE_STATUS_CODE_404"
 	CLOSE_CONNECTION                           = "HTTP_SECURITY_ACTION_CLOSE_CONN"
+	RATE_LIMIT                                 = "HTTP_SECURITY_ACTION_RATE_LIMIT"
+	STATUS_TOO_MANY_REQUESTS                   = "HTTP_LOCAL_RESPONSE_STATUS_CODE_429"
+	RL_ACTION_DROP_CONN                        = "RL_ACTION_DROP_CONN"
+	RL_ACTION_LOCAL_RSP                        = "RL_ACTION_LOCAL_RSP"
+	RL_ACTION_REDIRECT                         = "RL_ACTION_REDIRECT"
 	IS_IN                                      = "IS_IN"
-	SLOW_SYNC_TIME                             = 90 // seconds
+	IS_NOT_IN                                  = "IS_NOT_IN"
+	PERSISTENCE_TYPE_CLIENT_IP                 = "PERSISTENCE_TYPE_CLIENT_IP_ADDRESS"
+	HEALTH_MONITOR_HTTP                        = "HEALTH_MONITOR_HTTP"
+	SG_TYPE_STRING                             = "SG_TYPE_STRING"
+	REGEX_MATCH                                = "REGEX_MATCH"
+	SSL_CLIENT_CERTIFICATE_REQUIRE             = "SSL_CLIENT_CERTIFICATE_REQUIRE"
+	SSL_CLIENT_CERTIFICATE_REQUEST             = "SSL_CLIENT_CERTIFICATE_REQUEST"
+	SSL_CLIENT_SUBJECT                         = "HTTP_POLICY_VAR_SSL_CLIENT_SUBJECT"
+	ClientCertModeRequire                      = "Require"
+	ClientCertModeRequest                      = "Request"
+	ClientCACertKey                            = "ca.crt"
+	RateLimitActionDrop                        = "Drop"
+	RateLimitActionTooManyRequests             = "TooManyRequests"
+	RateLimitActionRedirect                    = "Redirect"
+	MaxClientIPPersistenceTimeout              = 720 // minutes
+	SLOW_SYNC_TIME                             = 90  // seconds
 	LOG_LEVEL                                  = "logLevel"
 	LAYER7_ONLY                                = "layer7Only"
 	NO_PG_FOR_SNI                              = "noPGForSNI"
@@ -87,16 +118,27 @@ const (
 	HostRule                                   = "HostRule"
 	HTTPRule                                   = "HTTPRule"
 	AviInfraSetting                            = "AviInfraSetting"
+	L4Rule                                     = "L4Rule"
+	PodReadinessGate                           = "PodReadinessGate"
+	OperStatus                                 = "OperStatus"
+	MaxGracefulDisableTimeout                  = 7200
+	MinServerRatio                             = 1
+	MaxServerRatio                             = 20
 	DummySecret                                = "@avisslkeycertrefdummy"
 	StatusRejected                             = "Rejected"
 	StatusAccepted                             = "Accepted"
 	AllowedApplicationProfile                  = "APPLICATION_PROFILE_TYPE_HTTP"
+	AllowedL4ApplicationProfile                = "APPLICATION_PROFILE_TYPE_L4"
 	TypeTLSReencrypt                           = "reencrypt"
 	DefaultPoolSSLProfile                      = "System-Standard"
 	LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER = "LB_ALGORITHM_CONSISTENT_HASH_CUSTOM_HEADER"
 	LB_ALGORITHM_CONSISTENT_HASH               = "LB_ALGORITHM_CONSISTENT_HASH"
 	Gateway                                    = "Gateway"
 	GatewayClass                               = "GatewayClass"
+	HTTPRoute                                  = "HTTPRoute"
+	TCPRoute                                   = "TCPRoute"
+	UDPRoute                                   = "UDPRoute"
+	TLSRoute                                   = "TLSRoute"
 	DuplicateBackends                          = "MultipleBackendsWithSameServiceError"
 	DummyVSForStaleData                        = "DummyVSForStaleData"
 	ControllerReqWaitTime                      = 300
@@ -106,6 +148,8 @@ const (
 	ClusterStatusCacheKey                      = "cluster-runtime"
 	AviObjDeletionTime                         = 30 // Minutes
 	AKOStatefulSet                             = "ako"
+	AKOLeaseName                               = "ako-lease"
+	AKOEventComponent                          = "avi-kubernetes-operator"
 	ObjectDeletionStartStatus                  = "Started"
 	ObjectDeletionDoneStatus                   = "Done"
 	ObjectDeletionTimeoutStatus                = "Timeout"
@@ -127,18 +171,27 @@ const (
 	HTTPRewriteRule                            = "HTTP Header Rewrite Rule"
 	HTTPRedirectPolicy                         = "HTTP Redirect Policy"
 	HeaderRewritePolicy                        = "Header Rewrite Policy"
+	ExactPathPolicy                            = "Exact Path Policy"
+	SourceRangesPolicy                         = "Source Ranges Policy"
+	HTTPRulePolicy                             = "HTTPRule Policy"
+	HTTPResponseRule                           = "HTTP Response Rule"
 	L4VS                                       = "L4 Virtual Service"
 	L4VIP                                      = "L4 VIP"
 	L4Pool                                     = "L4 Pool"
 	L4AdvPool                                  = "L4 Advance Pool"
 	L4PS                                       = "L4 Policyset"
 	L4PSRule                                   = "L4 Policyset Rule"
+	NSP                                        = "Network Security Policy"
 	SNIVS                                      = "SNI VirtualService"
 	VIP                                        = "VS VIP"
 	PG                                         = "Poolgroup"
 	PriorityLabel                              = "PriorityLabel"
 	SSLKeyCert                                 = "SSLKeyandCertificate"
 	PKIProfile                                 = "PKI Profile"
+	PersistenceProfile                         = "Application Persistence Profile"
+	HealthMonitor                              = "Health Monitor"
+	StringGroup                                = "String Group"
+	ClientCertProfile                          = "Client Certificate Application Profile"
 	PassthroughPG                              = "Passthrough PG"
 	Passthroughpool                            = "Passthrough pool"
 	PassthroughVS                              = "Passthrough VirtualService"
@@ -169,6 +222,7 @@ const (
 	NPLPodAnnotation               = "nodeportlocal.antrea.io"
 	NPLSvcAnnotation               = "nodeportlocal.antrea.io/enabled"
 	InfraSettingNameAnnotation     = "aviinfrasetting.ako.vmware.com/name"
+	L4RuleNameAnnotation           = "l4rule.ako.vmware.com/name"
 	SkipNodePortAnnotation         = "skipnodeport.ako.vmware.com/enabled"
 	PassthroughAnnotation          = "passthrough.ako.vmware.com/enabled"
 
@@ -179,6 +233,13 @@ const (
 	AVI_OBJ_NAME_MAX_LENGTH        = 255
 )
 
+// VIP address types, used as the auto_allocate_ip_type of the VsVip.
+const (
+	IPTypeV4Only = "V4_ONLY"
+	IPTypeV6Only = "V6_ONLY"
+	IPTypeV4V6   = "V4_V6"
+)
+
 // Cache Indexer constants.
 const (
 	// AviSettingGWClassIndex maintains a map of AviInfraSetting Name to
@@ -201,6 +262,11 @@ const (
 	// with a given AviInfraSetting.
 	AviSettingServicesIndex = "aviSettingServices"
 
+	// L4RuleServicesIndex maintains a map of L4Rule Namespace/Name to
+	// Service Objects. This helps in fetching all Services referring
+	// to a given L4Rule via annotation.
+	L4RuleServicesIndex = "l4RuleServices"
+
 	// AviSettingIngClassIndex maintains a map of AviInfraSetting Name to
 	// IngressClass Objects. This helps in fetching all IngressClasses with a
 	// given AviinfraSetting Name.
@@ -210,9 +276,20 @@ const (
 	// Route Objects. This helps in fetching all Routes with a
 	// given AviinfraSetting Name.
 	AviSettingRouteIndex = "aviSettingRoute"
+
+	// PodIPIndex maintains a map of Pod IP to Pod Objects. This helps in
+	// fetching the Pods backing the servers of a pool.
+	PodIPIndex = "podIP"
 )
 
-//Passthrough deployment same in EVH and SNI. Not changing log messages.
+// PodReadinessGateConditionType is the pod condition managed by AKO for the pods with a matching
+// readiness gate, set to True once the pod is added as a server to all the pools referring to it.
+const PodReadinessGateConditionType = "ako.vmware.com/pool-server-ready"
+
+// serverDrainDescriptionPrefix prefixes the drain deadline in the description of the pool servers being drained.
+const serverDrainDescriptionPrefix = "ako drain deadline: "
+
+// Passthrough deployment same in EVH and SNI. Not changing log messages.
 const (
 	PassthroughDatascript = `local avi_tls = require "Default-TLS"
 	buffered = avi.l4.collect(20)
@@ -27,6 +27,7 @@ import (
 var aviInfraSettingEnabled bool
 var hostRuleEnabled bool
 var httpRuleEnabled bool
+var l4RuleEnabled bool
 
 func SetCRDEnabledParams(cs akocrd.Interface) {
 	timeout := int64(120)
@@ -56,6 +57,15 @@ func SetCRDEnabledParams(cs akocrd.Interface) {
 		utils.AviLog.Infof("ako.vmware.com/v1alpha1/HTTPRule enabled on cluster")
 		httpRuleEnabled = true
 	}
+
+	_, l4RulesError := cs.AkoV1alpha1().L4Rules(metav1.NamespaceAll).List(context.TODO(), metav1.ListOptions{TimeoutSeconds: &timeout})
+	if l4RulesError != nil {
+		utils.AviLog.Infof("ako.vmware.com/v1alpha1/L4Rule not found/enabled on cluster: %v", l4RulesError)
+		l4RuleEnabled = false
+	} else {
+		utils.AviLog.Infof("ako.vmware.com/v1alpha1/L4Rule enabled on cluster")
+		l4RuleEnabled = true
+	}
 }
 
 func GetAviInfraSettingEnabled() bool {
@@ -70,6 +80,10 @@ func GetHttpRuleEnabled() bool {
 	return httpRuleEnabled
 }
 
+func GetL4RuleEnabled() bool {
+	return l4RuleEnabled
+}
+
 var CRDClientset akocrd.Interface
 
 func SetCRDClientset(cs akocrd.Interface) {
@@ -87,6 +101,7 @@ type AKOCrdInformers struct {
 	HostRuleInformer        akoinformer.HostRuleInformer
 	HTTPRuleInformer        akoinformer.HTTPRuleInformer
 	AviInfraSettingInformer akoinformer.AviInfraSettingInformer
+	L4RuleInformer          akoinformer.L4RuleInformer
 }
 
 func SetCRDInformers(c *AKOCrdInformers) {
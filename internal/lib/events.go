@@ -0,0 +1,85 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package lib
+
+import (
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// Reasons for the events raised by AKO on the kubernetes objects.
+const (
+	InvalidHostname   = "InvalidHostname"
+	DuplicateHostPath = "DuplicateHostPath"
+	SecretNotFound    = "SecretNotFound"
+	AviSyncFailed     = "AviSyncFailed"
+)
+
+var akoEventRecorder record.EventRecorder
+
+func SetEventRecorder(recorder record.EventRecorder) {
+	akoEventRecorder = recorder
+}
+
+func GetEventRecorder() record.EventRecorder {
+	return akoEventRecorder
+}
+
+// AKOEventf raises an event on the kubernetes object identified by the object type used in the ingestion
+// layer keys (Ingress, OshiftRoute, Service or L4LBService), namespace and name. The object is looked up
+// in the informer cache, and no event is raised if the object does not exist anymore, if this AKO
+// replica is not the leader, or in the dry run mode.
+func AKOEventf(objType, namespace, name, eventType, reason, messageFmt string, args ...interface{}) {
+	if akoEventRecorder == nil || !AKOIsLeader() || IsDryRun() {
+		return
+	}
+
+	ref := &corev1.ObjectReference{Namespace: namespace, Name: name}
+	var obj metav1.Object
+	var err error
+	informers := utils.GetInformers()
+	switch objType {
+	case utils.Ingress:
+		if informers.IngressInformer == nil {
+			return
+		}
+		obj, err = informers.IngressInformer.Lister().Ingresses(namespace).Get(name)
+		ref.Kind, ref.APIVersion = "Ingress", utils.GetIngressAPIVersion()
+	case utils.OshiftRoute:
+		if informers.RouteInformer == nil {
+			return
+		}
+		obj, err = informers.RouteInformer.Lister().Routes(namespace).Get(name)
+		ref.Kind, ref.APIVersion = "Route", "route.openshift.io/v1"
+	case utils.Service, utils.L4LBService:
+		if informers.ServiceInformer == nil {
+			return
+		}
+		obj, err = informers.ServiceInformer.Lister().Services(namespace).Get(name)
+		ref.Kind, ref.APIVersion = "Service", "v1"
+	default:
+		utils.AviLog.Debugf("events are not supported for object type %s", objType)
+		return
+	}
+	if err != nil {
+		return
+	}
+	ref.UID, ref.ResourceVersion = obj.GetUID(), obj.GetResourceVersion()
+
+	akoEventRecorder.Eventf(ref, eventType, reason, messageFmt, args...)
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package lib
+
+import (
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// IsIPv6VipNetwork returns true if any of the VIP networks has an IPv6 CIDR configured.
+func IsIPv6VipNetwork(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork) bool {
+	for _, vipNetwork := range vipNetworks {
+		if vipNetwork.V6Cidr != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// GetServiceIPFamilies returns the IP families of the Service, as set in spec.ipFamilies. A SingleStack Service
+// only gets its primary family. On clusters without dual-stack support, where spec.ipFamilies is not populated,
+// the family is derived from the clusterIP of the Service.
+func GetServiceIPFamilies(svc *v1.Service) []v1.IPFamily {
+	if len(svc.Spec.IPFamilies) > 0 {
+		if svc.Spec.IPFamilyPolicy != nil && *svc.Spec.IPFamilyPolicy == v1.IPFamilyPolicySingleStack {
+			return svc.Spec.IPFamilies[:1]
+		}
+		return svc.Spec.IPFamilies
+	}
+	if svc.Spec.ClusterIP != "" && svc.Spec.ClusterIP != v1.ClusterIPNone && !utils.IsV4(svc.Spec.ClusterIP) {
+		return []v1.IPFamily{v1.IPv6Protocol}
+	}
+	return []v1.IPFamily{v1.IPv4Protocol}
+}
@@ -27,6 +27,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
 	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
@@ -37,7 +39,7 @@ import (
 	oshiftclient "github.com/openshift/client-go/route/clientset/versioned"
 	"github.com/vmware/alb-sdk/go/models"
 	v1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes"
@@ -231,6 +233,48 @@ func GetAdvL4PoolName(svcName, namespace, gwName string, port int32) string {
 	return Encode(poolName, L4AdvPool)
 }
 
+// Gateway L7 object names. The objects of the routes are prefixed with the name of the virtualservice,
+// the parent or the SNI child, that they are attached to.
+func GetSvcApiSniNodeName(gwName, namespace, hostname string, port int32) string {
+	sniName := NamePrefix + namespace + "-" + gwName
+	if hostname != "" {
+		sniName += "-" + hostname
+	}
+	return Encode(sniName+"--"+strconv.Itoa(int(port)), SNIVS)
+}
+
+func GetSvcApiL7PGName(vsName, routeNamespace, routeName string, ruleIndex int) string {
+	pgName := vsName + "-" + routeNamespace + "-" + routeName + "-" + strconv.Itoa(ruleIndex)
+	return Encode(pgName, PG)
+}
+
+func GetSvcApiL7PoolName(vsName, routeNamespace, routeName string, ruleIndex int, svcName string, port int32) string {
+	poolName := vsName + "-" + routeNamespace + "-" + routeName + "-" + strconv.Itoa(ruleIndex) + "-" + svcName + "--" + strconv.Itoa(int(port))
+	return Encode(poolName, Pool)
+}
+
+func GetSvcApiL4RoutePGName(vsName, protocol string, port int32) string {
+	pgName := vsName + "-" + protocol + "-" + strconv.Itoa(int(port))
+	return Encode(pgName, PG)
+}
+
+func GetSvcApiL4RoutePoolName(vsName, routeKind, routeNamespace, routeName string, ruleIndex int, svcName string, port int32) string {
+	poolName := vsName + "-" + strings.ToLower(routeKind) + "-" + routeNamespace + "-" + routeName + "-" + strconv.Itoa(ruleIndex) + "-" + svcName + "--" + strconv.Itoa(int(port))
+	return Encode(poolName, Pool)
+}
+
+// GetSvcApiPassthroughPGName returns the name of the poolgroup of a SNI of the TLSRoutes of a gateway. The name is
+// not encoded, since the datascript of the gateway selects the poolgroup by appending the SNI to the prefix.
+func GetSvcApiPassthroughPGName(vsName, sni string) string {
+	pgName := GetSvcApiPassthroughPGPrefix(vsName) + sni
+	CheckObjectNameLength(pgName, PassthroughPG)
+	return pgName
+}
+
+func GetSvcApiPassthroughPGPrefix(vsName string) string {
+	return vsName + "--"
+}
+
 // All L7 object names.
 func GetVsVipName(vsName string) string {
 	vsVipName := vsName
@@ -277,6 +321,32 @@ func GetHeaderRewritePolicy(vsName, localHost string) string {
 	return headerWriterPolicy
 }
 
+func GetSourceRangesPolicy(vsName string) string {
+	sourceRangesPolicy := vsName + "--source-ranges"
+	CheckObjectNameLength(sourceRangesPolicy, SourceRangesPolicy)
+	return sourceRangesPolicy
+}
+
+func GetHTTPRulePolicy(vsName string) string {
+	httpRulePolicy := vsName + "--httprule"
+	CheckObjectNameLength(httpRulePolicy, HTTPRulePolicy)
+	return httpRulePolicy
+}
+
+// GetHTTPRuleStringGroupName returns the name of the stringgroup holding the path regex of the HTTPRule target,
+// which is used by the rate limiting security rule of the target in the httprule policy.
+func GetHTTPRuleStringGroupName(httpRulePolicy, target string) string {
+	stringGroupName := httpRulePolicy + "-" + strconv.FormatUint(uint64(utils.Hash(target)), 16)
+	CheckObjectNameLength(stringGroupName, StringGroup)
+	return stringGroupName
+}
+
+func GetL7ExactPathPolicy(poolName string) string {
+	exactPathPolicy := poolName + "--exact-path"
+	CheckObjectNameLength(exactPathPolicy, ExactPathPolicy)
+	return exactPathPolicy
+}
+
 func GetSniNodeName(infrasetting, sniHostName string) string {
 	namePrefix := NamePrefix
 	if infrasetting != "" {
@@ -453,6 +523,67 @@ func GetLabelToSyncNamespace() (string, string) {
 	return "", ""
 }
 
+// IsLeaderElectionEnabled returns true if AKO is deployed with more than one replica,
+// in which case only the elected leader pushes configuration to the Avi controller.
+func IsLeaderElectionEnabled() bool {
+	if ok, _ := strconv.ParseBool(os.Getenv(LEADER_ELECTION)); ok {
+		return true
+	}
+	return false
+}
+
+var akoIsLeader = true
+var leaderLock sync.RWMutex
+
+func SetAKOIsLeader(flag bool) {
+	leaderLock.Lock()
+	defer leaderLock.Unlock()
+	akoIsLeader = flag
+	utils.AviLog.Infof("Setting AKO leader status to: %v", flag)
+}
+
+// AKOIsLeader returns false for standby replicas, which keep their informers, models and
+// avi object cache up to date, but do not perform any rest operations or status updates.
+func AKOIsLeader() bool {
+	leaderLock.RLock()
+	defer leaderLock.RUnlock()
+	return akoIsLeader
+}
+
+var configLock sync.RWMutex
+
+// LockConfig blocks the graph and rest layer syncs, while the settings edited in the configmap are applied,
+// so that no model is built or synced with a mix of the old and new settings.
+func LockConfig() {
+	configLock.Lock()
+}
+
+func UnlockConfig() {
+	configLock.Unlock()
+}
+
+// RLockConfig is held by the graph and rest layer syncs, for the duration of a key.
+func RLockConfig() {
+	configLock.RLock()
+}
+
+func RUnlockConfig() {
+	configLock.RUnlock()
+}
+
+var dryRun bool
+
+func SetDryRun(flag bool) {
+	dryRun = flag
+	utils.AviLog.Infof("Setting AKO dry run mode to: %v", flag)
+}
+
+// IsDryRun returns true if AKO is running in the dry run mode, in which the rest operations are
+// recorded and logged instead of being sent to the Avi controller.
+func IsDryRun() bool {
+	return dryRun
+}
+
 // The port to run the AKO API server on
 func GetAkoApiServerPort() string {
 	port := os.Getenv("AKO_API_PORT")
@@ -463,6 +594,42 @@ func GetAkoApiServerPort() string {
 	return "8080"
 }
 
+// IsValidatingWebhookEnabled returns true if AKO should serve the admission reviews of the
+// ValidatingWebhookConfiguration for the AKO CRDs and Ingresses.
+func IsValidatingWebhookEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(ENABLE_VALIDATING_WEBHOOK))
+	return enabled
+}
+
+// IsWebhookDuplicateHostPathDenied returns true if the validating webhook should deny an Ingress using
+// a host/path of another Ingress, instead of only raising the DuplicateHostPath event while processing it.
+func IsWebhookDuplicateHostPathDenied() bool {
+	deny, _ := strconv.ParseBool(os.Getenv(WEBHOOK_DENY_DUP_HOSTPATH))
+	return deny
+}
+
+// The port to serve the validating webhook on
+func GetAkoWebhookPort() string {
+	port := os.Getenv(AKO_WEBHOOK_PORT)
+	if port != "" {
+		return port
+	}
+	// Default case, if not specified.
+	return "8443"
+}
+
+// GetHostRuleFqdnType returns the fqdnType of the HostRule. The fqdns starting with *. are matched as
+// wildcards when the fqdnType is not set, and the other fqdns are matched exactly.
+func GetHostRuleFqdnType(hostrule *akov1alpha1.HostRule) akov1alpha1.FqdnType {
+	if hostrule.Spec.VirtualHost.FqdnType != "" {
+		return hostrule.Spec.VirtualHost.FqdnType
+	}
+	if strings.HasPrefix(hostrule.Spec.VirtualHost.Fqdn, "*.") {
+		return akov1alpha1.FqdnTypeWildcard
+	}
+	return akov1alpha1.FqdnTypeExact
+}
+
 var VipNetworkList []akov1alpha1.AviInfraSettingVipNetwork
 
 func SetVipNetworkList(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork) {
@@ -925,6 +1092,63 @@ func L4PolicyChecksum(ports []int64, protocol string, ingestionMarkers utils.Avi
 	return checksum
 }
 
+func PersistenceProfileChecksum(timeout int32, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(PERSISTENCE_TYPE_CLIENT_IP + strconv.Itoa(int(timeout)))
+	if GetGRBACSupport() {
+		if populateCache {
+			if markers != nil {
+				checksum += ObjectLabelChecksum(markers)
+			}
+			return checksum
+		}
+		checksum += GetMarkersChecksum(ingestionMarkers)
+	}
+	return checksum
+}
+
+func StringGroupChecksum(values []string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	sort.Strings(values)
+	checksum := utils.Hash(SG_TYPE_STRING + utils.Stringify(values))
+	if GetGRBACSupport() {
+		if populateCache {
+			if markers != nil {
+				checksum += ObjectLabelChecksum(markers)
+			}
+			return checksum
+		}
+		checksum += GetMarkersChecksum(ingestionMarkers)
+	}
+	return checksum
+}
+
+func ClientCertProfileChecksum(mode, dnHeader string, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(AllowedApplicationProfile + mode + dnHeader)
+	if GetGRBACSupport() {
+		if populateCache {
+			if markers != nil {
+				checksum += ObjectLabelChecksum(markers)
+			}
+			return checksum
+		}
+		checksum += GetMarkersChecksum(ingestionMarkers)
+	}
+	return checksum
+}
+
+func HealthMonitorChecksum(monitorPort int32, ingestionMarkers utils.AviObjectMarkers, markers []*models.RoleFilterMatchLabel, populateCache bool) uint32 {
+	checksum := utils.Hash(HEALTH_MONITOR_HTTP + strconv.Itoa(int(monitorPort)))
+	if GetGRBACSupport() {
+		if populateCache {
+			if markers != nil {
+				checksum += ObjectLabelChecksum(markers)
+			}
+			return checksum
+		}
+		checksum += GetMarkersChecksum(ingestionMarkers)
+	}
+	return checksum
+}
+
 func IsNodePortMode() bool {
 	nodePortType := os.Getenv(SERVICE_TYPE)
 	if nodePortType == NODE_PORT {
@@ -948,6 +1172,127 @@ func AutoAnnotateNPLSvc() bool {
 	return false
 }
 
+// IsPodReadinessGateEnabled returns true if AKO manages the pool-server-ready readiness gate of the pods.
+// Pods are added as pool servers only in ClusterIP mode, hence the readiness gate is not managed otherwise.
+func IsPodReadinessGateEnabled() bool {
+	enabled, _ := strconv.ParseBool(os.Getenv(ENABLE_POD_READINESS_GATE))
+	return enabled && !IsNodePortMode() && GetServiceType() != NodePortLocal
+}
+
+// HasPodReadinessGate returns true if the pod has the readiness gate managed by AKO.
+func HasPodReadinessGate(pod *v1.Pod) bool {
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == PodReadinessGateConditionType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPodWaitingForReadinessGate returns true if all the containers of the pod are ready, but the
+// pod is not Ready as the readiness gate managed by AKO is not yet True.
+func IsPodWaitingForReadinessGate(pod *v1.Pod) bool {
+	if !HasPodReadinessGate(pod) {
+		return false
+	}
+	var containersReady bool
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == PodReadinessGateConditionType && condition.Status == v1.ConditionTrue {
+			return false
+		}
+		if condition.Type == v1.ContainersReady && condition.Status == v1.ConditionTrue {
+			containersReady = true
+		}
+	}
+	return containersReady
+}
+
+// GetServerDrainTimeout returns the duration for which a server removed from the endpoints of a Service
+// is kept disabled in the pool before being deleted. Servers are deleted immediately if it is not set.
+// The pools gracefully disable their servers for whole minutes, so the timeout is rounded up to minutes,
+// and capped at the maximum graceful disable timeout, for the servers to be deleted when Avi stops draining them.
+func GetServerDrainTimeout() time.Duration {
+	timeoutStr := os.Getenv(SERVER_DRAIN_TIMEOUT)
+	if timeoutStr == "" {
+		return 0
+	}
+	timeout, err := strconv.Atoi(timeoutStr)
+	if err != nil || timeout < 0 {
+		utils.AviLog.Warnf("Invalid value %s for %s, servers would not be drained", timeoutStr, SERVER_DRAIN_TIMEOUT)
+		return 0
+	}
+	minutes := (timeout + 59) / 60
+	if minutes > MaxGracefulDisableTimeout {
+		minutes = MaxGracefulDisableTimeout
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// GetServerDrainDescription returns the description of a pool server being drained, which records the
+// deadline of the drain on the controller, so that the drain survives AKO restarts.
+func GetServerDrainDescription(deadline time.Time) string {
+	return serverDrainDescriptionPrefix + deadline.UTC().Format(time.RFC3339)
+}
+
+// GetServerDrainDeadline returns the drain deadline recorded in the description of a pool server.
+func GetServerDrainDeadline(description string) (time.Time, bool) {
+	if !strings.HasPrefix(description, serverDrainDescriptionPrefix) {
+		return time.Time{}, false
+	}
+	deadline, err := time.Parse(time.RFC3339, strings.TrimPrefix(description, serverDrainDescriptionPrefix))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return deadline, true
+}
+
+// GetOperStatusSyncInterval returns the interval at which the oper status of the virtualservices and pools
+// is fetched from the Avi controller and published on the kubernetes objects. The sync is disabled if it is not set.
+func GetOperStatusSyncInterval() time.Duration {
+	intervalStr := os.Getenv(OPER_STATUS_SYNC_INTERVAL)
+	if intervalStr == "" {
+		return 0
+	}
+	interval, err := strconv.Atoi(intervalStr)
+	if err != nil || interval < 0 {
+		utils.AviLog.Warnf("Invalid value %s for %s, oper status would not be synced", intervalStr, OPER_STATUS_SYNC_INTERVAL)
+		return 0
+	}
+	return time.Duration(interval) * time.Second
+}
+
+// GetZoneServerRatios returns the ratio of the pool servers for each zone, as set in the topology.kubernetes.io/zone
+// label of the nodes of the servers. The ratios out of the 1-20 range supported by Avi are ignored.
+func GetZoneServerRatios() map[string]int32 {
+	zoneRatios := make(map[string]int32)
+	zoneRatiosStr := os.Getenv(ZONE_SERVER_RATIO)
+	if zoneRatiosStr == "" || zoneRatiosStr == "null" {
+		return zoneRatios
+	}
+	var zoneRatiosObj map[string]int32
+	if err := json.Unmarshal([]byte(zoneRatiosStr), &zoneRatiosObj); err != nil {
+		utils.AviLog.Warnf("Unable to unmarshall json for %s: %v", ZONE_SERVER_RATIO, err)
+		return zoneRatios
+	}
+	for zone, ratio := range zoneRatiosObj {
+		if ratio < MinServerRatio || ratio > MaxServerRatio {
+			utils.AviLog.Warnf("Invalid ratio %d for zone %s, ratio should be in the range %d-%d", ratio, zone, MinServerRatio, MaxServerRatio)
+			continue
+		}
+		zoneRatios[zone] = ratio
+	}
+	return zoneRatios
+}
+
+// GetNodeZone returns the zone of the node from its topology.kubernetes.io/zone label, or the deprecated
+// failure-domain.beta.kubernetes.io/zone label.
+func GetNodeZone(node *v1.Node) string {
+	if zone, ok := node.Labels[v1.LabelZoneFailureDomainStable]; ok {
+		return zone
+	}
+	return node.Labels[v1.LabelZoneFailureDomain]
+}
+
 func GetNodePortsSelector() map[string]string {
 	nodePortsSelectorLabels := make(map[string]string)
 	if IsNodePortMode() {
@@ -1097,7 +1442,7 @@ func GetDefaultSecretForRoutes() string {
 	return DefaultRouteCert
 }
 
-func ValidateIngressForClass(key string, ingress *networkingv1beta1.Ingress) bool {
+func ValidateIngressForClass(key string, ingress *networkingv1.Ingress) bool {
 	// see whether ingress class resources are present or not
 	if !utils.GetIngressClassEnabled() {
 		return filterIngressOnClassAnnotation(key, ingress)
@@ -1116,10 +1461,10 @@ func ValidateIngressForClass(key string, ingress *networkingv1beta1.Ingress) boo
 
 	// If the key is "syncstatus" then use a clientset, else using the lister cache. This is because
 	// the status sync happens before the informers are run and caches are synced.
-	var ingClassObj *networkingv1beta1.IngressClass
+	var ingClassObj *networkingv1.IngressClass
 	var err error
 	if key == SyncStatusKey {
-		ingClassObj, err = utils.GetInformers().ClientSet.NetworkingV1beta1().IngressClasses().Get(context.TODO(), *ingress.Spec.IngressClassName, metav1.GetOptions{})
+		ingClassObj, err = utils.GetIngressClass(utils.GetInformers().ClientSet, *ingress.Spec.IngressClassName)
 	} else {
 		ingClassObj, err = utils.GetInformers().IngressClassInformer.Lister().Get(*ingress.Spec.IngressClassName)
 	}
@@ -1139,7 +1484,7 @@ func ValidateIngressForClass(key string, ingress *networkingv1beta1.Ingress) boo
 	return true
 }
 
-func filterIngressOnClassAnnotation(key string, ingress *networkingv1beta1.Ingress) bool {
+func filterIngressOnClassAnnotation(key string, ingress *networkingv1.Ingress) bool {
 	// If Avi is not the default ingress, then filter on ingress class.
 	if !GetDefaultIngController() {
 		annotations := ingress.GetAnnotations()
@@ -1180,7 +1525,11 @@ func IsAviLBDefaultIngressClass() (string, bool) {
 }
 
 func IsAviLBDefaultIngressClassWithClient(kc kubernetes.Interface) (string, bool) {
-	ingClassObjs, _ := kc.NetworkingV1beta1().IngressClasses().List(context.TODO(), metav1.ListOptions{})
+	ingClassObjs, err := utils.ListIngressClasses(kc, metav1.ListOptions{})
+	if err != nil {
+		utils.AviLog.Warnf("Unable to list the IngressClasses: %v", err)
+		return "", false
+	}
 	for _, ingClass := range ingClassObjs.Items {
 		if ingClass.Spec.Controller == AviIngressController {
 			annotations := ingClass.GetAnnotations()
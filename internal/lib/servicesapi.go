@@ -41,6 +41,10 @@ func GetServicesAPIClientset() svcapi.Interface {
 type ServicesAPIInformers struct {
 	GatewayInformer      svcInformer.GatewayInformer
 	GatewayClassInformer svcInformer.GatewayClassInformer
+	HTTPRouteInformer    svcInformer.HTTPRouteInformer
+	TCPRouteInformer     svcInformer.TCPRouteInformer
+	UDPRouteInformer     svcInformer.UDPRouteInformer
+	TLSRouteInformer     svcInformer.TLSRouteInformer
 }
 
 func SetSvcAPIsInformers(c *ServicesAPIInformers) {
@@ -80,3 +84,62 @@ func UpdateSvcApiGatewayFinalizer(gw *svcapiv1alpha1.Gateway) {
 
 	utils.AviLog.Infof("Successfully patched the gateway with finalizers: %v", gw.GetFinalizers())
 }
+
+// IsSvcApiRouteListener returns true if the listener selects routes, instead of selecting
+// the LoadBalancer Services using the gateway name and namespace labels.
+func IsSvcApiRouteListener(listener svcapiv1alpha1.Listener) bool {
+	if listener.Routes.Group != "" && listener.Routes.Group != svcapiv1alpha1.GroupName {
+		return false
+	}
+	switch listener.Routes.Kind {
+	case HTTPRoute, TCPRoute, UDPRoute, TLSRoute:
+		return true
+	}
+	return false
+}
+
+// IsSvcApiL7Gateway returns true if any listener of the gateway selects HTTPRoutes, in which case
+// the gateway is realised as a L7 virtualservice.
+func IsSvcApiL7Gateway(gw *svcapiv1alpha1.Gateway) bool {
+	for _, listener := range gw.Spec.Listeners {
+		if IsSvcApiRouteListener(listener) && listener.Routes.Kind == HTTPRoute {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSvcApiL4RouteListener returns true for the listeners of TCPRoutes with the TCP protocol, of UDPRoutes with the
+// UDP protocol, and of TLSRoutes with the TLS protocol in Passthrough mode.
+func IsSvcApiL4RouteListener(listener svcapiv1alpha1.Listener) bool {
+	if !IsSvcApiRouteListener(listener) {
+		return false
+	}
+	switch listener.Routes.Kind {
+	case TCPRoute:
+		return listener.Protocol == svcapiv1alpha1.TCPProtocolType
+	case UDPRoute:
+		return listener.Protocol == svcapiv1alpha1.UDPProtocolType
+	case TLSRoute:
+		return listener.Protocol == svcapiv1alpha1.TLSProtocolType &&
+			listener.TLS != nil && listener.TLS.Mode == svcapiv1alpha1.TLSModePassthrough
+	}
+	return false
+}
+
+// IsSvcApiPassthroughGateway returns true if any listener of the gateway selects TLSRoutes in Passthrough mode.
+// The connections to the virtualservice of such a gateway are routed using the SNI, so the other listeners of the
+// gateway are not realised.
+func IsSvcApiPassthroughGateway(gw *svcapiv1alpha1.Gateway) bool {
+	for _, listener := range gw.Spec.Listeners {
+		if listener.Routes.Kind == TLSRoute && IsSvcApiL4RouteListener(listener) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSvcApiSecretRef returns true if the certificateRef of a listener refers to a Secret.
+func IsSvcApiSecretRef(ref svcapiv1alpha1.LocalObjectReference) bool {
+	return ref.Name != "" && ref.Kind == "Secret" && (ref.Group == "" || ref.Group == "core")
+}
@@ -44,6 +44,21 @@ func ExtractTypeNameNamespace(key string) (string, string, string) {
 	return "", "", segments[0]
 }
 
+// IngestionLogger returns the logger for a key of the ingestion layer, of the format objectType/namespace/name.
+func IngestionLogger(key string) *utils.AviLogger {
+	objType, namespace, name := ExtractTypeNameNamespace(key)
+	return utils.AviLog.With(utils.LogKey, key, utils.LogLayer, utils.ObjectIngestionLayer,
+		utils.LogObjectKind, objType, utils.LogNamespace, namespace, utils.LogName, name)
+}
+
+// ModelLogger returns the logger for a model name of the format tenant/vsName, which is the key processed by
+// the rest layer. The layer is set to the layer logging the model, the graph or the rest layer.
+func ModelLogger(key, layer string) *utils.AviLogger {
+	tenant, vsName := utils.ExtractNamespaceObjectName(key)
+	return utils.AviLog.With(utils.LogKey, key, utils.LogLayer, layer,
+		utils.LogTenant, tenant, utils.LogVirtualService, vsName)
+}
+
 func isServiceLBType(svcObj *corev1.Service) bool {
 	// If we don't find a service or it is not of type loadbalancer - return false.
 	if svcObj.Spec.Type == "LoadBalancer" {
@@ -40,6 +40,11 @@ func (o *AviObjectGraph) BuildAdvancedL4Graph(namespace string, gatewayName stri
 	}
 	if vsNode != nil {
 		o.ConstructAdvL4PolPoolNodes(vsNode, gatewayName, namespace, key)
+		if lib.UseServicesAPI() {
+			if gw, err := lib.GetSvcAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(gatewayName); err == nil {
+				o.ConstructSvcApiL4RouteNodes(vsNode, gw, key)
+			}
+		}
 		o.AddModelNode(vsNode)
 		utils.AviLog.Infof("key: %s, msg: checksum  for AVI VS object %v", key, vsNode.GetCheckSum())
 	}
@@ -139,10 +144,12 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 
 		var serviceNSNames []string
 		listenerSvcMapping := make(map[string][]string)
+		routeListeners := getSvcApiRouteListenerKeys(gw)
 		if found, services := objects.ServiceGWLister().GetGwToSvcs(namespace + "/" + gatewayName); found {
 			for svcListener, service := range services {
 				// assume it to have only a single backend service, the check is in isGatewayDelete
-				if utils.HasElem(listeners, svcListener) && len(service) == 1 && !utils.HasElem(serviceNSNames, service[0]) {
+				if utils.HasElem(listeners, svcListener) && !utils.HasElem(routeListeners, svcListener) &&
+					len(service) == 1 && !utils.HasElem(serviceNSNames, service[0]) {
 					serviceNSNames = append(serviceNSNames, service[0])
 					if val, ok := listenerSvcMapping[svcListener]; ok {
 						listenerSvcMapping[svcListener] = append(val, service[0])
@@ -155,6 +162,10 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 
 		var fqdns []string
 		for _, listener := range gw.Spec.Listeners {
+			if listener.Routes.Kind == lib.TLSRoute && lib.IsSvcApiRouteListener(listener) {
+				// The SNIs of the TLSRoutes are the FQDNs of the TLS listeners.
+				continue
+			}
 			autoFQDN := true
 			// Honour the hostname if specified corresponding to the listener.
 			if listener.Hostname != nil && string(*listener.Hostname) != "" {
@@ -191,9 +202,14 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 		for _, listener := range listeners {
 			portProto := strings.Split(listener, "/") // format: protocol/port
 			port, _ := utilsnet.ParsePort(portProto[1], true)
-			pp := AviPortHostProtocol{Port: int32(port), Protocol: portProto[0]}
+			protocol := portProto[0]
+			if protocol == string(svcapiv1alpha1.TLSProtocolType) {
+				// The TLS listeners of the TLSRoutes pass the TLS connections through.
+				protocol = utils.TCP
+			}
+			pp := AviPortHostProtocol{Port: int32(port), Protocol: protocol}
 			portProtocols = append(portProtocols, pp)
-			if portProto[0] == "" || portProto[0] == utils.TCP {
+			if protocol == "" || protocol == utils.TCP {
 				isTCP = true
 			} else if portProto[0] == utils.UDP {
 				isUDP = true
@@ -208,7 +224,10 @@ func (o *AviObjectGraph) ConstructSvcApiL4VsNode(gatewayName, namespace, key str
 		// and override required services with UDP Fast Path. Having a separate
 		// internally used network profile (MIXED_NET_PROFILE) helps ensure PUT calls
 		// on existing VSes.
-		if isTCP && !isUDP {
+		if lib.IsSvcApiPassthroughGateway(gw) {
+			// The datascript selecting the poolgroups of the SNIs requires the TCP proxy.
+			avi_vs_meta.NetworkProfile = utils.DEFAULT_TCP_NW_PROFILE
+		} else if isTCP && !isUDP {
 			avi_vs_meta.NetworkProfile = utils.TCP_NW_FAST_PATH
 		} else if isUDP && !isTCP {
 			avi_vs_meta.NetworkProfile = utils.SYSTEM_UDP_FAST_PATH
@@ -252,9 +271,12 @@ func (o *AviObjectGraph) ConstructAdvL4PolPoolNodes(vsNode *AviVsNode, gwName, n
 
 	// create a mapping of portProto to hostname
 	gwListenerHostNameMapping := make(map[string]string)
+	var routeListeners []string
 	if lib.UseServicesAPI() {
 		// enable fqdn for gateway services only for non-advancedl4 usecases.
 		gw, _ := lib.GetSvcAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(gwName)
+		// the listeners of the routes do not select the services using the gateway labels.
+		routeListeners = getSvcApiRouteListenerKeys(gw)
 		for _, gwlistener := range gw.Spec.Listeners {
 			if gwlistener.Hostname != nil && string(*gwlistener.Hostname) != "" {
 				gwListenerHostNameMapping[fmt.Sprintf("%s/%d", gwlistener.Protocol, gwlistener.Port)] = string(*gwlistener.Hostname)
@@ -264,7 +286,7 @@ func (o *AviObjectGraph) ConstructAdvL4PolPoolNodes(vsNode *AviVsNode, gwName, n
 
 	var portPoolSet []AviHostPathPortPoolPG
 	for listener, svc := range svcListeners {
-		if !utils.HasElem(gwListeners, listener) || len(svc) != 1 {
+		if !utils.HasElem(gwListeners, listener) || utils.HasElem(routeListeners, listener) || len(svc) != 1 {
 			continue
 		}
 		portProto := strings.Split(listener, "/") // format: protocol/port
@@ -30,7 +30,6 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	avimodels "github.com/vmware/alb-sdk/go/models"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -81,6 +80,11 @@ type AviVsEvhSniModel interface {
 
 	GetEnabled() *bool
 	SetEnabled(*bool)
+
+	GetClientCertProfile() *AviClientCertProfileNode
+	SetClientCertProfile(*AviClientCertProfileNode)
+
+	GetAviMarkers() utils.AviObjectMarkers
 }
 
 type AviEvhVsNode struct {
@@ -122,6 +126,7 @@ type AviEvhVsNode struct {
 	VsDatascriptRefs    []string
 	SSLProfileRef       string
 	SSLKeyCertAviRef    string
+	ClientCertProfile   *AviClientCertProfileNode
 }
 
 // Implementing AviVsEvhSniModel
@@ -246,6 +251,18 @@ func (v *AviEvhVsNode) SetEnabled(Enabled *bool) {
 	v.Enabled = Enabled
 }
 
+func (v *AviEvhVsNode) GetClientCertProfile() *AviClientCertProfileNode {
+	return v.ClientCertProfile
+}
+
+func (v *AviEvhVsNode) SetClientCertProfile(clientCertProfile *AviClientCertProfileNode) {
+	v.ClientCertProfile = clientCertProfile
+}
+
+func (v *AviEvhVsNode) GetAviMarkers() utils.AviObjectMarkers {
+	return v.AviMarkers
+}
+
 func (o *AviObjectGraph) GetAviEvhVS() []*AviEvhVsNode {
 	var aviVs []*AviEvhVsNode
 	for _, model := range o.modelNodes {
@@ -474,6 +491,10 @@ func (v *AviEvhVsNode) CalculateCheckSum() {
 		checksum += evhnode.GetCheckSum()
 	}
 
+	if v.ClientCertProfile != nil {
+		checksum += v.ClientCertProfile.GetCheckSum()
+	}
+
 	if vsRefs != "" {
 		checksum += utils.Hash(vsRefs)
 	}
@@ -598,6 +619,10 @@ func (o *AviObjectGraph) ConstructAviL7SharedVsNodeForEvh(vsName, key string, ro
 		buildWithInfraSettingForEvh(key, avi_vs_meta, vsVipNode, infraSetting)
 	}
 
+	if lib.IsIPv6VipNetwork(vsVipNode.VipNetworks) {
+		setVsVipIPType(vsVipNode, getVipNetworkIPFamilies(vsVipNode.VipNetworks))
+	}
+
 	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
 	return avi_vs_meta
 }
@@ -619,18 +644,6 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 
 		httpPGPath := AviHostPathPortPoolPG{Host: allFqdns}
 
-		if path.PathType == networkingv1beta1.PathTypeExact {
-			httpPGPath.MatchCriteria = "EQUALS"
-		} else {
-			// PathTypePrefix and PathTypeImplementationSpecific
-			// default behaviour for AKO set be Prefix match on the path
-			httpPGPath.MatchCriteria = "BEGINS_WITH"
-		}
-
-		if path.Path != "" {
-			httpPGPath.Path = append(httpPGPath.Path, path.Path)
-		}
-
 		pgName := lib.GetEvhPGName(ingName, namespace, hosts[0], path.Path, infraSettingName)
 		var pgNode *AviPoolGroupNode
 		// There can be multiple services for the same path in case of alternate backend.
@@ -641,7 +654,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForEVH(vsNode []*AviEvhVsNode, childN
 			localPGList[pgName] = pgNode
 			httpPGPath.PoolGroup = pgNode.Name
 			httpPGPath.Host = allFqdns
-			httpPolicySet = append(httpPolicySet, httpPGPath)
+			httpPolicySet = append(httpPolicySet, getHTTPPathMatches(httpPGPath, path.Path, path.PathType)...)
 		}
 		pgNode.AviMarkers = lib.PopulatePGNodeMarkers(namespace, hosts[0], ingName, path.Path, infraSettingName)
 		var poolName string
@@ -828,6 +841,7 @@ func (o *AviObjectGraph) BuildModelGraphForInsecureEVH(routeIgrObj RouteIngressM
 	}
 	// build host rule for insecure ingress in evh
 	BuildL7HostRule(host, namespace, ingName, key, evhNode)
+	BuildL7HTTPRulePolicy(host, key, evhNode)
 	manipulateEvhNodeForSSL(vsNode[0], evhNode)
 }
 
@@ -895,6 +909,7 @@ func (o *AviObjectGraph) BuildTlsCertNodeForEvh(svcLister *objects.SvcLister, tl
 				}
 			}
 			utils.AviLog.Infof("key: %s, msg: secret: %s has been deleted, err: %s", key, secretName, err)
+			secretNotFoundEvent(svcLister, ingNames, secretNS, secretName)
 			return false
 		}
 		keycertMap := secretObj.Data
@@ -1100,6 +1115,7 @@ func (o *AviObjectGraph) BuildModelGraphForSecureEVH(routeIgrObj RouteIngressMod
 		}
 		// Enable host rule
 		BuildL7HostRule(host, namespace, ingName, key, evhNode)
+		BuildL7HTTPRulePolicy(host, key, evhNode)
 		manipulateEvhNodeForSSL(vsNode[0], evhNode)
 
 	} else {
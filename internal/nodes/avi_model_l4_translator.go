@@ -16,9 +16,12 @@ package nodes
 
 import (
 	"fmt"
+	"net"
+	"reflect"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -30,6 +33,9 @@ import (
 	"github.com/vmware/alb-sdk/go/models"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
 )
 
 func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string) *AviVsNode {
@@ -89,6 +95,11 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		avi_vs_meta.NetworkProfile = utils.TCP_NW_FAST_PATH
 	}
 
+	// overrides the default profiles of the VS using the L4Rule referred by the service (via annotation).
+	if l4Rule, err := getL4Rule(key, svcObj); err == nil && l4Rule != nil {
+		buildWithL4Rule(key, avi_vs_meta, l4Rule)
+	}
+
 	vsVipName := lib.GetL4VSVipName(svcObj.ObjectMeta.Name, svcObj.ObjectMeta.Namespace)
 	vsVipNode := &AviVSVIPNode{
 		Name:        vsVipName,
@@ -110,17 +121,85 @@ func (o *AviObjectGraph) ConstructAviL4VsNode(svcObj *corev1.Service, key string
 		buildWithInfraSetting(key, avi_vs_meta, vsVipNode, infraSetting)
 	}
 
+	if lib.IsIPv6VipNetwork(vsVipNode.VipNetworks) {
+		setVsVipIPType(vsVipNode, lib.GetServiceIPFamilies(svcObj))
+	}
+
 	if svcObj.Spec.LoadBalancerIP != "" {
-		vsVipNode.IPAddress = svcObj.Spec.LoadBalancerIP
+		if utils.IsV4(svcObj.Spec.LoadBalancerIP) {
+			vsVipNode.IPAddress = svcObj.Spec.LoadBalancerIP
+		} else {
+			vsVipNode.IPv6Address = svcObj.Spec.LoadBalancerIP
+		}
 	}
 
 	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
+
+	if sourceRanges := getValidSourceRanges(key, svcObj.Spec.LoadBalancerSourceRanges); len(sourceRanges) > 0 {
+		nspNode := &AviNetworkSecurityPolicyNode{
+			Name:         vsName,
+			Tenant:       lib.GetTenant(),
+			SourceRanges: sourceRanges,
+			AviMarkers:   lib.PopulateL4VSNodeMarkers(svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name),
+		}
+		avi_vs_meta.NSPolicyRefs = append(avi_vs_meta.NSPolicyRefs, nspNode)
+		utils.AviLog.Infof("key: %s, msg: evaluated L4 network security policy :%v", key, utils.Stringify(nspNode))
+	}
 	return avi_vs_meta
 }
 
+// getValidSourceRanges returns the source ranges that are valid CIDRs, invalid ranges are skipped.
+func getValidSourceRanges(key string, sourceRanges []string) []string {
+	var validRanges []string
+	for _, sourceRange := range sourceRanges {
+		sourceRange = strings.TrimSpace(sourceRange)
+		if _, _, err := net.ParseCIDR(sourceRange); err != nil {
+			utils.AviLog.Warnf("key: %s, msg: skipping invalid source range %s: %v", key, sourceRange, err)
+			continue
+		}
+		validRanges = append(validRanges, sourceRange)
+	}
+	return validRanges
+}
+
+// setVsVipIPType sets the address type to be allocated for the VsVip, based on the requested IP families.
+// A VsVip without an IP type is allocated a v4 address.
+func setVsVipIPType(vsVipNode *AviVSVIPNode, ipFamilies []corev1.IPFamily) {
+	var v4, v6 bool
+	for _, ipFamily := range ipFamilies {
+		switch ipFamily {
+		case corev1.IPv4Protocol:
+			v4 = true
+		case corev1.IPv6Protocol:
+			v6 = true
+		}
+	}
+	if v4 && v6 {
+		vsVipNode.IPType = lib.IPTypeV4V6
+	} else if v6 {
+		vsVipNode.IPType = lib.IPTypeV6Only
+	}
+}
+
+// getVipNetworkIPFamilies returns the IP families served by the VIP networks. Networks without an IPv6 CIDR
+// are treated as v4 networks.
+func getVipNetworkIPFamilies(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork) []corev1.IPFamily {
+	var ipFamilies []corev1.IPFamily
+	for _, vipNetwork := range vipNetworks {
+		if vipNetwork.Cidr != "" || vipNetwork.V6Cidr == "" {
+			ipFamilies = append(ipFamilies, corev1.IPv4Protocol)
+		}
+		if vipNetwork.V6Cidr != "" {
+			ipFamilies = append(ipFamilies, corev1.IPv6Protocol)
+		}
+	}
+	return ipFamilies
+}
+
 func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNode *AviVsNode, key string) {
 	var l4Policies []*AviL4PolicyNode
 	var portPoolSet []AviHostPathPortPoolPG
+	l4Rule, _ := getL4Rule(key, svcObj)
 	for _, portProto := range vsNode.PortProto {
 		filterPort := portProto.Port
 		poolNode := &AviPoolNode{
@@ -135,6 +214,7 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 			// Unset the poolnode's vrfcontext.
 			poolNode.VrfContext = ""
 		}
+		poolNode.AviMarkers = lib.PopulateL4PoolNodeMarkers(svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, strconv.Itoa(int(filterPort)))
 		serviceType := lib.GetServiceType()
 		if serviceType == lib.NodePortLocal {
 			if svcObj.Spec.Type == "NodePort" {
@@ -153,12 +233,31 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 			if servers := PopulateServersForNodePort(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key); servers != nil {
 				poolNode.Servers = servers
 			}
+			if svcObj.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal && svcObj.Spec.HealthCheckNodePort != 0 {
+				// kube-proxy serves the local endpoint health of the service on the healthCheckNodePort of each node.
+				poolNode.HealthMonitorNode = &AviHealthMonitorNode{
+					Name:        poolNode.Name,
+					Tenant:      lib.GetTenant(),
+					MonitorPort: svcObj.Spec.HealthCheckNodePort,
+					AviMarkers:  poolNode.AviMarkers,
+				}
+			}
 		} else {
 			if servers := PopulateServers(poolNode, svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, false, key); servers != nil {
 				poolNode.Servers = servers
 			}
 		}
-		poolNode.AviMarkers = lib.PopulateL4PoolNodeMarkers(svcObj.ObjectMeta.Namespace, svcObj.ObjectMeta.Name, strconv.Itoa(int(filterPort)))
+		if l4Rule != nil {
+			buildPoolWithL4Rule(key, poolNode, l4Rule)
+		}
+		if svcObj.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+			poolNode.PersistenceProfile = &AviPersistenceProfileNode{
+				Name:       poolNode.Name,
+				Tenant:     lib.GetTenant(),
+				Timeout:    getClientIPPersistenceTimeout(svcObj),
+				AviMarkers: poolNode.AviMarkers,
+			}
+		}
 		pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
 		portPool := AviHostPathPortPoolPG{Port: uint32(filterPort), Pool: pool_ref, Protocol: portProto.Protocol}
 		portPoolSet = append(portPoolSet, portPool)
@@ -174,6 +273,58 @@ func (o *AviObjectGraph) ConstructAviL4PolPoolNodes(svcObj *corev1.Service, vsNo
 
 }
 
+// getClientIPPersistenceTimeout converts the sessionAffinityConfig timeout of the service, in seconds, to the
+// client ip persistence timeout of Avi, which is configured in minutes within the range of 1-720.
+func getClientIPPersistenceTimeout(svcObj *corev1.Service) int32 {
+	timeoutSeconds := int32(corev1.DefaultClientIPServiceAffinitySeconds)
+	if svcObj.Spec.SessionAffinityConfig != nil &&
+		svcObj.Spec.SessionAffinityConfig.ClientIP != nil &&
+		svcObj.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds != nil {
+		timeoutSeconds = *svcObj.Spec.SessionAffinityConfig.ClientIP.TimeoutSeconds
+	}
+	timeout := (timeoutSeconds + 59) / 60
+	if timeout < 1 {
+		timeout = 1
+	} else if timeout > lib.MaxClientIPPersistenceTimeout {
+		timeout = lib.MaxClientIPPersistenceTimeout
+	}
+	return timeout
+}
+
+// getNodesWithReadyEndpoints returns the names of the nodes which run ready endpoints of the service.
+func getNodesWithReadyEndpoints(ns, serviceName, key string) map[string]bool {
+	nodeNames := make(map[string]bool)
+	if utils.GetEndpointSliceEnabled() {
+		slices, err := getEndpointSlices(ns, serviceName)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error while retrieving endpointslices: %s", key, err)
+			return nodeNames
+		}
+		for _, slice := range slices {
+			for _, endpoint := range slice.Endpoints {
+				if nodeName := utils.GetEndpointNodeName(endpoint); nodeName != "" && utils.IsEndpointReady(endpoint.Conditions) {
+					nodeNames[nodeName] = true
+				}
+			}
+		}
+		return nodeNames
+	}
+
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
+		return nodeNames
+	}
+	for _, ss := range epObj.Subsets {
+		for _, addr := range ss.Addresses {
+			if addr.NodeName != nil {
+				nodeNames[*addr.NodeName] = true
+			}
+		}
+	}
+	return nodeNames
+}
+
 func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string, ingress bool, key string) []AviPoolMetaServer {
 	if ingress {
 		found, _ := objects.SharedClusterIpLister().Get(ns + "/" + serviceName)
@@ -204,6 +355,7 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 		targetPorts[port.TargetPort.IntValue()] = true
 	}
 
+	zoneRatios := lib.GetZoneServerRatios()
 	for _, pod := range pods {
 		var annotations []lib.NPLAnnotation
 		found, obj := objects.SharedNPLLister().Get(ns + "/" + pod.Name)
@@ -211,6 +363,12 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 			continue
 		}
 		annotations = obj.([]lib.NPLAnnotation)
+		var ratio int32
+		if len(zoneRatios) > 0 {
+			if podObj, err := utils.GetInformers().PodInformer.Lister().Pods(ns).Get(pod.Name); err == nil {
+				ratio = getServerRatioForNode(podObj.Spec.NodeName, zoneRatios, key)
+			}
+		}
 		for _, a := range annotations {
 			var atype string
 			if utils.IsV4(a.NodeIP) {
@@ -225,6 +383,7 @@ func PopulateServersForNPL(poolNode *AviPoolNode, ns string, serviceName string,
 						Addr: &a.NodeIP,
 						Type: &atype,
 					}}
+				server.Ratio = ratio
 				poolMeta = append(poolMeta, server)
 			}
 		}
@@ -256,6 +415,12 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 		utils.AviLog.Debugf("key: %s, msg: ClusterIP is not processed in NodePort: %s", key, serviceName)
 		return poolMeta
 	}
+	// With the Local external traffic policy, the traffic is dropped on nodes which do not run endpoints of the service.
+	var localEndpointNodes map[string]bool
+	if svcObj.Spec.ExternalTrafficPolicy == corev1.ServiceExternalTrafficPolicyTypeLocal {
+		localEndpointNodes = getNodesWithReadyEndpoints(ns, serviceName, key)
+	}
+	zoneRatios := lib.GetZoneServerRatios()
 	for _, port := range svcObj.Spec.Ports {
 		if port.Name != poolNode.PortName && len(svcObj.Spec.Ports) != 1 {
 			// continue only if port name does not match and its multiport svcobj
@@ -280,6 +445,10 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 				}
 
 			}
+			if localEndpointNodes != nil && !localEndpointNodes[node.Name] {
+				utils.AviLog.Debugf("key: %s, msg: skipping node %s without local endpoints for service: %s", key, node.Name, serviceName)
+				continue
+			}
 			addresses := node.Status.Addresses
 			ip := ""
 			var atype string
@@ -300,6 +469,9 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 
 			a := avimodels.IPAddr{Type: &atype, Addr: &ip}
 			server := AviPoolMetaServer{Ip: a}
+			if len(zoneRatios) > 0 {
+				server.Ratio = zoneRatios[lib.GetNodeZone(node)]
+			}
 			poolMeta = append(poolMeta, server)
 		}
 	}
@@ -307,6 +479,72 @@ func PopulateServersForNodePort(poolNode *AviPoolNode, ns string, serviceName st
 	return poolMeta
 }
 
+// getServerRatioForNode returns the ratio configured for the zone of the node, the ratio is not set if the
+// node is not known, or if no ratio is configured for its zone.
+func getServerRatioForNode(nodeName string, zoneRatios map[string]int32, key string) int32 {
+	nodeInformer := utils.GetInformers().NodeInformer
+	if nodeName == "" || nodeInformer == nil {
+		return 0
+	}
+	node, err := nodeInformer.Lister().Get(nodeName)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: error in getting node %s: %v", key, nodeName, err)
+		return 0
+	}
+	return zoneRatios[lib.GetNodeZone(node)]
+}
+
+// getEndpointSlices returns the EndpointSlices of the Service, sorted by name so that the order of the servers
+// does not change with the order of the informer cache.
+func getEndpointSlices(ns, serviceName string) ([]*discoveryv1beta1.EndpointSlice, error) {
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1beta1.LabelServiceName: serviceName})
+	slices, err := utils.GetInformers().EpSliceInformer.Lister().EndpointSlices(ns).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	if len(slices) == 0 {
+		return nil, errors.NewNotFound(discoveryv1beta1.Resource("endpointslices"), serviceName)
+	}
+	sort.Slice(slices, func(i, j int) bool {
+		return slices[i].Name < slices[j].Name
+	})
+	return slices, nil
+}
+
+// isWaitingForReadinessGate returns true if the endpoint is a pod which is not ready only because of the
+// readiness gate managed by AKO. These pods are added as pool servers, after which the readiness gate is set to True.
+func isWaitingForReadinessGate(targetRef *corev1.ObjectReference, key string) bool {
+	if targetRef == nil || targetRef.Kind != utils.Pod {
+		return false
+	}
+	pod, err := utils.GetInformers().PodInformer.Lister().Pods(targetRef.Namespace).Get(targetRef.Name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: error in getting pod %s/%s: %v", key, targetRef.Namespace, targetRef.Name, err)
+		return false
+	}
+	return lib.IsPodWaitingForReadinessGate(pod)
+}
+
+// getAddressesWaitingForReadinessGate returns the not ready endpoint addresses of the pods which are waiting
+// for the readiness gate managed by AKO.
+func getAddressesWaitingForReadinessGate(notReadyAddresses []corev1.EndpointAddress, key string) []corev1.EndpointAddress {
+	var addresses []corev1.EndpointAddress
+	for _, addr := range notReadyAddresses {
+		if isWaitingForReadinessGate(addr.TargetRef, key) {
+			addresses = append(addresses, addr)
+		}
+	}
+	return addresses
+}
+
+func newPoolMetaServer(ip, nodeName string) AviPoolMetaServer {
+	atype := "V4"
+	if !utils.IsV4(ip) {
+		atype = "V6"
+	}
+	return AviPoolMetaServer{Ip: avimodels.IPAddr{Type: &atype, Addr: &ip}, ServerNode: nodeName}
+}
+
 func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingress bool, key string) []AviPoolMetaServer {
 	// Find the servers that match the port.
 	if ingress {
@@ -317,11 +555,30 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			return nil
 		}
 	}
-	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+	var pool_meta []AviPoolMetaServer
+	var err error
+	if utils.GetEndpointSliceEnabled() {
+		pool_meta, err = populateServersFromEndpointSlices(poolNode, ns, serviceName, key)
+	} else {
+		pool_meta, err = populateServersFromEndpoints(poolNode, ns, serviceName, key)
+	}
 	if err != nil {
 		utils.AviLog.Warnf("key: %s, msg: error while retrieving endpoints: %s", key, err)
 		return nil
 	}
+	if drainTimeout := lib.GetServerDrainTimeout(); drainTimeout > 0 {
+		pool_meta = addDrainingServers(poolNode, pool_meta, ns, serviceName, drainTimeout, key)
+	}
+	utils.AviLog.Infof("key: %s, msg: servers for port: %v, are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
+	return pool_meta
+}
+
+// populateServersFromEndpoints builds the pool servers from the ready addresses of the Endpoints of the Service.
+func populateServersFromEndpoints(poolNode *AviPoolNode, ns, serviceName, key string) ([]AviPoolMetaServer, error) {
+	epObj, err := utils.GetInformers().EpInformer.Lister().Endpoints(ns).Get(serviceName)
+	if err != nil {
+		return nil, err
+	}
 	var pool_meta []AviPoolMetaServer
 	for _, ss := range epObj.Subsets {
 		port_match := false
@@ -338,27 +595,175 @@ func PopulateServers(poolNode *AviPoolNode, ns string, serviceName string, ingre
 			poolNode.Port = ss.Ports[0].Port
 		}
 		if port_match {
-			var atype string
 			utils.AviLog.Infof("key: %s, msg: found port match for port %v", key, poolNode.Port)
-			for _, addr := range ss.Addresses {
+			addresses := ss.Addresses
+			if lib.IsPodReadinessGateEnabled() {
+				addresses = append(append([]corev1.EndpointAddress{}, ss.Addresses...), getAddressesWaitingForReadinessGate(ss.NotReadyAddresses, key)...)
+			}
+			for _, addr := range addresses {
+				var nodeName string
+				if addr.NodeName != nil {
+					nodeName = *addr.NodeName
+				}
+				pool_meta = append(pool_meta, newPoolMetaServer(addr.IP, nodeName))
+			}
+		}
+	}
+	if zoneRatios := lib.GetZoneServerRatios(); len(zoneRatios) > 0 {
+		for i := range pool_meta {
+			pool_meta[i].Ratio = getServerRatioForNode(pool_meta[i].ServerNode, zoneRatios, key)
+		}
+	}
+	return pool_meta, nil
+}
 
-				ip := addr.IP
-				if utils.IsV4(addr.IP) {
-					atype = "V4"
-				} else {
-					atype = "V6"
+// populateServersFromEndpointSlices builds the pool servers from the conditions of the endpoints in the
+// EndpointSlices of the Service. The ready endpoints are added as servers. The endpoints which are terminating,
+// but still serving, are added as disabled servers so that their existing connections are not cut. The zone
+// ratio of a server is taken from the zone topology of its endpoint, and else from its node.
+func populateServersFromEndpointSlices(poolNode *AviPoolNode, ns, serviceName, key string) ([]AviPoolMetaServer, error) {
+	slices, err := getEndpointSlices(ns, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	zoneRatios := lib.GetZoneServerRatios()
+	singlePort := hasSingleEndpointPort(slices)
+	var pool_meta []AviPoolMetaServer
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1beta1.AddressTypeFQDN {
+			continue
+		}
+		port_match := false
+		for _, epp := range slice.Ports {
+			if epp.Port == nil {
+				continue
+			}
+			if (epp.Name != nil && poolNode.PortName == *epp.Name) || poolNode.TargetPort == *epp.Port || singlePort {
+				port_match = true
+				poolNode.Port = *epp.Port
+				break
+			}
+		}
+		if !port_match {
+			continue
+		}
+		utils.AviLog.Infof("key: %s, msg: found port match for port %v", key, poolNode.Port)
+		for _, endpoint := range slice.Endpoints {
+			if len(endpoint.Addresses) == 0 {
+				continue
+			}
+			var disabled bool
+			if !utils.IsEndpointReady(endpoint.Conditions) {
+				if utils.IsEndpointServingTerminating(endpoint.Conditions) {
+					disabled = true
+				} else if !lib.IsPodReadinessGateEnabled() || !isWaitingForReadinessGate(endpoint.TargetRef, key) {
+					continue
 				}
-				a := avimodels.IPAddr{Type: &atype, Addr: &ip}
-				server := AviPoolMetaServer{Ip: a}
-				if addr.NodeName != nil {
-					server.ServerNode = *addr.NodeName
+			}
+			// All the addresses of an endpoint are fungible, the first one is used.
+			server := newPoolMetaServer(endpoint.Addresses[0], utils.GetEndpointNodeName(endpoint))
+			server.Disabled = disabled
+			if len(zoneRatios) > 0 {
+				if zone := utils.GetEndpointZone(endpoint); zone != "" {
+					server.Ratio = zoneRatios[zone]
+				} else {
+					server.Ratio = getServerRatioForNode(server.ServerNode, zoneRatios, key)
 				}
-				pool_meta = append(pool_meta, server)
 			}
+			pool_meta = append(pool_meta, server)
 		}
 	}
-	utils.AviLog.Infof("key: %s, msg: servers for port: %v, are: %v", key, poolNode.Port, utils.Stringify(pool_meta))
-	return pool_meta
+	return pool_meta, nil
+}
+
+// hasSingleEndpointPort returns true if all the EndpointSlices carry the same single port, which is then used as
+// the server port.
+func hasSingleEndpointPort(slices []*discoveryv1beta1.EndpointSlice) bool {
+	var port *discoveryv1beta1.EndpointPort
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1beta1.AddressTypeFQDN {
+			continue
+		}
+		if len(slice.Ports) != 1 {
+			return false
+		}
+		if port == nil {
+			port = &slice.Ports[0]
+			continue
+		}
+		if !reflect.DeepEqual(*port, slice.Ports[0]) {
+			return false
+		}
+	}
+	return port != nil
+}
+
+// addDrainingServers adds the servers removed from the endpoints of the Service as disabled servers, until the
+// drain timeout expires, so that the existing connections to them are not cut by the removal from the pool.
+// The drain deadlines are kept on the pool servers in the controller and read back from the pool cache, so the
+// drains survive full syncs and AKO restarts. A terminating endpoint, which is disabled while still serving,
+// starts its drain then, and keeps the same deadline once it is removed from the endpoints. The Endpoints of the
+// Service are requeued at the earliest deadline, to delete the drained servers.
+func addDrainingServers(poolNode *AviPoolNode, servers []AviPoolMetaServer, ns, serviceName string, drainTimeout time.Duration, key string) []AviPoolMetaServer {
+	poolKey := avicache.NamespaceName{Namespace: poolNode.Tenant, Name: poolNode.Name}
+	cachedServers := avicache.SharedAviObjCache().GetPoolServers(poolKey)
+	now := time.Now()
+	// The deadline is kept in whole seconds, as it is recorded on the controller, and rounded up so that the
+	// server is not deleted before the graceful disable timeout of the pool.
+	newDeadline := now.Add(drainTimeout + time.Second).Truncate(time.Second).UTC()
+	var nextDeadline time.Time
+	drainUntil := func(deadline time.Time) *time.Time {
+		if deadline.After(now) && (nextDeadline.IsZero() || deadline.Before(nextDeadline)) {
+			nextDeadline = deadline
+		}
+		return &deadline
+	}
+
+	activeServers := make(map[string]bool, len(servers))
+	for i := range servers {
+		ip := *servers[i].Ip.Addr
+		activeServers[ip] = true
+		if !servers[i].Disabled {
+			continue
+		}
+		deadline := newDeadline
+		if cached, ok := cachedServers[ip]; ok && !cached.DrainDeadline.IsZero() {
+			deadline = cached.DrainDeadline
+		}
+		servers[i].DrainDeadline = drainUntil(deadline)
+	}
+
+	var drainingIPs []string
+	for ip := range cachedServers {
+		if !activeServers[ip] {
+			drainingIPs = append(drainingIPs, ip)
+		}
+	}
+	sort.Strings(drainingIPs)
+	for _, ip := range drainingIPs {
+		cached := cachedServers[ip]
+		deadline := cached.DrainDeadline
+		if deadline.IsZero() {
+			deadline = newDeadline
+		}
+		if !deadline.After(now) {
+			utils.AviLog.Infof("key: %s, msg: drain timeout expired for server %s of pool %s", key, ip, poolNode.Name)
+			continue
+		}
+		server := newPoolMetaServer(ip, cached.ServerNode)
+		server.Disabled = true
+		server.DrainDeadline = drainUntil(deadline)
+		servers = append(servers, server)
+		utils.AviLog.Debugf("key: %s, msg: draining server %s of pool %s until %v", key, ip, poolNode.Name, deadline)
+	}
+
+	if !nextDeadline.IsZero() {
+		epKey := utils.Endpoints + "/" + ns + "/" + serviceName
+		sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
+		bkt := utils.Bkt(ns, sharedQueue.NumWorkers)
+		sharedQueue.Workqueue[bkt].AddAfter(epKey, nextDeadline.Sub(now))
+	}
+	return servers
 }
 
 func (o *AviObjectGraph) BuildL4LBGraph(namespace string, svcName string, key string) {
@@ -458,3 +863,59 @@ func getL4InfraSetting(key string, svc *corev1.Service, advl4GWClassName *string
 
 	return infraSetting, nil
 }
+
+func getL4Rule(key string, svc *corev1.Service) (*akov1alpha1.L4Rule, error) {
+	l4RuleName, ok := svc.GetAnnotations()[lib.L4RuleNameAnnotation]
+	if !ok || l4RuleName == "" {
+		return nil, nil
+	}
+
+	l4Rule, err := lib.GetCRDInformers().L4RuleInformer.Lister().L4Rules(svc.Namespace).Get(l4RuleName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: Unable to get corresponding L4Rule via annotation %s", key, err.Error())
+		return nil, err
+	}
+
+	if l4Rule.Status.Status != lib.StatusAccepted {
+		utils.AviLog.Warnf("key: %s, msg: Referred L4Rule %s/%s is invalid", key, l4Rule.Namespace, l4Rule.Name)
+		return nil, fmt.Errorf("Referred L4Rule %s/%s is invalid", l4Rule.Namespace, l4Rule.Name)
+	}
+
+	return l4Rule, nil
+}
+
+// buildWithL4Rule replaces the default application, network and analytics profiles of the L4 VS
+// with the ones provided in the L4Rule.
+func buildWithL4Rule(key string, vs *AviVsNode, l4Rule *akov1alpha1.L4Rule) {
+	if l4Rule.Spec.ApplicationProfile != "" {
+		vs.ApplicationProfile = l4Rule.Spec.ApplicationProfile
+	}
+	if l4Rule.Spec.NetworkProfile != "" {
+		vs.NetworkProfile = l4Rule.Spec.NetworkProfile
+	}
+	if l4Rule.Spec.AnalyticsProfile != "" {
+		vs.AnalyticsProfileRef = fmt.Sprintf("/api/analyticsprofile?name=%s", l4Rule.Spec.AnalyticsProfile)
+	}
+	utils.AviLog.Debugf("key: %s, msg: Applied L4Rule %s/%s configuration over VSNode %s", key, l4Rule.Namespace, l4Rule.Name, vs.Name)
+}
+
+// buildPoolWithL4Rule sets the load balancer policy and health monitors provided in the L4Rule on the L4 pool.
+func buildPoolWithL4Rule(key string, pool *AviPoolNode, l4Rule *akov1alpha1.L4Rule) {
+	pool.LbAlgorithm = l4Rule.Spec.LoadBalancerPolicy.Algorithm
+	if pool.LbAlgorithm == lib.LB_ALGORITHM_CONSISTENT_HASH {
+		pool.LbAlgorithmHash = l4Rule.Spec.LoadBalancerPolicy.Hash
+	}
+
+	var poolHMs []string
+	for _, hm := range l4Rule.Spec.HealthMonitors {
+		if !utils.HasElem(poolHMs, fmt.Sprintf("/api/healthmonitor?name=%s", hm)) {
+			poolHMs = append(poolHMs, fmt.Sprintf("/api/healthmonitor?name=%s", hm))
+		}
+	}
+	if len(poolHMs) > 0 {
+		pool.HealthMonitors = poolHMs
+		// the health monitors of the L4Rule take precedence over the one created for externalTrafficPolicy Local.
+		pool.HealthMonitorNode = nil
+	}
+	utils.AviLog.Debugf("key: %s, msg: Applied L4Rule %s/%s configuration over PoolNode %s", key, l4Rule.Namespace, l4Rule.Name, pool.Name)
+}
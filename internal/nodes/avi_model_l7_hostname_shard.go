@@ -96,6 +96,7 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 				PortName:      obj.PortName,
 				Tenant:        lib.GetTenant(),
 				PriorityLabel: priorityLabel,
+				PathType:      obj.PathType,
 				Port:          obj.Port,
 				TargetPort:    obj.TargetPort,
 				ServiceMetadata: avicache.ServiceMetadataObj{
@@ -133,6 +134,7 @@ func (o *AviObjectGraph) BuildL7VSGraphHostNameShard(vsName, hostname string, ro
 		}
 
 	}
+	o.BuildExactPathPolicies(vsNode[0], hostname, key)
 	for _, obj := range pathsvc {
 		BuildPoolHTTPRule(hostname, obj.Path, ingName, namespace, key, vsNode[0], false)
 	}
@@ -177,6 +179,7 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 					}
 					if poolName == pool.Name {
 						o.RemovePoolNodeRefs(poolName)
+						RemoveExactPathHTTPPolicyInModel(vsNode[0], poolName, key)
 					}
 				}
 			}
@@ -195,6 +198,7 @@ func (o *AviObjectGraph) DeletePoolForHostname(vsName, hostname string, routeIgr
 		// Remove the httpredirect policy if any
 		if len(vsNode) > 0 {
 			RemoveHeaderRewriteHTTPPolicyInModel(vsNode[0], hostname, key)
+			o.BuildExactPathPolicies(vsNode[0], hostname, key)
 		}
 	} else {
 		// Remove the ingress from the hostmap
@@ -418,6 +422,7 @@ func (o *AviObjectGraph) BuildModelGraphForSNI(routeIgrObj RouteIngressModel, in
 			o.BuildPolicyRedirectForVS(vsNode, sniHosts, key)
 		}
 		BuildL7HostRule(sniHost, namespace, ingName, key, sniNode)
+		BuildL7HTTPRulePolicy(sniHost, key, sniNode)
 	} else {
 		hostMapOk, ingressHostMap := SharedHostNameLister().Get(sniHost)
 		if hostMapOk {
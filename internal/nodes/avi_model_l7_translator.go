@@ -17,6 +17,7 @@ package nodes
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
@@ -26,7 +27,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	avimodels "github.com/vmware/alb-sdk/go/models"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -134,6 +136,10 @@ func (o *AviObjectGraph) ConstructAviL7VsNode(vsName string, key string, routeIg
 		buildWithInfraSetting(key, avi_vs_meta, vsVipNode, infraSetting)
 	}
 
+	if lib.IsIPv6VipNetwork(vsVipNode.VipNetworks) {
+		setVsVipIPType(vsVipNode, getVipNetworkIPFamilies(vsVipNode.VipNetworks))
+	}
+
 	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
 	return avi_vs_meta
 }
@@ -184,6 +190,19 @@ func (o *AviObjectGraph) BuildCACertNode(tlsNode *AviVsNode, cacert, infraSettin
 	return cacertNode.Name
 }
 
+// secretNotFoundEvent raises an event on the ingresses or routes which refer to a secret that does not exist.
+func secretNotFoundEvent(svcLister *objects.SvcLister, ingNSNames []string, secretNS, secretName string) {
+	objType := utils.Ingress
+	if svcLister == objects.OshiftRouteSvcLister() {
+		objType = utils.OshiftRoute
+	}
+	for _, ingNSName := range ingNSNames {
+		ingNS, ingName := utils.ExtractNamespaceObjectName(ingNSName)
+		lib.AKOEventf(objType, ingNS, ingName, corev1.EventTypeWarning, lib.SecretNotFound,
+			"Secret %s/%s not found, TLS configuration is skipped", secretNS, secretName)
+	}
+}
+
 func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode *AviVsNode, namespace string, tlsData TlsSettings, key, infraSettingName, sniHost string) bool {
 	mClient := utils.GetInformers().ClientSet
 	secretName := tlsData.SecretName
@@ -229,6 +248,7 @@ func (o *AviObjectGraph) BuildTlsCertNode(svcLister *objects.SvcLister, tlsNode
 				}
 			}
 			utils.AviLog.Infof("key: %s, msg: secret: %s has been deleted, err: %s", key, secretName, err)
+			secretNotFoundEvent(svcLister, ingNames, secretNS, secretName)
 			return false
 		}
 		keycertMap := secretObj.Data
@@ -287,18 +307,6 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 
 			httpPGPath := AviHostPathPortPoolPG{Host: pathFQDNs}
 
-			if path.PathType == networkingv1beta1.PathTypeExact {
-				httpPGPath.MatchCriteria = "EQUALS"
-			} else {
-				// PathTypePrefix and PathTypeImplementationSpecific
-				// default behaviour for AKO set be Prefix match on the path
-				httpPGPath.MatchCriteria = "BEGINS_WITH"
-			}
-
-			if path.Path != "" {
-				httpPGPath.Path = append(httpPGPath.Path, path.Path)
-			}
-
 			var poolName string
 			var pgfound bool
 			var pgNode *AviPoolGroupNode
@@ -329,7 +337,7 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 				httpPGPath.PoolGroup = pgNode.Name
 				pgNode.AviMarkers = lib.PopulatePGNodeMarkers(namespace, host, ingName, path.Path, infraSettingName)
 			}
-			httpPolicySet = append(httpPolicySet, httpPGPath)
+			httpPolicySet = append(httpPolicySet, getHTTPPathMatches(httpPGPath, path.Path, path.PathType)...)
 			hostSlice := []string{host}
 			poolNode := &AviPoolNode{
 				Name:       poolName,
@@ -397,6 +405,41 @@ func (o *AviObjectGraph) BuildPolicyPGPoolsForSNI(vsNode []*AviVsNode, tlsNode *
 
 }
 
+// getHTTPPathMatches returns the http policy rules for an ingress path, as per the pathType of the path.
+// Exact paths are matched as is. Prefix paths are matched element wise, so /foo matches /foo and /foo/bar
+// but not /foobar, which needs an EQUALS and a BEGINS_WITH rule. ImplementationSpecific paths keep the
+// default AKO behaviour of a prefix match on the path.
+func getHTTPPathMatches(httpPGPath AviHostPathPortPoolPG, path string, pathType networkingv1.PathType) []AviHostPathPortPoolPG {
+	if path == "" {
+		httpPGPath.MatchCriteria = "BEGINS_WITH"
+		return []AviHostPathPortPoolPG{httpPGPath}
+	}
+
+	switch pathType {
+	case networkingv1.PathTypeExact:
+		httpPGPath.MatchCriteria = "EQUALS"
+		httpPGPath.Path = []string{path}
+	case networkingv1.PathTypePrefix:
+		// The trailing slash is ignored for Prefix paths, /foo/ matches /foo as well.
+		prefix := strings.TrimSuffix(path, "/")
+		if prefix == "" {
+			httpPGPath.MatchCriteria = "BEGINS_WITH"
+			httpPGPath.Path = []string{"/"}
+			return []AviHostPathPortPoolPG{httpPGPath}
+		}
+		exactPath := httpPGPath
+		exactPath.MatchCriteria = "EQUALS"
+		exactPath.Path = []string{prefix}
+		httpPGPath.MatchCriteria = "BEGINS_WITH"
+		httpPGPath.Path = []string{prefix + "/"}
+		return []AviHostPathPortPoolPG{exactPath, httpPGPath}
+	default:
+		httpPGPath.MatchCriteria = "BEGINS_WITH"
+		httpPGPath.Path = []string{path}
+	}
+	return []AviHostPathPortPoolPG{httpPGPath}
+}
+
 func (o *AviObjectGraph) BuildPoolSecurity(poolNode *AviPoolNode, tlsData TlsSettings, key string, aviMarkers utils.AviObjectMarkers) {
 	poolNode.SniEnabled = true
 	poolNode.SslProfileRef = fmt.Sprintf("/api/sslprofile?name=%s", lib.DefaultPoolSSLProfile)
@@ -458,6 +501,96 @@ func (o *AviObjectGraph) BuildHeaderRewrite(vsNode []*AviVsNode, gslbHost, local
 
 }
 
+// BuildExactPathPolicies enforces the Exact pathType on the shard VS, where the datascript selects the pool using the
+// first element of the request path. Requests for the sub paths of an Exact path are served a local 404 response,
+// unless another path of the host claims them, in which case they are switched to the pool of that path first.
+func (o *AviObjectGraph) BuildExactPathPolicies(vsNode *AviVsNode, hostname, key string) {
+	var hostPools []*AviPoolNode
+	for _, pool := range vsNode.PoolRefs {
+		if strings.HasPrefix(pool.PriorityLabel, hostname+"/") {
+			hostPools = append(hostPools, pool)
+		}
+	}
+
+	for _, pool := range hostPools {
+		path := strings.TrimPrefix(pool.PriorityLabel, hostname)
+		prefix := strings.TrimSuffix(path, "/")
+		if pool.PathType != networkingv1.PathTypeExact || prefix == "" {
+			RemoveExactPathHTTPPolicyInModel(vsNode, pool.Name, key)
+			continue
+		}
+
+		var claimed []AviHostPathPortPoolPG
+		claimedAll := false
+		for _, other := range hostPools {
+			if other.Name == pool.Name {
+				continue
+			}
+			otherPath := strings.TrimPrefix(other.PriorityLabel, hostname)
+			if other.PathType != networkingv1.PathTypeExact && strings.TrimSuffix(otherPath, "/") == prefix {
+				// A non Exact path with the same prefix claims all the sub paths.
+				claimedAll = true
+				break
+			}
+			if strings.HasPrefix(otherPath, prefix+"/") {
+				claimPath := AviHostPathPortPoolPG{Host: []string{hostname}, Pool: other.Name}
+				claimed = append(claimed, getHTTPPathMatches(claimPath, otherPath, other.PathType)...)
+			}
+		}
+		if claimedAll {
+			RemoveExactPathHTTPPolicyInModel(vsNode, pool.Name, key)
+			continue
+		}
+		if path != prefix {
+			// The Exact path ends with a slash, serve it from its own pool and 404 the path without the slash.
+			claimed = append(claimed, AviHostPathPortPoolPG{Host: []string{hostname}, Path: []string{path}, MatchCriteria: "EQUALS", Pool: pool.Name})
+		}
+		// Avi evaluates the rules in order, the longest claimed paths go first.
+		sort.SliceStable(claimed, func(i, j int) bool {
+			return len(claimed[i].Path[0]) > len(claimed[j].Path[0])
+		})
+
+		hppMap := claimed
+		if path != prefix {
+			hppMap = append(hppMap, AviHostPathPortPoolPG{
+				Host:               []string{hostname},
+				Path:               []string{prefix},
+				MatchCriteria:      "EQUALS",
+				LocalRspStatusCode: lib.STATUS_NOT_FOUND,
+			})
+		}
+		hppMap = append(hppMap, AviHostPathPortPoolPG{
+			Host:               []string{hostname},
+			Path:               []string{prefix + "/"},
+			MatchCriteria:      "BEGINS_WITH",
+			LocalRspStatusCode: lib.STATUS_NOT_FOUND,
+		})
+
+		policyname := lib.GetL7ExactPathPolicy(pool.Name)
+		exactPathPolicy := &AviHttpPolicySetNode{
+			Tenant: lib.GetTenant(),
+			Name:   policyname,
+			HppMap: hppMap,
+		}
+		exactPathPolicy.AttachedToSharedVS = vsNode.SharedVS
+		exactPathPolicy.CalculateCheckSum()
+		RemoveExactPathHTTPPolicyInModel(vsNode, pool.Name, key)
+		vsNode.HttpPolicyRefs = append(vsNode.HttpPolicyRefs, exactPathPolicy)
+		utils.AviLog.Infof("key: %s, msg: added exact path policy %s for host %s path %s in model", key, policyname, hostname, path)
+	}
+}
+
+func RemoveExactPathHTTPPolicyInModel(vsNode *AviVsNode, poolName, key string) {
+	policyName := lib.GetL7ExactPathPolicy(poolName)
+	for i, policy := range vsNode.HttpPolicyRefs {
+		if policy.Name == policyName {
+			vsNode.HttpPolicyRefs = append(vsNode.HttpPolicyRefs[:i], vsNode.HttpPolicyRefs[i+1:]...)
+			utils.AviLog.Infof("key: %s, msg: removed exact path policy %s in model", key, policy.Name)
+			return
+		}
+	}
+}
+
 func FindAndReplaceRedirectHTTPPolicyInModel(vsNode *AviVsNode, httpPolicy *AviHttpPolicySetNode, hostnames []string, key string) bool {
 	// The hostnames slice can at max have 2 elements.
 	var policyFound bool
@@ -21,6 +21,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -28,7 +29,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	avimodels "github.com/vmware/alb-sdk/go/models"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 /*
@@ -178,6 +179,9 @@ func (v *AviVsNode) CalculateForGraphChecksum() uint32 {
 	for _, l4pol := range v.L4PolicyRefs {
 		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(l4pol.GetCheckSum()))
 	}
+	for _, nsp := range v.NSPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, fmt.Sprint(nsp.GetCheckSum()))
+	}
 
 	return utils.Hash(strings.Join(checksumStringSlice, ":"))
 }
@@ -367,6 +371,7 @@ type AviVsNode struct {
 	HttpPolicyRefs        []*AviHttpPolicySetNode
 	VSVIPRefs             []*AviVSVIPNode
 	L4PolicyRefs          []*AviL4PolicyNode
+	NSPolicyRefs          []*AviNetworkSecurityPolicyNode
 	VHParentName          string
 	VHDomainNames         []string
 	TLSType               string
@@ -381,6 +386,7 @@ type AviVsNode struct {
 	SSLProfileRef         string
 	VsDatascriptRefs      []string
 	SSLKeyCertAviRef      string
+	ClientCertProfile     *AviClientCertProfileNode
 	AviMarkers            utils.AviObjectMarkers
 }
 
@@ -506,6 +512,18 @@ func (v *AviVsNode) SetEnabled(Enabled *bool) {
 	v.Enabled = Enabled
 }
 
+func (v *AviVsNode) GetClientCertProfile() *AviClientCertProfileNode {
+	return v.ClientCertProfile
+}
+
+func (v *AviVsNode) SetClientCertProfile(clientCertProfile *AviClientCertProfileNode) {
+	v.ClientCertProfile = clientCertProfile
+}
+
+func (v *AviVsNode) GetAviMarkers() utils.AviObjectMarkers {
+	return v.AviMarkers
+}
+
 func (o *AviObjectGraph) GetAviVS() []*AviVsNode {
 	var aviVs []*AviVsNode
 	for _, model := range o.modelNodes {
@@ -719,6 +737,10 @@ func (v *AviVsNode) CalculateCheckSum() {
 		checksumStringSlice = append(checksumStringSlice, "L4Policy"+l4policy.Name)
 	}
 
+	for _, nsp := range v.NSPolicyRefs {
+		checksumStringSlice = append(checksumStringSlice, "NetworkSecurityPolicy"+nsp.Name)
+	}
+
 	for _, vhdomain := range v.VHDomainNames {
 		checksumStringSlice = append(checksumStringSlice, "VHDomain"+vhdomain)
 	}
@@ -763,6 +785,10 @@ func (v *AviVsNode) CalculateCheckSum() {
 		checksum += passthroughChild.GetCheckSum()
 	}
 
+	if v.ClientCertProfile != nil {
+		checksum += v.ClientCertProfile.GetCheckSum()
+	}
+
 	if vsRefs != "" {
 		checksum += utils.Hash(vsRefs)
 	}
@@ -840,6 +866,48 @@ func (v *AviL4PolicyNode) CopyNode() AviModelNode {
 	return &newNode
 }
 
+// AviNetworkSecurityPolicyNode restricts the clients of a VS to the given source ranges,
+// every client outside of the ranges is denied.
+type AviNetworkSecurityPolicyNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	SourceRanges     []string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviNetworkSecurityPolicyNode) CalculateCheckSum() {
+	sort.Strings(v.SourceRanges)
+	checksum := utils.Hash(utils.Stringify(v.SourceRanges))
+	if lib.GetGRBACSupport() {
+		checksum += lib.GetMarkersChecksum(v.AviMarkers)
+	}
+	v.CloudConfigCksum = checksum
+}
+
+func (v *AviNetworkSecurityPolicyNode) GetNodeType() string {
+	return "AviNetworkSecurityPolicyNode"
+}
+
+func (v *AviNetworkSecurityPolicyNode) CopyNode() AviModelNode {
+	newNode := AviNetworkSecurityPolicyNode{}
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to marshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	err = json.Unmarshal(bytes, &newNode)
+	if err != nil {
+		utils.AviLog.Warnf("Unable to unmarshal AviNetworkSecurityPolicyNode: %s", err)
+	}
+	return &newNode
+}
+
 type AviHttpPolicySetNode struct {
 	Name               string
 	Tenant             string
@@ -848,6 +916,8 @@ type AviHttpPolicySetNode struct {
 	RedirectPorts      []AviRedirectPort
 	HeaderReWrite      *AviHostHeaderRewrite
 	SecurityRules      []AviHTTPSecurity
+	ResponseRules      []AviHTTPResponseRule
+	StringGroupRefs    []*AviStringGroupNode
 	AviMarkers         utils.AviObjectMarkers
 	AttachedToSharedVS bool
 }
@@ -874,6 +944,19 @@ func (v *AviHttpPolicySetNode) CalculateCheckSum() {
 	for _, sec_rule := range v.SecurityRules {
 		checksum = checksum + utils.Hash(sec_rule.Action) + utils.Hash(sec_rule.MatchCriteria)
 		checksum = checksum + uint32(sec_rule.Port)
+		if len(sec_rule.SourceRanges) > 0 {
+			checksum = checksum + utils.Hash(utils.Stringify(sec_rule.SourceRanges))
+		}
+		if sec_rule.RateLimit != nil {
+			checksum = checksum + utils.Hash(sec_rule.StringGroup) + utils.Hash(utils.Stringify(sec_rule.RateLimit))
+		}
+	}
+	for _, stringGroup := range v.StringGroupRefs {
+		checksum = checksum + stringGroup.GetCheckSum()
+	}
+	for _, rspRule := range v.ResponseRules {
+		sort.Strings(rspRule.Path)
+		checksum = checksum + utils.Hash(utils.Stringify(rspRule))
 	}
 	if v.HeaderReWrite != nil {
 		checksum = checksum + utils.Hash(utils.Stringify(v.HeaderReWrite))
@@ -910,6 +993,50 @@ type AviHostPathPortPoolPG struct {
 	PoolGroup     string
 	MatchCriteria string
 	Protocol      string
+	// LocalRspStatusCode is set for the rules that serve a local response instead of selecting a pool.
+	LocalRspStatusCode string
+	// HdrMatch and HdrAction are the request header matches and the request header modifications of the rule.
+	HdrMatch  []AviHostPathHdrMatch  `json:",omitempty"`
+	HdrAction []AviHostPathHdrAction `json:",omitempty"`
+	// RewritePath and Redirect are set for the rules that rewrite the path of the request or redirect the request.
+	RewritePath *AviHostPathRewrite  `json:",omitempty"`
+	Redirect    *AviHostPathRedirect `json:",omitempty"`
+}
+
+// AviHostPathRewrite replaces the StripPrefix of the request path with Prefix.
+type AviHostPathRewrite struct {
+	StripPrefix string
+	Prefix      string
+}
+
+// AviHostPathRedirect redirects the request, the empty fields are retained from the request.
+type AviHostPathRedirect struct {
+	Protocol   string
+	Host       string
+	Port       int32
+	Path       string
+	StatusCode string
+}
+
+// AviHTTPResponseRule modifies the response headers of the requests matching the Path.
+type AviHTTPResponseRule struct {
+	Path          []string
+	MatchCriteria string
+	HdrAction     []AviHostPathHdrAction
+}
+
+// AviHostPathHdrMatch matches the request header Name, MatchCriteria is one of the Avi header match criteria.
+type AviHostPathHdrMatch struct {
+	Name          string
+	MatchCriteria string
+	Values        []string
+}
+
+// AviHostPathHdrAction modifies the request header Name, Action is one of HTTP_ADD_HDR, HTTP_REPLACE_HDR and HTTP_REMOVE_HDR.
+type AviHostPathHdrAction struct {
+	Action string
+	Name   string
+	Value  string
 }
 
 type AviRedirectPort struct {
@@ -923,6 +1050,22 @@ type AviHTTPSecurity struct {
 	MatchCriteria string
 	Enable        bool
 	Port          int64
+	SourceRanges  []string
+	// StringGroup and RateLimit are set for the rules that limit the rate of the requests with a path matching
+	// the regex in the stringgroup.
+	StringGroup string        `json:",omitempty"`
+	RateLimit   *AviRateLimit `json:",omitempty"`
+}
+
+// AviRateLimit allows Count requests every Period seconds, Action is one of the Avi rate limiter actions.
+type AviRateLimit struct {
+	Count       int32
+	Period      int32
+	BurstSize   int32
+	PerClientIP bool
+	Action      string
+	StatusCode  string
+	Redirect    *AviHostPathRedirect
 }
 type AviHostHeaderRewrite struct {
 	SourceHost string
@@ -989,6 +1132,8 @@ type AviVSVIPNode struct {
 	FQDNs                   []string
 	VrfContext              string
 	IPAddress               string
+	IPv6Address             string
+	IPType                  string
 	VipNetworks             []akov1alpha1.AviInfraSettingVipNetwork
 	EnablePublicIP          *bool
 	BGPPeerLabels           []string
@@ -1014,10 +1159,22 @@ func (v *AviVSVIPNode) CalculateCheckSum() {
 		checksum += utils.Hash(v.IPAddress)
 	}
 
+	if v.IPv6Address != "" {
+		checksum += utils.Hash(v.IPv6Address)
+	}
+
+	if v.IPType != "" {
+		checksum += utils.Hash(v.IPType)
+	}
+
 	if len(v.VipNetworks) > 0 {
 		var vipNetworkStringList []string
 		for _, vipNetwork := range v.VipNetworks {
-			vipNetworkStringList = append(vipNetworkStringList, vipNetwork.NetworkName+":"+vipNetwork.Cidr)
+			vipNetworkString := vipNetwork.NetworkName + ":" + vipNetwork.Cidr
+			if vipNetwork.V6Cidr != "" {
+				vipNetworkString += ":" + vipNetwork.V6Cidr
+			}
+			vipNetworkStringList = append(vipNetworkStringList, vipNetworkString)
 		}
 		sort.Strings(vipNetworkStringList)
 		checksum += utils.Hash(utils.Stringify(vipNetworkStringList))
@@ -1194,6 +1351,88 @@ func (v *AviPkiProfileNode) CalculateCheckSum() {
 	v.CloudConfigCksum = checksum
 }
 
+type AviPersistenceProfileNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	Timeout          int32 // client ip persistence timeout, in minutes.
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviPersistenceProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviPersistenceProfileNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.PersistenceProfileChecksum(v.Timeout, v.AviMarkers, nil, false)
+}
+
+type AviHealthMonitorNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	MonitorPort      int32
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviHealthMonitorNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviHealthMonitorNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.HealthMonitorChecksum(v.MonitorPort, v.AviMarkers, nil, false)
+}
+
+// AviStringGroupNode is a stringgroup of type SG_TYPE_STRING, whose values are matched as regex by the
+// rules of the httppolicyset referring to it.
+type AviStringGroupNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	Values           []string
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviStringGroupNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviStringGroupNode) CalculateCheckSum() {
+	v.CloudConfigCksum = lib.StringGroupChecksum(v.Values, v.AviMarkers, nil, false)
+}
+
+// AviClientCertProfileNode is the application profile which validates the client certificates of a virtualservice
+// with its PKI profile. The application profile and the PKI profile are named after the virtualservice.
+type AviClientCertProfileNode struct {
+	Name             string
+	Tenant           string
+	CloudConfigCksum uint32
+	Mode             string // SSL_CLIENT_CERTIFICATE_REQUIRE or SSL_CLIENT_CERTIFICATE_REQUEST
+	DNHeader         string // request header carrying the subject DN of the client certificate
+	PkiProfile       *AviPkiProfileNode
+	AviMarkers       utils.AviObjectMarkers
+}
+
+func (v *AviClientCertProfileNode) GetCheckSum() uint32 {
+	// Calculate checksum and return
+	v.CalculateCheckSum()
+	return v.CloudConfigCksum
+}
+
+func (v *AviClientCertProfileNode) CalculateCheckSum() {
+	checksum := lib.ClientCertProfileChecksum(v.Mode, v.DNHeader, v.AviMarkers, nil, false)
+	if v.PkiProfile != nil {
+		checksum += v.PkiProfile.GetCheckSum()
+	}
+	v.CloudConfigCksum = checksum
+}
+
 type AviPoolNode struct {
 	Name                   string
 	Tenant                 string
@@ -1208,12 +1447,16 @@ type AviPoolNode struct {
 	LbAlgoHostHeader       string
 	IngressName            string
 	PriorityLabel          string
+	PathType               networkingv1.PathType
 	ServiceMetadata        avicache.ServiceMetadataObj
 	SniEnabled             bool
 	SslProfileRef          string
 	PkiProfile             *AviPkiProfileNode
+	PersistenceProfile     *AviPersistenceProfileNode
+	HealthMonitorNode      *AviHealthMonitorNode
 	HealthMonitors         []string
 	ApplicationPersistence string
+	MaxConcurrentConns     int32
 	VrfContext             string
 	T1Lr                   string // Only applicable to NSX-T cloud, if this value is set, we automatically should unset the VRF context value.
 	AviMarkers             utils.AviObjectMarkers
@@ -1271,16 +1514,31 @@ func (v *AviPoolNode) CalculateCheckSum() {
 		checksum += v.PkiProfile.GetCheckSum()
 	}
 
+	if v.PersistenceProfile != nil {
+		checksum += v.PersistenceProfile.GetCheckSum()
+	}
+
+	if v.HealthMonitorNode != nil {
+		checksum += v.HealthMonitorNode.GetCheckSum()
+	}
+
 	if v.ApplicationPersistence != "" {
 		checksum += utils.Hash(v.ApplicationPersistence)
 	}
 
+	if v.MaxConcurrentConns != 0 {
+		checksum += uint32(v.MaxConcurrentConns)
+	}
+
 	if lib.GetGRBACSupport() {
 		checksum += lib.GetMarkersChecksum(v.AviMarkers)
 	}
 	if v.T1Lr != "" {
 		checksum += utils.Hash(v.T1Lr)
 	}
+	if drainTimeout := lib.GetServerDrainTimeout(); drainTimeout > 0 {
+		checksum += utils.Hash(drainTimeout.String())
+	}
 
 	v.CloudConfigCksum = checksum
 }
@@ -1335,12 +1593,18 @@ type AviPoolMetaServer struct {
 	Ip         avimodels.IPAddr
 	ServerNode string
 	Port       int32
+	// Disabled is set for the servers being drained after their removal from the endpoints.
+	Disabled bool `json:",omitempty"`
+	// DrainDeadline is set for the servers being drained, which are deleted from the pool after the deadline.
+	DrainDeadline *time.Time `json:",omitempty"`
+	// Ratio is set from the zone of the node of the server, when zone server ratios are configured.
+	Ratio int32 `json:",omitempty"`
 }
 
 type IngressHostPathSvc struct {
 	ServiceName string
 	Path        string
-	PathType    networkingv1beta1.PathType
+	PathType    networkingv1.PathType
 	Port        int32
 	weight      int32 //required for alternate backends in openshift route
 	PortName    string
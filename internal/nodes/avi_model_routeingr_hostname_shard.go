@@ -420,6 +420,12 @@ func updateHostPathCache(ns, ingress string, oldHostMap, newHostMap map[string]*
 		for path := range oldMap.PathSvc {
 			SharedHostNameLister().RemoveHostPathStore(host, path, mmapval)
 		}
+		if _, ok := newHostMap[host]; !ok {
+			// the hostrule is no longer applied on a host without ingresses
+			if found, _ := SharedHostNameLister().GetHostPathStore(host); !found {
+				updateHostRuleAppliedFqdn(host, "", ns+"/"+ingress)
+			}
+		}
 	}
 
 	// add from newHostMap
@@ -0,0 +1,279 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	svcapiv1alpha1 "sigs.k8s.io/service-apis/apis/v1alpha1"
+)
+
+// The listeners of a L4 gateway which select TCPRoutes and UDPRoutes are realised as ports of the L4 virtualservice,
+// with a L4 policy rule per listener that selects the poolgroup of the route bound to the listener. The connections
+// to the listeners which select TLSRoutes are routed by a datascript, which selects the poolgroup of the SNI of the
+// TLS ClientHello. Since the datascript closes the connections which are not TLS, the other listeners of a gateway
+// with TLS passthrough listeners are not realised.
+
+// svcApiL4RouteRule is a rule of a TCPRoute, UDPRoute or TLSRoute.
+type svcApiL4RouteRule struct {
+	// snis are the SNIs matched by a rule of a TLSRoute.
+	snis         []svcapiv1alpha1.Hostname
+	extensionRef bool
+	forwardTo    []svcapiv1alpha1.RouteForwardTo
+}
+
+// ConstructSvcApiL4RouteNodes adds the pools and poolgroups of the TCPRoutes, UDPRoutes and TLSRoutes bound to the
+// gateway to the virtualservice, and updates the status of the routes. A listener, or a SNI of the TLS listeners,
+// selected by multiple routes is bound to the oldest route.
+func (o *AviObjectGraph) ConstructSvcApiL4RouteNodes(vsNode *AviVsNode, gw *svcapiv1alpha1.Gateway, key string) {
+	_, routeKeys := objects.ServiceGWLister().GetGatewayToRoutes(gw.Namespace + "/" + gw.Name)
+	var routes []*svcApiRoute
+	for _, routeKey := range routeKeys {
+		kind, routeNS, routeName := lib.ExtractTypeNameNamespace(routeKey)
+		if kind == lib.HTTPRoute {
+			continue
+		}
+		route, err := getSvcApiRoute(kind, routeNS, routeName)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the route %s: %v", key, routeKey, err)
+			continue
+		}
+		routes = append(routes, route)
+	}
+	sort.SliceStable(routes, func(i, j int) bool {
+		if !routes[i].meta.CreationTimestamp.Equal(&routes[j].meta.CreationTimestamp) {
+			return routes[i].meta.CreationTimestamp.Before(&routes[j].meta.CreationTimestamp)
+		}
+		return routes[i].kind+"/"+routes[i].meta.Namespace+"/"+routes[i].meta.Name <
+			routes[j].kind+"/"+routes[j].meta.Namespace+"/"+routes[j].meta.Name
+	})
+
+	// the routes bound to the listeners, keyed by the index of the listener, and to the SNIs.
+	listenerRoutes := make(map[int]string)
+	sniRoutes := make(map[string]string)
+	var portPoolSet []AviHostPathPortPoolPG
+	for _, route := range routes {
+		routeKey := route.kind + "/" + route.meta.Namespace + "/" + route.meta.Name
+		routeListeners := make(map[int]svcApiRouteListener)
+		var conflicts []string
+		err := validateSvcApiL4Route(route)
+		if err == nil {
+			for i, listener := range gw.Spec.Listeners {
+				if !isSvcApiL4ListenerRealised(gw, listener) || !svcApiListenerSelectsRoute(gw, listener, route.kind, route.meta) {
+					continue
+				}
+				listenerKey := fmt.Sprintf("%s/%d", listener.Protocol, listener.Port)
+				if route.kind == lib.TLSRoute {
+					hostnames, conflict := o.buildSvcApiTLSRouteNodes(vsNode, gw, route, listener, sniRoutes, key)
+					if len(hostnames) > 0 {
+						routeListeners[i] = svcApiRouteListener{listener: listener, hostnames: hostnames}
+					} else if conflict {
+						conflicts = append(conflicts, listenerKey)
+					}
+					continue
+				}
+				if _, ok := listenerRoutes[i]; ok {
+					conflicts = append(conflicts, listenerKey)
+					continue
+				}
+				listenerRoutes[i] = routeKey
+				routeListeners[i] = svcApiRouteListener{listener: listener}
+
+				var ruleIndexes []int
+				for ruleIndex := range route.rules {
+					ruleIndexes = append(ruleIndexes, ruleIndex)
+				}
+				pgName := lib.GetSvcApiL4RoutePGName(vsNode.Name, string(listener.Protocol), int32(listener.Port))
+				if pgNode := o.buildSvcApiL4RoutePG(vsNode, gw, route, pgName, ruleIndexes, string(listener.Protocol), key); pgNode != nil {
+					portPoolSet = append(portPoolSet, AviHostPathPortPoolPG{
+						Port:      uint32(listener.Port),
+						PoolGroup: pgNode.Name,
+						Protocol:  string(listener.Protocol),
+					})
+				}
+			}
+		}
+
+		condition := getSvcApiRouteAdmittedCondition(route.meta.Generation, routeListeners, err)
+		if err == nil && len(routeListeners) == 0 && len(conflicts) > 0 {
+			condition.Reason = "RouteConflict"
+			condition.Message = "The listeners " + strings.Join(conflicts, ", ") + " are bound to older routes"
+		}
+		route.updateStatus(key, gw.Namespace, gw.Name, condition)
+		if condition.Status != metav1.ConditionTrue {
+			utils.AviLog.Warnf("key: %s, msg: route %s is not admitted: %s", key, routeKey, condition.Message)
+		}
+	}
+
+	if len(portPoolSet) > 0 {
+		if len(vsNode.L4PolicyRefs) == 0 {
+			l4policyNode := &AviL4PolicyNode{Name: vsNode.Name, Tenant: lib.GetTenant()}
+			l4policyNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+			vsNode.L4PolicyRefs = append(vsNode.L4PolicyRefs, l4policyNode)
+		}
+		vsNode.L4PolicyRefs[0].PortPool = append(vsNode.L4PolicyRefs[0].PortPool, portPoolSet...)
+	}
+	utils.AviLog.Infof("key: %s, msg: evaluated L4 route policies :%v", key, utils.Stringify(vsNode.L4PolicyRefs))
+}
+
+// buildSvcApiTLSRouteNodes adds the poolgroups of the SNIs of the TLSRoute served by the listener to the virtualservice
+// and to the datascript of the virtualservice, and returns the SNIs. The SNIs bound to older routes are skipped, in
+// which case true is returned along with the SNIs.
+func (o *AviObjectGraph) buildSvcApiTLSRouteNodes(vsNode *AviVsNode, gw *svcapiv1alpha1.Gateway, route *svcApiRoute, listener svcapiv1alpha1.Listener, sniRoutes map[string]string, key string) ([]string, bool) {
+	routeKey := route.kind + "/" + route.meta.Namespace + "/" + route.meta.Name
+	var snis []string
+	conflict := false
+	for ruleIndex, rule := range route.rules {
+		hostnames, ok := getSvcApiListenerHostnames(listener, rule.snis)
+		if !ok {
+			continue
+		}
+		for _, hostname := range hostnames {
+			if owner, ok := sniRoutes[hostname]; ok {
+				// The SNI is served by an older route, or by an earlier rule or listener of the route.
+				if owner != routeKey {
+					conflict = true
+				} else if !utils.HasElem(snis, hostname) {
+					snis = append(snis, hostname)
+				}
+				continue
+			}
+			sniRoutes[hostname] = routeKey
+			snis = append(snis, hostname)
+
+			if len(vsNode.HTTPDSrefs) == 0 {
+				// The poolgroups of the gateway are selected by the SNI, appended to the prefix of the gateway.
+				dsNode := &AviHTTPDataScriptNode{
+					Name:   lib.GetL7InsecureDSName(vsNode.Name),
+					Tenant: lib.GetTenant(),
+					DataScript: &DataScript{
+						Script: strings.Replace(lib.PassthroughDatascript, "CLUSTER--", lib.GetSvcApiPassthroughPGPrefix(vsNode.Name), 1),
+						Evt:    "VS_DATASCRIPT_EVT_L4_REQUEST",
+					},
+					ProtocolParsers: []string{"/api/protocolparser/?name=Default-TLS"},
+				}
+				vsNode.HTTPDSrefs = append(vsNode.HTTPDSrefs, dsNode)
+			}
+			pgName := lib.GetSvcApiPassthroughPGName(vsNode.Name, hostname)
+			if pgNode := o.buildSvcApiL4RoutePG(vsNode, gw, route, pgName, []int{ruleIndex}, utils.TCP, key); pgNode != nil {
+				vsNode.HTTPDSrefs[0].PoolGroupRefs = append(vsNode.HTTPDSrefs[0].PoolGroupRefs, pgNode.Name)
+			}
+			if !utils.HasElem(vsNode.VSVIPRefs[0].FQDNs, hostname) {
+				vsNode.VSVIPRefs[0].FQDNs = append(vsNode.VSVIPRefs[0].FQDNs, hostname)
+			}
+			if !utils.HasElem(vsNode.ServiceMetadata.HostNames, hostname) {
+				vsNode.ServiceMetadata.HostNames = append(vsNode.ServiceMetadata.HostNames, hostname)
+			}
+		}
+	}
+	return snis, conflict
+}
+
+// buildSvcApiL4RoutePG adds the poolgroup, with a pool for each of the forwardTo services of the rules of the route
+// weighed by the weight of the service, to the virtualservice. The services with weight 0 do not receive traffic.
+// nil is returned if the rules do not forward to any service.
+func (o *AviObjectGraph) buildSvcApiL4RoutePG(vsNode *AviVsNode, gw *svcapiv1alpha1.Gateway, route *svcApiRoute, pgName string, ruleIndexes []int, protocol, key string) *AviPoolGroupNode {
+	pgNode := &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant()}
+	pgNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+	for _, ruleIndex := range ruleIndexes {
+		for _, forwardTo := range route.rules[ruleIndex].forwardTo {
+			if forwardTo.ServiceName == nil || forwardTo.Weight == 0 {
+				continue
+			}
+			svcName := *forwardTo.ServiceName
+			port := int32(forwardTo.Port)
+			poolName := lib.GetSvcApiL4RoutePoolName(vsNode.Name, route.kind, route.meta.Namespace, route.meta.Name, ruleIndex, svcName, port)
+			poolFound := false
+			for _, pool := range vsNode.PoolRefs {
+				if pool.Name == poolName {
+					poolFound = true
+					break
+				}
+			}
+			if !poolFound {
+				poolNode := buildSvcApiRoutePool(gw, route.meta.Namespace, poolName, svcName, port, key)
+				poolNode.Protocol = protocol
+				vsNode.PoolRefs = append(vsNode.PoolRefs, poolNode)
+			}
+			pool_ref := fmt.Sprintf("/api/pool?name=%s", poolName)
+			ratio := forwardTo.Weight
+			pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, Ratio: &ratio})
+		}
+	}
+
+	if len(pgNode.Members) == 0 {
+		return nil
+	}
+	vsNode.PoolGroupRefs = append(vsNode.PoolGroupRefs, pgNode)
+	return pgNode
+}
+
+// isSvcApiL4ListenerRealised returns true for the listeners of TCPRoutes, UDPRoutes and TLSRoutes which are
+// realised on the virtualservice of the gateway.
+func isSvcApiL4ListenerRealised(gw *svcapiv1alpha1.Gateway, listener svcapiv1alpha1.Listener) bool {
+	if !lib.IsSvcApiL4RouteListener(listener) {
+		return false
+	}
+	return listener.Routes.Kind == lib.TLSRoute || !lib.IsSvcApiPassthroughGateway(gw)
+}
+
+// validateSvcApiL4Route returns an error if the route uses a match or a backend which is not supported.
+func validateSvcApiL4Route(route *svcApiRoute) error {
+	if len(route.rules) == 0 {
+		return errors.New("the route has no rules")
+	}
+	for i, rule := range route.rules {
+		if rule.extensionRef {
+			return fmt.Errorf("rule %d: extensionRef matches are not supported", i)
+		}
+		if route.kind == lib.TLSRoute {
+			if len(rule.snis) == 0 {
+				return fmt.Errorf("rule %d: SNI matches are required in %ss", i, lib.TLSRoute)
+			}
+			for _, sni := range rule.snis {
+				if strings.HasPrefix(string(sni), "*") {
+					return fmt.Errorf("rule %d: wildcard SNI matches are not supported", i)
+				}
+			}
+		}
+		for _, forwardTo := range rule.forwardTo {
+			if forwardTo.ServiceName == nil || forwardTo.BackendRef != nil {
+				return fmt.Errorf("rule %d: forwardTo backendRefs are not supported, only serviceName is", i)
+			}
+		}
+	}
+	return nil
+}
+
+// getSvcApiRouteListenerKeys returns the protocol/port of the listeners of the gateway which select routes, which
+// are not bound to the Services selected using the gateway labels.
+func getSvcApiRouteListenerKeys(gw *svcapiv1alpha1.Gateway) []string {
+	var listenerKeys []string
+	for _, listener := range gw.Spec.Listeners {
+		if lib.IsSvcApiRouteListener(listener) {
+			listenerKeys = append(listenerKeys, fmt.Sprintf("%s/%d", listener.Protocol, listener.Port))
+		}
+	}
+	return listenerKeys
+}
@@ -0,0 +1,679 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package nodes
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+	"google.golang.org/protobuf/proto"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	svcapiv1alpha1 "sigs.k8s.io/service-apis/apis/v1alpha1"
+)
+
+// The Gateways with listeners that select HTTPRoutes are realised as a L7 parent virtualservice, with a port
+// per listener. The rules of the HTTPRoutes bound to the HTTP listeners are added to the httppolicyset of the
+// parent, and every HTTPS listener is realised as a SNI child, which terminates TLS with the certificateRef of
+// the listener and holds the rules of the HTTPRoutes bound to the listener.
+
+// svcApiRouteListener is a listener of the gateway that accepts a route, along with the route hostnames
+// served by the listener. nil hostnames means that the listener serves the route for all the hosts.
+type svcApiRouteListener struct {
+	listener  svcapiv1alpha1.Listener
+	hostnames []string
+}
+
+func (o *AviObjectGraph) BuildSvcApiL7Graph(namespace string, gatewayName string, key string) {
+	o.Lock.Lock()
+	defer o.Lock.Unlock()
+	gw, err := lib.GetSvcAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(gatewayName)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the gateway %s/%s: %v", key, namespace, gatewayName, err)
+		return
+	}
+	vsNode := o.ConstructSvcApiL7VsNode(gw, key)
+	o.ConstructSvcApiL7RouteNodes(vsNode, gw, key)
+	o.AddModelNode(vsNode)
+	utils.AviLog.Infof("key: %s, msg: checksum  for AVI VS object %v", key, vsNode.GetCheckSum())
+}
+
+func (o *AviObjectGraph) ConstructSvcApiL7VsNode(gw *svcapiv1alpha1.Gateway, key string) *AviVsNode {
+	vsNode := &AviVsNode{
+		Name:       lib.GetL4VSName(gw.Name, gw.Namespace),
+		Tenant:     lib.GetTenant(),
+		VrfContext: lib.GetVrf(),
+		ServiceMetadata: avicache.ServiceMetadataObj{
+			Gateway: gw.Namespace + "/" + gw.Name,
+		},
+		ServiceEngineGroup: lib.GetSEGName(),
+		EnableRhi:          proto.Bool(lib.GetEnableRHI()),
+		ApplicationProfile: utils.DEFAULT_L7_APP_PROFILE,
+		NetworkProfile:     utils.DEFAULT_TCP_NW_PROFILE,
+	}
+	vsNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+
+	for _, listener := range gw.Spec.Listeners {
+		if !IsSvcApiL7Listener(listener) {
+			continue
+		}
+		enableSSL := listener.Protocol == svcapiv1alpha1.HTTPSProtocolType
+		if enableSSL {
+			vsNode.SNIParent = true
+		}
+		portFound := false
+		for _, pp := range vsNode.PortProto {
+			if pp.Port == int32(listener.Port) {
+				portFound = true
+				break
+			}
+		}
+		if !portFound {
+			vsNode.PortProto = append(vsNode.PortProto, AviPortHostProtocol{Port: int32(listener.Port), Protocol: utils.HTTP, EnableSSL: enableSSL})
+		}
+	}
+
+	vsVipNode := &AviVSVIPNode{
+		Name:        lib.GetL4VSVipName(gw.Name, gw.Namespace),
+		Tenant:      lib.GetTenant(),
+		VrfContext:  lib.GetVrf(),
+		VipNetworks: lib.GetVipNetworkList(),
+	}
+
+	if vsNode.EnableRhi != nil && *vsNode.EnableRhi {
+		vsVipNode.BGPPeerLabels = lib.GetGlobalBgpPeerLabels()
+	}
+
+	// configures VS and VsVip nodes using infraSetting object (via CRD).
+	if infraSetting, err := getL4InfraSetting(key, nil, &gw.Spec.GatewayClassName); err == nil {
+		buildWithInfraSetting(key, vsNode, vsVipNode, infraSetting)
+	}
+
+	if len(gw.Spec.Addresses) > 0 && gw.Spec.Addresses[0].Type == svcapiv1alpha1.IPAddressType {
+		vsVipNode.IPAddress = gw.Spec.Addresses[0].Value
+	}
+
+	vsNode.VSVIPRefs = append(vsNode.VSVIPRefs, vsVipNode)
+	return vsNode
+}
+
+// ConstructSvcApiL7RouteNodes adds the pools, poolgroups and http policies of the HTTPRoutes bound to the
+// gateway to the parent virtualservice and to its SNI children, and updates the status of the HTTPRoutes.
+func (o *AviObjectGraph) ConstructSvcApiL7RouteNodes(vsNode *AviVsNode, gw *svcapiv1alpha1.Gateway, key string) {
+	_, routes := objects.ServiceGWLister().GetGatewayToRoutes(gw.Namespace + "/" + gw.Name)
+	routeKeys := append([]string{}, routes...)
+	sort.Strings(routeKeys)
+
+	httpPolicy := &AviHttpPolicySetNode{Name: vsNode.Name, Tenant: lib.GetTenant()}
+	httpPolicy.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+
+	// the SNI children of the HTTPS listeners, keyed by the index of the listener.
+	sniNodes := make(map[int]*AviVsNode)
+	sniPolicies := make(map[int]*AviHttpPolicySetNode)
+	sniHostnames := make(map[int][]string)
+
+	for _, routeKey := range routeKeys {
+		_, routeNS, routeName := lib.ExtractTypeNameNamespace(routeKey)
+		route, err := lib.GetSvcAPIInformers().HTTPRouteInformer.Lister().HTTPRoutes(routeNS).Get(routeName)
+		if err != nil {
+			utils.AviLog.Debugf("key: %s, msg: unable to get the httproute %s: %v", key, routeKey, err)
+			continue
+		}
+
+		var routeListeners map[int]svcApiRouteListener
+		err = validateSvcApiHTTPRoute(route)
+		if err == nil {
+			routeListeners = getSvcApiListenersForRoute(gw, lib.HTTPRoute, route.ObjectMeta, route.Spec.Hostnames)
+		}
+		status.UpdateSvcApiHTTPRouteStatus(key, route, gw.Namespace, gw.Name, getSvcApiRouteAdmittedCondition(route.Generation, routeListeners, err))
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: httproute %s is not admitted: %v", key, routeKey, err)
+			continue
+		}
+
+		listenerIndexes := make([]int, 0, len(routeListeners))
+		for index := range routeListeners {
+			listenerIndexes = append(listenerIndexes, index)
+		}
+		sort.Ints(listenerIndexes)
+		for _, index := range listenerIndexes {
+			routeListener := routeListeners[index]
+			owner, policy := vsNode, httpPolicy
+			port := uint32(routeListener.listener.Port)
+			if routeListener.listener.Protocol == svcapiv1alpha1.HTTPSProtocolType {
+				if _, ok := sniNodes[index]; !ok {
+					sniNodes[index] = o.ConstructSvcApiSniNode(vsNode, gw, routeListener.listener, key)
+					sniPolicies[index] = &AviHttpPolicySetNode{Name: sniNodes[index].Name, Tenant: lib.GetTenant()}
+					sniPolicies[index].AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+				}
+				owner, policy = sniNodes[index], sniPolicies[index]
+				// the SNI child only receives the traffic of its hostnames, the rules do not match on the port.
+				port = 0
+				for _, hostname := range routeListener.hostnames {
+					if !utils.HasElem(sniHostnames[index], hostname) {
+						sniHostnames[index] = append(sniHostnames[index], hostname)
+					}
+				}
+			}
+			for ruleIndex, rule := range route.Spec.Rules {
+				policy.HppMap = append(policy.HppMap, o.buildSvcApiHTTPRouteRule(owner, gw, route, ruleIndex, rule, routeListener.hostnames, port, key)...)
+			}
+		}
+	}
+
+	sortSvcApiHTTPRules(httpPolicy.HppMap)
+	if len(httpPolicy.HppMap) > 0 {
+		vsNode.HttpPolicyRefs = append(vsNode.HttpPolicyRefs, httpPolicy)
+	}
+
+	// Listeners with an exact hostname serve that hostname, others serve the hostnames of their routes.
+	var fqdns []string
+	for index, listener := range gw.Spec.Listeners {
+		if listener.Hostname != nil && string(*listener.Hostname) != "" && !strings.HasPrefix(string(*listener.Hostname), "*") {
+			sniHostnames[index] = []string{string(*listener.Hostname)}
+		}
+	}
+	for _, rule := range httpPolicy.HppMap {
+		for _, host := range rule.Host {
+			if !utils.HasElem(fqdns, host) {
+				fqdns = append(fqdns, host)
+			}
+		}
+	}
+
+	sniIndexes := make([]int, 0, len(sniNodes))
+	for index := range sniNodes {
+		sniIndexes = append(sniIndexes, index)
+	}
+	sort.Ints(sniIndexes)
+	for _, index := range sniIndexes {
+		sniNode := sniNodes[index]
+		if len(sniHostnames[index]) == 0 || len(sniNode.SSLKeyCertRefs) == 0 {
+			utils.AviLog.Warnf("key: %s, msg: skipping the SNI child %s, with hostnames %v, certificates found %d",
+				key, sniNode.Name, sniHostnames[index], len(sniNode.SSLKeyCertRefs))
+			continue
+		}
+		sniNode.VHDomainNames = sniHostnames[index]
+		sniNode.ServiceMetadata.HostNames = sniHostnames[index]
+		sortSvcApiHTTPRules(sniPolicies[index].HppMap)
+		if len(sniPolicies[index].HppMap) > 0 {
+			sniNode.HttpPolicyRefs = append(sniNode.HttpPolicyRefs, sniPolicies[index])
+		}
+		vsNode.SniNodes = append(vsNode.SniNodes, sniNode)
+		for _, host := range sniNode.VHDomainNames {
+			if !utils.HasElem(fqdns, host) {
+				fqdns = append(fqdns, host)
+			}
+		}
+	}
+
+	vsNode.ServiceMetadata.HostNames = fqdns
+	vsNode.VSVIPRefs[0].FQDNs = fqdns
+	utils.AviLog.Infof("key: %s, msg: evaluated L7 gateway policies :%v", key, utils.Stringify(vsNode.HttpPolicyRefs))
+}
+
+// ConstructSvcApiSniNode builds the SNI child of a HTTPS listener, with the certificate of the listener.
+// No certificate is set on the SNI child if the certificate cannot be read from the Secret.
+func (o *AviObjectGraph) ConstructSvcApiSniNode(vsNode *AviVsNode, gw *svcapiv1alpha1.Gateway, listener svcapiv1alpha1.Listener, key string) *AviVsNode {
+	var hostname string
+	if listener.Hostname != nil {
+		hostname = string(*listener.Hostname)
+	}
+	sniNode := &AviVsNode{
+		Name:               lib.GetSvcApiSniNodeName(gw.Name, gw.Namespace, hostname, int32(listener.Port)),
+		VHParentName:       vsNode.Name,
+		Tenant:             lib.GetTenant(),
+		IsSNIChild:         true,
+		ServiceEngineGroup: lib.GetSEGName(),
+		VrfContext:         lib.GetVrf(),
+	}
+	sniNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+
+	secretName := listener.TLS.CertificateRef.Name
+	secretObj, err := utils.GetInformers().SecretInformer.Lister().Secrets(gw.Namespace).Get(secretName)
+	if err != nil || secretObj == nil {
+		utils.AviLog.Warnf("key: %s, msg: secret %s/%s of the gateway listener not found: %v", key, gw.Namespace, secretName, err)
+		return sniNode
+	}
+	cert, certFound := secretObj.Data[tlsCert]
+	tlsKey, keyFound := secretObj.Data[utils.K8S_TLS_SECRET_KEY]
+	if !certFound || !keyFound {
+		utils.AviLog.Warnf("key: %s, msg: certificate or key not found in secret %s/%s", key, gw.Namespace, secretName)
+		return sniNode
+	}
+	certNode := &AviTLSKeyCertNode{
+		Name:   sniNode.Name,
+		Tenant: lib.GetTenant(),
+		Type:   lib.CertTypeVS,
+		Cert:   cert,
+		Key:    tlsKey,
+	}
+	certNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+	sniNode.SSLKeyCertRefs = append(sniNode.SSLKeyCertRefs, certNode)
+	return sniNode
+}
+
+// buildSvcApiHTTPRouteRule adds the poolgroup and the pools of the rule of a HTTPRoute to the owner virtualservice,
+// and returns the http policy rules of the matches of the rule.
+func (o *AviObjectGraph) buildSvcApiHTTPRouteRule(owner *AviVsNode, gw *svcapiv1alpha1.Gateway, route *svcapiv1alpha1.HTTPRoute, ruleIndex int, rule svcapiv1alpha1.HTTPRouteRule, hostnames []string, port uint32, key string) []AviHostPathPortPoolPG {
+	httpPGPath := AviHostPathPortPoolPG{Host: hostnames, Port: port}
+	pgName := lib.GetSvcApiL7PGName(owner.Name, route.Namespace, route.Name, ruleIndex)
+	if pgNode := o.buildSvcApiHTTPRoutePG(owner, gw, route, pgName, ruleIndex, rule, key); pgNode != nil {
+		httpPGPath.PoolGroup = pgNode.Name
+	} else {
+		// Requests matching a rule without backends are rejected.
+		httpPGPath.LocalRspStatusCode = lib.STATUS_NOT_FOUND
+	}
+
+	for _, filter := range rule.Filters {
+		if filter.Type != svcapiv1alpha1.HTTPRouteFilterRequestHeaderModifier || filter.RequestHeaderModifier == nil {
+			continue
+		}
+		for _, name := range getSortedKeys(filter.RequestHeaderModifier.Add) {
+			httpPGPath.HdrAction = append(httpPGPath.HdrAction, AviHostPathHdrAction{
+				Action: "HTTP_ADD_HDR",
+				Name:   name,
+				Value:  filter.RequestHeaderModifier.Add[name],
+			})
+		}
+		for _, name := range filter.RequestHeaderModifier.Remove {
+			httpPGPath.HdrAction = append(httpPGPath.HdrAction, AviHostPathHdrAction{
+				Action: "HTTP_REMOVE_HDR",
+				Name:   name,
+			})
+		}
+	}
+
+	matches := rule.Matches
+	if len(matches) == 0 {
+		matches = []svcapiv1alpha1.HTTPRouteMatch{{Path: svcapiv1alpha1.HTTPPathMatch{Type: svcapiv1alpha1.PathMatchPrefix, Value: "/"}}}
+	}
+	var httpPolicySet []AviHostPathPortPoolPG
+	for _, match := range matches {
+		matchPGPath := httpPGPath
+		matchPGPath.HdrMatch = nil
+		if match.Headers != nil {
+			for _, name := range getSortedKeys(match.Headers.Values) {
+				matchPGPath.HdrMatch = append(matchPGPath.HdrMatch, AviHostPathHdrMatch{
+					Name:          name,
+					MatchCriteria: "HDR_EQUALS",
+					Values:        []string{match.Headers.Values[name]},
+				})
+			}
+		}
+		path := match.Path.Value
+		if path == "" {
+			path = "/"
+		}
+		pathType := networkingv1.PathTypePrefix
+		switch match.Path.Type {
+		case svcapiv1alpha1.PathMatchExact:
+			pathType = networkingv1.PathTypeExact
+		case svcapiv1alpha1.PathMatchImplementationSpecific:
+			pathType = networkingv1.PathTypeImplementationSpecific
+		}
+		httpPolicySet = append(httpPolicySet, getHTTPPathMatches(matchPGPath, path, pathType)...)
+	}
+	return httpPolicySet
+}
+
+// buildSvcApiHTTPRoutePG adds the poolgroup of the rule, with a pool for each of the forwardTo services of
+// the rule weighed by the weight of the service, to the owner virtualservice. The services with weight 0 do
+// not receive traffic. nil is returned if the rule does not forward to any service.
+func (o *AviObjectGraph) buildSvcApiHTTPRoutePG(owner *AviVsNode, gw *svcapiv1alpha1.Gateway, route *svcapiv1alpha1.HTTPRoute, pgName string, ruleIndex int, rule svcapiv1alpha1.HTTPRouteRule, key string) *AviPoolGroupNode {
+	for _, pgNode := range owner.PoolGroupRefs {
+		if pgNode.Name == pgName {
+			return pgNode
+		}
+	}
+
+	pgNode := &AviPoolGroupNode{Name: pgName, Tenant: lib.GetTenant()}
+	pgNode.AviMarkers = lib.PopulateAdvL4VSNodeMarkers(gw.Namespace, gw.Name)
+	for _, forwardTo := range rule.ForwardTo {
+		if forwardTo.ServiceName == nil || forwardTo.Weight == 0 {
+			continue
+		}
+		svcName := *forwardTo.ServiceName
+		port := int32(forwardTo.Port)
+		poolNode := buildSvcApiRoutePool(gw, route.Namespace, lib.GetSvcApiL7PoolName(owner.Name, route.Namespace, route.Name, ruleIndex, svcName, port), svcName, port, key)
+
+		poolFound := false
+		for _, pool := range owner.PoolRefs {
+			if pool.Name == poolNode.Name {
+				poolFound = true
+				break
+			}
+		}
+		if !poolFound {
+			owner.PoolRefs = append(owner.PoolRefs, poolNode)
+			pool_ref := fmt.Sprintf("/api/pool?name=%s", poolNode.Name)
+			ratio := forwardTo.Weight
+			pgNode.Members = append(pgNode.Members, &avimodels.PoolGroupMember{PoolRef: &pool_ref, Ratio: &ratio})
+		}
+	}
+
+	if len(pgNode.Members) == 0 {
+		return nil
+	}
+	owner.PoolGroupRefs = append(owner.PoolGroupRefs, pgNode)
+	return pgNode
+}
+
+// buildSvcApiRoutePool returns the pool of the service a route forwards to, with the endpoints of the port of the service.
+func buildSvcApiRoutePool(gw *svcapiv1alpha1.Gateway, namespace, poolName, svcName string, port int32, key string) *AviPoolNode {
+	poolNode := &AviPoolNode{
+		Name:       poolName,
+		Tenant:     lib.GetTenant(),
+		VrfContext: lib.GetVrf(),
+		ServiceMetadata: avicache.ServiceMetadataObj{
+			NamespaceServiceName: []string{namespace + "/" + svcName},
+		},
+	}
+	if lib.GetT1LRPath() != "" {
+		poolNode.T1Lr = lib.GetT1LRPath()
+		// Unset the poolnode's vrfcontext.
+		poolNode.VrfContext = ""
+	}
+
+	// Obtain the matching portname from the service, for selecting the endpoints of the port.
+	if svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(svcName); err == nil {
+		for _, svcPort := range svcObj.Spec.Ports {
+			if svcPort.Port == port {
+				poolNode.PortName = svcPort.Name
+				poolNode.TargetPort = svcPort.TargetPort.IntVal
+			}
+		}
+	} else {
+		utils.AviLog.Warnf("key: %s, msg: error while retrieving service %s/%s: %v", key, namespace, svcName, err)
+	}
+
+	serviceType := lib.GetServiceType()
+	if serviceType == lib.NodePortLocal {
+		if servers := PopulateServersForNPL(poolNode, namespace, svcName, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else if serviceType == lib.NodePort {
+		if servers := PopulateServersForNodePort(poolNode, namespace, svcName, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	} else {
+		if servers := PopulateServers(poolNode, namespace, svcName, false, key); servers != nil {
+			poolNode.Servers = servers
+		}
+	}
+	poolNode.AviMarkers = lib.PopulateAdvL4PoolNodeMarkers(namespace, svcName, gw.Name, int(port))
+	return poolNode
+}
+
+// IsSvcApiL7Listener returns true for the listeners of HTTPRoutes with the HTTP protocol, and for the listeners
+// with the HTTPS protocol which terminate TLS using a Secret.
+func IsSvcApiL7Listener(listener svcapiv1alpha1.Listener) bool {
+	if !lib.IsSvcApiRouteListener(listener) || listener.Routes.Kind != lib.HTTPRoute {
+		return false
+	}
+	switch listener.Protocol {
+	case svcapiv1alpha1.HTTPProtocolType:
+		return true
+	case svcapiv1alpha1.HTTPSProtocolType:
+		return listener.TLS != nil && listener.TLS.Mode != svcapiv1alpha1.TLSModePassthrough &&
+			lib.IsSvcApiSecretRef(listener.TLS.CertificateRef)
+	}
+	return false
+}
+
+// validateSvcApiHTTPRoute returns an error if the route uses a match, filter or backend which is not supported.
+func validateSvcApiHTTPRoute(route *svcapiv1alpha1.HTTPRoute) error {
+	for i, rule := range route.Spec.Rules {
+		for _, match := range rule.Matches {
+			if match.Path.Type == svcapiv1alpha1.PathMatchRegularExpression {
+				return fmt.Errorf("rule %d: RegularExpression path matches are not supported", i)
+			}
+			if match.Headers != nil && match.Headers.Type == svcapiv1alpha1.HeaderMatchRegularExpression {
+				return fmt.Errorf("rule %d: RegularExpression header matches are not supported", i)
+			}
+			if match.ExtensionRef != nil {
+				return fmt.Errorf("rule %d: extensionRef matches are not supported", i)
+			}
+		}
+		for _, filter := range rule.Filters {
+			if filter.Type != svcapiv1alpha1.HTTPRouteFilterRequestHeaderModifier || filter.RequestHeaderModifier == nil {
+				return fmt.Errorf("rule %d: %s filters are not supported", i, filter.Type)
+			}
+		}
+		for _, forwardTo := range rule.ForwardTo {
+			if forwardTo.ServiceName == nil || forwardTo.BackendRef != nil {
+				return fmt.Errorf("rule %d: forwardTo backendRefs are not supported, only serviceName is", i)
+			}
+			if len(forwardTo.Filters) > 0 {
+				return fmt.Errorf("rule %d: forwardTo filters are not supported", i)
+			}
+		}
+	}
+	return nil
+}
+
+// getSvcApiRouteAdmittedCondition returns the Admitted condition reported in the route status for the gateway.
+func getSvcApiRouteAdmittedCondition(generation int64, routeListeners map[int]svcApiRouteListener, err error) *metav1.Condition {
+	condition := &metav1.Condition{
+		Type:               string(svcapiv1alpha1.ConditionRouteAdmitted),
+		Status:             metav1.ConditionTrue,
+		Reason:             "Admitted",
+		ObservedGeneration: generation,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidRoute"
+		condition.Message = err.Error()
+		return condition
+	}
+	if len(routeListeners) == 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "NoMatchingListener"
+		condition.Message = "No listener of the gateway accepts the route"
+		return condition
+	}
+
+	indexes := make([]int, 0, len(routeListeners))
+	for index := range routeListeners {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	var listeners []string
+	for _, index := range indexes {
+		listener := routeListeners[index].listener
+		listeners = append(listeners, fmt.Sprintf("%s/%d", listener.Protocol, listener.Port))
+	}
+	condition.Message = "Admitted by listeners " + strings.Join(listeners, ", ")
+	return condition
+}
+
+// getSvcApiListenersForRoute returns the listeners of the gateway that accept the route, keyed by the index of
+// the listener in the gateway.
+func getSvcApiListenersForRoute(gw *svcapiv1alpha1.Gateway, kind string, routeMeta metav1.ObjectMeta, routeHostnames []svcapiv1alpha1.Hostname) map[int]svcApiRouteListener {
+	routeListeners := make(map[int]svcApiRouteListener)
+	for i, listener := range gw.Spec.Listeners {
+		if !IsSvcApiL7Listener(listener) || !svcApiListenerSelectsRoute(gw, listener, kind, routeMeta) {
+			continue
+		}
+		if hostnames, ok := getSvcApiListenerHostnames(listener, routeHostnames); ok {
+			routeListeners[i] = svcApiRouteListener{listener: listener, hostnames: hostnames}
+		}
+	}
+	return routeListeners
+}
+
+// isSvcApiRouteForGateway returns true if the route is processed in the model of the gateway, which is when the
+// route allows the gateway, and either refers to the gateway explicitly or is selected by a listener of the gateway.
+func isSvcApiRouteForGateway(gw *svcapiv1alpha1.Gateway, kind string, routeMeta metav1.ObjectMeta, routeGateways svcapiv1alpha1.RouteGateways) bool {
+	selected, explicit := svcApiRouteSelectsGateway(routeMeta.Namespace, routeGateways, gw)
+	if !selected {
+		return false
+	}
+	if explicit {
+		return true
+	}
+	for _, listener := range gw.Spec.Listeners {
+		if lib.IsSvcApiRouteListener(listener) && svcApiListenerSelectsRoute(gw, listener, kind, routeMeta) {
+			return true
+		}
+	}
+	return false
+}
+
+// svcApiRouteSelectsGateway returns true if the gateways allowed by the route include the gateway, along with
+// whether the gateway is referred to explicitly in the gatewayRefs of the route.
+func svcApiRouteSelectsGateway(routeNamespace string, routeGateways svcapiv1alpha1.RouteGateways, gw *svcapiv1alpha1.Gateway) (bool, bool) {
+	switch routeGateways.Allow {
+	case svcapiv1alpha1.GatewayAllowAll:
+		return true, false
+	case svcapiv1alpha1.GatewayAllowFromList:
+		for _, gwRef := range routeGateways.GatewayRefs {
+			if gwRef.Name == gw.Name && gwRef.Namespace == gw.Namespace {
+				return true, true
+			}
+		}
+		return false, false
+	default:
+		return routeNamespace == gw.Namespace, false
+	}
+}
+
+// svcApiListenerSelectsRoute returns true if the route is of the kind of the routes of the listener, and is
+// selected by the namespaces and the label selector of the listener.
+func svcApiListenerSelectsRoute(gw *svcapiv1alpha1.Gateway, listener svcapiv1alpha1.Listener, kind string, routeMeta metav1.ObjectMeta) bool {
+	if listener.Routes.Kind != kind {
+		return false
+	}
+
+	from := svcapiv1alpha1.RouteSelectSame
+	if listener.Routes.Namespaces != nil && listener.Routes.Namespaces.From != "" {
+		from = listener.Routes.Namespaces.From
+	}
+	switch from {
+	case svcapiv1alpha1.RouteSelectAll:
+	case svcapiv1alpha1.RouteSelectSame:
+		if routeMeta.Namespace != gw.Namespace {
+			return false
+		}
+	case svcapiv1alpha1.RouteSelectSelector:
+		nsSelector, err := metav1.LabelSelectorAsSelector(&listener.Routes.Namespaces.Selector)
+		if err != nil || utils.GetInformers().NSInformer == nil {
+			return false
+		}
+		ns, err := utils.GetInformers().NSInformer.Lister().Get(routeMeta.Namespace)
+		if err != nil || !nsSelector.Matches(labels.Set(ns.Labels)) {
+			return false
+		}
+	default:
+		return false
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&listener.Routes.Selector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(routeMeta.Labels))
+}
+
+// getSvcApiListenerHostnames returns the hostnames of the route that are served by the listener, and false if
+// the listener does not serve the route. A listener hostname of the form *.example.com serves the hostnames
+// with a single label in place of the wildcard. Wildcard route hostnames are not supported.
+func getSvcApiListenerHostnames(listener svcapiv1alpha1.Listener, routeHostnames []svcapiv1alpha1.Hostname) ([]string, bool) {
+	var listenerHostname string
+	if listener.Hostname != nil {
+		listenerHostname = string(*listener.Hostname)
+	}
+	isWildcard := strings.HasPrefix(listenerHostname, "*.")
+
+	if len(routeHostnames) == 0 {
+		if listenerHostname == "" {
+			return nil, true
+		}
+		if isWildcard {
+			return nil, false
+		}
+		return []string{listenerHostname}, true
+	}
+
+	var hostnames []string
+	for _, routeHostname := range routeHostnames {
+		hostname := string(routeHostname)
+		if hostname == "" || strings.HasPrefix(hostname, "*") || utils.HasElem(hostnames, hostname) {
+			continue
+		}
+		if listenerHostname == "" || hostname == listenerHostname {
+			hostnames = append(hostnames, hostname)
+		} else if isWildcard && strings.HasSuffix(hostname, listenerHostname[1:]) {
+			if label := strings.TrimSuffix(hostname, listenerHostname[1:]); label != "" && !strings.Contains(label, ".") {
+				hostnames = append(hostnames, hostname)
+			}
+		}
+	}
+	return hostnames, len(hostnames) > 0
+}
+
+// sortSvcApiHTTPRules orders the http policy rules by precedence, since the first matching rule is applied.
+// The rules with hostnames come first, followed by the exact path matches, the longer paths and the rules
+// with more header matches. Rules with the same precedence keep the order of the routes and of the rules.
+func sortSvcApiHTTPRules(rules []AviHostPathPortPoolPG) {
+	sort.SliceStable(rules, func(i, j int) bool {
+		if (len(rules[i].Host) > 0) != (len(rules[j].Host) > 0) {
+			return len(rules[i].Host) > 0
+		}
+		if (rules[i].MatchCriteria == "EQUALS") != (rules[j].MatchCriteria == "EQUALS") {
+			return rules[i].MatchCriteria == "EQUALS"
+		}
+		if pathLenI, pathLenJ := len(strings.Join(rules[i].Path, "")), len(strings.Join(rules[j].Path, "")); pathLenI != pathLenJ {
+			return pathLenI > pathLenJ
+		}
+		return len(rules[i].HdrMatch) > len(rules[j].HdrMatch)
+	})
+}
+
+func getSortedKeys(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseServicesForHTTPRoute returns the services the HTTPRoute forwards to, as namespace/name.
+func parseServicesForHTTPRoute(route *svcapiv1alpha1.HTTPRoute, key string) []string {
+	var services []string
+	for _, rule := range route.Spec.Rules {
+		for _, forwardTo := range rule.ForwardTo {
+			if forwardTo.ServiceName == nil {
+				continue
+			}
+			service := route.Namespace + "/" + *forwardTo.ServiceName
+			if !utils.HasElem(services, service) {
+				services = append(services, service)
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: total services retrieved from httproute: %v", key, services)
+	return services
+}
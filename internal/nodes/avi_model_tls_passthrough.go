@@ -71,6 +71,10 @@ func (o *AviObjectGraph) BuildVSForPassthrough(vsName, namespace, hostname, key
 		vsVipNode.BGPPeerLabels = lib.GetGlobalBgpPeerLabels()
 	}
 
+	if lib.IsIPv6VipNetwork(vsVipNode.VipNetworks) {
+		setVsVipIPType(vsVipNode, getVipNetworkIPFamilies(vsVipNode.VipNetworks))
+	}
+
 	avi_vs_meta.VSVIPRefs = append(avi_vs_meta.VSVIPRefs, vsVipNode)
 	return avi_vs_meta
 }
@@ -79,17 +79,23 @@ func findRoutePrefix(nodeRoutes, aviRoutes []*models.StaticRoute, key string) bo
 }
 
 func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid int) ([]*models.StaticRoute, error) {
-	var nodeIP string
+	var nodeIP, nodeIPv6 string
 	var nodeRoutes []*models.StaticRoute
 
 	nodeAddrs := node.Status.Addresses
 	for _, addr := range nodeAddrs {
-		if addr.Type == "InternalIP" {
-			nodeIP = addr.Address
-			break
+		if addr.Type != "InternalIP" {
+			continue
+		}
+		if utils.IsV4(addr.Address) {
+			if nodeIP == "" {
+				nodeIP = addr.Address
+			}
+		} else if nodeIPv6 == "" {
+			nodeIPv6 = addr.Address
 		}
 	}
-	if nodeIP == "" {
+	if nodeIP == "" && nodeIPv6 == "" {
 		utils.AviLog.Errorf("Error in fetching nodeIP for %v", node.ObjectMeta.Name)
 		return nil, errors.New("nodeip not found")
 	}
@@ -99,7 +105,6 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 		utils.AviLog.Errorf("Error in fetching Pod CIDR for %v", node.ObjectMeta.Name)
 		return nil, errors.New("podcidr not found")
 	}
-	nodeipType := "V4"
 
 	for _, podCIDR := range podCIDRs {
 		s := strings.Split(podCIDR, "/")
@@ -108,6 +113,17 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 			return nil, errors.New("wrong podcidr")
 		}
 
+		// the static route for a v6 pod CIDR is routed via the v6 address of the node.
+		prefixipType, nextHop := "V4", nodeIP
+		if !utils.IsV4(s[0]) {
+			prefixipType, nextHop = "V6", nodeIPv6
+		}
+		if nextHop == "" {
+			utils.AviLog.Warnf("No %s nodeIP found for %v, skipping the static route for Pod CIDR %s", prefixipType, node.ObjectMeta.Name, podCIDR)
+			continue
+		}
+		nodeipType := prefixipType
+
 		m, err := strconv.Atoi(s[1])
 		if err != nil {
 			utils.AviLog.Errorf("Error in getting mask %v", err)
@@ -116,7 +132,6 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 
 		clusterName := lib.GetClusterName()
 		labels := lib.GetLabels()
-		prefixipType := "V4"
 		mask := int32(m)
 		routeIDString := clusterName + "-" + strconv.Itoa(routeid)
 		nodeRoute := models.StaticRoute{
@@ -129,7 +144,7 @@ func (o *AviObjectGraph) addRouteForNode(node *v1.Node, vrfName string, routeid
 				Mask: &mask,
 			},
 			NextHop: &models.IPAddr{
-				Addr: &nodeIP,
+				Addr: &nextHop,
 				Type: &nodeipType,
 			},
 			Labels: labels,
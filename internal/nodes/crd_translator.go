@@ -17,13 +17,17 @@ package nodes
 import (
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	networkingv1 "k8s.io/api/networking/v1"
 )
 
 func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniModel) {
@@ -50,6 +54,12 @@ func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniMod
 		}
 	}
 
+	if deleteCase {
+		updateHostRuleAppliedFqdn(host, "", key)
+	} else {
+		updateHostRuleAppliedFqdn(host, hrNamespaceName, key)
+	}
+
 	// host specific
 	var vsWafPolicy, vsAppProfile, vsSslKeyCertificate, vsErrorPageProfile, vsAnalyticsProfile, vsSslProfile string
 	var vsEnabled *bool
@@ -58,6 +68,8 @@ func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniMod
 	// Initializing the values of vsHTTPPolicySets and vsDatascripts, using a nil value would impact the value of VS checksum
 	vsHTTPPolicySets := []string{}
 	vsDatascripts := []string{}
+	var vsSourceRanges []string
+	var vsClientCertProfile *AviClientCertProfileNode
 
 	if !deleteCase {
 		if hostrule.Spec.VirtualHost.TLS.SSLKeyCertificate.Name != "" {
@@ -96,6 +108,8 @@ func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniMod
 			vsNode.SetHttpPolicyRefs([]*AviHttpPolicySetNode{})
 		}
 
+		vsSourceRanges = getValidSourceRanges(key, hostrule.Spec.VirtualHost.SourceRanges)
+
 		for _, script := range hostrule.Spec.VirtualHost.Datascripts {
 			if !utils.HasElem(vsDatascripts, fmt.Sprintf("/api/vsdatascriptset?name=%s", script)) {
 				vsDatascripts = append(vsDatascripts, fmt.Sprintf("/api/vsdatascriptset?name=%s", script))
@@ -103,6 +117,19 @@ func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniMod
 		}
 
 		vsEnabled = hostrule.Spec.VirtualHost.EnableVirtualHost
+
+		// client certificates are validated only on the virtualhosts terminating TLS
+		isSecure := vsSslKeyCertificate != "" || len(vsNode.GetSSLKeyCertRefs()) > 0
+		if hostrule.Spec.VirtualHost.TLS.ClientCertificate.CASecret != "" && isSecure {
+			vsClientCertProfile = buildClientCertProfile(vsNode, hostrule, key)
+			if vsClientCertProfile != nil {
+				vsAppProfile = fmt.Sprintf("/api/applicationprofile?name=%s", vsClientCertProfile.Name)
+			} else {
+				// disable the virtualhost instead of accepting clients without validating their certificates
+				vsEnabled = new(bool)
+			}
+		}
+
 		crdStatus = cache.CRDMetadata{
 			Type:   "HostRule",
 			Value:  hostrule.Namespace + "/" + hostrule.Name,
@@ -124,6 +151,8 @@ func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniMod
 	vsNode.SetSSLProfileRef(vsSslProfile)
 	vsNode.SetVsDatascriptRefs(vsDatascripts)
 	vsNode.SetEnabled(vsEnabled)
+	vsNode.SetClientCertProfile(vsClientCertProfile)
+	buildSourceRangesPolicy(vsNode, vsSourceRanges)
 
 	serviceMetadataObj := vsNode.GetServiceMetadata()
 	serviceMetadataObj.CRDStatus = crdStatus
@@ -132,6 +161,97 @@ func BuildL7HostRule(host, namespace, ingName, key string, vsNode AviVsEvhSniMod
 	utils.AviLog.Infof("key: %s, Attached hostrule %s on vsNode %s", key, hrNamespaceName, vsNode.GetName())
 }
 
+// updateHostRuleAppliedFqdn records the hostrule applied on the host, and publishes the status of the hostrules
+// whose applied fqdns change. An empty hostrule removes the host from the hostrule it was applied on.
+func updateHostRuleAppliedFqdn(host, hrNamespaceName, key string) {
+	oldHrNamespaceName := objects.SharedCRDLister().UpdateAppliedFqdnHostruleMapping(host, hrNamespaceName)
+	if oldHrNamespaceName == hrNamespaceName {
+		return
+	}
+	for _, hr := range []string{oldHrNamespaceName, hrNamespaceName} {
+		if hr == "" {
+			continue
+		}
+		hrNSName := strings.Split(hr, "/")
+		status.PublishToStatusQueue(hr, status.StatusOptions{
+			ObjType:   lib.HostRule,
+			Op:        lib.UpdateStatus,
+			Namespace: hrNSName[0],
+			ObjName:   hrNSName[1],
+			Key:       key,
+		})
+	}
+}
+
+// buildClientCertProfile builds the application profile which validates the client certificates with the CA certificates
+// in the Secret of the HostRule, nil is returned when the CA certificates are not available.
+func buildClientCertProfile(vsNode AviVsEvhSniModel, hostrule *akov1alpha1.HostRule, key string) *AviClientCertProfileNode {
+	clientCert := hostrule.Spec.VirtualHost.TLS.ClientCertificate
+	if utils.GetInformers().SecretInformer == nil {
+		utils.AviLog.Warnf("key: %s, msg: secret informer is not available to read the CA secret %s/%s", key, hostrule.Namespace, clientCert.CASecret)
+		return nil
+	}
+	secret, err := utils.GetInformers().SecretInformer.Lister().Secrets(hostrule.Namespace).Get(clientCert.CASecret)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to get the CA secret %s/%s: %v", key, hostrule.Namespace, clientCert.CASecret, err)
+		return nil
+	}
+	caCert := secret.Data[lib.ClientCACertKey]
+	if len(caCert) == 0 {
+		utils.AviLog.Warnf("key: %s, msg: no %s found in the CA secret %s/%s", key, lib.ClientCACertKey, hostrule.Namespace, clientCert.CASecret)
+		return nil
+	}
+
+	mode := lib.SSL_CLIENT_CERTIFICATE_REQUIRE
+	if clientCert.Mode == lib.ClientCertModeRequest {
+		mode = lib.SSL_CLIENT_CERTIFICATE_REQUEST
+	}
+	clientCertProfile := &AviClientCertProfileNode{
+		Name:     vsNode.GetName(),
+		Tenant:   lib.GetTenant(),
+		Mode:     mode,
+		DNHeader: clientCert.ForwardDNHeader,
+		PkiProfile: &AviPkiProfileNode{
+			Name:       vsNode.GetName(),
+			Tenant:     lib.GetTenant(),
+			CACert:     string(caCert),
+			AviMarkers: vsNode.GetAviMarkers(),
+		},
+		AviMarkers: vsNode.GetAviMarkers(),
+	}
+	clientCertProfile.CalculateCheckSum()
+	return clientCertProfile
+}
+
+// buildSourceRangesPolicy replaces the AKO created httppolicyset which closes the connections from
+// clients outside of the source ranges of the HostRule, the policy is removed when there are no source ranges.
+func buildSourceRangesPolicy(vsNode AviVsEvhSniModel, sourceRanges []string) {
+	policyName := lib.GetSourceRangesPolicy(vsNode.GetName())
+	var httpPolicyRefs []*AviHttpPolicySetNode
+	for _, policy := range vsNode.GetHttpPolicyRefs() {
+		if policy.Name != policyName {
+			httpPolicyRefs = append(httpPolicyRefs, policy)
+		}
+	}
+
+	if len(sourceRanges) > 0 {
+		securityPolicy := &AviHttpPolicySetNode{
+			Name:   policyName,
+			Tenant: lib.GetTenant(),
+			SecurityRules: []AviHTTPSecurity{{
+				Action:        lib.CLOSE_CONNECTION,
+				MatchCriteria: lib.IS_NOT_IN,
+				Enable:        true,
+				SourceRanges:  sourceRanges,
+			}},
+			AviMarkers: vsNode.GetAviMarkers(),
+		}
+		securityPolicy.CalculateCheckSum()
+		httpPolicyRefs = append(httpPolicyRefs, securityPolicy)
+	}
+	vsNode.SetHttpPolicyRefs(httpPolicyRefs)
+}
+
 // BuildPoolHTTPRule notes
 // when we get an ingress update and we are building the corresponding pools of that ingress
 // we need to get all httprules which match ingress's host/path
@@ -239,6 +359,7 @@ func BuildPoolHTTPRule(host, path, ingName, namespace, key string, vsNode AviVsE
 				pool.PkiProfile = destinationCertNode
 				pool.HealthMonitors = pathHMs
 				pool.ApplicationPersistence = persistenceProfile
+				pool.MaxConcurrentConns = httpRulePath.MaxConcurrentConnectionsPerServer
 
 				// from this path, generate refs to this pool node
 				pool.LbAlgorithm = httpRulePath.LoadBalancerPolicy.Algorithm
@@ -266,3 +387,197 @@ func BuildPoolHTTPRule(host, path, ingName, namespace, key string, vsNode AviVsE
 
 	return
 }
+
+// BuildL7HTTPRulePolicy replaces the AKO created httppolicyset which modifies the headers, rewrites the path,
+// redirects or limits the rate of the requests for the HTTPRule paths of the host, the policy is removed when
+// no path has such settings.
+func BuildL7HTTPRulePolicy(host, key string, vsNode AviVsEvhSniModel) {
+	policyName := lib.GetHTTPRulePolicy(vsNode.GetName())
+	var httpPolicyRefs []*AviHttpPolicySetNode
+	for _, policy := range vsNode.GetHttpPolicyRefs() {
+		if policy.Name != policyName {
+			httpPolicyRefs = append(httpPolicyRefs, policy)
+		}
+	}
+
+	var requestRules []AviHostPathPortPoolPG
+	var responseRules []AviHTTPResponseRule
+	var securityRules []AviHTTPSecurity
+	var stringGroups []*AviStringGroupNode
+	if found, pathRules := objects.SharedCRDLister().GetFqdnHTTPRulesMapping(host); found {
+		// the longer targets are matched first, so that the most specific target applies on a request
+		targets := make([]string, 0, len(pathRules))
+		for target := range pathRules {
+			targets = append(targets, target)
+		}
+		sort.Slice(targets, func(i, j int) bool {
+			if len(targets[i]) != len(targets[j]) {
+				return len(targets[i]) > len(targets[j])
+			}
+			return targets[i] < targets[j]
+		})
+
+		isSecure := vsNode.GetSSLKeyCertAviRef() != "" || len(vsNode.GetSSLKeyCertRefs()) > 0
+		for _, target := range targets {
+			httpRulePath := getHTTPRulePath(pathRules[target], target, key)
+			if httpRulePath == nil {
+				continue
+			}
+			requestRules = append(requestRules, buildHTTPRuleRequestRules(*httpRulePath, isSecure)...)
+			responseRules = append(responseRules, buildHTTPRuleResponseRules(*httpRulePath)...)
+			if rateLimitRule, stringGroup := buildHTTPRuleRateLimitRule(*httpRulePath, policyName, isSecure, vsNode.GetAviMarkers()); rateLimitRule != nil {
+				securityRules = append(securityRules, *rateLimitRule)
+				stringGroups = append(stringGroups, stringGroup)
+			}
+		}
+	}
+
+	if len(requestRules) > 0 || len(responseRules) > 0 || len(securityRules) > 0 {
+		httpRulePolicy := &AviHttpPolicySetNode{
+			Name:            policyName,
+			Tenant:          lib.GetTenant(),
+			HppMap:          requestRules,
+			ResponseRules:   responseRules,
+			SecurityRules:   securityRules,
+			StringGroupRefs: stringGroups,
+			AviMarkers:      vsNode.GetAviMarkers(),
+		}
+		httpRulePolicy.CalculateCheckSum()
+		httpPolicyRefs = append(httpPolicyRefs, httpRulePolicy)
+		utils.AviLog.Infof("key: %s, msg: Attached httprule policy %s on vsNode %s", key, policyName, vsNode.GetName())
+	}
+	vsNode.SetHttpPolicyRefs(httpPolicyRefs)
+}
+
+// getHTTPRulePath returns the path settings of the target in the HTTPRule, nil is returned for a rejected HTTPRule.
+func getHTTPRulePath(rule, target, key string) *akov1alpha1.HTTPRulePaths {
+	ruleNSName := strings.Split(rule, "/")
+	httpRuleObj, err := lib.GetCRDInformers().HTTPRuleInformer.Lister().HTTPRules(ruleNSName[0]).Get(ruleNSName[1])
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: httprule not found err: %+v", key, err)
+		return nil
+	} else if httpRuleObj.Status.Status == lib.StatusRejected {
+		return nil
+	}
+	for i := range httpRuleObj.Spec.Paths {
+		if httpRuleObj.Spec.Paths[i].Target == target {
+			return &httpRuleObj.Spec.Paths[i]
+		}
+	}
+	return nil
+}
+
+func getHTTPRuleHdrActions(headers akov1alpha1.HTTPRuleHeaders) []AviHostPathHdrAction {
+	var hdrActions []AviHostPathHdrAction
+	for _, header := range headers.Add {
+		hdrActions = append(hdrActions, AviHostPathHdrAction{Action: "HTTP_ADD_HDR", Name: header.Name, Value: header.Value})
+	}
+	for _, header := range headers.Replace {
+		hdrActions = append(hdrActions, AviHostPathHdrAction{Action: "HTTP_REPLACE_HDR", Name: header.Name, Value: header.Value})
+	}
+	for _, name := range headers.Remove {
+		hdrActions = append(hdrActions, AviHostPathHdrAction{Action: "HTTP_REMOVE_HDR", Name: name})
+	}
+	return hdrActions
+}
+
+// buildHTTPRuleRequestRules returns the request rules which modify the request headers, rewrite the path or
+// redirect the requests of the path. The target is matched element wise, as a Prefix path of an ingress.
+func buildHTTPRuleRequestRules(httpRulePath akov1alpha1.HTTPRulePaths, isSecure bool) []AviHostPathPortPoolPG {
+	httpPGPath := AviHostPathPortPoolPG{HdrAction: getHTTPRuleHdrActions(httpRulePath.RequestHeaders)}
+	if httpRulePath.Redirect != nil {
+		httpPGPath.Redirect = buildHTTPRuleRedirect(httpRulePath.Redirect, isSecure)
+	} else if httpRulePath.Rewrite.Prefix != "" {
+		httpPGPath.RewritePath = &AviHostPathRewrite{
+			StripPrefix: strings.TrimSuffix(httpRulePath.Target, "/"),
+			Prefix:      httpRulePath.Rewrite.Prefix,
+		}
+	} else if len(httpPGPath.HdrAction) == 0 {
+		return nil
+	}
+	return getHTTPPathMatches(httpPGPath, httpRulePath.Target, networkingv1.PathTypePrefix)
+}
+
+// buildHTTPRuleRedirect returns the redirect of a HTTPRule path, the protocol of the host is retained by default.
+func buildHTTPRuleRedirect(redirect *akov1alpha1.HTTPRuleRedirect, isSecure bool) *AviHostPathRedirect {
+	redirectNode := &AviHostPathRedirect{
+		Protocol:   redirect.Protocol,
+		Host:       redirect.Host,
+		Port:       redirect.Port,
+		Path:       redirect.Path,
+		StatusCode: lib.STATUS_REDIRECT,
+	}
+	if redirectNode.Protocol == "" {
+		redirectNode.Protocol = "HTTP"
+		if isSecure {
+			redirectNode.Protocol = "HTTPS"
+		}
+	}
+	if redirect.StatusCode != 0 {
+		redirectNode.StatusCode = fmt.Sprintf("HTTP_REDIRECT_STATUS_CODE_%d", redirect.StatusCode)
+	}
+	return redirectNode
+}
+
+// buildHTTPRuleRateLimitRule returns the security rule which limits the rate of the requests of the path, along with
+// the stringgroup holding the path regex it matches. The target is matched as a Prefix path, so that the rate of /foo
+// and /foo/bar is limited, but not the rate of /foobar. A single rule is used for the target, since the rate limiter
+// of Avi counts the requests per security rule.
+func buildHTTPRuleRateLimitRule(httpRulePath akov1alpha1.HTTPRulePaths, policyName string, isSecure bool, aviMarkers utils.AviObjectMarkers) (*AviHTTPSecurity, *AviStringGroupNode) {
+	rateLimit := httpRulePath.RateLimit
+	if rateLimit == nil {
+		return nil, nil
+	}
+	rateLimitNode := &AviRateLimit{
+		Count:       rateLimit.Count,
+		Period:      rateLimit.Period,
+		BurstSize:   rateLimit.Burst,
+		PerClientIP: rateLimit.PerClientIP,
+	}
+	if rateLimitNode.Period == 0 {
+		rateLimitNode.Period = 1
+	}
+	switch rateLimit.Action {
+	case lib.RateLimitActionDrop:
+		rateLimitNode.Action = lib.RL_ACTION_DROP_CONN
+	case lib.RateLimitActionRedirect:
+		rateLimitNode.Action = lib.RL_ACTION_REDIRECT
+		rateLimitNode.Redirect = buildHTTPRuleRedirect(rateLimit.Redirect, isSecure)
+	default:
+		rateLimitNode.Action = lib.RL_ACTION_LOCAL_RSP
+		rateLimitNode.StatusCode = lib.STATUS_TOO_MANY_REQUESTS
+	}
+	// The trailing slash is ignored for Prefix paths, /foo/ matches /foo as well.
+	prefix := strings.TrimSuffix(httpRulePath.Target, "/")
+	stringGroup := &AviStringGroupNode{
+		Name:       lib.GetHTTPRuleStringGroupName(policyName, httpRulePath.Target),
+		Tenant:     lib.GetTenant(),
+		Values:     []string{"^" + regexp.QuoteMeta(prefix) + "(/|$)"},
+		AviMarkers: aviMarkers,
+	}
+	rateLimitRule := &AviHTTPSecurity{
+		Action:        lib.RATE_LIMIT,
+		MatchCriteria: lib.REGEX_MATCH,
+		Enable:        true,
+		StringGroup:   stringGroup.Name,
+		RateLimit:     rateLimitNode,
+	}
+	return rateLimitRule, stringGroup
+}
+
+// buildHTTPRuleResponseRules returns the response rules which modify the response headers of the requests of the path.
+func buildHTTPRuleResponseRules(httpRulePath akov1alpha1.HTTPRulePaths) []AviHTTPResponseRule {
+	hdrActions := getHTTPRuleHdrActions(httpRulePath.ResponseHeaders)
+	if len(hdrActions) == 0 {
+		return nil
+	}
+	var responseRules []AviHTTPResponseRule
+	for _, pathMatch := range getHTTPPathMatches(AviHostPathPortPoolPG{}, httpRulePath.Target, networkingv1.PathTypePrefix) {
+		responseRules = append(responseRules, AviHTTPResponseRule{
+			Path:          pathMatch.Path,
+			MatchCriteria: pathMatch.MatchCriteria,
+			HdrAction:     hdrActions,
+		})
+	}
+	return responseRules
+}
@@ -33,7 +33,8 @@ func DequeueIngestion(key string, fullsync bool) {
 	// The assumption is that an update either affects an LB service type or an ingress. It cannot be both.
 	var ingressFound, routeFound bool
 	var ingressNames, routeNames []string
-	utils.AviLog.Infof("key: %s, msg: starting graph Sync", key)
+	log := lib.IngestionLogger(key)
+	log.Infof("key: %s, msg: starting graph Sync", key)
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 
 	objType, namespace, name := lib.ExtractTypeNameNamespace(key)
@@ -54,7 +55,7 @@ func DequeueIngestion(key string, fullsync bool) {
 	// if we get update for object of type k8s node, create vrf graph
 	// if in NodePort Mode we update pool servers
 	if objType == utils.NodeObj {
-		utils.AviLog.Debugf("key: %s, msg: processing node obj", key)
+		log.Debugf("key: %s, msg: processing node obj", key)
 		processNodeObj(key, name, sharedQueue, fullsync)
 		if lib.IsNodePortMode() && !fullsync {
 			svcl4Keys, svcl7Keys := lib.GetSvcKeysForNodeCRUD()
@@ -88,7 +89,7 @@ func DequeueIngestion(key string, fullsync bool) {
 		// This service is found in the LB list - this means it's a transition from LB to clusterIP or NodePort.
 		if found {
 			objects.SharedlbLister().Delete(namespace + "/" + name)
-			utils.AviLog.Infof("key: %s, msg: service transitioned from type loadbalancer to ClusterIP or NodePort, will delete model", name)
+			log.Infof("key: %s, msg: service transitioned from type loadbalancer to ClusterIP or NodePort, will delete model", name)
 			model_name := lib.GetModelName(lib.GetTenant(), lib.Encode(lib.GetNamePrefix()+namespace+"-"+name, lib.L4VS))
 			objects.SharedAviGraphLister().Save(model_name, nil)
 			if !fullsync {
@@ -111,6 +112,16 @@ func DequeueIngestion(key string, fullsync bool) {
 		}
 	}
 
+	// Push Services referring to the L4Rule via annotation.
+	if objType == lib.L4Rule && !lib.GetAdvancedL4() && !lib.UseServicesAPI() {
+		svcNames, svcFound := schema.GetParentServices(name, namespace, key)
+		if svcFound && utils.CheckIfNamespaceAccepted(namespace) {
+			for _, svcNSNameKey := range svcNames {
+				handleL4Service(utils.L4LBService+"/"+svcNSNameKey, fullsync)
+			}
+		}
+	}
+
 	if !ingressFound && !lib.GetAdvancedL4() && !lib.UseServicesAPI() {
 		// If ingress is not found, let's do the other checks.
 		if objType == utils.L4LBService {
@@ -119,7 +130,7 @@ func DequeueIngestion(key string, fullsync bool) {
 		} else if objType == utils.Endpoints {
 			svcObj, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(name)
 			if err != nil {
-				utils.AviLog.Debugf("key: %s, msg: there was an error in retrieving the service for endpoint", key)
+				log.Debugf("key: %s, msg: there was an error in retrieving the service for endpoint", key)
 				return
 			}
 			//Do not handle service update if it belongs to unaccepted namespace
@@ -142,7 +153,9 @@ func DequeueIngestion(key string, fullsync bool) {
 
 	// handle the services APIs
 	if lib.GetAdvancedL4() || lib.UseServicesAPI() &&
-		(objType == utils.L4LBService || objType == lib.Gateway || objType == lib.GatewayClass || objType == utils.Endpoints || objType == lib.AviInfraSetting) {
+		(objType == utils.L4LBService || objType == lib.Gateway || objType == lib.GatewayClass || objType == utils.Endpoints || objType == lib.AviInfraSetting ||
+			objType == utils.Service || objType == utils.Secret || objType == lib.HTTPRoute ||
+			objType == lib.TCPRoute || objType == lib.UDPRoute || objType == lib.TLSRoute) {
 		if !valid && objType == utils.L4LBService {
 			schema, _ = ConfigDescriptor().GetByType(utils.Service)
 		}
@@ -164,7 +177,11 @@ func DequeueIngestion(key string, fullsync bool) {
 					}
 				} else {
 					aviModelGraph := NewAviObjectGraph()
-					aviModelGraph.BuildAdvancedL4Graph(namespace, gwName, key)
+					if isSvcApiL7Gateway(namespace, gwName) {
+						aviModelGraph.BuildSvcApiL7Graph(namespace, gwName, key)
+					} else {
+						aviModelGraph.BuildAdvancedL4Graph(namespace, gwName, key)
+					}
 					ok := saveAviModel(modelName, aviModelGraph, key)
 					if ok && len(aviModelGraph.GetOrderedNodes()) != 0 && !fullsync {
 						PublishKeyToRestLayer(modelName, key, sharedQueue)
@@ -286,6 +303,15 @@ func isGatewayDelete(gatewayKey, key string) bool {
 	return false
 }
 
+// isSvcApiL7Gateway returns true if the gateway selects HTTPRoutes, in which case a L7 model is built for the gateway.
+func isSvcApiL7Gateway(namespace, gwName string) bool {
+	if !lib.UseServicesAPI() {
+		return false
+	}
+	gateway, err := lib.GetSvcAPIInformers().GatewayInformer.Lister().Gateways(namespace).Get(gwName)
+	return err == nil && lib.IsSvcApiL7Gateway(gateway)
+}
+
 func handleRoute(key string, fullsync bool, routeNames []string) {
 	objType, namespace, _ := lib.ExtractTypeNameNamespace(key)
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
@@ -384,20 +410,21 @@ func getIngressNSNameForIngestion(objType, namespace, nsname string) (string, st
 }
 
 func saveAviModel(model_name string, aviGraph *AviObjectGraph, key string) bool {
-	utils.AviLog.Debugf("key: %s, msg: Evaluating model :%s", key, model_name)
+	log := lib.ModelLogger(model_name, utils.GraphLayer)
+	log.Debugf("key: %s, msg: Evaluating model :%s", key, model_name)
 	if lib.DisableSync {
 		// Note: This is not thread safe, however locking is expensive and the condition for locking should happen rarely
-		utils.AviLog.Infof("key: %s, msg: Disable Sync is True, model %s can not be saved", key, model_name)
+		log.Infof("key: %s, msg: Disable Sync is True, model %s can not be saved", key, model_name)
 		return false
 	}
 	found, aviModel := objects.SharedAviGraphLister().Get(model_name)
 	if found && aviModel != nil {
 		prevChecksum := aviModel.(*AviObjectGraph).GraphChecksum
-		utils.AviLog.Debugf("key: %s, msg: the model: %s has a previous checksum: %v", key, model_name, prevChecksum)
+		log.Debugf("key: %s, msg: the model: %s has a previous checksum: %v", key, model_name, prevChecksum)
 		presentChecksum := aviGraph.GetCheckSum()
-		utils.AviLog.Debugf("key: %s, msg: the model: %s has a present checksum: %v", key, model_name, presentChecksum)
+		log.Debugf("key: %s, msg: the model: %s has a present checksum: %v", key, model_name, presentChecksum)
 		if prevChecksum == presentChecksum {
-			utils.AviLog.Debugf("key: %s, msg: The model: %s has identical checksums, hence not processing. Checksum value: %v", key, model_name, presentChecksum)
+			log.Debugf("key: %s, msg: The model: %s has identical checksums, hence not processing. Checksum value: %v", key, model_name, presentChecksum)
 			return false
 		}
 	}
@@ -442,8 +469,9 @@ func processNodeObj(key, nodename string, sharedQueue *utils.WorkerQueue, fullsy
 
 func PublishKeyToRestLayer(modelName string, key string, sharedQueue *utils.WorkerQueue) {
 	bkt := utils.Bkt(modelName, sharedQueue.NumWorkers)
+	utils.TraceEnqueue(sharedQueue.WorkqueueName, modelName, utils.GetActiveSpan(utils.ObjectIngestionLayer, key))
 	sharedQueue.Workqueue[bkt].AddRateLimited(modelName)
-	utils.AviLog.Infof("key: %s, msg: Published key with modelName: %s", key, modelName)
+	lib.ModelLogger(modelName, utils.GraphLayer).Infof("key: %s, msg: Published key with modelName: %s", key, modelName)
 
 }
 
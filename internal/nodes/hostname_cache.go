@@ -59,6 +59,12 @@ func (a *HostNameLister) Delete(hostname string) {
 	a.secureHostNameStore.Delete(hostname)
 }
 
+// Reset removes the secure hostname mappings, when the models of the L7 virtualservices are removed to be
+// rebuilt with a changed shard layout. The hostname path mappings are kept, as they only depend on the ingresses.
+func (a *HostNameLister) Reset() {
+	a.secureHostNameStore.DeleteAll()
+}
+
 // thread safe for namespace based sharding in case of same hostname in different namespaces
 // cache sample: foo.com -> {path1: [ns1/ingress1], path2: [ns2/ingress2]}
 type HostNamePathStore struct {
@@ -123,6 +129,17 @@ func (h *HostNamePathStore) RemoveHostPathStore(host, path string, ing string) {
 	}
 }
 
+// GetHostsMatching returns the hosts in the store for which matches returns true.
+func (h *HostNamePathStore) GetHostsMatching(matches func(host string) bool) []string {
+	var hosts []string
+	for host := range h.hostNamePathStore.CopyAllObjects() {
+		if matches(host) {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
 func (h *HostNamePathStore) DeleteHostPathStore(host string) {
 	h.hostNamePathStore.Delete(host)
 }
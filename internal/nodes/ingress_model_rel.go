@@ -23,15 +23,17 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	routev1 "github.com/openshift/api/route/v1"
 	advl4v1alpha1pre1 "github.com/vmware-tanzu/service-apis/apis/v1alpha1pre1"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	servicesapi "sigs.k8s.io/service-apis/apis/v1alpha1"
 	svcapiv1alpha1 "sigs.k8s.io/service-apis/apis/v1alpha1"
 )
@@ -94,6 +96,22 @@ var (
 		Type:              "GatewayClass",
 		GetParentGateways: GWClassToGateway,
 	}
+	HTTPRoute = GraphSchema{
+		Type:              lib.HTTPRoute,
+		GetParentGateways: HTTPRouteToGateway,
+	}
+	TCPRoute = GraphSchema{
+		Type:              lib.TCPRoute,
+		GetParentGateways: TCPRouteToGateway,
+	}
+	UDPRoute = GraphSchema{
+		Type:              lib.UDPRoute,
+		GetParentGateways: UDPRouteToGateway,
+	}
+	TLSRoute = GraphSchema{
+		Type:              lib.TLSRoute,
+		GetParentGateways: TLSRouteToGateway,
+	}
 	AviInfraSetting = GraphSchema{
 		Type:               "AviInfraSetting",
 		GetParentIngresses: AviSettingToIng,
@@ -101,6 +119,10 @@ var (
 		GetParentServices:  AviSettingToSvc,
 		GetParentRoutes:    AviSettingToRoute,
 	}
+	L4Rule = GraphSchema{
+		Type:              "L4Rule",
+		GetParentServices: L4RuleToSvc,
+	}
 	SupportedGraphTypes = GraphDescriptor{
 		Ingress,
 		IngressClass,
@@ -114,7 +136,12 @@ var (
 		HTTPRule,
 		Gateway,
 		GatewayClass,
+		HTTPRoute,
+		TCPRoute,
+		UDPRoute,
+		TLSRoute,
 		AviInfraSetting,
+		L4Rule,
 	}
 )
 
@@ -197,7 +224,9 @@ func SvcToGateway(svcName string, namespace string, key string) ([]string, bool)
 			}
 		}
 
-		if gateway, svcPortProtocols := ParseL4ServiceForGateway(myService, key); gateway != "" {
+		// With services API, only the services of type LoadBalancer are bound to the gateways using labels.
+		if gateway, svcPortProtocols := ParseL4ServiceForGateway(myService, key); gateway != "" &&
+			(!lib.UseServicesAPI() || myService.Spec.Type == corev1.ServiceTypeLoadBalancer) {
 			_, svcListeners := objects.ServiceGWLister().GetGwToSvcs(gateway)
 			newSvcListeners := svcListeners
 			for _, portProto := range svcPortProtocols {
@@ -222,6 +251,19 @@ func SvcToGateway(svcName string, namespace string, key string) ([]string, bool)
 		}
 	}
 
+	// The gateways of the routes that forward to the service.
+	if lib.UseServicesAPI() {
+		_, routes := objects.ServiceGWLister().GetSvcToRoutes(svcNSName)
+		for _, route := range routes {
+			_, gateways := objects.ServiceGWLister().GetRouteToGateways(route)
+			for _, gateway := range gateways {
+				if !utils.HasElem(allGateways, gateway) {
+					allGateways = append(allGateways, gateway)
+				}
+			}
+		}
+	}
+
 	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, allGateways)
 	return allGateways, true
 }
@@ -262,6 +304,7 @@ func GatewayChanges(gwName string, namespace string, key string) ([]string, bool
 			// Remove all the Gateway to Services mapping.
 			objects.ServiceGWLister().DeleteGWListeners(namespace + "/" + gwName)
 			objects.ServiceGWLister().RemoveGatewayGWclassMappings(namespace + "/" + gwName)
+			updateSvcApiGatewayRouteMappings(namespace, gwName, nil, key)
 		} else {
 			if gwListeners := parseSvcApiGatewayForListeners(gateway, key); len(gwListeners) > 0 {
 				objects.ServiceGWLister().UpdateGWListeners(namespace+"/"+gwName, gwListeners)
@@ -270,11 +313,260 @@ func GatewayChanges(gwName string, namespace string, key string) ([]string, bool
 				objects.ServiceGWLister().RemoveGatewayGWclassMappings(namespace + "/" + gwName)
 				objects.ServiceGWLister().DeleteGWListeners(namespace + "/" + gwName)
 			}
+			updateSvcApiGatewayRouteMappings(namespace, gwName, getSvcApiRoutesForGateway(gateway, key), key)
+		}
+	}
+	return allGateways, true
+}
+
+func HTTPRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return svcApiRouteToGateway(lib.HTTPRoute, routeName, namespace, key)
+}
+
+func TCPRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return svcApiRouteToGateway(lib.TCPRoute, routeName, namespace, key)
+}
+
+func UDPRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return svcApiRouteToGateway(lib.UDPRoute, routeName, namespace, key)
+}
+
+func TLSRouteToGateway(routeName string, namespace string, key string) ([]string, bool) {
+	return svcApiRouteToGateway(lib.TLSRoute, routeName, namespace, key)
+}
+
+// svcApiRouteToGateway updates the gateway and service mappings of the route of the kind. The gateways the route
+// was bound to are returned along with the gateways the route is now bound to. HTTPRoutes are bound to the L7
+// gateways, and the other kinds of routes to the L4 gateways.
+func svcApiRouteToGateway(kind, routeName, namespace, key string) ([]string, bool) {
+	routeKey := kind + "/" + namespace + "/" + routeName
+	_, oldGateways := objects.ServiceGWLister().GetRouteToGateways(routeKey)
+	allGateways := append([]string{}, oldGateways...)
+
+	route, err := getSvcApiRoute(kind, namespace, routeName)
+	if err != nil {
+		if k8serrors.IsNotFound(err) {
+			objects.ServiceGWLister().DeleteRouteMappings(routeKey)
+		}
+		utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, allGateways)
+		return allGateways, true
+	}
+	if !utils.CheckIfNamespaceAccepted(namespace) {
+		// The routes in the namespaces which are not accepted are not bound to the gateways.
+		objects.ServiceGWLister().DeleteRouteMappings(routeKey)
+		utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, allGateways)
+		return allGateways, true
+	}
+
+	var gateways []string
+	gwObjs, err := lib.GetSvcAPIInformers().GatewayInformer.Lister().List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the gateways: %v", key, err)
+		return allGateways, true
+	}
+	for _, gw := range gwObjs {
+		if utils.CheckIfNamespaceAccepted(gw.Namespace) && lib.IsSvcApiL7Gateway(gw) == (kind == lib.HTTPRoute) &&
+			isSvcApiRouteForGateway(gw, kind, route.meta, route.gateways) {
+			gateways = append(gateways, gw.Namespace+"/"+gw.Name)
+		}
+	}
+	objects.ServiceGWLister().UpdateRouteGatewayMappings(routeKey, gateways)
+	objects.ServiceGWLister().UpdateRouteServiceMappings(routeKey, route.services)
+
+	for _, gateway := range oldGateways {
+		if !utils.HasElem(gateways, gateway) {
+			// The route is no longer bound to the gateway, remove the gateway from the route status.
+			gwNSName := strings.Split(gateway, "/")
+			route.updateStatus(key, gwNSName[0], gwNSName[1], nil)
+		}
+	}
+	for _, gateway := range gateways {
+		if !utils.HasElem(allGateways, gateway) {
+			allGateways = append(allGateways, gateway)
 		}
 	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, allGateways)
 	return allGateways, true
 }
 
+// getSvcApiRoutesForGateway returns the routes bound to the gateway, and updates their service mappings.
+func getSvcApiRoutesForGateway(gw *svcapiv1alpha1.Gateway, key string) []string {
+	kinds := []string{lib.TCPRoute, lib.UDPRoute, lib.TLSRoute}
+	if lib.IsSvcApiL7Gateway(gw) {
+		kinds = []string{lib.HTTPRoute}
+	}
+	var routes []string
+	for _, kind := range kinds {
+		routeObjs, err := listSvcApiRoutes(kind)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: unable to list the %s objects: %v", key, kind, err)
+			continue
+		}
+		for _, route := range routeObjs {
+			if !utils.CheckIfNamespaceAccepted(route.meta.Namespace) ||
+				!isSvcApiRouteForGateway(gw, kind, route.meta, route.gateways) {
+				continue
+			}
+			routeKey := kind + "/" + route.meta.Namespace + "/" + route.meta.Name
+			objects.ServiceGWLister().UpdateRouteServiceMappings(routeKey, route.services)
+			routes = append(routes, routeKey)
+		}
+	}
+	return routes
+}
+
+// updateSvcApiGatewayRouteMappings replaces the routes bound to the gateway, and removes the gateway from the
+// status of the routes which are no longer bound to the gateway.
+func updateSvcApiGatewayRouteMappings(namespace, gwName string, routes []string, key string) {
+	_, oldRoutes := objects.ServiceGWLister().GetGatewayToRoutes(namespace + "/" + gwName)
+	for _, routeKey := range oldRoutes {
+		if utils.HasElem(routes, routeKey) {
+			continue
+		}
+		kind, routeNS, routeName := lib.ExtractTypeNameNamespace(routeKey)
+		if route, err := getSvcApiRoute(kind, routeNS, routeName); err == nil {
+			route.updateStatus(key, namespace, gwName, nil)
+		}
+	}
+	objects.ServiceGWLister().UpdateGatewayRouteMappings(namespace+"/"+gwName, routes)
+}
+
+// svcApiRoute holds the fields of a route of any kind, which bind the route to the gateways and to the services.
+type svcApiRoute struct {
+	kind     string
+	meta     metav1.ObjectMeta
+	gateways svcapiv1alpha1.RouteGateways
+	status   *svcapiv1alpha1.RouteStatus
+	// services are the services the route forwards to, as namespace/name.
+	services []string
+	// rules are the rules of the TCPRoutes, UDPRoutes and TLSRoutes.
+	rules []svcApiL4RouteRule
+}
+
+func getSvcApiRoute(kind, namespace, name string) (*svcApiRoute, error) {
+	informers := lib.GetSvcAPIInformers()
+	var obj interface{}
+	var err error
+	switch kind {
+	case lib.HTTPRoute:
+		obj, err = informers.HTTPRouteInformer.Lister().HTTPRoutes(namespace).Get(name)
+	case lib.TCPRoute:
+		obj, err = informers.TCPRouteInformer.Lister().TCPRoutes(namespace).Get(name)
+	case lib.UDPRoute:
+		obj, err = informers.UDPRouteInformer.Lister().UDPRoutes(namespace).Get(name)
+	case lib.TLSRoute:
+		obj, err = informers.TLSRouteInformer.Lister().TLSRoutes(namespace).Get(name)
+	default:
+		return nil, fmt.Errorf("unsupported route kind %s", kind)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return newSvcApiRoute(obj), nil
+}
+
+func listSvcApiRoutes(kind string) ([]*svcApiRoute, error) {
+	informers := lib.GetSvcAPIInformers()
+	var routes []*svcApiRoute
+	switch kind {
+	case lib.HTTPRoute:
+		routeObjs, err := informers.HTTPRouteInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routeObjs {
+			routes = append(routes, newSvcApiRoute(route))
+		}
+	case lib.TCPRoute:
+		routeObjs, err := informers.TCPRouteInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routeObjs {
+			routes = append(routes, newSvcApiRoute(route))
+		}
+	case lib.UDPRoute:
+		routeObjs, err := informers.UDPRouteInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routeObjs {
+			routes = append(routes, newSvcApiRoute(route))
+		}
+	case lib.TLSRoute:
+		routeObjs, err := informers.TLSRouteInformer.Lister().List(labels.Everything())
+		if err != nil {
+			return nil, err
+		}
+		for _, route := range routeObjs {
+			routes = append(routes, newSvcApiRoute(route))
+		}
+	default:
+		return nil, fmt.Errorf("unsupported route kind %s", kind)
+	}
+	return routes, nil
+}
+
+func newSvcApiRoute(obj interface{}) *svcApiRoute {
+	var route *svcApiRoute
+	switch routeObj := obj.(type) {
+	case *svcapiv1alpha1.HTTPRoute:
+		return &svcApiRoute{
+			kind:     lib.HTTPRoute,
+			meta:     routeObj.ObjectMeta,
+			gateways: routeObj.Spec.Gateways,
+			status:   &routeObj.Status.RouteStatus,
+			services: parseServicesForHTTPRoute(routeObj, ""),
+		}
+	case *svcapiv1alpha1.TCPRoute:
+		route = &svcApiRoute{kind: lib.TCPRoute, meta: routeObj.ObjectMeta, gateways: routeObj.Spec.Gateways, status: &routeObj.Status.RouteStatus}
+		for _, rule := range routeObj.Spec.Rules {
+			l4Rule := svcApiL4RouteRule{forwardTo: rule.ForwardTo}
+			for _, match := range rule.Matches {
+				l4Rule.extensionRef = l4Rule.extensionRef || match.ExtensionRef != nil
+			}
+			route.rules = append(route.rules, l4Rule)
+		}
+	case *svcapiv1alpha1.UDPRoute:
+		route = &svcApiRoute{kind: lib.UDPRoute, meta: routeObj.ObjectMeta, gateways: routeObj.Spec.Gateways, status: &routeObj.Status.RouteStatus}
+		for _, rule := range routeObj.Spec.Rules {
+			l4Rule := svcApiL4RouteRule{forwardTo: rule.ForwardTo}
+			for _, match := range rule.Matches {
+				l4Rule.extensionRef = l4Rule.extensionRef || match.ExtensionRef != nil
+			}
+			route.rules = append(route.rules, l4Rule)
+		}
+	case *svcapiv1alpha1.TLSRoute:
+		route = &svcApiRoute{kind: lib.TLSRoute, meta: routeObj.ObjectMeta, gateways: routeObj.Spec.Gateways, status: &routeObj.Status.RouteStatus}
+		for _, rule := range routeObj.Spec.Rules {
+			l4Rule := svcApiL4RouteRule{forwardTo: rule.ForwardTo}
+			for _, match := range rule.Matches {
+				l4Rule.extensionRef = l4Rule.extensionRef || match.ExtensionRef != nil
+				l4Rule.snis = append(l4Rule.snis, match.SNIs...)
+			}
+			route.rules = append(route.rules, l4Rule)
+		}
+	}
+	for _, rule := range route.rules {
+		for _, forwardTo := range rule.forwardTo {
+			if forwardTo.ServiceName == nil {
+				continue
+			}
+			service := route.meta.Namespace + "/" + *forwardTo.ServiceName
+			if !utils.HasElem(route.services, service) {
+				route.services = append(route.services, service)
+			}
+		}
+	}
+	return route
+}
+
+// updateStatus publishes the Admitted condition reported for the gateway in the route status.
+// A nil condition removes the gateway from the route status.
+func (route *svcApiRoute) updateStatus(key, gwNamespace, gwName string, condition *metav1.Condition) {
+	status.PublishSvcApiRouteStatus(key, route.kind, route.meta.Namespace, route.meta.Name, route.status, gwNamespace, gwName, condition)
+}
+
 func GWClassToGateway(gwClassName string, namespace string, key string) ([]string, bool) {
 	found, gateways := objects.ServiceGWLister().GetGWclassToGateways(gwClassName)
 	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, gateways)
@@ -508,8 +800,9 @@ func EPToIng(epName string, namespace string, key string) ([]string, bool) {
 
 func SecretToIng(secretName string, namespace string, key string) ([]string, bool) {
 	ok, ingNames := objects.SharedSvcLister().IngressMappings(namespace).GetSecretToIng(secretName)
+	ingNames = getIngressesForHostRuleCASecret(secretName, namespace, ingNames, key)
 	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, ingNames)
-	if ok {
+	if ok || len(ingNames) > 0 {
 		return ingNames, true
 	}
 	return nil, false
@@ -517,15 +810,56 @@ func SecretToIng(secretName string, namespace string, key string) ([]string, boo
 
 func SecretToRoute(secretName string, namespace string, key string) ([]string, bool) {
 	ok, ingNames := objects.OshiftRouteSvcLister().IngressMappings(namespace).GetSecretToIng(secretName)
+	ingNames = getIngressesForHostRuleCASecret(secretName, namespace, ingNames, key)
 	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, ingNames)
-	if ok {
+	if ok || len(ingNames) > 0 {
 		return ingNames, true
 	}
 	return nil, false
 }
 
+// getIngressesForHostRuleCASecret appends the ingresses of the hosts of the accepted hostrules, which validate
+// the client certificates with the CA certificates in the secret, to ingresses.
+func getIngressesForHostRuleCASecret(secretName, namespace string, ingresses []string, key string) []string {
+	if lib.GetCRDInformers() == nil || lib.GetCRDInformers().HostRuleInformer == nil {
+		return ingresses
+	}
+	hostrules, err := lib.GetCRDInformers().HostRuleInformer.Lister().HostRules(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the hostrules: %v", key, err)
+		return ingresses
+	}
+	for _, hostrule := range hostrules {
+		if hostrule.Status.Status != lib.StatusAccepted || hostrule.Spec.VirtualHost.TLS.ClientCertificate.CASecret != secretName {
+			continue
+		}
+		_, match := objects.SharedCRDLister().GetHostruleFQDNMatch(hostrule.Namespace + "/" + hostrule.Name)
+		ingresses = getIngressesForHostRuleFqdn(hostrule.Spec.VirtualHost.Fqdn, match, ingresses, key)
+	}
+	return ingresses
+}
+
 func SecretToGateway(secretName string, namespace string, key string) ([]string, bool) {
-	return nil, false
+	if !lib.UseServicesAPI() {
+		return nil, false
+	}
+	// The gateways in the namespace of the secret, with listeners using the secret as certificateRef.
+	var allGateways []string
+	gwObjs, err := lib.GetSvcAPIInformers().GatewayInformer.Lister().Gateways(namespace).List(labels.Everything())
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to list the gateways: %v", key, err)
+		return nil, false
+	}
+	for _, gw := range gwObjs {
+		for _, listener := range gw.Spec.Listeners {
+			if listener.TLS != nil && lib.IsSvcApiSecretRef(listener.TLS.CertificateRef) && listener.TLS.CertificateRef.Name == secretName {
+				allGateways = append(allGateways, gw.Namespace+"/"+gw.Name)
+				break
+			}
+		}
+	}
+	utils.AviLog.Debugf("key: %s, msg: Gateways retrieved %s", key, allGateways)
+	return allGateways, len(allGateways) > 0
 }
 
 func parseServicesForRoute(routeSpec routev1.RouteSpec, key string) []string {
@@ -544,6 +878,7 @@ func parseServicesForRoute(routeSpec routev1.RouteSpec, key string) []string {
 func HostRuleToIng(hrname string, namespace string, key string) ([]string, bool) {
 	var err error
 	var oldFqdn, fqdn string
+	var oldMatch, match *objects.HostRuleFQDNMatch
 	var oldFound bool
 
 	allIngresses := make([]string, 0)
@@ -551,6 +886,7 @@ func HostRuleToIng(hrname string, namespace string, key string) ([]string, bool)
 	if k8serrors.IsNotFound(err) {
 		utils.AviLog.Debugf("key: %s, msg: HostRule Deleted\n", key)
 		_, fqdn = objects.SharedCRDLister().GetHostruleToFQDNMapping(namespace + "/" + hrname)
+		_, match = objects.SharedCRDLister().GetHostruleFQDNMatch(namespace + "/" + hrname)
 		objects.SharedCRDLister().DeleteHostruleFQDNMapping(namespace + "/" + hrname)
 	} else if err != nil {
 		utils.AviLog.Errorf("key: %s, msg: Error getting hostrule: %v\n", key, err)
@@ -562,43 +898,50 @@ func HostRuleToIng(hrname string, namespace string, key string) ([]string, bool)
 		fqdn = hostrule.Spec.VirtualHost.Fqdn
 		oldFound, oldFqdn = objects.SharedCRDLister().GetHostruleToFQDNMapping(namespace + "/" + hrname)
 		if oldFound {
+			_, oldMatch = objects.SharedCRDLister().GetHostruleFQDNMatch(namespace + "/" + hrname)
 			objects.SharedCRDLister().DeleteHostruleFQDNMapping(namespace + "/" + hrname)
 		}
-		objects.SharedCRDLister().UpdateFQDNHostruleMapping(fqdn, namespace+"/"+hrname)
-	}
-
-	// find ingresses with host==fqdn, across all namespaces
-	ok, obj := SharedHostNameLister().GetHostPathStore(fqdn)
-	if !ok {
-		utils.AviLog.Debugf("key: %s, msg: Couldn't find hostpath info for host: %s in cache", key, fqdn)
-	} else {
-		for _, ingresses := range obj {
-			for _, ing := range ingresses {
-				if !utils.HasElem(allIngresses, ing) {
-					allIngresses = append(allIngresses, ing)
-				}
-			}
+		if fqdnType := lib.GetHostRuleFqdnType(hostrule); fqdnType == akov1alpha1.FqdnTypeExact {
+			objects.SharedCRDLister().UpdateFQDNHostruleMapping(fqdn, namespace+"/"+hrname)
+		} else if match, err = objects.NewHostRuleFQDNMatch(fqdn, fqdnType); err == nil {
+			objects.SharedCRDLister().UpdateFQDNMatchHostruleMapping(match, namespace+"/"+hrname)
 		}
 	}
 
+	// find ingresses with host==fqdn, or with a host matching a Wildcard or Regex fqdn, across all namespaces
+	allIngresses = getIngressesForHostRuleFqdn(fqdn, match, allIngresses, key)
+
 	// in case the hostname is updated, we need to find ingresses for the old ones as well to recompute
 	if oldFound {
-		ok, oldobj := SharedHostNameLister().GetHostPathStore(oldFqdn)
+		allIngresses = getIngressesForHostRuleFqdn(oldFqdn, oldMatch, allIngresses, key)
+	}
+
+	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, allIngresses)
+	return allIngresses, true
+}
+
+// getIngressesForHostRuleFqdn appends the ingresses of the hosts matching the fqdn of a hostrule to ingresses.
+// match is nil for an Exact fqdn.
+func getIngressesForHostRuleFqdn(fqdn string, match *objects.HostRuleFQDNMatch, ingresses []string, key string) []string {
+	hosts := []string{fqdn}
+	if match != nil {
+		hosts = SharedHostNameLister().GetHostsMatching(match.Matches)
+	}
+	for _, host := range hosts {
+		ok, obj := SharedHostNameLister().GetHostPathStore(host)
 		if !ok {
-			utils.AviLog.Debugf("key: %s, msg: Couldn't find hostpath info for host: %s in cache", key, oldFqdn)
-		} else {
-			for _, ingresses := range oldobj {
-				for _, ing := range ingresses {
-					if !utils.HasElem(allIngresses, ing) {
-						allIngresses = append(allIngresses, ing)
-					}
+			utils.AviLog.Debugf("key: %s, msg: Couldn't find hostpath info for host: %s in cache", key, host)
+			continue
+		}
+		for _, ings := range obj {
+			for _, ing := range ings {
+				if !utils.HasElem(ingresses, ing) {
+					ingresses = append(ingresses, ing)
 				}
 			}
 		}
 	}
-
-	utils.AviLog.Debugf("key: %s, msg: Ingresses retrieved %s", key, allIngresses)
-	return allIngresses, true
+	return ingresses
 }
 
 func HTTPRuleToIng(rrname string, namespace string, key string) ([]string, bool) {
@@ -709,7 +1052,7 @@ func AviSettingToIng(infraSettingName, namespace, key string) ([]string, bool) {
 	}
 
 	for _, ingClass := range ingClasses {
-		if ingClassObj, isIngClass := ingClass.(*networkingv1beta1.IngressClass); isIngClass {
+		if ingClassObj, isIngClass := ingClass.(*networkingv1.IngressClass); isIngClass {
 			if ingresses, found := IngClassToIng(ingClassObj.Name, namespace, key); found {
 				allIngresses = append(allIngresses, ingresses...)
 			}
@@ -791,13 +1134,36 @@ func AviSettingToSvc(infraSettingName string, namespace string, key string) ([]s
 	return allSvcs, true
 }
 
-func parseServicesForIngress(ingSpec networkingv1beta1.IngressSpec, key string) []string {
+func L4RuleToSvc(l4RuleName string, namespace string, key string) ([]string, bool) {
+	allSvcs := make([]string, 0)
+
+	// get all services in the namespace of the l4rule that refer to it
+	services, err := utils.GetInformers().ServiceInformer.Informer().GetIndexer().ByIndex(lib.L4RuleServicesIndex, namespace+"/"+l4RuleName)
+	if err != nil {
+		return allSvcs, false
+	}
+
+	for _, svc := range services {
+		svcObj, isSvc := svc.(*corev1.Service)
+		if isSvc {
+			allSvcs = append(allSvcs, svcObj.Namespace+"/"+svcObj.Name)
+		}
+	}
+
+	utils.AviLog.Debugf("key: %s, msg: total services retrieved from L4Rule: %s", key, allSvcs)
+	return allSvcs, true
+}
+
+func parseServicesForIngress(ingSpec networkingv1.IngressSpec, key string) []string {
 	// Figure out the service names that are part of this ingress
 	var services []string
 	for _, rule := range ingSpec.Rules {
 		if rule.IngressRuleValue.HTTP != nil {
 			for _, path := range rule.IngressRuleValue.HTTP.Paths {
-				services = append(services, path.Backend.ServiceName)
+				if path.Backend.Service == nil {
+					continue
+				}
+				services = append(services, path.Backend.Service.Name)
 			}
 		}
 	}
@@ -805,7 +1171,7 @@ func parseServicesForIngress(ingSpec networkingv1beta1.IngressSpec, key string)
 	return services
 }
 
-func parseSecretsForIngress(ingSpec networkingv1beta1.IngressSpec, key string) []string {
+func parseSecretsForIngress(ingSpec networkingv1.IngressSpec, key string) []string {
 	// Figure out the service names that are part of this ingress
 	var secrets []string
 	for _, tlsSettings := range ingSpec.TLS {
@@ -860,6 +1226,17 @@ func parseGatewayForListeners(gateway *advl4v1alpha1pre1.Gateway, key string) []
 func parseSvcApiGatewayForListeners(gateway *svcapiv1alpha1.Gateway, key string) []string {
 	var listeners []string
 	for _, listener := range gateway.Spec.Listeners {
+		if lib.IsSvcApiRouteListener(listener) {
+			// the listeners of routes select the routes instead of the services of the gateway.
+			if IsSvcApiL7Listener(listener) || isSvcApiL4ListenerRealised(gateway, listener) {
+				listeners = append(listeners, fmt.Sprintf("%s/%d", listener.Protocol, listener.Port))
+			}
+			continue
+		}
+		if lib.IsSvcApiPassthroughGateway(gateway) {
+			// the connections to a gateway with TLS passthrough listeners are routed using the SNI.
+			continue
+		}
 		gwName, nameOk := listener.Routes.Selector.MatchLabels[lib.SvcApiGatewayNameLabelKey]
 		gwNamespace, nsOk := listener.Routes.Selector.MatchLabels[lib.SvcApiGatewayNamespaceLabelKey]
 		if nameOk && nsOk && gwName == gateway.Name && gwNamespace == gateway.Namespace {
@@ -905,6 +1282,9 @@ func validateSvcApiGatewayForClass(key string, gateway *svcapiv1alpha1.Gateway)
 	}
 
 	for _, listener := range gateway.Spec.Listeners {
+		if lib.IsSvcApiRouteListener(listener) {
+			continue
+		}
 		gwName, nameOk := listener.Routes.Selector.MatchLabels[lib.SvcApiGatewayNameLabelKey]
 		gwNamespace, nsOk := listener.Routes.Selector.MatchLabels[lib.SvcApiGatewayNamespaceLabelKey]
 		if !nameOk || !nsOk ||
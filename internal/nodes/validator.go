@@ -24,7 +24,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
 	routev1 "github.com/openshift/api/route/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -57,15 +58,16 @@ func (v *Validator) IsValidHostName(hostname string) bool {
 	return false
 }
 
-func validateSpecFromHostnameCache(key, ns, ingName string, ingSpec networkingv1beta1.IngressSpec) bool {
+func validateSpecFromHostnameCache(key, ns, ingName string, ingSpec networkingv1.IngressSpec) bool {
 	nsIngress := ns + "/" + ingName
 	for _, rule := range ingSpec.Rules {
 		if rule.IngressRuleValue.HTTP != nil {
 			for _, svcPath := range rule.IngressRuleValue.HTTP.Paths {
 				found, val := SharedHostNameLister().GetHostPathStoreIngresses(rule.Host, svcPath.Path)
 				if found && len(val) > 1 && utils.HasElem(val, nsIngress) {
-					// TODO: push in ako apiserver
 					utils.AviLog.Warnf("key: %s, msg: Duplicate entries found for hostpath %s%s: %s in ingresses: %+v", key, nsIngress, rule.Host, svcPath.Path, utils.Stringify(val))
+					lib.AKOEventf(utils.Ingress, ns, ingName, corev1.EventTypeWarning, lib.DuplicateHostPath,
+						"Hostpath %s%s is also used by ingresses %v", rule.Host, svcPath.Path, val)
 				}
 			}
 		} else {
@@ -81,6 +83,8 @@ func validateRouteSpecFromHostnameCache(key, ns, routeName string, routeSpec rou
 	found, val := SharedHostNameLister().GetHostPathStoreIngresses(routeSpec.Host, routeSpec.Path)
 	if found && len(val) > 1 && utils.HasElem(val, nsRoute) {
 		utils.AviLog.Warnf("key: %s, msg: Duplicate entries found for hostpath %s%s: %s in routes: %+v", key, nsRoute, routeSpec.Host, routeSpec.Path, utils.Stringify(val))
+		lib.AKOEventf(utils.OshiftRoute, ns, routeName, corev1.EventTypeWarning, lib.DuplicateHostPath,
+			"Hostpath %s%s is also used by routes %v", routeSpec.Host, routeSpec.Path, val)
 	}
 }
 
@@ -160,7 +164,7 @@ func destinationCAHTTPRulePresent(key, host, path string) (bool, string) {
 
 // ParseHostPathForIngress handling for hostrule: if the host has a hostrule, and that hostrule has a tls.sslkeycertref then
 // move that host in the tls.hosts, this should be only in case of hostname sharding
-func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec networkingv1beta1.IngressSpec, annotations map[string]string, key string) IngressConfig {
+func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec networkingv1.IngressSpec, annotations map[string]string, key string) IngressConfig {
 	// Figure out the service names that are part of this ingress
 
 	ingressConfig := IngressConfig{}
@@ -188,6 +192,8 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 		if rule.Host == "" {
 			if subDomains == nil {
 				utils.AviLog.Warnf("No sub-domain configured in cloud")
+				lib.AKOEventf(utils.Ingress, ns, ingName, corev1.EventTypeWarning, lib.InvalidHostname,
+					"Rule without host is skipped, no sub-domain is configured in the cloud")
 				continue
 			} else {
 				// The Host field is empty. Generate a hostName using the sub-domain info
@@ -199,6 +205,8 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 			}
 		} else {
 			if !v.IsValidHostName(rule.Host) {
+				lib.AKOEventf(utils.Ingress, ns, ingName, corev1.EventTypeWarning, lib.InvalidHostname,
+					"Hostname %s does not match any of the sub-domains %v", rule.Host, v.subDomains)
 				continue
 			}
 			hostName = rule.Host
@@ -231,17 +239,22 @@ func (v *Validator) ParseHostPathForIngress(ns string, ingName string, ingSpec n
 		}
 		if rule.IngressRuleValue.HTTP != nil {
 			for _, path := range rule.IngressRuleValue.HTTP.Paths {
-				pathType := networkingv1beta1.PathTypeImplementationSpecific
+				if path.Backend.Service == nil {
+					utils.AviLog.Warnf("key: %s, msg: skipping path %s of host %s, only service backends are supported", key, path.Path, hostName)
+					continue
+				}
+				pathType := networkingv1.PathTypeImplementationSpecific
 				if path.PathType != nil {
 					pathType = *path.PathType
 				}
+				svcBackend := path.Backend.Service
 				hostPathMapSvc := IngressHostPathSvc{
 					Path:        path.Path,
 					PathType:    pathType,
-					ServiceName: path.Backend.ServiceName,
-					Port:        path.Backend.ServicePort.IntVal,
-					PortName:    path.Backend.ServicePort.StrVal,
-					TargetPort:  v.findTargetPort(path.Backend.ServiceName, ns, path.Backend.ServicePort.IntVal, key),
+					ServiceName: svcBackend.Name,
+					Port:        svcBackend.Port.Number,
+					PortName:    svcBackend.Port.Name,
+					TargetPort:  v.findTargetPort(svcBackend.Name, ns, svcBackend.Port.Number, key),
 				}
 				if hostPathMapSvc.Port == 0 {
 					// Default to port 80 if not set in the ingress object
@@ -368,6 +381,8 @@ func (v *Validator) ParseHostPathForRoute(ns string, routeName string, routeSpec
 	hostMap := make(IngressHostMap)
 	hostName := routeSpec.Host
 	if !v.IsValidHostName(hostName) {
+		lib.AKOEventf(utils.OshiftRoute, ns, routeName, corev1.EventTypeWarning, lib.InvalidHostname,
+			"Hostname %s does not match any of the sub-domains %v", hostName, v.subDomains)
 		return ingressConfig
 	}
 	defaultWeight := int32(100)
@@ -15,7 +15,12 @@
 package objects
 
 import (
+	"regexp"
+	"sort"
+	"strings"
 	"sync"
+
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 )
 
 var CRDinstance *CRDLister
@@ -25,11 +30,13 @@ var crdonce sync.Once
 func SharedCRDLister() *CRDLister {
 	crdonce.Do(func() {
 		CRDinstance = &CRDLister{
-			FqdnHostRuleCache:  NewObjectMapStore(),
-			HostRuleFQDNCache:  NewObjectMapStore(),
-			FqdnHTTPRulesCache: NewObjectMapStore(),
-			HTTPRuleFqdnCache:  NewObjectMapStore(),
-			FqdnToGSFQDNCache:  NewObjectMapStore(),
+			FqdnHostRuleCache:        NewObjectMapStore(),
+			HostRuleFQDNCache:        NewObjectMapStore(),
+			HostRuleFQDNMatchCache:   NewObjectMapStore(),
+			AppliedFqdnHostRuleCache: NewObjectMapStore(),
+			FqdnHTTPRulesCache:       NewObjectMapStore(),
+			HTTPRuleFqdnCache:        NewObjectMapStore(),
+			FqdnToGSFQDNCache:        NewObjectMapStore(),
 		}
 	})
 	return CRDinstance
@@ -46,6 +53,12 @@ type CRDLister struct {
 	// hr1: fqdn.com - required for httprule
 	HostRuleFQDNCache *ObjectMapStore
 
+	// hr1: {*.fqdn.com Wildcard} - the hostrules with Wildcard and Regex fqdns
+	HostRuleFQDNMatchCache *ObjectMapStore
+
+	// foo.fqdn.com: hr1 - the hostrule applied on the host
+	AppliedFqdnHostRuleCache *ObjectMapStore
+
 	// hr1: gsfqdn.com
 	FqdnToGSFQDNCache *ObjectMapStore
 
@@ -58,7 +71,77 @@ type CRDLister struct {
 
 // FqdnHostRuleCache
 
+// HostRuleFQDNMatch is the Wildcard or Regex fqdn of a hostrule
+type HostRuleFQDNMatch struct {
+	Fqdn     string
+	FqdnType akov1alpha1.FqdnType
+	regex    *regexp.Regexp
+}
+
+// NewHostRuleFQDNMatch returns the matcher of the fqdn, or an error if a Regex fqdn is not a valid regular expression.
+func NewHostRuleFQDNMatch(fqdn string, fqdnType akov1alpha1.FqdnType) (*HostRuleFQDNMatch, error) {
+	match := &HostRuleFQDNMatch{Fqdn: fqdn, FqdnType: fqdnType}
+	if fqdnType == akov1alpha1.FqdnTypeRegex {
+		regex, err := regexp.Compile("^(?:" + fqdn + ")$")
+		if err != nil {
+			return nil, err
+		}
+		match.regex = regex
+	}
+	return match, nil
+}
+
+// Matches returns true if the host matches the fqdn. A Wildcard fqdn matches the hosts with a
+// single label in place of the *, like the wildcard hosts of Ingresses.
+func (m *HostRuleFQDNMatch) Matches(host string) bool {
+	switch m.FqdnType {
+	case akov1alpha1.FqdnTypeWildcard:
+		suffix := strings.TrimPrefix(m.Fqdn, "*")
+		if !strings.HasSuffix(host, suffix) {
+			return false
+		}
+		label := strings.TrimSuffix(host, suffix)
+		return label != "" && !strings.Contains(label, ".")
+	case akov1alpha1.FqdnTypeRegex:
+		return m.regex.MatchString(host)
+	}
+	return m.Fqdn == host
+}
+
+// GetFQDNToHostruleMapping returns the hostrule applicable to the fqdn. The hostrules are matched in the order
+// Exact, Wildcard and Regex. Among the Wildcard hostrules the one with the longest fqdn wins, and the remaining
+// ties are broken by the namespace/name of the hostrules.
 func (c *CRDLister) GetFQDNToHostruleMapping(fqdn string) (bool, string) {
+	found, hostrule := c.FqdnHostRuleCache.Get(fqdn)
+	if found {
+		return true, hostrule.(string)
+	}
+
+	var matched []string
+	matches := c.HostRuleFQDNMatchCache.CopyAllObjects()
+	for hostrule, match := range matches {
+		if match.(*HostRuleFQDNMatch).Matches(fqdn) {
+			matched = append(matched, hostrule)
+		}
+	}
+	if len(matched) == 0 {
+		return false, ""
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		mi, mj := matches[matched[i]].(*HostRuleFQDNMatch), matches[matched[j]].(*HostRuleFQDNMatch)
+		if mi.FqdnType != mj.FqdnType {
+			return mi.FqdnType == akov1alpha1.FqdnTypeWildcard
+		}
+		if mi.FqdnType == akov1alpha1.FqdnTypeWildcard && len(mi.Fqdn) != len(mj.Fqdn) {
+			return len(mi.Fqdn) > len(mj.Fqdn)
+		}
+		return matched[i] < matched[j]
+	})
+	return true, matched[0]
+}
+
+// GetExactFQDNToHostruleMapping returns the Exact hostrule of the fqdn.
+func (c *CRDLister) GetExactFQDNToHostruleMapping(fqdn string) (bool, string) {
 	found, hostrule := c.FqdnHostRuleCache.Get(fqdn)
 	if !found {
 		return false, ""
@@ -66,6 +149,25 @@ func (c *CRDLister) GetFQDNToHostruleMapping(fqdn string) (bool, string) {
 	return true, hostrule.(string)
 }
 
+// GetHostruleFQDNMatch returns the matcher of the fqdn of a Wildcard or Regex hostrule.
+func (c *CRDLister) GetHostruleFQDNMatch(hostrule string) (bool, *HostRuleFQDNMatch) {
+	found, match := c.HostRuleFQDNMatchCache.Get(hostrule)
+	if !found {
+		return false, nil
+	}
+	return true, match.(*HostRuleFQDNMatch)
+}
+
+// GetHostruleForFQDNMatch returns the Wildcard or Regex hostrule with the given fqdn and type.
+func (c *CRDLister) GetHostruleForFQDNMatch(fqdn string, fqdnType akov1alpha1.FqdnType) (bool, string) {
+	for hostrule, match := range c.HostRuleFQDNMatchCache.CopyAllObjects() {
+		if match.(*HostRuleFQDNMatch).Fqdn == fqdn && match.(*HostRuleFQDNMatch).FqdnType == fqdnType {
+			return true, hostrule
+		}
+	}
+	return false, ""
+}
+
 func (c *CRDLister) GetHostruleToFQDNMapping(hostrule string) (bool, string) {
 	found, fqdn := c.HostRuleFQDNCache.Get(hostrule)
 	if !found {
@@ -88,6 +190,9 @@ func (c *CRDLister) DeleteHostruleFQDNMapping(hostrule string) bool {
 	found, fqdn := c.HostRuleFQDNCache.Get(hostrule)
 	if found {
 		success1 := c.HostRuleFQDNCache.Delete(hostrule)
+		if foundMatch, _ := c.HostRuleFQDNMatchCache.Get(hostrule); foundMatch {
+			return success1 && c.HostRuleFQDNMatchCache.Delete(hostrule)
+		}
 		success2 := c.FqdnHostRuleCache.Delete(fqdn.(string))
 		return success1 && success2
 	}
@@ -119,6 +224,45 @@ func (c *CRDLister) UpdateFQDNHostruleMapping(fqdn string, hostrule string) {
 	c.HostRuleFQDNCache.AddOrUpdate(hostrule, fqdn)
 }
 
+// UpdateFQDNMatchHostruleMapping stores the fqdn of a Wildcard or Regex hostrule.
+func (c *CRDLister) UpdateFQDNMatchHostruleMapping(match *HostRuleFQDNMatch, hostrule string) {
+	c.NSLock.Lock()
+	defer c.NSLock.Unlock()
+	c.HostRuleFQDNMatchCache.AddOrUpdate(hostrule, match)
+	c.HostRuleFQDNCache.AddOrUpdate(hostrule, match.Fqdn)
+}
+
+// AppliedFqdnHostRuleCache
+
+// UpdateAppliedFqdnHostruleMapping records the hostrule applied on the fqdn, an empty hostrule removes the
+// fqdn. The hostrule previously applied on the fqdn is returned.
+func (c *CRDLister) UpdateAppliedFqdnHostruleMapping(fqdn, hostrule string) string {
+	c.NSLock.Lock()
+	defer c.NSLock.Unlock()
+	var oldHostrule string
+	if found, obj := c.AppliedFqdnHostRuleCache.Get(fqdn); found {
+		oldHostrule = obj.(string)
+	}
+	if hostrule == "" {
+		c.AppliedFqdnHostRuleCache.Delete(fqdn)
+	} else {
+		c.AppliedFqdnHostRuleCache.AddOrUpdate(fqdn, hostrule)
+	}
+	return oldHostrule
+}
+
+// GetHostruleAppliedFqdns returns the sorted fqdns the hostrule is applied on.
+func (c *CRDLister) GetHostruleAppliedFqdns(hostrule string) []string {
+	var fqdns []string
+	for fqdn, obj := range c.AppliedFqdnHostRuleCache.CopyAllObjects() {
+		if obj.(string) == hostrule {
+			fqdns = append(fqdns, fqdn)
+		}
+	}
+	sort.Strings(fqdns)
+	return fqdns
+}
+
 // FqdnHTTPRulesCache
 
 func (c *CRDLister) GetFqdnHTTPRulesMapping(fqdn string) (bool, map[string]string) {
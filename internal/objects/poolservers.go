@@ -0,0 +1,101 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package objects
+
+import (
+	"sync"
+)
+
+var poolServerInstance *PoolServerLister
+var poolServerOnce sync.Once
+
+func SharedPoolServerLister() *PoolServerLister {
+	poolServerOnce.Do(func() {
+		poolServerInstance = &PoolServerLister{
+			poolServers: make(map[string]map[string]bool),
+		}
+	})
+	return poolServerInstance
+}
+
+// PoolServerLister stores the server IPs of each pool, along with whether the
+// server has been added to the pool on the Avi controller.
+type PoolServerLister struct {
+	lock sync.RWMutex
+	// pool tenant/name -> server IP -> added on the controller
+	poolServers map[string]map[string]bool
+}
+
+// SetServers records the server IPs to be configured in the pool, servers already added are retained.
+func (p *PoolServerLister) SetServers(pool string, ips []string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	servers := make(map[string]bool, len(ips))
+	for _, ip := range ips {
+		servers[ip] = p.poolServers[pool][ip]
+	}
+	p.poolServers[pool] = servers
+}
+
+// MarkServersAdded marks the server IPs as added to the pool on the controller.
+func (p *PoolServerLister) MarkServersAdded(pool string, ips []string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	servers, ok := p.poolServers[pool]
+	if !ok {
+		return
+	}
+	for _, ip := range ips {
+		if _, ok := servers[ip]; ok {
+			servers[ip] = true
+		}
+	}
+}
+
+// GetServers returns the server IPs recorded for the pool.
+func (p *PoolServerLister) GetServers(pool string) []string {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	var ips []string
+	for ip := range p.poolServers[pool] {
+		ips = append(ips, ip)
+	}
+	return ips
+}
+
+func (p *PoolServerLister) DeletePool(pool string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	delete(p.poolServers, pool)
+}
+
+// IsServerAdded returns true if the server IP is present in at least one pool,
+// and has been added on the controller to all the pools it is present in.
+func (p *PoolServerLister) IsServerAdded(ip string) bool {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+	found := false
+	for _, servers := range p.poolServers {
+		added, ok := servers[ip]
+		if !ok {
+			continue
+		}
+		if !added {
+			return false
+		}
+		found = true
+	}
+	return found
+}
@@ -25,6 +25,7 @@ var gwonce sync.Once
 
 // This file builds cache relations for all services API objects.
 // Relationships stored are: gatewayclass to gateway, service to gateway,
+// route to gateway, service to route.
 // GatewayClass is a cluster scoped resource.
 
 func ServiceGWLister() *SvcGWLister {
@@ -35,6 +36,10 @@ func ServiceGWLister() *SvcGWLister {
 			GwListenersStore: NewObjectMapStore(),
 			SvcGWStore:       NewObjectMapStore(),
 			GwSvcsStore:      NewObjectMapStore(),
+			RouteGwStore:     NewObjectMapStore(),
+			GwRouteStore:     NewObjectMapStore(),
+			RouteSvcStore:    NewObjectMapStore(),
+			SvcRouteStore:    NewObjectMapStore(),
 		}
 	})
 	return gwsvclister
@@ -60,6 +65,20 @@ type SvcGWLister struct {
 	// the protocol and port mapped here are of the service
 	// nsX/gw1 -> {proto1/port1: ns1/svc1, proto2/port2: ns2/svc2, ...}
 	GwSvcsStore *ObjectMapStore
+
+	// the routes mapped to a gateway are the routes bound to the gateway listeners,
+	// and the routes that refer to the gateway in their gatewayRefs.
+	// HTTPRoute/ns1/route1 -> [nsX/gw1, nsY/gw2]
+	RouteGwStore *ObjectMapStore
+
+	// nsX/gw1 -> [HTTPRoute/ns1/route1, HTTPRoute/ns2/route2]
+	GwRouteStore *ObjectMapStore
+
+	// HTTPRoute/ns1/route1 -> [ns1/svc1, ns1/svc2]
+	RouteSvcStore *ObjectMapStore
+
+	// ns1/svc1 -> [HTTPRoute/ns1/route1, HTTPRoute/ns1/route2]
+	SvcRouteStore *ObjectMapStore
 }
 
 // Gateway <-> GatewayClass
@@ -176,3 +195,114 @@ func (v *SvcGWLister) RemoveGatewayMappings(gateway, service string) bool {
 	}
 	return v.SvcGWStore.Delete(service)
 }
+
+//=====All route <-> gateway and route <-> service mappings go here.
+
+func (v *SvcGWLister) GetRouteToGateways(route string) (bool, []string) {
+	found, gateways := v.RouteGwStore.Get(route)
+	if !found {
+		return false, make([]string, 0)
+	}
+	return true, gateways.([]string)
+}
+
+func (v *SvcGWLister) GetGatewayToRoutes(gateway string) (bool, []string) {
+	found, routes := v.GwRouteStore.Get(gateway)
+	if !found {
+		return false, make([]string, 0)
+	}
+	return true, routes.([]string)
+}
+
+// UpdateRouteGatewayMappings replaces the gateways mapped to the route.
+func (v *SvcGWLister) UpdateRouteGatewayMappings(route string, gateways []string) {
+	v.SvcGWLock.Lock()
+	defer v.SvcGWLock.Unlock()
+	_, oldGateways := v.GetRouteToGateways(route)
+	for _, gateway := range oldGateways {
+		if !utils.HasElem(gateways, gateway) {
+			v.updateMapping(v.GwRouteStore, gateway, route, false)
+		}
+	}
+	for _, gateway := range gateways {
+		v.updateMapping(v.GwRouteStore, gateway, route, true)
+	}
+	v.replaceMapping(v.RouteGwStore, route, gateways)
+}
+
+// UpdateGatewayRouteMappings replaces the routes mapped to the gateway.
+func (v *SvcGWLister) UpdateGatewayRouteMappings(gateway string, routes []string) {
+	v.SvcGWLock.Lock()
+	defer v.SvcGWLock.Unlock()
+	_, oldRoutes := v.GetGatewayToRoutes(gateway)
+	for _, route := range oldRoutes {
+		if !utils.HasElem(routes, route) {
+			v.updateMapping(v.RouteGwStore, route, gateway, false)
+		}
+	}
+	for _, route := range routes {
+		v.updateMapping(v.RouteGwStore, route, gateway, true)
+	}
+	v.replaceMapping(v.GwRouteStore, gateway, routes)
+}
+
+func (v *SvcGWLister) GetSvcToRoutes(service string) (bool, []string) {
+	found, routes := v.SvcRouteStore.Get(service)
+	if !found {
+		return false, make([]string, 0)
+	}
+	return true, routes.([]string)
+}
+
+func (v *SvcGWLister) GetRouteToSvcs(route string) (bool, []string) {
+	found, services := v.RouteSvcStore.Get(route)
+	if !found {
+		return false, make([]string, 0)
+	}
+	return true, services.([]string)
+}
+
+// UpdateRouteServiceMappings replaces the backend services mapped to the route.
+func (v *SvcGWLister) UpdateRouteServiceMappings(route string, services []string) {
+	v.SvcGWLock.Lock()
+	defer v.SvcGWLock.Unlock()
+	_, oldServices := v.GetRouteToSvcs(route)
+	for _, service := range oldServices {
+		if !utils.HasElem(services, service) {
+			v.updateMapping(v.SvcRouteStore, service, route, false)
+		}
+	}
+	for _, service := range services {
+		v.updateMapping(v.SvcRouteStore, service, route, true)
+	}
+	v.replaceMapping(v.RouteSvcStore, route, services)
+}
+
+// DeleteRouteMappings removes all the gateway and service mappings of the route.
+func (v *SvcGWLister) DeleteRouteMappings(route string) {
+	v.UpdateRouteGatewayMappings(route, nil)
+	v.UpdateRouteServiceMappings(route, nil)
+}
+
+// updateMapping adds or removes the value from the list stored against the key. The stored list
+// is copied, since the lists returned by the getters can be in use by the callers.
+func (v *SvcGWLister) updateMapping(store *ObjectMapStore, key, value string, add bool) {
+	var values []string
+	if found, obj := store.Get(key); found {
+		values = append(values, obj.([]string)...)
+	}
+	if add && !utils.HasElem(values, value) {
+		values = append(values, value)
+	} else if !add && utils.HasElem(values, value) {
+		values = utils.Remove(values, value)
+	}
+	v.replaceMapping(store, key, values)
+}
+
+func (v *SvcGWLister) replaceMapping(store *ObjectMapStore, key string, values []string) {
+	if len(values) == 0 {
+		store.Delete(key)
+		return
+	}
+	store.AddOrUpdate(key, values)
+}
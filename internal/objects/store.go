@@ -104,6 +104,12 @@ func (o *ObjectMapStore) Delete(objName string) bool {
 
 }
 
+func (o *ObjectMapStore) DeleteAll() {
+	o.ObjLock.Lock()
+	defer o.ObjLock.Unlock()
+	o.ObjectMap = make(map[string]interface{})
+}
+
 func (o *ObjectMapStore) Get(objName string) (bool, interface{}) {
 	o.ObjLock.RLock()
 	defer o.ObjLock.RUnlock()
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviClientCertProfileBuild(profile_meta *nodes.AviClientCertProfileNode, cache_obj *avicache.AviAppProfileCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(profile_meta.Name, lib.ClientCertProfile) {
+		utils.AviLog.Warnf("key: %s not processing application profile object", key)
+		return nil
+	}
+	name := profile_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", profile_meta.Tenant)
+	profileType := lib.AllowedApplicationProfile
+	cr := lib.AKOUser
+	mode := profile_meta.Mode
+	pkiProfileRef := "/api/pkiprofile?name=" + profile_meta.PkiProfile.Name
+
+	httpProfile := &avimodels.HTTPApplicationProfile{
+		PkiProfileRef:            &pkiProfileRef,
+		SslClientCertificateMode: &mode,
+	}
+	if profile_meta.DNHeader != "" {
+		dnHeader := profile_meta.DNHeader
+		dnHeaderValue := lib.SSL_CLIENT_SUBJECT
+		httpProfile.SslClientCertificateAction = &avimodels.SSLClientCertificateAction{
+			Headers: []*avimodels.SSLClientRequestHeader{{
+				RequestHeader:      &dnHeader,
+				RequestHeaderValue: &dnHeaderValue,
+			}},
+		}
+	}
+
+	appProfile := avimodels.ApplicationProfile{
+		Name:        &name,
+		TenantRef:   &tenant,
+		Type:        &profileType,
+		CreatedBy:   &cr,
+		HTTPProfile: httpProfile,
+	}
+	if lib.GetGRBACSupport() {
+		appProfile.Markers = lib.GetAllMarkers(profile_meta.AviMarkers)
+	}
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/applicationprofile/" + cache_obj.Uuid
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPut, Obj: appProfile,
+			Tenant: profile_meta.Tenant, Model: "ApplicationProfile", Version: utils.CtrlVersion}
+	} else {
+		path = "/api/applicationprofile/"
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPost, Obj: appProfile,
+			Tenant: profile_meta.Tenant, Model: "ApplicationProfile", Version: utils.CtrlVersion}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: ApplicationProfile Restop %v AviClientCertProfileMeta %v\n", key,
+		rest_op, utils.Stringify(profile_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviAppProfileDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/applicationprofile/" + uuid
+	rest_op := utils.RestOp{Path: path, Method: "DELETE",
+		Tenant: tenant, Model: "ApplicationProfile", Version: utils.CtrlVersion}
+	utils.AviLog.Info(spew.Sprintf("key: %s, msg: ApplicationProfile DELETE Restop %v \n", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviAppProfileCacheAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for applicationprofile, err: %v, response: %v", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "applicationprofile", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find ApplicationProfile obj in resp %v", key, rest_op.Response)
+		return errors.New("ApplicationProfile not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var appProfile avimodels.ApplicationProfile
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			appProfile = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile)
+		case avimodels.ApplicationProfile:
+			appProfile = rest_op.Obj.(avimodels.ApplicationProfile)
+		}
+		var mode, dnHeader string
+		if appProfile.HTTPProfile != nil {
+			if appProfile.HTTPProfile.SslClientCertificateMode != nil {
+				mode = *appProfile.HTTPProfile.SslClientCertificateMode
+			}
+			if appProfile.HTTPProfile.SslClientCertificateAction != nil && len(appProfile.HTTPProfile.SslClientCertificateAction.Headers) > 0 {
+				dnHeader = *appProfile.HTTPProfile.SslClientCertificateAction.Headers[0].RequestHeader
+			}
+		}
+
+		var lastModifiedStr string
+		if lastModifiedIntf, ok := resp["_last_modified"]; ok {
+			lastModifiedStr, _ = lastModifiedIntf.(string)
+		}
+
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		app_profile_cache_obj := avicache.AviAppProfileCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			CloudConfigCksum: lib.ClientCertProfileChecksum(mode, dnHeader, emptyIngestionMarkers, appProfile.Markers, true),
+			LastModified:     lastModifiedStr,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.AppProfileCache.AviCacheAdd(k, &app_profile_cache_obj)
+		utils.AviLog.Info(spew.Sprintf("key: %s, msg: Added ApplicationProfile cache k %v val %v\n", key, k,
+			app_profile_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviAppProfileCacheDel(rest_op *utils.RestOp, key string) error {
+	appProfileKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	utils.AviLog.Infof("key: %s, msg: deleting ApplicationProfile cache %v", key, appProfileKey)
+	rest.cache.AppProfileCache.AviCacheDelete(appProfileKey)
+	return nil
+}
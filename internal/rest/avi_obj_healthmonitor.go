@@ -0,0 +1,149 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviHealthMonitorBuild(hm_meta *nodes.AviHealthMonitorNode, cache_obj *avicache.AviHealthMonitorCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(hm_meta.Name, lib.HealthMonitor) {
+		utils.AviLog.Warnf("key: %s not processing health monitor object", key)
+		return nil
+	}
+	name := hm_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", hm_meta.Tenant)
+	hmType := lib.HEALTH_MONITOR_HTTP
+	monitorPort := hm_meta.MonitorPort
+	// kube-proxy responds with 200 on the healthCheckNodePort only when the node has local endpoints of the service.
+	httpRequest := "GET /healthz HTTP/1.0"
+
+	hm := avimodels.HealthMonitor{
+		Name:        &name,
+		TenantRef:   &tenant,
+		Type:        &hmType,
+		MonitorPort: &monitorPort,
+		HTTPMonitor: &avimodels.HealthMonitorHTTP{
+			HTTPRequest:      &httpRequest,
+			HTTPResponseCode: []string{"HTTP_2XX"},
+		},
+	}
+	if lib.GetGRBACSupport() {
+		hm.Markers = lib.GetAllMarkers(hm_meta.AviMarkers)
+	}
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/healthmonitor/" + cache_obj.Uuid
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPut, Obj: hm,
+			Tenant: hm_meta.Tenant, Model: "HealthMonitor", Version: utils.CtrlVersion}
+	} else {
+		path = "/api/healthmonitor/"
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPost, Obj: hm,
+			Tenant: hm_meta.Tenant, Model: "HealthMonitor", Version: utils.CtrlVersion}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: HealthMonitor Restop %v AviHealthMonitorMeta %v\n", key,
+		rest_op, utils.Stringify(hm_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviHealthMonitorDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/healthmonitor/" + uuid
+	rest_op := utils.RestOp{Path: path, Method: "DELETE",
+		Tenant: tenant, Model: "HealthMonitor", Version: utils.CtrlVersion}
+	utils.AviLog.Info(spew.Sprintf("key: %s, msg: HealthMonitor DELETE Restop %v \n", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviHealthMonitorCacheAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for healthmonitor, err: %v, response: %v", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "healthmonitor", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find HealthMonitor obj in resp %v", key, rest_op.Response)
+		return errors.New("HealthMonitor not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var hm avimodels.HealthMonitor
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			hm = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor)
+		case avimodels.HealthMonitor:
+			hm = rest_op.Obj.(avimodels.HealthMonitor)
+		}
+		var monitorPort int32
+		if hm.MonitorPort != nil {
+			monitorPort = *hm.MonitorPort
+		}
+
+		var lastModifiedStr string
+		if lastModifiedIntf, ok := resp["_last_modified"]; ok {
+			lastModifiedStr, _ = lastModifiedIntf.(string)
+		}
+
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		hm_cache_obj := avicache.AviHealthMonitorCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			CloudConfigCksum: lib.HealthMonitorChecksum(monitorPort, emptyIngestionMarkers, hm.Markers, true),
+			LastModified:     lastModifiedStr,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.HealthMonitorCache.AviCacheAdd(k, &hm_cache_obj)
+		utils.AviLog.Info(spew.Sprintf("key: %s, msg: Added HealthMonitor cache k %v val %v\n", key, k,
+			hm_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviHealthMonitorCacheDel(rest_op *utils.RestOp, key string) error {
+	hmKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	utils.AviLog.Infof("key: %s, msg: deleting HealthMonitor cache %v", key, hmKey)
+	rest.cache.HealthMonitorCache.AviCacheDelete(hmKey)
+	return nil
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strconv"
+	"strings"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
@@ -65,12 +66,24 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 		action := avimodels.HttpsecurityAction{
 			Action: &sec_rule.Action,
 		}
-		portMatch := avimodels.PortMatch{
-			MatchCriteria: &sec_rule.MatchCriteria,
-			Ports:         []int64{sec_rule.Port},
+		if sec_rule.RateLimit != nil {
+			action.RateProfile = rateLimitToRateProfile(sec_rule.RateLimit)
 		}
-		match := avimodels.MatchTarget{
-			VsPort: &portMatch,
+		match := avimodels.MatchTarget{}
+		if len(sec_rule.SourceRanges) > 0 {
+			match.ClientIP = sourceRangesToIPAddrMatch(sec_rule.SourceRanges, sec_rule.MatchCriteria)
+		} else if sec_rule.StringGroup != "" {
+			match_case := "SENSITIVE"
+			match.Path = &avimodels.PathMatch{
+				MatchCriteria:   &sec_rule.MatchCriteria,
+				MatchCase:       &match_case,
+				StringGroupRefs: []string{fmt.Sprintf("/api/stringgroup/?name=%s", sec_rule.StringGroup)},
+			}
+		} else {
+			match.VsPort = &avimodels.PortMatch{
+				MatchCriteria: &sec_rule.MatchCriteria,
+				Ports:         []int64{sec_rule.Port},
+			}
 		}
 		var j int32
 		j = idx
@@ -124,6 +137,18 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			match_target.VsPort = &vsport_match
 		}
 
+		for _, hdrMatch := range hppmap.HdrMatch {
+			hdr := hdrMatch.Name
+			match_crit := hdrMatch.MatchCriteria
+			match_case := "SENSITIVE"
+			match_target.Hdrs = append(match_target.Hdrs, &avimodels.HdrMatch{
+				Hdr:           &hdr,
+				MatchCase:     &match_case,
+				MatchCriteria: &match_crit,
+				Value:         hdrMatch.Values,
+			})
+		}
+
 		sw_action := avimodels.HttpswitchingAction{}
 		if hppmap.Pool != "" {
 			action := "HTTP_SWITCHING_SELECT_POOL"
@@ -135,16 +160,34 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 			sw_action.Action = &action
 			pg_ref := fmt.Sprintf("/api/poolgroup/?name=%s", hppmap.PoolGroup)
 			sw_action.PoolGroupRef = &pg_ref
+		} else if hppmap.LocalRspStatusCode != "" {
+			action := "HTTP_SWITCHING_SELECT_LOCAL"
+			sw_action.Action = &action
+			statusCode := hppmap.LocalRspStatusCode
+			sw_action.StatusCode = &statusCode
 		}
 
 		var j int32
 		j = idx
 		rule := avimodels.HTTPRequestRule{
-			Index:           &j,
-			Enable:          &enable,
-			Name:            &name,
-			Match:           &match_target,
-			SwitchingAction: &sw_action,
+			Index:  &j,
+			Enable: &enable,
+			Name:   &name,
+			Match:  &match_target,
+		}
+		if sw_action.Action != nil {
+			rule.SwitchingAction = &sw_action
+		}
+		if hppmap.RewritePath != nil {
+			rule.RewriteURLAction = &avimodels.HTTPRewriteURLAction{
+				Path: rewritePathToURIParam(hppmap.RewritePath),
+			}
+		}
+		if hppmap.Redirect != nil {
+			rule.RedirectAction = redirectToHTTPRedirectAction(hppmap.Redirect)
+		}
+		for _, hdrAction := range hppmap.HdrAction {
+			rule.HdrAction = append(rule.HdrAction, hdrActionToHTTPHdrAction(hdrAction))
 		}
 		http_req_pol.Rules = append(http_req_pol.Rules, &rule)
 		idx = idx + 1
@@ -180,6 +223,41 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 		idx = idx + 1
 
 	}
+	if len(hps_meta.ResponseRules) > 0 {
+		http_rsp_pol := avimodels.HTTPResponsePolicy{}
+		for _, rsp_rule := range hps_meta.ResponseRules {
+			enable := true
+			name := fmt.Sprintf("%s-%d", hps_meta.Name, idx)
+			if lib.CheckObjectNameLength(name, lib.HTTPResponseRule) {
+				utils.AviLog.Warnf("key: %s not adding response rule to HTTPS object", key)
+				continue
+			}
+			match_crit := rsp_rule.MatchCriteria
+			match_case := "SENSITIVE"
+			match_target := avimodels.ResponseMatchTarget{
+				Path: &avimodels.PathMatch{
+					MatchCriteria: &match_crit,
+					MatchCase:     &match_case,
+					MatchStr:      rsp_rule.Path,
+				},
+			}
+			var j int32
+			j = idx
+			rule := avimodels.HTTPResponseRule{
+				Index:  &j,
+				Enable: &enable,
+				Name:   &name,
+				Match:  &match_target,
+			}
+			for _, hdrAction := range rsp_rule.HdrAction {
+				rule.HdrAction = append(rule.HdrAction, hdrActionToHTTPHdrAction(hdrAction))
+			}
+			http_rsp_pol.Rules = append(http_rsp_pol.Rules, &rule)
+			idx = idx + 1
+		}
+		hps.HTTPResponsePolicy = &http_rsp_pol
+	}
+
 	if hps_meta.HeaderReWrite != nil {
 		name := fmt.Sprintf("%s-%d", hps_meta.Name, idx)
 		if lib.CheckObjectNameLength(name, lib.HTTPRewriteRule) {
@@ -243,6 +321,101 @@ func (rest *RestOperations) AviHttpPSBuild(hps_meta *nodes.AviHttpPolicySetNode,
 	return &rest_op
 }
 
+// httpSecurityStringGroups returns the names of the stringgroups referred by name in the rules of the http security policy.
+func httpSecurityStringGroups(securityPolicy *avimodels.HttpsecurityPolicy) []string {
+	var stringGroups []string
+	if securityPolicy == nil {
+		return stringGroups
+	}
+	for _, rule := range securityPolicy.Rules {
+		if rule.Match == nil || rule.Match.Path == nil {
+			continue
+		}
+		for _, sgRef := range rule.Match.Path.StringGroupRefs {
+			stringGroups = append(stringGroups, strings.TrimPrefix(sgRef, "/api/stringgroup/?name="))
+		}
+	}
+	return stringGroups
+}
+
+func hdrActionToHTTPHdrAction(hdrAction nodes.AviHostPathHdrAction) *avimodels.HTTPHdrAction {
+	action, hdrName, hdrValue := hdrAction.Action, hdrAction.Name, hdrAction.Value
+	hdrData := &avimodels.HTTPHdrData{Name: &hdrName}
+	if action != "HTTP_REMOVE_HDR" {
+		hdrData.Value = &avimodels.HTTPHdrValue{Val: &hdrValue}
+	}
+	return &avimodels.HTTPHdrAction{Action: &action, Hdr: hdrData}
+}
+
+// rewritePathToURIParam builds the path of the request with the path segments of the StripPrefix replaced by the Prefix.
+func rewritePathToURIParam(rewrite *nodes.AviHostPathRewrite) *avimodels.URIParam {
+	paramType := "URI_PARAM_TYPE_TOKENIZED"
+	uriParam := &avimodels.URIParam{Type: &paramType}
+	if prefix := strings.Trim(rewrite.Prefix, "/"); prefix != "" {
+		tokenType := "URI_TOKEN_TYPE_STRING"
+		uriParam.Tokens = append(uriParam.Tokens, &avimodels.URIParamToken{Type: &tokenType, StrValue: &prefix})
+	}
+	var startIndex int32
+	if stripPrefix := strings.Trim(rewrite.StripPrefix, "/"); stripPrefix != "" {
+		startIndex = int32(len(strings.Split(stripPrefix, "/")))
+	}
+	// the end index 65535 denotes the last path segment of the request
+	tokenType, endIndex := "URI_TOKEN_TYPE_PATH", int32(65535)
+	uriParam.Tokens = append(uriParam.Tokens, &avimodels.URIParamToken{Type: &tokenType, StartIndex: &startIndex, EndIndex: &endIndex})
+	return uriParam
+}
+
+func redirectToHTTPRedirectAction(redirect *nodes.AviHostPathRedirect) *avimodels.HTTPRedirectAction {
+	keepQuery := true
+	protocol, statusCode := redirect.Protocol, redirect.StatusCode
+	redirectAction := &avimodels.HTTPRedirectAction{
+		Protocol:   &protocol,
+		StatusCode: &statusCode,
+		KeepQuery:  &keepQuery,
+	}
+	paramType, tokenType := "URI_PARAM_TYPE_TOKENIZED", "URI_TOKEN_TYPE_STRING"
+	if redirect.Host != "" {
+		host := redirect.Host
+		redirectAction.Host = &avimodels.URIParam{
+			Type:   &paramType,
+			Tokens: []*avimodels.URIParamToken{{Type: &tokenType, StrValue: &host}},
+		}
+	}
+	if redirect.Path != "" {
+		path := strings.TrimPrefix(redirect.Path, "/")
+		redirectAction.Path = &avimodels.URIParam{
+			Type:   &paramType,
+			Tokens: []*avimodels.URIParamToken{{Type: &tokenType, StrValue: &path}},
+		}
+	}
+	if redirect.Port != 0 {
+		port := redirect.Port
+		redirectAction.Port = &port
+	}
+	return redirectAction
+}
+
+func rateLimitToRateProfile(rateLimit *nodes.AviRateLimit) *avimodels.HttpsecurityActionRateProfile {
+	count, period, burstSize := rateLimit.Count, rateLimit.Period, rateLimit.BurstSize
+	perClientIP, actionType := rateLimit.PerClientIP, rateLimit.Action
+	rateProfile := &avimodels.HttpsecurityActionRateProfile{
+		RateLimiter: &avimodels.RateLimiter{Count: &count, Period: &period},
+		PerClientIP: &perClientIP,
+		Action:      &avimodels.RateLimiterAction{Type: &actionType},
+	}
+	if burstSize != 0 {
+		rateProfile.RateLimiter.BurstSz = &burstSize
+	}
+	if rateLimit.StatusCode != "" {
+		statusCode := rateLimit.StatusCode
+		rateProfile.Action.StatusCode = &statusCode
+	}
+	if rateLimit.Redirect != nil {
+		rateProfile.Action.Redirect = redirectToHTTPRedirectAction(rateLimit.Redirect)
+	}
+	return rateProfile
+}
+
 func (rest *RestOperations) AviHttpPolicyDel(uuid string, tenant string, key string) *utils.RestOp {
 	path := "/api/httppolicyset/" + uuid
 	rest_op := utils.RestOp{Path: path, Method: "DELETE",
@@ -294,7 +467,7 @@ func (rest *RestOperations) AviHTTPPolicyCacheAdd(rest_op *utils.RestOp, vsKey a
 		if resp["http_request_policy"] != nil {
 			rules, rulessOk := resp["http_request_policy"].(map[string]interface{})
 			if rulessOk {
-				rulesArr := rules["rules"].([]interface{})
+				rulesArr, _ := rules["rules"].([]interface{})
 				for _, ruleIntf := range rulesArr {
 					rulemap, _ := ruleIntf.(map[string]interface{})
 					if rulemap["switching_action"] != nil {
@@ -317,12 +490,22 @@ func (rest *RestOperations) AviHTTPPolicyCacheAdd(rest_op *utils.RestOp, vsKey a
 				}
 			}
 		}
+		var sgMembers []string
+		switch rest_op.Obj.(type) {
+		case avimodels.HTTPPolicySet:
+			sgMembers = httpSecurityStringGroups(rest_op.Obj.(avimodels.HTTPPolicySet).HTTPSecurityPolicy)
+		case utils.AviRestObjMacro:
+			if hps, ok := rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HTTPPolicySet); ok {
+				sgMembers = httpSecurityStringGroups(hps.HTTPSecurityPolicy)
+			}
+		}
 		http_cache_obj := avicache.AviHTTPPolicyCache{Name: name, Tenant: rest_op.Tenant,
 			Uuid:             uuid,
 			CloudConfigCksum: cksum,
 			LastModified:     lastModifiedStr,
 			PoolGroups:       pgMembers,
 			Pools:            poolMembers,
+			StringGroups:     sgMembers,
 		}
 		if lastModifiedStr == "" {
 			http_cache_obj.InvalidData = true
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviNSPolicyBuild(nsp_meta *nodes.AviNetworkSecurityPolicyNode, cache_obj *avicache.AviNSPolicyCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(nsp_meta.Name, lib.NSP) {
+		utils.AviLog.Warnf("key: %s not processing network security policy object", key)
+		return nil
+	}
+	name := nsp_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", nsp_meta.Tenant)
+	cr := lib.AKOUser
+	cksum := strconv.Itoa(int(nsp_meta.GetCheckSum()))
+
+	nsp := avimodels.NetworkSecurityPolicy{Name: &name,
+		CreatedBy: &cr, TenantRef: &tenant, CloudConfigCksum: &cksum}
+	if lib.GetGRBACSupport() {
+		nsp.Markers = lib.GetAllMarkers(nsp_meta.AviMarkers)
+	}
+
+	// A single rule denies all the clients which are not in the allowed source ranges.
+	ruleName := name + "-sourceranges"
+	action := "NETWORK_SECURITY_POLICY_ACTION_TYPE_DENY"
+	enable := true
+	var idx int32
+	nsp.Rules = []*avimodels.NetworkSecurityRule{{
+		Name:   &ruleName,
+		Action: &action,
+		Enable: &enable,
+		Index:  &idx,
+		Match: &avimodels.NetworkSecurityMatchTarget{
+			ClientIP: sourceRangesToIPAddrMatch(nsp_meta.SourceRanges, lib.IS_NOT_IN),
+		},
+	}}
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/networksecuritypolicy/" + cache_obj.Uuid
+		rest_op = utils.RestOp{Path: path, Method: utils.RestPut, Obj: nsp,
+			Tenant: nsp_meta.Tenant, Model: "NetworkSecurityPolicy", Version: utils.CtrlVersion}
+	} else {
+		// Patch an existing network security policy object if it exists in the cache but not associated with this VS.
+		nsp_key := avicache.NamespaceName{Namespace: nsp_meta.Tenant, Name: nsp_meta.Name}
+		nsp_cache, ok := rest.cache.NSPolicyCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNSPolicyCache)
+			path = "/api/networksecuritypolicy/" + nsp_cache_obj.Uuid
+			rest_op = utils.RestOp{Path: path, Method: utils.RestPut, Obj: nsp,
+				Tenant: nsp_meta.Tenant, Model: "NetworkSecurityPolicy", Version: utils.CtrlVersion}
+		} else {
+			path = "/api/networksecuritypolicy/"
+			rest_op = utils.RestOp{Path: path, Method: utils.RestPost, Obj: nsp,
+				Tenant: nsp_meta.Tenant, Model: "NetworkSecurityPolicy", Version: utils.CtrlVersion}
+		}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("NetworkSecurityPolicy Restop %v AviNetworkSecurityPolicyMeta %v\n",
+		rest_op, utils.Stringify(nsp_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNSPolicyDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/networksecuritypolicy/" + uuid
+	rest_op := utils.RestOp{Path: path, Method: "DELETE",
+		Tenant: tenant, Model: "NetworkSecurityPolicy", Version: utils.CtrlVersion}
+	utils.AviLog.Infof(spew.Sprintf("Network Security Policy DELETE Restop %v \n",
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviNSPolicyCacheAdd(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for networksecuritypolicy, err: %s, response: %s", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "networksecuritypolicy", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("Unable to find Network Security Policy obj in resp %v", rest_op.Response)
+		return errors.New("Network Security Policy object not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("Name not present in response %v", resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("Uuid not present in response %v", resp)
+			continue
+		}
+
+		cksum, _ := resp["cloud_config_cksum"].(string)
+
+		var lastModifiedStr string
+		lastModifiedIntf, ok := resp["_last_modified"]
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: last_modified not present in response %v", key, resp)
+		} else {
+			lastModifiedStr, ok = lastModifiedIntf.(string)
+			if !ok {
+				utils.AviLog.Warnf("key: %s, msg: last_modified is not of type string", key)
+			}
+		}
+
+		nsp_cache_obj := avicache.AviNSPolicyCache{Name: name, Tenant: rest_op.Tenant,
+			Uuid:             uuid,
+			LastModified:     lastModifiedStr,
+			CloudConfigCksum: cksum,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.NSPolicyCache.AviCacheAdd(k, &nsp_cache_obj)
+		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if ok {
+			vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+			if found {
+				vs_cache_obj.AddToNSPolicyCollection(k)
+				utils.AviLog.Infof("Modified the VS cache for networksecuritypolicy object. The cache now is :%v", utils.Stringify(vs_cache_obj))
+			}
+		} else {
+			vs_cache_obj := rest.cache.VsCacheMeta.AviCacheAddVS(vsKey)
+			vs_cache_obj.AddToNSPolicyCollection(k)
+			utils.AviLog.Info(spew.Sprintf("Added VS cache key during network security policy update %v val %v\n", vsKey,
+				vs_cache_obj))
+		}
+		utils.AviLog.Info(spew.Sprintf("Added Network Security Policy cache k %v val %v\n", k,
+			nsp_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviNSPolicyCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
+	nspKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	rest.cache.NSPolicyCache.AviCacheDelete(nspKey)
+	vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+	if ok {
+		vs_cache_obj, found := vs_cache.(*avicache.AviVsCache)
+		if found {
+			vs_cache_obj.RemoveFromNSPolicyCollection(nspKey)
+		}
+	}
+
+	return nil
+}
+
+// sourceRangesToIPAddrMatch converts the source range CIDRs to a client IP match.
+func sourceRangesToIPAddrMatch(sourceRanges []string, matchCriteria string) *avimodels.IPAddrMatch {
+	var prefixes []*avimodels.IPAddrPrefix
+	for _, sourceRange := range sourceRanges {
+		ipType := "V4"
+		if strings.Contains(sourceRange, ":") {
+			ipType = "V6"
+		}
+		if prefix := cidrToIPAddrPrefix(sourceRange, ipType); prefix != nil {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	return &avimodels.IPAddrMatch{
+		MatchCriteria: &matchCriteria,
+		Prefixes:      prefixes,
+	}
+}
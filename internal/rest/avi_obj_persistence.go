@@ -0,0 +1,145 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviPersistenceProfileBuild(persistence_meta *nodes.AviPersistenceProfileNode, cache_obj *avicache.AviPersistenceProfileCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(persistence_meta.Name, lib.PersistenceProfile) {
+		utils.AviLog.Warnf("key: %s not processing application persistence profile object", key)
+		return nil
+	}
+	name := persistence_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", persistence_meta.Tenant)
+	persistenceType := lib.PERSISTENCE_TYPE_CLIENT_IP
+	timeout := persistence_meta.Timeout
+
+	persistence := avimodels.ApplicationPersistenceProfile{
+		Name:            &name,
+		TenantRef:       &tenant,
+		PersistenceType: &persistenceType,
+		IPPersistenceProfile: &avimodels.IPPersistenceProfile{
+			IPPersistentTimeout: &timeout,
+		},
+	}
+	if lib.GetGRBACSupport() {
+		persistence.Markers = lib.GetAllMarkers(persistence_meta.AviMarkers)
+	}
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/applicationpersistenceprofile/" + cache_obj.Uuid
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPut, Obj: persistence,
+			Tenant: persistence_meta.Tenant, Model: "ApplicationPersistenceProfile", Version: utils.CtrlVersion}
+	} else {
+		path = "/api/applicationpersistenceprofile/"
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPost, Obj: persistence,
+			Tenant: persistence_meta.Tenant, Model: "ApplicationPersistenceProfile", Version: utils.CtrlVersion}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: ApplicationPersistenceProfile Restop %v AviPersistenceProfileMeta %v\n", key,
+		rest_op, utils.Stringify(persistence_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviPersistenceProfileDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/applicationpersistenceprofile/" + uuid
+	rest_op := utils.RestOp{Path: path, Method: "DELETE",
+		Tenant: tenant, Model: "ApplicationPersistenceProfile", Version: utils.CtrlVersion}
+	utils.AviLog.Info(spew.Sprintf("key: %s, msg: ApplicationPersistenceProfile DELETE Restop %v \n", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviPersistenceProfileCacheAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for applicationpersistenceprofile, err: %v, response: %v", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "applicationpersistenceprofile", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find ApplicationPersistenceProfile obj in resp %v", key, rest_op.Response)
+		return errors.New("ApplicationPersistenceProfile not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var persistence avimodels.ApplicationPersistenceProfile
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			persistence = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationPersistenceProfile)
+		case avimodels.ApplicationPersistenceProfile:
+			persistence = rest_op.Obj.(avimodels.ApplicationPersistenceProfile)
+		}
+		var timeout int32
+		if persistence.IPPersistenceProfile != nil && persistence.IPPersistenceProfile.IPPersistentTimeout != nil {
+			timeout = *persistence.IPPersistenceProfile.IPPersistentTimeout
+		}
+
+		var lastModifiedStr string
+		if lastModifiedIntf, ok := resp["_last_modified"]; ok {
+			lastModifiedStr, _ = lastModifiedIntf.(string)
+		}
+
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		persistence_cache_obj := avicache.AviPersistenceProfileCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			CloudConfigCksum: lib.PersistenceProfileChecksum(timeout, emptyIngestionMarkers, persistence.Markers, true),
+			LastModified:     lastModifiedStr,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.PersistenceCache.AviCacheAdd(k, &persistence_cache_obj)
+		utils.AviLog.Info(spew.Sprintf("key: %s, msg: Added ApplicationPersistenceProfile cache k %v val %v\n", key, k,
+			persistence_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviPersistenceProfileCacheDel(rest_op *utils.RestOp, key string) error {
+	persistenceKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	utils.AviLog.Infof("key: %s, msg: deleting ApplicationPersistenceProfile cache %v", key, persistenceKey)
+	rest.cache.PersistenceCache.AviCacheDelete(persistenceKey)
+	return nil
+}
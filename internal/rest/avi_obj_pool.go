@@ -20,10 +20,12 @@ import (
 	"net"
 	"strconv"
 	"strings"
+	"time"
 
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -125,6 +127,23 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 
 	if pool_meta.ApplicationPersistence != "" {
 		pool.ApplicationPersistenceProfileRef = &pool_meta.ApplicationPersistence
+	} else if pool_meta.PersistenceProfile != nil {
+		persistenceProfileRef := "/api/applicationpersistenceprofile?name=" + pool_meta.PersistenceProfile.Name
+		pool.ApplicationPersistenceProfileRef = &persistenceProfileRef
+	}
+
+	if pool_meta.MaxConcurrentConns != 0 {
+		pool.MaxConcurrentConnectionsPerServer = &pool_meta.MaxConcurrentConns
+	}
+
+	if lib.IsPodReadinessGateEnabled() {
+		var serverIPs []string
+		for _, server := range pool_meta.Servers {
+			if server.Ip.Addr != nil {
+				serverIPs = append(serverIPs, *server.Ip.Addr)
+			}
+		}
+		objects.SharedPoolServerLister().SetServers(pool_meta.Tenant+"/"+name, serverIPs)
 	}
 
 	for i, server := range pool_meta.Servers {
@@ -138,12 +157,33 @@ func (rest *RestOperations) AviPoolBuild(pool_meta *nodes.AviPoolNode, cache_obj
 			sn := server.ServerNode
 			s.ServerNode = &sn
 		}
+		if server.Disabled {
+			enabled := false
+			s.Enabled = &enabled
+		}
+		if server.DrainDeadline != nil {
+			description := lib.GetServerDrainDescription(*server.DrainDeadline)
+			s.Description = &description
+		}
+		if server.Ratio > 0 {
+			ratio := server.Ratio
+			s.Ratio = &ratio
+		}
 		pool.Servers = append(pool.Servers, &s)
 	}
 
+	// The disabled servers are gracefully disabled, letting the existing connections complete until the drain timeout,
+	// which is in whole minutes.
+	if drainTimeout := lib.GetServerDrainTimeout(); drainTimeout > 0 {
+		gracefulDisableTimeout := int32(drainTimeout / time.Minute)
+		pool.GracefulDisableTimeout = &gracefulDisableTimeout
+	}
+
 	// overwrite with healthmonitors provided by CRD
 	if len(pool_meta.HealthMonitors) > 0 {
 		pool.HealthMonitorRefs = pool_meta.HealthMonitors
+	} else if pool_meta.HealthMonitorNode != nil {
+		pool.HealthMonitorRefs = append(pool.HealthMonitorRefs, "/api/healthmonitor?name="+pool_meta.HealthMonitorNode.Name)
 	} else {
 		var hm string
 		if pool_meta.Protocol == utils.UDP {
@@ -254,6 +294,7 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 			ServiceMetadataObj:   svc_mdata_obj,
 			PkiProfileCollection: pkiKey,
 			LastModified:         lastModifiedStr,
+			Servers:              getPoolServersFromResponse(resp, key),
 		}
 		if lastModifiedStr == "" {
 			pool_cache_obj.InvalidData = true
@@ -261,6 +302,9 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 
 		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
 		rest.cache.PoolCache.AviCacheAdd(k, &pool_cache_obj)
+		if lib.IsPodReadinessGateEnabled() {
+			rest.updatePoolServersAdded(k, resp, key)
+		}
 		// Update the VS object
 		vs_cache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
 		if ok {
@@ -271,6 +315,7 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 				if len(svc_mdata_obj.NamespaceServiceName) > 0 {
 					updateOptions := status.UpdateOptions{
 						Vip:                vs_cache_obj.Vip,
+						V6Vip:              vs_cache_obj.V6Vip,
 						ServiceMetadata:    svc_mdata_obj,
 						Key:                key,
 						VirtualServiceUUID: vs_cache_obj.Uuid,
@@ -284,6 +329,7 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 				} else if svc_mdata_obj.Namespace != "" {
 					updateOptions := status.UpdateOptions{
 						Vip:                vs_cache_obj.Vip,
+						V6Vip:              vs_cache_obj.V6Vip,
 						ServiceMetadata:    svc_mdata_obj,
 						Key:                key,
 						VirtualServiceUUID: vs_cache_obj.Uuid,
@@ -312,6 +358,60 @@ func (rest *RestOperations) AviPoolCacheAdd(rest_op *utils.RestOp, vsKey avicach
 	return nil
 }
 
+// getPoolServersFromResponse returns the servers in the pool response, along with the drain deadlines of the
+// servers being drained.
+func getPoolServersFromResponse(resp map[string]interface{}, key string) map[string]avicache.AviPoolServerCache {
+	serversIntf, ok := resp["servers"]
+	if !ok {
+		return nil
+	}
+	var servers []*avimodels.Server
+	serversBytes, _ := json.Marshal(serversIntf)
+	if err := json.Unmarshal(serversBytes, &servers); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error in parsing the pool servers: %v", key, err)
+		return nil
+	}
+	return avicache.GetPoolServersCache(servers)
+}
+
+// updatePoolServersAdded marks the servers present in the pool response as added on the controller, and
+// publishes the pool to the status queue to set the readiness gate of the pods backing the servers.
+// In the dry run mode the servers are never added on the controller, and the readiness gates are left unset.
+func (rest *RestOperations) updatePoolServersAdded(poolKey avicache.NamespaceName, resp map[string]interface{}, key string) {
+	if lib.IsDryRun() {
+		return
+	}
+	servers, ok := resp["servers"].([]interface{})
+	if !ok {
+		return
+	}
+	var serverIPs []string
+	for _, serverIntf := range servers {
+		server, ok := serverIntf.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ip, ok := server["ip"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if addr, ok := ip["addr"].(string); ok {
+			serverIPs = append(serverIPs, addr)
+		}
+	}
+
+	pool := poolKey.Namespace + "/" + poolKey.Name
+	objects.SharedPoolServerLister().MarkServersAdded(pool, serverIPs)
+	statusOption := status.StatusOptions{
+		ObjType:   lib.PodReadinessGate,
+		Op:        lib.UpdateStatus,
+		ObjName:   poolKey.Name,
+		Namespace: poolKey.Namespace,
+		Key:       key,
+	}
+	status.PublishToStatusQueue(pool, statusOption)
+}
+
 func (rest *RestOperations) AviPoolCacheDel(rest_op *utils.RestOp, vsKey avicache.NamespaceName, key string) error {
 	// Delete the pool from the vs cache as well.
 	poolKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
@@ -329,6 +429,7 @@ func (rest *RestOperations) AviPoolCacheDel(rest_op *utils.RestOp, vsKey avicach
 	rest.DeletePoolIngressStatus(poolKey, false, key)
 	// Now delete the cache.
 	rest.cache.PoolCache.AviCacheDelete(poolKey)
+	objects.SharedPoolServerLister().DeletePool(poolKey.Namespace + "/" + poolKey.Name)
 
 	return nil
 }
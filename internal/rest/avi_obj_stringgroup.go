@@ -0,0 +1,146 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"errors"
+	"fmt"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	avimodels "github.com/vmware/alb-sdk/go/models"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func (rest *RestOperations) AviStringGroupBuild(sg_meta *nodes.AviStringGroupNode, cache_obj *avicache.AviStringGroupCache, key string) *utils.RestOp {
+	if lib.CheckObjectNameLength(sg_meta.Name, lib.StringGroup) {
+		utils.AviLog.Warnf("key: %s not processing stringgroup object", key)
+		return nil
+	}
+	name := sg_meta.Name
+	tenant := fmt.Sprintf("/api/tenant/?name=%s", sg_meta.Tenant)
+	sgType := lib.SG_TYPE_STRING
+
+	sg := avimodels.StringGroup{
+		Name:      &name,
+		TenantRef: &tenant,
+		Type:      &sgType,
+	}
+	for i := range sg_meta.Values {
+		sg.Kv = append(sg.Kv, &avimodels.KeyValue{Key: &sg_meta.Values[i]})
+	}
+	if lib.GetGRBACSupport() {
+		sg.Markers = lib.GetAllMarkers(sg_meta.AviMarkers)
+	}
+
+	var path string
+	var rest_op utils.RestOp
+	if cache_obj != nil {
+		path = "/api/stringgroup/" + cache_obj.Uuid
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPut, Obj: sg,
+			Tenant: sg_meta.Tenant, Model: "StringGroup", Version: utils.CtrlVersion}
+	} else {
+		path = "/api/stringgroup/"
+		rest_op = utils.RestOp{ObjName: name, Path: path, Method: utils.RestPost, Obj: sg,
+			Tenant: sg_meta.Tenant, Model: "StringGroup", Version: utils.CtrlVersion}
+	}
+
+	utils.AviLog.Debug(spew.Sprintf("key: %s, msg: StringGroup Restop %v AviStringGroupMeta %v\n", key,
+		rest_op, utils.Stringify(sg_meta)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviStringGroupDel(uuid string, tenant string, key string) *utils.RestOp {
+	path := "/api/stringgroup/" + uuid
+	rest_op := utils.RestOp{Path: path, Method: "DELETE",
+		Tenant: tenant, Model: "StringGroup", Version: utils.CtrlVersion}
+	utils.AviLog.Info(spew.Sprintf("key: %s, msg: StringGroup DELETE Restop %v \n", key,
+		utils.Stringify(rest_op)))
+	return &rest_op
+}
+
+func (rest *RestOperations) AviStringGroupCacheAdd(rest_op *utils.RestOp, key string) error {
+	if (rest_op.Err != nil) || (rest_op.Response == nil) {
+		utils.AviLog.Warnf("key: %s, rest_op has err or no response for stringgroup, err: %v, response: %v", key, rest_op.Err, rest_op.Response)
+		return errors.New("Errored rest_op")
+	}
+
+	resp_elems := RestRespArrToObjByType(rest_op, "stringgroup", key)
+	if resp_elems == nil {
+		utils.AviLog.Warnf("key: %s, msg: unable to find StringGroup obj in resp %v", key, rest_op.Response)
+		return errors.New("StringGroup not found")
+	}
+
+	for _, resp := range resp_elems {
+		name, ok := resp["name"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Name not present in response %v", key, resp)
+			continue
+		}
+
+		uuid, ok := resp["uuid"].(string)
+		if !ok {
+			utils.AviLog.Warnf("key: %s, msg: Uuid not present in response %v", key, resp)
+			continue
+		}
+
+		var sg avimodels.StringGroup
+		switch rest_op.Obj.(type) {
+		case utils.AviRestObjMacro:
+			sg = rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.StringGroup)
+		case avimodels.StringGroup:
+			sg = rest_op.Obj.(avimodels.StringGroup)
+		}
+		var values []string
+		for _, kv := range sg.Kv {
+			if kv.Key != nil {
+				values = append(values, *kv.Key)
+			}
+		}
+
+		var lastModifiedStr string
+		if lastModifiedIntf, ok := resp["_last_modified"]; ok {
+			lastModifiedStr, _ = lastModifiedIntf.(string)
+		}
+
+		emptyIngestionMarkers := utils.AviObjectMarkers{}
+		sg_cache_obj := avicache.AviStringGroupCache{
+			Name:             name,
+			Tenant:           rest_op.Tenant,
+			Uuid:             uuid,
+			CloudConfigCksum: lib.StringGroupChecksum(values, emptyIngestionMarkers, sg.Markers, true),
+			LastModified:     lastModifiedStr,
+		}
+
+		k := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: name}
+		rest.cache.StringGroupCache.AviCacheAdd(k, &sg_cache_obj)
+		utils.AviLog.Info(spew.Sprintf("key: %s, msg: Added StringGroup cache k %v val %v\n", key, k,
+			sg_cache_obj))
+	}
+
+	return nil
+}
+
+func (rest *RestOperations) AviStringGroupCacheDel(rest_op *utils.RestOp, key string) error {
+	sgKey := avicache.NamespaceName{Namespace: rest_op.Tenant, Name: rest_op.ObjName}
+	utils.AviLog.Infof("key: %s, msg: deleting StringGroup cache %v", key, sgKey)
+	rest.cache.StringGroupCache.AviCacheDelete(sgKey)
+	return nil
+}
@@ -123,8 +123,12 @@ func (rest *RestOperations) AviVsBuild(vs_meta *nodes.AviVsNode, rest_method uti
 		}
 		vs.NetworkProfileRef = proto.String("/api/networkprofile/?name=" + vs_meta.NetworkProfile)
 
-		if vs_meta.SharedVS {
-			// This is a shared VS - which should have a datascript
+		if vs_meta.AnalyticsProfileRef != "" {
+			vs.AnalyticsProfileRef = &vs_meta.AnalyticsProfileRef
+		}
+
+		if vs_meta.SharedVS || len(vs_meta.HTTPDSrefs) > 0 {
+			// This is a shared VS or a gateway VS with TLS passthrough - which should have a datascript
 			var i int32
 			var vsdatascripts []*avimodels.VSDataScripts
 			for _, ds := range vs_meta.HTTPDSrefs {
@@ -182,6 +186,10 @@ func (rest *RestOperations) AviVsBuild(vs_meta *nodes.AviVsNode, rest_method uti
 			}
 			vs.L4Policies = l4Policies
 		}
+		for _, nsp := range vs_meta.NSPolicyRefs {
+			nspRef := fmt.Sprintf("/api/networksecuritypolicy/?name=%s", nsp.Name)
+			vs.NetworkSecurityPolicyRef = &nspRef
+		}
 
 		var rest_ops []*utils.RestOp
 
@@ -459,6 +467,9 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 									vs_cache_obj.Vip = vip
 									utils.AviLog.Info(spew.Sprintf("key: %s, msg: updated vsvip to the cache: %s", key, vip))
 								}
+								if len(vsvip_cache_obj.V6IPs) > 0 {
+									vs_cache_obj.V6Vip = vsvip_cache_obj.V6IPs[0]
+								}
 							}
 						}
 					}
@@ -496,6 +507,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 								if len(pool_cache_obj.ServiceMetadataObj.NamespaceServiceName) > 0 {
 									updateOptions := status.UpdateOptions{
 										Vip:                vs_cache_obj.Vip,
+										V6Vip:              vs_cache_obj.V6Vip,
 										ServiceMetadata:    pool_cache_obj.ServiceMetadataObj,
 										Key:                key,
 										VirtualServiceUUID: vs_cache_obj.Uuid,
@@ -509,6 +521,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 								} else if pool_cache_obj.ServiceMetadataObj.Namespace != "" {
 									updateOptions := status.UpdateOptions{
 										Vip:                vs_cache_obj.Vip,
+										V6Vip:              vs_cache_obj.V6Vip,
 										ServiceMetadata:    pool_cache_obj.ServiceMetadataObj,
 										Key:                key,
 										VirtualServiceUUID: vs_cache_obj.Uuid,
@@ -557,6 +570,9 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 								vs_cache_obj.Vip = vip
 								utils.AviLog.Info(spew.Sprintf("key: %s, msg: added vsvip to the cache: %s", key, vip))
 							}
+							if len(vsvip_cache_obj.V6IPs) > 0 {
+								vs_cache_obj.V6Vip = vsvip_cache_obj.V6IPs[0]
+							}
 						}
 					}
 				}
@@ -569,6 +585,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 		if svc_mdata_obj.Gateway != "" {
 			updateOptions := status.UpdateOptions{
 				Vip:             vs_cache_obj.Vip,
+				V6Vip:           vs_cache_obj.V6Vip,
 				ServiceMetadata: svc_mdata_obj,
 				Key:             key,
 			}
@@ -586,6 +603,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 			// This service needs an update of the status
 			updateOptions := status.UpdateOptions{
 				Vip:                vs_cache_obj.Vip,
+				V6Vip:              vs_cache_obj.V6Vip,
 				ServiceMetadata:    svc_mdata_obj,
 				Key:                key,
 				VirtualServiceUUID: vs_cache_obj.Uuid,
@@ -599,6 +617,7 @@ func (rest *RestOperations) AviVsCacheAdd(rest_op *utils.RestOp, key string) err
 		} else if (svc_mdata_obj.IngressName != "" || len(svc_mdata_obj.NamespaceIngressName) > 0) && svc_mdata_obj.Namespace != "" && parentVsObj != nil {
 			updateOptions := status.UpdateOptions{
 				Vip:                parentVsObj.Vip,
+				V6Vip:              parentVsObj.V6Vip,
 				ServiceMetadata:    svc_mdata_obj,
 				Key:                key,
 				VirtualServiceUUID: vs_cache_obj.Uuid,
@@ -46,7 +46,7 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 	var dns_info_arr []*avimodels.DNSInfo
 	var path string
 	var rest_op utils.RestOp
-	vipId, ipType := "0", "V4"
+	vipId, ipType, ip6Type := "0", "V4", "V6"
 
 	cksum := vsvip_meta.CloudConfigCksum
 	cksumstr := strconv.Itoa(int(cksum))
@@ -86,6 +86,12 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 		if vsvip_meta.IPAddress != "" {
 			vip.IPAddress = &avimodels.IPAddr{Type: &ipType, Addr: &vsvip_meta.IPAddress}
 		}
+		if vsvip_meta.IPv6Address != "" {
+			vip.Ip6Address = &avimodels.IPAddr{Type: &ip6Type, Addr: &vsvip_meta.IPv6Address}
+		}
+		if vsvip_meta.IPType != "" {
+			vip.AutoAllocateIPType = &vsvip_meta.IPType
+		}
 
 		if lib.IsPublicCloud() && lib.GetCloudType() != lib.CLOUD_GCP {
 			vips := networkNamesToVips(vsvip_meta.VipNetworks, vsvip_meta.EnablePublicIP)
@@ -138,6 +144,12 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 		if vsvip_meta.IPAddress != "" {
 			vip.IPAddress = &avimodels.IPAddr{Type: &ipType, Addr: &vsvip_meta.IPAddress}
 		}
+		if vsvip_meta.IPv6Address != "" {
+			vip.Ip6Address = &avimodels.IPAddr{Type: &ip6Type, Addr: &vsvip_meta.IPv6Address}
+		}
+		if vsvip_meta.IPType != "" {
+			vip.AutoAllocateIPType = &vsvip_meta.IPType
+		}
 
 		// selecting network with user input, in case user input is not provided AKO relies on
 		// usable network configuration in ipamdnsproviderprofile
@@ -154,25 +166,16 @@ func (rest *RestOperations) AviVsVipBuild(vsvip_meta *nodes.AviVSVIPNode, vsCach
 				vip.IPAMNetworkSubnet.NetworkRef = &networkRef
 
 				// setting IPAMNetworkSubnet.Subnet value in case subnetCIDR is provided
-				if vipNetwork.Cidr == "" {
+				if vipNetwork.Cidr == "" && vipNetwork.V6Cidr == "" {
 					utils.AviLog.Warnf("Incomplete values provided for CIDR, will not use IPAMNetworkSubnet in vsvip")
-				} else {
-					ipPrefixSlice := strings.Split(vipNetwork.Cidr, "/")
-					mask, _ := strconv.Atoi(ipPrefixSlice[1])
-					if lib.IsPublicCloud() && lib.GetCloudType() == lib.CLOUD_GCP {
-						vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
-							Subnet: &avimodels.IPAddrPrefix{
-								IPAddr: &avimodels.IPAddr{Type: &ipType, Addr: &ipPrefixSlice[0]},
-								Mask:   proto.Int32(int32(mask)),
-							},
-						}
-					} else if !lib.GetAdvancedL4() {
-						vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
-							Subnet: &avimodels.IPAddrPrefix{
-								IPAddr: &avimodels.IPAddr{Type: &ipType, Addr: &ipPrefixSlice[0]},
-								Mask:   proto.Int32(int32(mask)),
-							},
-						}
+				} else if lib.IsPublicCloud() && lib.GetCloudType() == lib.CLOUD_GCP {
+					vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
+						Subnet: cidrToIPAddrPrefix(vipNetwork.Cidr, ipType),
+					}
+				} else if !lib.GetAdvancedL4() {
+					vip.IPAMNetworkSubnet = &avimodels.IPNetworkSubnet{
+						Subnet:  cidrToIPAddrPrefix(vipNetwork.Cidr, ipType),
+						Subnet6: cidrToIPAddrPrefix(vipNetwork.V6Cidr, ip6Type),
 					}
 				}
 			}
@@ -428,7 +431,7 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 			}
 		}
 
-		var vsvipVips []string
+		var vsvipVips, vsvipV6Vips []string
 		var networkNames []string
 		if _, found := resp["vip"]; found {
 			if vips, ok := resp["vip"].([]interface{}); ok {
@@ -438,17 +441,26 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 						utils.AviLog.Infof("key: %s, msg: invalid type for vip in vsvip: %s", key, name)
 						continue
 					}
-					ip_address, valid := vip["ip_address"].(map[string]interface{})
-					if !valid {
+					ip_address, v4Valid := vip["ip_address"].(map[string]interface{})
+					ip6_address, v6Valid := vip["ip6_address"].(map[string]interface{})
+					if !v4Valid && !v6Valid {
 						utils.AviLog.Infof("key: %s, msg: invalid type for ip_address in vsvip: %s", key, name)
 						continue
 					}
-					addr, valid := ip_address["addr"].(string)
-					if !valid {
-						utils.AviLog.Infof("key: %s, msg: invalid type for addr in vsvip: %s", key, name)
-						continue
+					if v4Valid {
+						if addr, valid := ip_address["addr"].(string); valid {
+							vsvipVips = append(vsvipVips, addr)
+						} else {
+							utils.AviLog.Infof("key: %s, msg: invalid type for addr in vsvip: %s", key, name)
+						}
+					}
+					if v6Valid {
+						if addr, valid := ip6_address["addr"].(string); valid {
+							vsvipV6Vips = append(vsvipV6Vips, addr)
+						} else {
+							utils.AviLog.Infof("key: %s, msg: invalid type for ip6 addr in vsvip: %s", key, name)
+						}
 					}
-					vsvipVips = append(vsvipVips, addr)
 					if ipamNetworkSubnet, ipamOk := vip["ipam_network_subnet"].(map[string]interface{}); ipamOk {
 						if networkRef, netRefOk := ipamNetworkSubnet["network_ref"].(string); netRefOk {
 							if networkRefName := strings.Split(networkRef, "#"); len(networkRefName) == 2 {
@@ -471,6 +483,7 @@ func (rest *RestOperations) AviVsVipCacheAdd(rest_op *utils.RestOp, vsKey avicac
 			LastModified: lastModifiedStr,
 			FQDNs:        vsvipFQDNs,
 			Vips:         vsvipVips,
+			V6IPs:        vsvipV6Vips,
 			NetworkNames: networkNames,
 		}
 
@@ -522,6 +535,23 @@ func (rest *RestOperations) AviVsVipCacheDel(rest_op *utils.RestOp, vsKey avicac
 	return nil
 }
 
+// cidrToIPAddrPrefix converts a CIDR to the Avi IPAddrPrefix, returns nil for an empty CIDR.
+func cidrToIPAddrPrefix(cidr, ipType string) *avimodels.IPAddrPrefix {
+	if cidr == "" {
+		return nil
+	}
+	ipPrefixSlice := strings.Split(cidr, "/")
+	if len(ipPrefixSlice) != 2 {
+		utils.AviLog.Warnf("Invalid CIDR %s provided for the vip network", cidr)
+		return nil
+	}
+	mask, _ := strconv.Atoi(ipPrefixSlice[1])
+	return &avimodels.IPAddrPrefix{
+		IPAddr: &avimodels.IPAddr{Type: &ipType, Addr: &ipPrefixSlice[0]},
+		Mask:   proto.Int32(int32(mask)),
+	}
+}
+
 func networkNamesToVips(vipNetworks []akov1alpha1.AviInfraSettingVipNetwork, enablePublicIP *bool) []*avimodels.Vip {
 	var vipList []*avimodels.Vip
 	autoAllocate := true
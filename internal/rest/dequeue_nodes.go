@@ -52,12 +52,21 @@ func (rest *RestOperations) CleanupVS(key string, skipVS bool) {
 }
 
 func (rest *RestOperations) DequeueNodes(key string) {
-	utils.AviLog.Infof("key: %s, msg: start rest layer sync.", key)
+	log := lib.ModelLogger(key, utils.RestLayer)
+	if !lib.AKOIsLeader() {
+		log.Debugf("key: %s, msg: AKO is not the leader, skipping rest layer sync.", key)
+		if lib.StaticRouteSyncChan != nil {
+			close(lib.StaticRouteSyncChan)
+			lib.StaticRouteSyncChan = nil
+		}
+		return
+	}
+	log.Infof("key: %s, msg: start rest layer sync.", key)
 	namespace, name := utils.ExtractNamespaceObjectName(key)
 	// Got the key from the Graph Layer - let's fetch the model
 	ok, avimodelIntf := objects.SharedAviGraphLister().Get(key)
 	if !ok {
-		utils.AviLog.Warnf("key: %s, msg: no model found for the key", key)
+		log.Warnf("key: %s, msg: no model found for the key", key)
 	}
 
 	vsKey := avicache.NamespaceName{Namespace: namespace, Name: name}
@@ -68,37 +77,37 @@ func (rest *RestOperations) DequeueNodes(key string) {
 			lib.StaticRouteSyncChan = nil
 		}
 		if vs_cache_obj != nil {
-			utils.AviLog.Infof("key: %s, msg: nil model found, this is a vs deletion case", key)
+			log.Infof("key: %s, msg: nil model found, this is a vs deletion case", key)
 			rest.deleteVSOper(vsKey, vs_cache_obj, namespace, key, false, false)
 		}
 	} else if ok && avimodelIntf != nil {
 		avimodel := avimodelIntf.(*nodes.AviObjectGraph)
 		if avimodel == nil {
-			utils.AviLog.Debugf("Empty Model found, skipping")
+			log.Debugf("Empty Model found, skipping")
 			return
 		}
 		avimodel, ok = avimodel.GetCopy(key)
 		if !ok {
-			utils.AviLog.Warnf("key: %s, failed to get process model", key)
+			log.Warnf("key: %s, failed to get process model", key)
 			return
 		}
 		if avimodel.IsVrf {
-			utils.AviLog.Infof("key: %s, msg: processing vrf object\n", key)
+			log.Infof("key: %s, msg: processing vrf object\n", key)
 			rest.vrfCU(key, name, avimodel)
 			return
 		}
-		utils.AviLog.Debugf("key: %s, msg: VS create/update.", key)
+		log.Debugf("key: %s, msg: VS create/update.", key)
 
 		if strings.Contains(name, "-EVH-") && lib.IsEvhEnabled() {
 			if len(avimodel.GetAviEvhVS()) != 1 {
-				utils.AviLog.Warnf("key: %s, msg: virtualservice in the model is not equal to 1:%v", key, avimodel.GetAviEvhVS())
+				log.Warnf("key: %s, msg: virtualservice in the model is not equal to 1:%v", key, avimodel.GetAviEvhVS())
 				return
 			}
 			rest.RestOperationForEvh(name, namespace, avimodel, false, vs_cache_obj, key)
 
 		} else {
 			if len(avimodel.GetAviVS()) != 1 {
-				utils.AviLog.Warnf("key: %s, msg: virtualservice in the model is not equal to 1:%v", key, avimodel.GetAviVS())
+				log.Warnf("key: %s, msg: virtualservice in the model is not equal to 1:%v", key, avimodel.GetAviVS())
 				return
 			}
 			rest.RestOperation(name, namespace, avimodel, vs_cache_obj, key)
@@ -109,8 +118,9 @@ func (rest *RestOperations) DequeueNodes(key string) {
 }
 
 func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObjectGraph) {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	if lib.GetDisableStaticRoute() {
-		utils.AviLog.Debugf("key: %s, msg: static route sync disabled\n", key)
+		log.Debugf("key: %s, msg: static route sync disabled\n", key)
 		if lib.StaticRouteSyncChan != nil {
 			close(lib.StaticRouteSyncChan)
 			lib.StaticRouteSyncChan = nil
@@ -119,12 +129,12 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 	}
 	// Disable static route sync if ako is in  NodePort mode
 	if lib.IsNodePortMode() {
-		utils.AviLog.Debugf("key: %s, msg: static route sync disabled in NodePort Mode\n", key)
+		log.Debugf("key: %s, msg: static route sync disabled in NodePort Mode\n", key)
 		return
 	}
 	vrfNode := avimodel.GetAviVRF()
 	if len(vrfNode) != 1 {
-		utils.AviLog.Warnf("key: %s, msg: Number of vrf nodes is not one\n", key)
+		log.Warnf("key: %s, msg: Number of vrf nodes is not one\n", key)
 		if lib.StaticRouteSyncChan != nil {
 			close(lib.StaticRouteSyncChan)
 			lib.StaticRouteSyncChan = nil
@@ -134,7 +144,7 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 	aviVrfNode := vrfNode[0]
 	vrfCacheObj := rest.getVrfCacheObj(vrfName)
 	if vrfCacheObj == nil {
-		utils.AviLog.Warnf("key: %s, vrf %s not found in cache, exiting\n", key, vrfName)
+		log.Warnf("key: %s, vrf %s not found in cache, exiting\n", key, vrfName)
 		if lib.StaticRouteSyncChan != nil {
 			close(lib.StaticRouteSyncChan)
 			lib.StaticRouteSyncChan = nil
@@ -142,7 +152,7 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 		return
 	}
 	if vrfCacheObj.CloudConfigCksum == aviVrfNode.CloudConfigCksum {
-		utils.AviLog.Debugf("key: %s, msg: checksum for vrf %s has not changed, skipping\n", key, vrfName)
+		log.Debugf("key: %s, msg: checksum for vrf %s has not changed, skipping\n", key, vrfName)
 		if lib.StaticRouteSyncChan != nil {
 			close(lib.StaticRouteSyncChan)
 			lib.StaticRouteSyncChan = nil
@@ -152,7 +162,7 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 	var restOps []*utils.RestOp
 	restOp := rest.AviVrfBuild(key, aviVrfNode, vrfCacheObj.Uuid)
 	if restOp == nil {
-		utils.AviLog.Debugf("key: %s, no rest operation for vrf %s\n", key, vrfName)
+		log.Debugf("key: %s, no rest operation for vrf %s\n", key, vrfName)
 		if lib.StaticRouteSyncChan != nil {
 			close(lib.StaticRouteSyncChan)
 			lib.StaticRouteSyncChan = nil
@@ -161,15 +171,15 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 	}
 	restOps = append(restOps, restOp)
 	vrfKey := avicache.NamespaceName{Namespace: lib.GetTenant(), Name: vrfName}
-	utils.AviLog.Debugf("key: %s, msg: Executing rest for vrf %s\n", key, vrfName)
-	utils.AviLog.Debugf("key: %s, msg: restops %v\n", key, *restOp)
+	log.Debugf("key: %s, msg: Executing rest for vrf %s\n", key, vrfName)
+	log.Debugf("key: %s, msg: restops %v\n", key, *restOp)
 	success := rest.ExecuteRestAndPopulateCache(restOps, vrfKey, avimodel, key, false)
 
 	if success && lib.ConfigDeleteSyncChan != nil {
 		vsKeysPending := rest.cache.VsCacheMeta.AviGetAllKeys()
-		utils.AviLog.Infof("key: %s, msg: Number of VS deletion pending: %d", key, len(vsKeysPending))
+		log.Infof("key: %s, msg: Number of VS deletion pending: %d", key, len(vsKeysPending))
 		if len(vsKeysPending) == 0 {
-			utils.AviLog.Debugf("key: %s, msg: sending signal for vs deletion notification", key)
+			log.Debugf("key: %s, msg: sending signal for vs deletion notification", key)
 			close(lib.ConfigDeleteSyncChan)
 			lib.ConfigDeleteSyncChan = nil
 		}
@@ -179,6 +189,7 @@ func (rest *RestOperations) vrfCU(key, vrfName string, avimodel *nodes.AviObject
 // CheckAndPublishForRetry : Check if the error is of type 401, has string "Rest request error" or was timed out,
 // then publish the key to retry layer. These error do not depend on the objet state, hence cache refresh is not required.
 func (rest *RestOperations) CheckAndPublishForRetry(err error, publishKey, key string, avimodel *nodes.AviObjectGraph) bool {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	if err == nil {
 		return false
 	}
@@ -187,25 +198,25 @@ func (rest *RestOperations) CheckAndPublishForRetry(err error, publishKey, key s
 			switch aviError.HttpStatusCode {
 			case 401:
 				if strings.Contains(*aviError.Message, "Invalid credentials") {
-					utils.AviLog.Errorf("key: %s, msg: Invalid credentials error, Shutting down API Server", key)
+					log.Errorf("key: %s, msg: Invalid credentials error, Shutting down API Server", key)
 					lib.ShutdownApi()
 				} else if avimodel != nil && avimodel.GetRetryCounter() != 0 {
-					utils.AviLog.Warnf("key: %s, msg: got 401 error while executing rest request, adding to fast retry queue", key)
+					log.Warnf("key: %s, msg: got 401 error while executing rest request, adding to fast retry queue", key)
 					rest.PublishKeyToRetryLayer(publishKey, key)
 				} else {
-					utils.AviLog.Warnf("key: %s, msg: got 401 error while executing rest request, adding to slow retry queue", key)
+					log.Warnf("key: %s, msg: got 401 error while executing rest request, adding to slow retry queue", key)
 					rest.PublishKeyToSlowRetryLayer(publishKey, key)
 				}
 				return true
 			case 400:
 				if strings.Contains(*aviError.Message, lib.NoFreeIPError) {
-					utils.AviLog.Warnf("key: %s, msg: no Free IP available, adding to slow retry queue", key)
+					log.Warnf("key: %s, msg: no Free IP available, adding to slow retry queue", key)
 					rest.PublishKeyToSlowRetryLayer(publishKey, key)
 					return true
 				}
 			case 403:
 				if strings.Contains(*aviError.Message, lib.ConfigDisallowedDuringUpgradeError) {
-					utils.AviLog.Warnf("key: %s, msg: controller upgrade in progress, adding to slow retry queue", key)
+					log.Warnf("key: %s, msg: controller upgrade in progress, adding to slow retry queue", key)
 					rest.PublishKeyToSlowRetryLayer(publishKey, key)
 					return true
 				}
@@ -213,14 +224,16 @@ func (rest *RestOperations) CheckAndPublishForRetry(err error, publishKey, key s
 		}
 	}
 	if strings.Contains(err.Error(), "Rest request error") || strings.Contains(err.Error(), "timed out waiting for rest response") {
-		utils.AviLog.Warnf("key: %s, msg: got error while executing rest request: %s, adding to slow retry queue", key, err.Error())
+		log.Warnf("key: %s, msg: got error while executing rest request: %s, adding to slow retry queue", key, err.Error())
 		rest.PublishKeyToSlowRetryLayer(publishKey, key)
 		return true
 	}
+	publishSyncFailureEvents(err, avimodel, key)
 	return false
 }
 
 func (rest *RestOperations) RestOperation(vsName string, namespace string, avimodel *nodes.AviObjectGraph, vs_cache_obj *avicache.AviVsCache, key string) {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	var pools_to_delete []avicache.NamespaceName
 	var pgs_to_delete []avicache.NamespaceName
 	var ds_to_delete []avicache.NamespaceName
@@ -228,6 +241,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	var sni_to_delete []avicache.NamespaceName
 	var httppol_to_delete []avicache.NamespaceName
 	var l4pol_to_delete []avicache.NamespaceName
+	var nsp_to_delete []avicache.NamespaceName
 	var vsvipErr error
 	var publishKey string
 
@@ -257,11 +271,12 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		httppol_to_delete, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
 		ds_to_delete, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, vs_cache_obj, namespace, rest_ops, key)
 		l4pol_to_delete, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, vs_cache_obj, namespace, rest_ops, key)
-		utils.AviLog.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
+		nsp_to_delete, rest_ops = rest.NSPolicyCU(aviVsNode.NSPolicyRefs, vs_cache_obj, namespace, rest_ops, key)
+		log.Debugf("key: %s, msg: stored checksum for VS: %s, model checksum: %s", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 		if vs_cache_obj.CloudConfigCksum == strconv.Itoa(int(aviVsNode.GetCheckSum())) {
-			utils.AviLog.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
+			log.Debugf("key: %s, msg: the checksums are same for vs %s, not doing anything", key, vs_cache_obj.Name)
 		} else {
-			utils.AviLog.Debugf("key: %s, msg: the stored checksum for vs is %v, and the obtained checksum for VS is: %v", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
+			log.Debugf("key: %s, msg: the stored checksum for vs is %v, and the obtained checksum for VS is: %v", key, vs_cache_obj.CloudConfigCksum, strconv.Itoa(int(aviVsNode.GetCheckSum())))
 			// The checksums are different, so it should be a PUT call.
 			restOp := rest.AviVsBuild(aviVsNode, utils.RestPut, vs_cache_obj, key)
 			if restOp != nil {
@@ -285,6 +300,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		_, rest_ops = rest.PoolGroupCU(aviVsNode.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(aviVsNode.HttpPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.L4PolicyCU(aviVsNode.L4PolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.NSPolicyCU(aviVsNode.NSPolicyRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.DatascriptCU(aviVsNode.HTTPDSrefs, nil, namespace, rest_ops, key)
 
 		// The cache was not found - it's a POST call.
@@ -292,8 +308,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		if restOp != nil {
 			rest_ops = append(rest_ops, restOp...)
 		}
-		utils.AviLog.Debugf("POST key: %s, vsKey: %s", key, vsKey)
-		utils.AviLog.Debugf("POST restops %s", utils.Stringify(rest_ops))
+		log.Debugf("POST key: %s, vsKey: %s", key, vsKey)
+		log.Debugf("POST restops %s", utils.Stringify(rest_ops))
 		if success := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, avimodel, key, false); !success {
 			return
 		}
@@ -304,7 +320,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 			if ok {
 				sni_to_delete = append(sni_to_delete, sni_vs_key.(avicache.NamespaceName))
 			} else {
-				utils.AviLog.Debugf("key: %s, msg: Couldn't get SNI key for uuid: %v", key, sni_uuid)
+				log.Debugf("key: %s, msg: Couldn't get SNI key for uuid: %v", key, sni_uuid)
 			}
 		}
 	}
@@ -313,6 +329,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	rest_ops = rest.VSVipDelete(vsvip_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.HTTPPolicyDelete(httppol_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.L4PolicyDelete(l4pol_to_delete, namespace, rest_ops, key)
+	rest_ops = rest.NSPolicyDelete(nsp_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.DSDelete(ds_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolGroupDelete(pgs_to_delete, namespace, rest_ops, key)
 	rest_ops = rest.PoolDelete(pools_to_delete, namespace, rest_ops, key)
@@ -321,8 +338,8 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 	}
 
 	for _, sni_node := range aviVsNode.SniNodes {
-		utils.AviLog.Debugf("key: %s, msg: processing sni node: %s", key, sni_node.Name)
-		utils.AviLog.Debugf("key: %s, msg: probable SNI delete candidates: %s", key, sni_to_delete)
+		log.Debugf("key: %s, msg: processing sni node: %s", key, sni_node.Name)
+		log.Debugf("key: %s, msg: probable SNI delete candidates: %s", key, sni_to_delete)
 		var rest_ops []*utils.RestOp
 		vsKey = avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
 		if vs_cache_obj != nil {
@@ -337,7 +354,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 
 	// Let's populate all the DELETE entries
 	if len(sni_to_delete) > 0 {
-		utils.AviLog.Infof("key: %s, msg: SNI delete candidates are : %s", key, sni_to_delete)
+		log.Infof("key: %s, msg: SNI delete candidates are : %s", key, sni_to_delete)
 		var rest_ops []*utils.RestOp
 		for _, del_sni := range sni_to_delete {
 			rest.SNINodeDelete(del_sni, namespace, rest_ops, avimodel, key)
@@ -351,7 +368,7 @@ func (rest *RestOperations) RestOperation(vsName string, namespace string, avimo
 		var rest_ops []*utils.RestOp
 		passChildVSKey := avicache.NamespaceName{Namespace: namespace, Name: passChildNode.Name}
 		passChildVSCacheObj := rest.getVsCacheObj(passChildVSKey, key)
-		utils.AviLog.Debugf("key: %s, msg: processing passthrough node: %s", key, passChildNode)
+		log.Debugf("key: %s, msg: processing passthrough node: %s", key, passChildNode)
 		vsKey = avicache.NamespaceName{Namespace: namespace, Name: passChildNode.Name}
 		if passChildVSCacheObj != nil {
 			rest_ops = rest.PassthroughChildCU(passChildNode, passChildVSCacheObj, namespace, rest_ops, key)
@@ -408,6 +425,7 @@ func (rest *RestOperations) getVsCacheObj(vsKey avicache.NamespaceName, key stri
 }
 
 func (rest *RestOperations) deleteVSOper(vsKey avicache.NamespaceName, vs_cache_obj *avicache.AviVsCache, namespace string, key string, skipVS, skipVSVip bool) bool {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	var rest_ops []*utils.RestOp
 	if vs_cache_obj != nil {
 		sni_vs_keys := make([]string, len(vs_cache_obj.SNIChildCollection))
@@ -450,16 +468,17 @@ func (rest *RestOperations) deleteVSOper(vsKey avicache.NamespaceName, vs_cache_
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.L4PolicyDelete(vs_cache_obj.L4PolicyCollection, namespace, rest_ops, key)
+		rest_ops = rest.NSPolicyDelete(vs_cache_obj.NSPolicyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(vs_cache_obj.PGKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(vs_cache_obj.PoolKeyCollection, namespace, rest_ops, key)
 		success := rest.ExecuteRestAndPopulateCache(rest_ops, vsKey, nil, key, false)
 		if success {
 			vsKeysPending := rest.cache.VsCacheMeta.AviGetAllKeys()
-			utils.AviLog.Infof("key: %s, msg: Number of VS deletion pending: %d", key, len(vsKeysPending))
+			log.Infof("key: %s, msg: Number of VS deletion pending: %d", key, len(vsKeysPending))
 			if len(vsKeysPending) == 0 {
 				// All VSes got deleted, done with deleteConfig operation. Now notify the user
 				if lib.ConfigDeleteSyncChan != nil {
-					utils.AviLog.Debugf("key: %s, msg: sending signal for vs deletion notification", key)
+					log.Debugf("key: %s, msg: sending signal for vs deletion notification", key)
 					close(lib.ConfigDeleteSyncChan)
 					lib.ConfigDeleteSyncChan = nil
 				}
@@ -470,7 +489,7 @@ func (rest *RestOperations) deleteVSOper(vsKey avicache.NamespaceName, vs_cache_
 
 	// All VSes got deleted, done with deleteConfig operation. Now notify the user
 	if lib.ConfigDeleteSyncChan != nil {
-		utils.AviLog.Debugf("key: %s, msg: sending signal for vs deletion notification", key)
+		log.Debugf("key: %s, msg: sending signal for vs deletion notification", key)
 		close(lib.ConfigDeleteSyncChan)
 		lib.ConfigDeleteSyncChan = nil
 	}
@@ -486,6 +505,7 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 		if ok {
 			rest_ops = append(rest_ops, rest_op)
 		}
+		rest_ops = rest.ClientCertProfileDelete([]avicache.NamespaceName{vsKey}, namespace, rest_ops, key)
 		rest_ops = rest.DataScriptDelete(vs_cache_obj.DSKeyCollection, namespace, rest_ops, key)
 		rest_ops = rest.SSLKeyCertDelete(vs_cache_obj.SSLKeyCertCollection, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(vs_cache_obj.HTTPKeyCollection, namespace, rest_ops, key)
@@ -497,6 +517,7 @@ func (rest *RestOperations) deleteSniVs(vsKey avicache.NamespaceName, vs_cache_o
 }
 
 func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp, aviObjKey avicache.NamespaceName, avimodel *nodes.AviObjectGraph, key string, isEvh bool, sslKey ...utils.NamespaceName) bool {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	// Choose a avi client based on the model name hash. This would ensure that the same worker queue processes updates for a given VS all the time.
 	shardSize := lib.GetshardSize()
 	if shardSize == 0 {
@@ -507,12 +528,17 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 	if shardSize != 0 {
 		bkt := utils.Bkt(key, shardSize)
 		if len(rest.aviRestPoolClient.AviClient) > 0 && len(rest_ops) > 0 {
-			utils.AviLog.Infof("key: %s, msg: processing in rest queue number: %v", key, bkt)
+			log.Infof("key: %s, msg: processing in rest queue number: %v", key, bkt)
 			aviclient := rest.aviRestPoolClient.AviClient[bkt]
-			err := rest.AviRestOperateWrapper(aviclient, rest_ops)
+			var err error
+			if lib.IsDryRun() {
+				rest.DryRunRestOperate(rest_ops, aviObjKey, key)
+			} else {
+				err = rest.AviRestOperateWrapper(aviclient, rest_ops, key)
+			}
 			if err == nil {
 				models.RestStatus.UpdateAviApiRestStatus(utils.AVIAPI_CONNECTED, nil)
-				utils.AviLog.Debugf("key: %s, msg: rest call executed successfully, will update cache", key)
+				log.Debugf("key: %s, msg: rest call executed successfully, will update cache", key)
 
 				// Add to local obj caches
 				for _, rest_op := range rest_ops {
@@ -520,7 +546,7 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 				}
 
 			} else if aviObjKey.Name == lib.DummyVSForStaleData {
-				utils.AviLog.Warnf("key: %s, msg: error in rest request %v, for %s, won't retry", key, err.Error(), lib.DummyVSForStaleData)
+				log.Warnf("key: %s, msg: error in rest request %v, for %s, won't retry", key, err.Error(), lib.DummyVSForStaleData)
 				return false
 			} else {
 				var publishKey string
@@ -541,7 +567,7 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 				if rest.CheckAndPublishForRetry(err, publishKey, key, avimodel) {
 					return false
 				}
-				utils.AviLog.Warnf("key: %s, msg: there was an error sending the macro %v", key, err.Error())
+				log.Warnf("key: %s, msg: there was an error sending the macro %v", key, err.Error())
 				models.RestStatus.UpdateAviApiRestStatus("", err)
 				for i := len(rest_ops) - 1; i >= 0; i-- {
 					// Go over each of the failed requests and enqueue them to the worker queue for retry.
@@ -560,10 +586,10 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 							refreshCacheForRetry = true
 						}
 						if refreshCacheForRetry {
-							utils.AviLog.Warnf("key: %s, msg: Retrieved key for Retry:%s, object: %s", key, publishKey, rest_ops[i].ObjName)
+							log.Warnf("key: %s, msg: Retrieved key for Retry:%s, object: %s", key, publishKey, rest_ops[i].ObjName)
 							aviError, ok := rest_ops[i].Err.(session.AviError)
 							if !ok {
-								utils.AviLog.Infof("key: %s, msg: Error is not of type AviError, err: %v, %T", key, rest_ops[i].Err, rest_ops[i].Err)
+								log.Infof("key: %s, msg: Error is not of type AviError, err: %v, %T", key, rest_ops[i].Err, rest_ops[i].Err)
 								continue
 							}
 							if avimodel.GetRetryCounter() != 0 {
@@ -573,12 +599,12 @@ func (rest *RestOperations) ExecuteRestAndPopulateCache(rest_ops []*utils.RestOp
 							} else if aviError.HttpStatusCode == 400 && strings.Contains(*aviError.Message, lib.NoFreeIPError) {
 								fastRetry = false
 								retry = true
-								utils.AviLog.Warnf("key: %s, msg: Got no free IP error, would be added to slow retry queue", key)
+								log.Warnf("key: %s, msg: Got no free IP error, would be added to slow retry queue", key)
 							} else {
-								utils.AviLog.Warnf("key: %s, msg: retry count exhausted, skipping", key)
+								log.Warnf("key: %s, msg: retry count exhausted, skipping", key)
 							}
 						} else {
-							utils.AviLog.Warnf("key: %s, msg: Avi model not set, possibly a DELETE call", key)
+							log.Warnf("key: %s, msg: Avi model not set, possibly a DELETE call", key)
 							aviError, ok := rest_ops[i].Err.(session.AviError)
 							// If it's 404, don't retry
 							if ok {
@@ -626,11 +652,12 @@ func checkVsVipUpdateErrors(key string, rest_op *utils.RestOp) bool {
 }
 
 func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey avicache.NamespaceName, key string) {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	if (rest_op.Err == nil || rest_op.Message != "") &&
 		(rest_op.Method == utils.RestPost ||
 			rest_op.Method == utils.RestPut ||
 			rest_op.Method == utils.RestPatch) {
-		utils.AviLog.Infof("key: %s, msg: creating/updating %s cache, method: %s", key, rest_op.Model, rest_op.Method)
+		log.Infof("key: %s, msg: creating/updating %s cache, method: %s", key, rest_op.Model, rest_op.Method)
 		if rest_op.Model == "PKIprofile" {
 			rest.AviPkiProfileAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "Pool" {
@@ -647,6 +674,16 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLKeyCertAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNSPolicyCacheAdd(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "ApplicationPersistenceProfile" {
+			rest.AviPersistenceProfileCacheAdd(rest_op, key)
+		} else if rest_op.Model == "HealthMonitor" {
+			rest.AviHealthMonitorCacheAdd(rest_op, key)
+		} else if rest_op.Model == "StringGroup" {
+			rest.AviStringGroupCacheAdd(rest_op, key)
+		} else if rest_op.Model == "ApplicationProfile" {
+			rest.AviAppProfileCacheAdd(rest_op, key)
 		} else if rest_op.Model == "VrfContext" {
 			rest.AviVrfCacheAdd(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VsVip" {
@@ -654,7 +691,7 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 		}
 
 	} else if rest_op.Err == nil && rest_op.Method == utils.RestDelete {
-		utils.AviLog.Infof("key: %s, msg: deleting %s cache", key, rest_op.Model)
+		log.Infof("key: %s, msg: deleting %s cache", key, rest_op.Model)
 		if rest_op.Model == "PKIprofile" {
 			rest.AviPkiProfileCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "Pool" {
@@ -669,6 +706,16 @@ func (rest *RestOperations) PopulateOneCache(rest_op *utils.RestOp, aviObjKey av
 			rest.AviSSLCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "L4PolicySet" {
 			rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "NetworkSecurityPolicy" {
+			rest.AviNSPolicyCacheDel(rest_op, aviObjKey, key)
+		} else if rest_op.Model == "ApplicationPersistenceProfile" {
+			rest.AviPersistenceProfileCacheDel(rest_op, key)
+		} else if rest_op.Model == "HealthMonitor" {
+			rest.AviHealthMonitorCacheDel(rest_op, key)
+		} else if rest_op.Model == "StringGroup" {
+			rest.AviStringGroupCacheDel(rest_op, key)
+		} else if rest_op.Model == "ApplicationProfile" {
+			rest.AviAppProfileCacheDel(rest_op, key)
 		} else if rest_op.Model == "VsVip" {
 			rest.AviVsVipCacheDel(rest_op, aviObjKey, key)
 		} else if rest_op.Model == "VSDataScriptSet" {
@@ -695,6 +742,7 @@ func (rest *RestOperations) PublishKeyToRetryLayer(parentVsKey string, key strin
 	var bkt uint32
 	bkt = 0
 	fastRetryQueue := utils.SharedWorkQueue().GetQueueByName(lib.FAST_RETRY_LAYER)
+	utils.TraceEnqueue(lib.FAST_RETRY_LAYER, parentVsKey, utils.GetActiveSpan(utils.GraphLayer, key))
 	fastRetryQueue.Workqueue[bkt].AddRateLimited(parentVsKey)
 	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to fast path retry queue: %s", key, parentVsKey)
 }
@@ -703,14 +751,15 @@ func (rest *RestOperations) PublishKeyToSlowRetryLayer(parentVsKey string, key s
 	var bkt uint32
 	bkt = 0
 	slowRetryQueue := utils.SharedWorkQueue().GetQueueByName(lib.SLOW_RETRY_LAYER)
+	utils.TraceEnqueue(lib.SLOW_RETRY_LAYER, parentVsKey, utils.GetActiveSpan(utils.GraphLayer, key))
 	slowRetryQueue.Workqueue[bkt].AddRateLimited(parentVsKey)
 	utils.AviLog.Infof("key: %s, msg: Published key with vs_key to slow path retry queue: %s", key, parentVsKey)
 }
 
-func (rest *RestOperations) AviRestOperateWrapper(aviClient *clients.AviClient, rest_ops []*utils.RestOp) error {
+func (rest *RestOperations) AviRestOperateWrapper(aviClient *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
 	restTimeoutChan := make(chan error, 1)
 	go func() {
-		err := AviRestOperate(aviClient, rest_ops)
+		err := AviRestOperate(aviClient, rest_ops, key)
 		restTimeoutChan <- err
 	}()
 	select {
@@ -723,12 +772,13 @@ func (rest *RestOperations) AviRestOperateWrapper(aviClient *clients.AviClient,
 }
 
 func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObjKey avicache.NamespaceName, rest_op *utils.RestOp, aviError session.AviError, c *clients.AviClient, avimodel *nodes.AviObjectGraph, key string, isEvh bool) (bool, bool) {
+	log := lib.ModelLogger(key, utils.RestLayer)
 	var fastRetry bool
 	statuscode := aviError.HttpStatusCode
 	errorStr := aviError.Error()
 	retry := true
-	utils.AviLog.Warnf("key: %s, msg: problem in processing request for: %s", key, rest_op.Model)
-	utils.AviLog.Infof("key: %s, msg: error str: %s", key, errorStr)
+	log.Warnf("key: %s, msg: problem in processing request for: %s", key, rest_op.Model)
+	log.Infof("key: %s, msg: error str: %s", key, errorStr)
 	aviObjCache := avicache.SharedAviObjCache()
 
 	if statuscode >= 500 && statuscode < 599 {
@@ -790,7 +840,7 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 								}
 							}
 						}
-						utils.AviLog.Debugf("key: %s, msg: pools in model during retry: %s", key, pools)
+						log.Debugf("key: %s, msg: pools in model during retry: %s", key, pools)
 						// Find out pool members that exist in the model but do not exist in the cache and delete them.
 
 						poolsCopy := make([]string, len(pools))
@@ -803,11 +853,11 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 						// Whatever is left it in poolsCopy - remove them from the avi pools cache
 						for _, poolsToDel := range poolsCopy {
 							rest_op.ObjName = poolsToDel
-							utils.AviLog.Debugf("key: %s, msg: deleting pool from cache due to pool not found %s", key, poolsToDel)
+							log.Debugf("key: %s, msg: deleting pool from cache due to pool not found %s", key, poolsToDel)
 							rest.AviPoolCacheDel(rest_op, aviObjKey, key)
 						}
 					} else {
-						utils.AviLog.Infof("key: %s, msg: PG object not found during retry pgname: %s", key, pgObjName)
+						log.Infof("key: %s, msg: PG object not found during retry pgname: %s", key, pgObjName)
 					}
 				}
 				rest.AviPGCacheDel(rest_op, aviObjKey, key)
@@ -847,6 +897,66 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					rest_op.ObjName = L4PolicySet
 				}
 				rest.AviL4PolicyCacheDel(rest_op, aviObjKey, key)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				if NetworkSecurityPolicy != "" {
+					rest_op.ObjName = NetworkSecurityPolicy
+				}
+				rest.AviNSPolicyCacheDel(rest_op, aviObjKey, key)
+			case "ApplicationPersistenceProfile":
+				var ApplicationPersistenceProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationPersistenceProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationPersistenceProfile).Name
+				case avimodels.ApplicationPersistenceProfile:
+					ApplicationPersistenceProfile = *rest_op.Obj.(avimodels.ApplicationPersistenceProfile).Name
+				}
+				if ApplicationPersistenceProfile != "" {
+					rest_op.ObjName = ApplicationPersistenceProfile
+				}
+				rest.AviPersistenceProfileCacheDel(rest_op, key)
+			case "HealthMonitor":
+				var HealthMonitor string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					HealthMonitor = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor).Name
+				case avimodels.HealthMonitor:
+					HealthMonitor = *rest_op.Obj.(avimodels.HealthMonitor).Name
+				}
+				if HealthMonitor != "" {
+					rest_op.ObjName = HealthMonitor
+				}
+				rest.AviHealthMonitorCacheDel(rest_op, key)
+			case "StringGroup":
+				var StringGroup string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					StringGroup = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.StringGroup).Name
+				case avimodels.StringGroup:
+					StringGroup = *rest_op.Obj.(avimodels.StringGroup).Name
+				}
+				if StringGroup != "" {
+					rest_op.ObjName = StringGroup
+				}
+				rest.AviStringGroupCacheDel(rest_op, key)
+			case "ApplicationProfile":
+				var ApplicationProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile).Name
+				case avimodels.ApplicationProfile:
+					ApplicationProfile = *rest_op.Obj.(avimodels.ApplicationProfile).Name
+				}
+				if ApplicationProfile != "" {
+					rest_op.ObjName = ApplicationProfile
+				}
+				rest.AviAppProfileCacheDel(rest_op, key)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -891,7 +1001,7 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 			// TODO (sudswas): if error code 400 happens, it means layer 2's model has issue - can re-trigger a model eval in that case?
 			// If it's 409 it refers to a conflict. That means the cache should be refreshed for the particular object.
 
-			utils.AviLog.Infof("key: %s, msg: Conflict for object: %s of type :%s", key, rest_op.ObjName, rest_op.Model)
+			log.Infof("key: %s, msg: Conflict for object: %s of type :%s", key, rest_op.ObjName, rest_op.Model)
 			switch rest_op.Model {
 			case "Pool":
 				var poolObjName string
@@ -938,6 +1048,51 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 					L4PolicySet = *rest_op.Obj.(avimodels.L4PolicySet).Name
 				}
 				aviObjCache.AviPopulateOneVsL4PolCache(c, utils.CloudName, L4PolicySet)
+			case "NetworkSecurityPolicy":
+				var NetworkSecurityPolicy string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					NetworkSecurityPolicy = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.NetworkSecurityPolicy).Name
+				case avimodels.NetworkSecurityPolicy:
+					NetworkSecurityPolicy = *rest_op.Obj.(avimodels.NetworkSecurityPolicy).Name
+				}
+				aviObjCache.AviPopulateOneNSPolicyCache(c, utils.CloudName, NetworkSecurityPolicy)
+			case "ApplicationPersistenceProfile":
+				var ApplicationPersistenceProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationPersistenceProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationPersistenceProfile).Name
+				case avimodels.ApplicationPersistenceProfile:
+					ApplicationPersistenceProfile = *rest_op.Obj.(avimodels.ApplicationPersistenceProfile).Name
+				}
+				aviObjCache.AviPopulateOnePersistenceProfileCache(c, utils.CloudName, ApplicationPersistenceProfile)
+			case "HealthMonitor":
+				var HealthMonitor string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					HealthMonitor = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.HealthMonitor).Name
+				case avimodels.HealthMonitor:
+					HealthMonitor = *rest_op.Obj.(avimodels.HealthMonitor).Name
+				}
+				aviObjCache.AviPopulateOneHealthMonitorCache(c, utils.CloudName, HealthMonitor)
+			case "StringGroup":
+				var StringGroup string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					StringGroup = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.StringGroup).Name
+				case avimodels.StringGroup:
+					StringGroup = *rest_op.Obj.(avimodels.StringGroup).Name
+				}
+				aviObjCache.AviPopulateOneStringGroupCache(c, utils.CloudName, StringGroup)
+			case "ApplicationProfile":
+				var ApplicationProfile string
+				switch rest_op.Obj.(type) {
+				case utils.AviRestObjMacro:
+					ApplicationProfile = *rest_op.Obj.(utils.AviRestObjMacro).Data.(avimodels.ApplicationProfile).Name
+				case avimodels.ApplicationProfile:
+					ApplicationProfile = *rest_op.Obj.(avimodels.ApplicationProfile).Name
+				}
+				aviObjCache.AviPopulateOneAppProfileCache(c, utils.CloudName, ApplicationProfile)
 			case "SSLKeyAndCertificate":
 				var SSLKeyAndCertificate string
 				switch rest_op.Obj.(type) {
@@ -961,7 +1116,7 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 				vsObjMeta, ok := rest.cache.VsCacheMeta.AviCacheGet(aviObjKey)
 				if !ok {
 					// Object deleted
-					utils.AviLog.Warnf("key: %s, msg: VS object already deleted during retry", key)
+					log.Warnf("key: %s, msg: VS object already deleted during retry", key)
 				} else {
 					vsCopy, done := vsObjMeta.(*avicache.AviVsCache).GetVSCopy()
 					if done {
@@ -980,18 +1135,18 @@ func (rest *RestOperations) RefreshCacheForRetryLayer(parentVsKey string, aviObj
 			}
 		} else if statuscode == 408 {
 			// This status code refers to a problem with the controller timeouts. We need to re-init the session object.
-			utils.AviLog.Infof("key :%s, msg: Controller request timed out, will re-init session by retrying", key)
+			log.Infof("key :%s, msg: Controller request timed out, will re-init session by retrying", key)
 
 		} else if statuscode == 400 && strings.Contains(*aviError.Message, lib.NoFreeIPError) {
-			utils.AviLog.Infof("key: %s, msg:  msg: Got no free IP error, would be added to slow retry queue", key)
+			log.Infof("key: %s, msg:  msg: Got no free IP error, would be added to slow retry queue", key)
 			fastRetry = false
 		} else if statuscode == 403 && strings.Contains(*aviError.Message, lib.ConfigDisallowedDuringUpgradeError) {
-			utils.AviLog.Infof("key: %s, msg: Controller upgrade in progress, would be added to slow retry queue", key)
+			log.Infof("key: %s, msg: Controller upgrade in progress, would be added to slow retry queue", key)
 			fastRetry = false
 		} else {
 
 			// We don't want to handle any other error code like 400 etc.
-			utils.AviLog.Infof("key: %s, msg: Detected error code %d that we don't support, not going to retry", key, statuscode)
+			log.Infof("key: %s, msg: Detected error code %d that we don't support, not going to retry", key, statuscode)
 			retry = false
 		}
 	}
@@ -1025,6 +1180,8 @@ func (rest *RestOperations) PoolDelete(pools_to_delete []avicache.NamespaceName,
 			if pkiProfile.Name != "" {
 				rest_ops = rest.PkiProfileDelete([]avicache.NamespaceName{pkiProfile}, namespace, rest_ops, key)
 			}
+			rest_ops = rest.PersistenceProfileDelete([]avicache.NamespaceName{pool_key}, namespace, rest_ops, key)
+			rest_ops = rest.HealthMonitorDelete([]avicache.NamespaceName{pool_key}, namespace, rest_ops, key)
 		}
 	}
 	return rest_ops
@@ -1089,6 +1246,9 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 		utils.AviLog.Debugf("key: %s, msg: the cached pools are: %v", key, utils.Stringify(cache_pool_nodes))
 		if cache_pool_nodes != nil {
 			for _, pool := range pool_nodes {
+				var pool_persistence_delete, pool_hm_delete []avicache.NamespaceName
+				pool_persistence_delete, rest_ops = rest.PersistenceProfileCU(pool, namespace, rest_ops, key)
+				pool_hm_delete, rest_ops = rest.HealthMonitorCU(pool, namespace, rest_ops, key)
 				// check in the pool cache to see if this pool exists in AVI
 				pool_key := avicache.NamespaceName{Namespace: namespace, Name: pool.Name}
 				found := utils.HasElem(cache_pool_nodes, pool_key)
@@ -1126,12 +1286,17 @@ func (rest *RestOperations) PoolCU(pool_nodes []*nodes.AviPoolNode, vs_cache_obj
 				if len(pool_pkiprofile_delete) > 0 {
 					rest_ops = rest.PkiProfileDelete(pool_pkiprofile_delete, namespace, rest_ops, key)
 				}
+				// The persistence profile and the health monitor are deleted only after the pool stops referring to them.
+				rest_ops = rest.PersistenceProfileDelete(pool_persistence_delete, namespace, rest_ops, key)
+				rest_ops = rest.HealthMonitorDelete(pool_hm_delete, namespace, rest_ops, key)
 			}
 		}
 	} else {
 		// Everything is a POST call
 		for _, pool := range pool_nodes {
 			_, rest_ops = rest.PkiProfileCU(pool.PkiProfile, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.PersistenceProfileCU(pool, namespace, rest_ops, key)
+			_, rest_ops = rest.HealthMonitorCU(pool, namespace, rest_ops, key)
 
 			utils.AviLog.Debugf("key: %s, msg: pool cache does not exist %s, operation: POST", key, pool.Name)
 			restOp := rest.AviPoolBuild(pool, nil, key)
@@ -1193,6 +1358,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 	var sni_pgs_to_delete []avicache.NamespaceName
 	var http_policies_to_delete []avicache.NamespaceName
 	var sslkey_cert_delete []avicache.NamespaceName
+	var client_cert_profile_delete []avicache.NamespaceName
 	if vs_cache_obj != nil {
 		sni_key := avicache.NamespaceName{Namespace: namespace, Name: sni_node.Name}
 		// Search the VS cache and obtain the UUID of this VS. Then see if this UUID is part of the SNIChildCollection or not.
@@ -1211,6 +1377,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 				sni_pools_to_delete, rest_ops = rest.PoolCU(sni_node.PoolRefs, sni_cache_obj, namespace, rest_ops, key)
 				sni_pgs_to_delete, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, sni_cache_obj, namespace, rest_ops, key)
 				http_policies_to_delete, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, sni_cache_obj, namespace, rest_ops, key)
+				client_cert_profile_delete, rest_ops = rest.ClientCertProfileCU(sni_node.Name, sni_node.ClientCertProfile, namespace, rest_ops, key)
 				// The checksums are different, so it should be a PUT call.
 				if sni_cache_obj.CloudConfigCksum != strconv.Itoa(int(sni_node.GetCheckSum())) {
 					restOp := rest.AviVsBuild(sni_node, utils.RestPut, sni_cache_obj, key)
@@ -1228,6 +1395,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 			_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 			_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+			_, rest_ops = rest.ClientCertProfileCU(sni_node.Name, sni_node.ClientCertProfile, namespace, rest_ops, key)
 
 			// Not found - it should be a POST call.
 			restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1237,6 +1405,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		}
 		rest_ops = rest.SSLKeyCertDelete(sslkey_cert_delete, namespace, rest_ops, key)
 		rest_ops = rest.HTTPPolicyDelete(http_policies_to_delete, namespace, rest_ops, key)
+		rest_ops = rest.ClientCertProfileDelete(client_cert_profile_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolGroupDelete(sni_pgs_to_delete, namespace, rest_ops, key)
 		rest_ops = rest.PoolDelete(sni_pools_to_delete, namespace, rest_ops, key)
 		utils.AviLog.Debugf("key: %s, msg: the SNI VSes to be deleted are: %s", key, cache_sni_nodes)
@@ -1247,6 +1416,7 @@ func (rest *RestOperations) SNINodeCU(sni_node *nodes.AviVsNode, vs_cache_obj *a
 		_, rest_ops = rest.PoolCU(sni_node.PoolRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.PoolGroupCU(sni_node.PoolGroupRefs, nil, namespace, rest_ops, key)
 		_, rest_ops = rest.HTTPPolicyCU(sni_node.HttpPolicyRefs, nil, namespace, rest_ops, key)
+		_, rest_ops = rest.ClientCertProfileCU(sni_node.Name, sni_node.ClientCertProfile, namespace, rest_ops, key)
 
 		// Not found - it should be a POST call.
 		restOp := rest.AviVsBuild(sni_node, utils.RestPost, nil, key)
@@ -1426,6 +1596,7 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 		cache_http_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.HTTPKeyCollection))
 		copy(cache_http_nodes, vs_cache_obj.HTTPKeyCollection)
 		for _, http := range http_nodes {
+			rest_ops = rest.StringGroupCU(http.StringGroupRefs, namespace, rest_ops, key)
 			http_key := avicache.NamespaceName{Namespace: namespace, Name: http.Name}
 			found := utils.HasElem(cache_http_nodes, http_key)
 			if found {
@@ -1443,6 +1614,14 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 							rest_ops = append(rest_ops, restOp)
 						}
 					}
+					// The stringgroups are deleted only after the http policyset stops referring to them.
+					var sgToDelete []string
+					for _, sgName := range http_cache_obj.StringGroups {
+						if !stringGroupInNodes(http.StringGroupRefs, sgName) {
+							sgToDelete = append(sgToDelete, sgName)
+						}
+					}
+					rest_ops = rest.StringGroupDelete(sgToDelete, namespace, rest_ops, key)
 				}
 			} else {
 				// Not found - it should be a POST call.
@@ -1456,6 +1635,7 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 	} else {
 		// Everything is a POST call
 		for _, http := range http_nodes {
+			rest_ops = rest.StringGroupCU(http.StringGroupRefs, namespace, rest_ops, key)
 			restOp := rest.AviHttpPSBuild(http, nil, key)
 			if restOp != nil {
 				rest_ops = append(rest_ops, restOp)
@@ -1468,6 +1648,49 @@ func (rest *RestOperations) HTTPPolicyCU(http_nodes []*nodes.AviHttpPolicySetNod
 	return cache_http_nodes, rest_ops
 }
 
+// StringGroupCU creates or updates the stringgroups used by the rules of a http policyset.
+func (rest *RestOperations) StringGroupCU(sg_nodes []*nodes.AviStringGroupNode, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, sg := range sg_nodes {
+		sgKey := avicache.NamespaceName{Namespace: namespace, Name: sg.Name}
+		var sgCacheObj *avicache.AviStringGroupCache
+		if sgCache, ok := rest.cache.StringGroupCache.AviCacheGet(sgKey); ok {
+			sgCacheObj, _ = sgCache.(*avicache.AviStringGroupCache)
+		}
+		if sgCacheObj != nil && sgCacheObj.CloudConfigCksum == sg.GetCheckSum() {
+			utils.AviLog.Debugf("key: %s, msg: the checksums are same for stringgroup %s, not doing anything", key, sg.Name)
+			continue
+		}
+		if restOp := rest.AviStringGroupBuild(sg, sgCacheObj, key); restOp != nil {
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+func (rest *RestOperations) StringGroupDelete(sgToDelete []string, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, delSG := range sgToDelete {
+		sgKey := avicache.NamespaceName{Namespace: namespace, Name: delSG}
+		sgCache, ok := rest.cache.StringGroupCache.AviCacheGet(sgKey)
+		if ok {
+			utils.AviLog.Debugf("key: %s, msg: about to delete stringgroup %s", key, delSG)
+			sgCacheObj, _ := sgCache.(*avicache.AviStringGroupCache)
+			restOp := rest.AviStringGroupDel(sgCacheObj.Uuid, namespace, key)
+			restOp.ObjName = delSG
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+func stringGroupInNodes(sg_nodes []*nodes.AviStringGroupNode, sgName string) bool {
+	for _, sg := range sg_nodes {
+		if sg.Name == sgName {
+			return true
+		}
+	}
+	return false
+}
+
 func (rest *RestOperations) L4PolicyCU(l4_nodes []*nodes.AviL4PolicyNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
 	var cache_l4_nodes []avicache.NamespaceName
 	// Default is POST
@@ -1517,6 +1740,53 @@ func (rest *RestOperations) L4PolicyCU(l4_nodes []*nodes.AviL4PolicyNode, vs_cac
 	return cache_l4_nodes, rest_ops
 }
 
+func (rest *RestOperations) NSPolicyCU(nsp_nodes []*nodes.AviNetworkSecurityPolicyNode, vs_cache_obj *avicache.AviVsCache, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var cache_nsp_nodes []avicache.NamespaceName
+	// Default is POST
+	if vs_cache_obj != nil {
+		cache_nsp_nodes = make([]avicache.NamespaceName, len(vs_cache_obj.NSPolicyCollection))
+		copy(cache_nsp_nodes, vs_cache_obj.NSPolicyCollection)
+		for _, nsp := range nsp_nodes {
+			nsp_key := avicache.NamespaceName{Namespace: namespace, Name: nsp.Name}
+			found := utils.HasElem(cache_nsp_nodes, nsp_key)
+			if found {
+				nsp_cache, ok := rest.cache.NSPolicyCache.AviCacheGet(nsp_key)
+				if ok {
+					cache_nsp_nodes = avicache.RemoveNamespaceName(cache_nsp_nodes, nsp_key)
+					nsp_cache_obj, _ := nsp_cache.(*avicache.AviNSPolicyCache)
+					// Cache found. Let's compare the checksums
+					if nsp_cache_obj.CloudConfigCksum == strconv.Itoa(int(nsp.GetCheckSum())) {
+						utils.AviLog.Debugf("The checksums are same for network security policy cache obj %s, not doing anything", nsp_cache_obj.Name)
+					} else {
+						// The checksums are different, so it should be a PUT call.
+						restOp := rest.AviNSPolicyBuild(nsp, nsp_cache_obj, key)
+						if restOp != nil {
+							rest_ops = append(rest_ops, restOp)
+						}
+					}
+				}
+			} else {
+				// Not found - it should be a POST call.
+				restOp := rest.AviNSPolicyBuild(nsp, nil, key)
+				if restOp != nil {
+					rest_ops = append(rest_ops, restOp)
+				}
+			}
+		}
+	} else {
+		// Everything is a POST call
+		for _, nsp := range nsp_nodes {
+			restOp := rest.AviNSPolicyBuild(nsp, nil, key)
+			if restOp != nil {
+				rest_ops = append(rest_ops, restOp)
+			}
+		}
+	}
+	utils.AviLog.Debugf("The network security policies rest_op is %s", utils.Stringify(rest_ops))
+	utils.AviLog.Debugf("key: %s, msg: the network security policies to be deleted are: %s", key, cache_nsp_nodes)
+	return cache_nsp_nodes, rest_ops
+}
+
 func (rest *RestOperations) HTTPPolicyDelete(https_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	for _, del_http := range https_to_delete {
 		// fetch trhe http policyset uuid from cache
@@ -1527,6 +1797,7 @@ func (rest *RestOperations) HTTPPolicyDelete(https_to_delete []avicache.Namespac
 			restOp := rest.AviHttpPolicyDel(http_cache_obj.Uuid, namespace, key)
 			restOp.ObjName = del_http.Name
 			rest_ops = append(rest_ops, restOp)
+			rest_ops = rest.StringGroupDelete(http_cache_obj.StringGroups, namespace, rest_ops, key)
 		}
 	}
 	return rest_ops
@@ -1556,6 +1827,21 @@ func (rest *RestOperations) L4PolicyDelete(l4_to_delete []avicache.NamespaceName
 	return rest_ops
 }
 
+func (rest *RestOperations) NSPolicyDelete(nsp_to_delete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	utils.AviLog.Infof("key: %s, msg: about to delete network security policies %s", key, utils.Stringify(nsp_to_delete))
+	for _, del_nsp := range nsp_to_delete {
+		nsp_key := avicache.NamespaceName{Namespace: namespace, Name: del_nsp.Name}
+		nsp_cache, ok := rest.cache.NSPolicyCache.AviCacheGet(nsp_key)
+		if ok {
+			nsp_cache_obj, _ := nsp_cache.(*avicache.AviNSPolicyCache)
+			restOp := rest.AviNSPolicyDel(nsp_cache_obj.Uuid, namespace, key)
+			restOp.ObjName = del_nsp.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
 func (rest *RestOperations) KeyCertCU(sslkey_nodes []*nodes.AviTLSKeyCertNode, certKeys []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
 	// Default is POST
 	var cache_ssl_nodes []avicache.NamespaceName
@@ -1670,6 +1956,138 @@ func (rest *RestOperations) PkiProfileCU(pki_node *nodes.AviPkiProfileNode, pool
 	return cache_pki_nodes, rest_ops
 }
 
+// PersistenceProfileCU creates or updates the application persistence profile of the pool, which is named after the pool.
+// The profile of a pool which no longer requires one is returned for deletion.
+func (rest *RestOperations) PersistenceProfileCU(pool *nodes.AviPoolNode, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var persistenceToDelete []avicache.NamespaceName
+	persistenceKey := avicache.NamespaceName{Namespace: namespace, Name: pool.Name}
+	var persistenceCacheObj *avicache.AviPersistenceProfileCache
+	if persistenceCache, ok := rest.cache.PersistenceCache.AviCacheGet(persistenceKey); ok {
+		persistenceCacheObj, _ = persistenceCache.(*avicache.AviPersistenceProfileCache)
+	}
+	if pool.PersistenceProfile == nil {
+		if persistenceCacheObj != nil {
+			persistenceToDelete = append(persistenceToDelete, persistenceKey)
+		}
+		return persistenceToDelete, rest_ops
+	}
+	if persistenceCacheObj != nil && persistenceCacheObj.CloudConfigCksum == pool.PersistenceProfile.GetCheckSum() {
+		utils.AviLog.Debugf("key: %s, msg: the checksums are same for application persistence profile %s, not doing anything", key, persistenceKey.Name)
+		return persistenceToDelete, rest_ops
+	}
+	if restOp := rest.AviPersistenceProfileBuild(pool.PersistenceProfile, persistenceCacheObj, key); restOp != nil {
+		rest_ops = append(rest_ops, restOp)
+	}
+	return persistenceToDelete, rest_ops
+}
+
+func (rest *RestOperations) PersistenceProfileDelete(persistenceToDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, delPersistence := range persistenceToDelete {
+		persistenceKey := avicache.NamespaceName{Namespace: namespace, Name: delPersistence.Name}
+		persistenceCache, ok := rest.cache.PersistenceCache.AviCacheGet(persistenceKey)
+		if ok {
+			utils.AviLog.Debugf("key: %s, msg: about to delete application persistence profile %s", key, delPersistence.Name)
+			persistenceCacheObj, _ := persistenceCache.(*avicache.AviPersistenceProfileCache)
+			restOp := rest.AviPersistenceProfileDel(persistenceCacheObj.Uuid, namespace, key)
+			restOp.ObjName = delPersistence.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+// HealthMonitorCU creates or updates the health monitor of the pool, which is named after the pool.
+// The health monitor of a pool which no longer requires one is returned for deletion.
+func (rest *RestOperations) HealthMonitorCU(pool *nodes.AviPoolNode, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var hmToDelete []avicache.NamespaceName
+	hmKey := avicache.NamespaceName{Namespace: namespace, Name: pool.Name}
+	var hmCacheObj *avicache.AviHealthMonitorCache
+	if hmCache, ok := rest.cache.HealthMonitorCache.AviCacheGet(hmKey); ok {
+		hmCacheObj, _ = hmCache.(*avicache.AviHealthMonitorCache)
+	}
+	if pool.HealthMonitorNode == nil {
+		if hmCacheObj != nil {
+			hmToDelete = append(hmToDelete, hmKey)
+		}
+		return hmToDelete, rest_ops
+	}
+	if hmCacheObj != nil && hmCacheObj.CloudConfigCksum == pool.HealthMonitorNode.GetCheckSum() {
+		utils.AviLog.Debugf("key: %s, msg: the checksums are same for health monitor %s, not doing anything", key, hmKey.Name)
+		return hmToDelete, rest_ops
+	}
+	if restOp := rest.AviHealthMonitorBuild(pool.HealthMonitorNode, hmCacheObj, key); restOp != nil {
+		rest_ops = append(rest_ops, restOp)
+	}
+	return hmToDelete, rest_ops
+}
+
+func (rest *RestOperations) HealthMonitorDelete(hmToDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, delHM := range hmToDelete {
+		hmKey := avicache.NamespaceName{Namespace: namespace, Name: delHM.Name}
+		hmCache, ok := rest.cache.HealthMonitorCache.AviCacheGet(hmKey)
+		if ok {
+			utils.AviLog.Debugf("key: %s, msg: about to delete health monitor %s", key, delHM.Name)
+			hmCacheObj, _ := hmCache.(*avicache.AviHealthMonitorCache)
+			restOp := rest.AviHealthMonitorDel(hmCacheObj.Uuid, namespace, key)
+			restOp.ObjName = delHM.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+	}
+	return rest_ops
+}
+
+// ClientCertProfileCU creates or updates the application profile and the PKI profile which validate the client certificates
+// of the virtualservice, both are named after the virtualservice. The profiles of a virtualservice which no longer
+// validates the client certificates are returned for deletion.
+func (rest *RestOperations) ClientCertProfileCU(vsName string, profile *nodes.AviClientCertProfileNode, namespace string, rest_ops []*utils.RestOp, key string) ([]avicache.NamespaceName, []*utils.RestOp) {
+	var profileToDelete []avicache.NamespaceName
+	profileKey := avicache.NamespaceName{Namespace: namespace, Name: vsName}
+	var appProfileCacheObj *avicache.AviAppProfileCache
+	if appProfileCache, ok := rest.cache.AppProfileCache.AviCacheGet(profileKey); ok {
+		appProfileCacheObj, _ = appProfileCache.(*avicache.AviAppProfileCache)
+	}
+	var pkiCacheObj *avicache.AviPkiProfileCache
+	if pkiCache, ok := rest.cache.PKIProfileCache.AviCacheGet(profileKey); ok {
+		pkiCacheObj, _ = pkiCache.(*avicache.AviPkiProfileCache)
+	}
+	if profile == nil {
+		if appProfileCacheObj != nil || pkiCacheObj != nil {
+			profileToDelete = append(profileToDelete, profileKey)
+		}
+		return profileToDelete, rest_ops
+	}
+
+	// The PKI profile has to be created first, as it is referred by the application profile
+	if pkiCacheObj != nil && pkiCacheObj.CloudConfigCksum == profile.PkiProfile.GetCheckSum() {
+		utils.AviLog.Debugf("key: %s, msg: the checksums are same for pki profile %s, not doing anything", key, profileKey.Name)
+	} else if restOp := rest.AviPkiProfileBuild(profile.PkiProfile, pkiCacheObj); restOp != nil {
+		rest_ops = append(rest_ops, restOp)
+	}
+	appProfileChecksum := lib.ClientCertProfileChecksum(profile.Mode, profile.DNHeader, profile.AviMarkers, nil, false)
+	if appProfileCacheObj != nil && appProfileCacheObj.CloudConfigCksum == appProfileChecksum {
+		utils.AviLog.Debugf("key: %s, msg: the checksums are same for application profile %s, not doing anything", key, profileKey.Name)
+	} else if restOp := rest.AviClientCertProfileBuild(profile, appProfileCacheObj, key); restOp != nil {
+		rest_ops = append(rest_ops, restOp)
+	}
+	return profileToDelete, rest_ops
+}
+
+// ClientCertProfileDelete deletes the application profile before the PKI profile it refers to.
+func (rest *RestOperations) ClientCertProfileDelete(profileToDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
+	for _, delProfile := range profileToDelete {
+		profileKey := avicache.NamespaceName{Namespace: namespace, Name: delProfile.Name}
+		if appProfileCache, ok := rest.cache.AppProfileCache.AviCacheGet(profileKey); ok {
+			utils.AviLog.Debugf("key: %s, msg: about to delete application profile %s", key, delProfile.Name)
+			appProfileCacheObj, _ := appProfileCache.(*avicache.AviAppProfileCache)
+			restOp := rest.AviAppProfileDel(appProfileCacheObj.Uuid, namespace, key)
+			restOp.ObjName = delProfile.Name
+			rest_ops = append(rest_ops, restOp)
+		}
+		rest_ops = rest.PkiProfileDelete([]avicache.NamespaceName{profileKey}, namespace, rest_ops, key)
+	}
+	return rest_ops
+}
+
 func (rest *RestOperations) PkiProfileDelete(pkiProfileDelete []avicache.NamespaceName, namespace string, rest_ops []*utils.RestOp, key string) []*utils.RestOp {
 	utils.AviLog.Debugf("key: %s, msg: about to delete pki profile %s", key, utils.Stringify(pkiProfileDelete))
 	for _, delPki := range pkiProfileDelete {
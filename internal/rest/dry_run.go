@@ -0,0 +1,143 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package rest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// dryRunUuids maps "<object type>/<name>" to the uuid of the objects created or updated in the dry run mode,
+// and is used to resolve the name based refs in the synthesized responses.
+var dryRunUuids sync.Map
+
+var nameRefRegex = regexp.MustCompile(`^/api/([a-z]+)/\?name=(.+)$`)
+
+// DryRunRestOperate records and logs the rest operations instead of sending them to the Avi controller.
+// The response of each operation is filled in as if the call had succeeded, so that the avi object cache
+// can be populated and the subsequent model diffs remain consistent.
+func (rest *RestOperations) DryRunRestOperate(rest_ops []*utils.RestOp, aviObjKey avicache.NamespaceName, key string) {
+	var plannedOps []models.PlannedRestOp
+	for _, op := range rest_ops {
+		utils.AviLog.Infof("key: %s, msg: dry run, method: %s, path: %s, object: %s", key, op.Method, op.Path, utils.Stringify(op.Obj))
+		plannedOps = append(plannedOps, models.PlannedRestOp{
+			Method:    string(op.Method),
+			Path:      op.Path,
+			Model:     op.Model,
+			Name:      op.ObjName,
+			Tenant:    op.Tenant,
+			Data:      op.Obj,
+			Timestamp: time.Now(),
+		})
+		op.Err = nil
+		op.Response = dryRunResponse(op, aviObjKey)
+	}
+	models.DryRun.RecordRestOps(aviObjKey.Namespace+"/"+aviObjKey.Name, plannedOps)
+}
+
+// dryRunResponse builds the response the Avi controller would return for the rest operation.
+func dryRunResponse(op *utils.RestOp, aviObjKey avicache.NamespaceName) interface{} {
+	segments := strings.Split(strings.Trim(op.Path, "/"), "/")
+	if len(segments) < 2 {
+		return nil
+	}
+	objType := segments[1]
+
+	if op.Method == utils.RestDelete {
+		if op.ObjName != "" {
+			dryRunUuids.Delete(objType + "/" + op.ObjName)
+		}
+		return nil
+	}
+
+	resp := make(map[string]interface{})
+	if op.Obj != nil {
+		data, err := json.Marshal(op.Obj)
+		if err != nil {
+			utils.AviLog.Warnf("dry run, unable to marshal %s object: %v", op.Model, err)
+			return nil
+		}
+		if err := json.Unmarshal(data, &resp); err != nil {
+			utils.AviLog.Warnf("dry run, unable to unmarshal %s object: %v", op.Model, err)
+			return nil
+		}
+	}
+
+	if op.Method == utils.RestPatch {
+		// The patch payload only carries the modified fields, and the deleted fields are not known here.
+		if op.PatchOp == utils.PatchDeleteOp {
+			for field := range resp {
+				resp[field] = nil
+			}
+		}
+		if _, ok := resp["name"]; !ok {
+			resp["name"] = aviObjKey.Name
+		}
+	}
+
+	name, _ := resp["name"].(string)
+	var uuid string
+	if op.Method == utils.RestPost {
+		uuid = fmt.Sprintf("%s-dryrun-%08x", objType, utils.Hash(op.Tenant+"/"+name))
+	} else {
+		uuid = segments[len(segments)-1]
+	}
+	dryRunUuids.Store(objType+"/"+name, uuid)
+
+	resp["uuid"] = uuid
+	resp["url"] = fmt.Sprintf("/api/%s/%s#%s", objType, uuid, name)
+	resp["_last_modified"] = strconv.FormatInt(time.Now().UnixNano()/1000, 10)
+	cksumField := "cloud_config_cksum"
+	if objType == "vsvip" {
+		cksumField = "vsvip_cloud_config_cksum"
+	}
+	if _, ok := resp[cksumField].(string); !ok {
+		resp[cksumField] = ""
+	}
+	return resolveDryRunRefs(resp)
+}
+
+// resolveDryRunRefs replaces the name based refs, for objects which have a uuid assigned in the dry run mode,
+// with the uuid based refs returned by the Avi controller.
+func resolveDryRunRefs(obj interface{}) interface{} {
+	switch val := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range val {
+			val[k] = resolveDryRunRefs(v)
+		}
+	case []interface{}:
+		for i, v := range val {
+			val[i] = resolveDryRunRefs(v)
+		}
+	case string:
+		match := nameRefRegex.FindStringSubmatch(val)
+		if len(match) != 3 {
+			return val
+		}
+		if uuid, ok := dryRunUuids.Load(match[1] + "/" + match[2]); ok {
+			return fmt.Sprintf("/api/%s/%s#%s", match[1], uuid, match[2])
+		}
+	}
+	return obj
+}
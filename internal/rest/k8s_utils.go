@@ -17,15 +17,24 @@ package rest
 import (
 	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	corev1 "k8s.io/api/core/v1"
 )
 
 // SyncObjectStatuses gets data from L3 cache and does a status update on the ingress objects
 // based on the service metadata objects it finds in the cache
 // This is executed once AKO is done with populating the L3 cache in reboot scenarios
+// The statuses are not synced in the dry run mode
 func (rest *RestOperations) SyncObjectStatuses() {
+	if lib.IsDryRun() {
+		utils.AviLog.Infof("dry run, skipping the status sync of the kubernetes objects")
+		return
+	}
 	vsKeys := rest.cache.VsCacheMeta.AviGetAllKeys()
 	utils.AviLog.Debugf("Ingress status sync for vsKeys %+v", utils.Stringify(vsKeys))
 
@@ -53,6 +62,7 @@ func (rest *RestOperations) SyncObjectStatuses() {
 			allGatewayUpdateOptions = append(allGatewayUpdateOptions,
 				status.UpdateOptions{
 					Vip:             vsCacheObj.Vip,
+					V6Vip:           vsCacheObj.V6Vip,
 					ServiceMetadata: vsSvcMetadataObj,
 					Key:             lib.SyncStatusKey,
 				})
@@ -68,6 +78,7 @@ func (rest *RestOperations) SyncObjectStatuses() {
 				allIngressUpdateOptions = append(allIngressUpdateOptions,
 					status.UpdateOptions{
 						Vip:                parentVsObj.Vip,
+						V6Vip:              parentVsObj.V6Vip,
 						ServiceMetadata:    vsSvcMetadataObj,
 						Key:                lib.SyncStatusKey,
 						VirtualServiceUUID: vsCacheObj.Uuid,
@@ -78,6 +89,7 @@ func (rest *RestOperations) SyncObjectStatuses() {
 			allServiceLBUpdateOptions = append(allServiceLBUpdateOptions,
 				status.UpdateOptions{
 					Vip:                vsCacheObj.Vip,
+					V6Vip:              vsCacheObj.V6Vip,
 					ServiceMetadata:    vsSvcMetadataObj,
 					Key:                lib.SyncStatusKey,
 					VirtualServiceUUID: vsCacheObj.Uuid,
@@ -100,6 +112,7 @@ func (rest *RestOperations) SyncObjectStatuses() {
 					allIngressUpdateOptions = append(allIngressUpdateOptions,
 						status.UpdateOptions{
 							Vip:                vsCacheObj.Vip,
+							V6Vip:              vsCacheObj.V6Vip,
 							ServiceMetadata:    poolCacheObj.ServiceMetadataObj,
 							Key:                lib.SyncStatusKey,
 							VirtualServiceUUID: vsCacheObj.Uuid,
@@ -123,3 +136,140 @@ func (rest *RestOperations) SyncObjectStatuses() {
 	}
 	utils.AviLog.Infof("Status syncing completed")
 }
+
+// SyncObjectOperStatuses publishes the oper status of the virtualservices and pools in the oper status cache on the
+// Services, Ingresses, Routes and HostRules, which are found from the service metadata of the objects in the L3 cache.
+// The oper status cache is expected to be populated before this is called.
+func (rest *RestOperations) SyncObjectOperStatuses() {
+	ingType := utils.Ingress
+	if utils.GetInformers().RouteInformer != nil {
+		ingType = utils.OshiftRoute
+	}
+
+	operStatuses := make(status.OperStatuses)
+	for _, vsKey := range rest.cache.VsCacheMeta.AviGetAllKeys() {
+		if vsKey.Name == lib.DummyVSForStaleData {
+			continue
+		}
+		vsCache, ok := rest.cache.VsCacheMeta.AviCacheGet(vsKey)
+		if !ok {
+			continue
+		}
+		vsCacheObj, found := vsCache.(*avicache.AviVsCache)
+		if !found {
+			continue
+		}
+		vsOperStatus, found := rest.cache.GetOperStatus(vsCacheObj.Uuid)
+		if !found {
+			continue
+		}
+
+		vsSvcMetadataObj := vsCacheObj.ServiceMetadataObj
+		if vsSvcMetadataObj.Gateway != "" {
+			continue
+		}
+		for _, poolKey := range vsCacheObj.PoolKeyCollection {
+			poolCache, ok := rest.cache.PoolCache.AviCacheGet(poolKey)
+			if !ok {
+				continue
+			}
+			poolCacheObj, found := poolCache.(*avicache.AviPoolCache)
+			if !found {
+				continue
+			}
+			poolOperStatus, _ := rest.cache.GetOperStatus(poolCacheObj.Uuid)
+
+			svcMetadataObj := vsSvcMetadataObj
+			if vsCacheObj.ParentVSRef == (avicache.NamespaceName{}) && len(vsSvcMetadataObj.NamespaceServiceName) == 0 {
+				// insecure pools of the shared virtualservice
+				svcMetadataObj = poolCacheObj.ServiceMetadataObj
+			}
+			for _, obj := range getOperStatusObjects(svcMetadataObj, ingType) {
+				operStatuses.Add(obj, vsOperStatus, poolOperStatus)
+			}
+		}
+		if vsCacheObj.ParentVSRef != (avicache.NamespaceName{}) || len(vsSvcMetadataObj.NamespaceServiceName) > 0 {
+			// virtualservices without pools
+			for _, obj := range getOperStatusObjects(vsSvcMetadataObj, ingType) {
+				operStatuses.Add(obj, vsOperStatus, nil)
+			}
+		}
+	}
+
+	status.UpdateOperStatuses(operStatuses)
+}
+
+// getOperStatusObjects returns the Services, Ingresses or Routes and HostRules in the service metadata.
+func getOperStatusObjects(svcMetadataObj avicache.ServiceMetadataObj, ingType string) []status.OperStatusObject {
+	var objs []status.OperStatusObject
+	for _, nsSvcName := range svcMetadataObj.NamespaceServiceName {
+		objs = append(objs, status.OperStatusObject{ObjType: utils.Service, NamespaceName: nsSvcName})
+	}
+	if svcMetadataObj.Namespace == "" {
+		return objs
+	}
+	for _, nsIngName := range svcMetadataObj.NamespaceIngressName {
+		objs = append(objs, status.OperStatusObject{ObjType: ingType, NamespaceName: nsIngName})
+	}
+	if svcMetadataObj.IngressName != "" {
+		objs = append(objs, status.OperStatusObject{ObjType: ingType, NamespaceName: svcMetadataObj.Namespace + "/" + svcMetadataObj.IngressName})
+	}
+	for _, host := range svcMetadataObj.HostNames {
+		if found, hostrule := objects.SharedCRDLister().GetFQDNToHostruleMapping(host); found {
+			objs = append(objs, status.OperStatusObject{ObjType: lib.HostRule, NamespaceName: hostrule})
+		}
+	}
+	return objs
+}
+
+// publishSyncFailureEvents raises an event with the Avi controller error on the ingresses, routes and services
+// that are referred to in the service metadata of the virtualservices and pools in the model.
+func publishSyncFailureEvents(err error, avimodel *nodes.AviObjectGraph, key string) {
+	if avimodel == nil {
+		return
+	}
+	var svcMetadataObjs []avicache.ServiceMetadataObj
+	for _, vsNode := range avimodel.GetAviVS() {
+		svcMetadataObjs = append(svcMetadataObjs, vsNode.ServiceMetadata)
+		for _, sniNode := range vsNode.SniNodes {
+			svcMetadataObjs = append(svcMetadataObjs, sniNode.ServiceMetadata)
+		}
+		for _, poolNode := range vsNode.PoolRefs {
+			svcMetadataObjs = append(svcMetadataObjs, poolNode.ServiceMetadata)
+		}
+	}
+	for _, vsNode := range avimodel.GetAviEvhVS() {
+		svcMetadataObjs = append(svcMetadataObjs, vsNode.ServiceMetadata)
+		for _, evhNode := range vsNode.EvhNodes {
+			svcMetadataObjs = append(svcMetadataObjs, evhNode.ServiceMetadata)
+		}
+		for _, poolNode := range vsNode.PoolRefs {
+			svcMetadataObjs = append(svcMetadataObjs, poolNode.ServiceMetadata)
+		}
+	}
+
+	ingType := utils.Ingress
+	if utils.GetInformers().RouteInformer != nil {
+		ingType = utils.OshiftRoute
+	}
+	type k8sObj struct{ objType, nsName string }
+	objs := make(map[k8sObj]bool)
+	for _, svcMetadataObj := range svcMetadataObjs {
+		for _, nsSvcName := range svcMetadataObj.NamespaceServiceName {
+			objs[k8sObj{utils.Service, nsSvcName}] = true
+		}
+		for _, nsIngName := range svcMetadataObj.NamespaceIngressName {
+			objs[k8sObj{ingType, nsIngName}] = true
+		}
+		if svcMetadataObj.IngressName != "" && svcMetadataObj.Namespace != "" {
+			objs[k8sObj{ingType, svcMetadataObj.Namespace + "/" + svcMetadataObj.IngressName}] = true
+		}
+	}
+
+	for obj := range objs {
+		namespace, name := utils.ExtractNamespaceObjectName(obj.nsName)
+		utils.AviLog.Debugf("key: %s, msg: raising sync failure event on %s %s", key, obj.objType, obj.nsName)
+		lib.AKOEventf(obj.objType, namespace, name, corev1.EventTypeWarning, lib.AviSyncFailed,
+			"Failed to sync the configuration to the Avi controller: %s", err.Error())
+	}
+}
@@ -18,6 +18,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
@@ -25,6 +26,10 @@ import (
 	"github.com/vmware/alb-sdk/go/clients"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	"github.com/vmware/alb-sdk/go/session"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // modelSchema defines an interface to handle rest operations for an object type.
@@ -245,12 +250,21 @@ type AviRestClientPool struct {
 	AviClient []*clients.AviClient
 }
 
-func AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp) error {
+// AviRestOperate executes the rest operations in order, for the model with the key. The rest operations after
+// a failed operation are aborted, unless the error can be ignored.
+func AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp, key string) error {
+	modelLog := lib.ModelLogger(key, utils.RestLayer)
+	parentSpan := utils.GetActiveSpan(utils.GraphLayer, key)
 	for i, op := range rest_ops {
+		log := modelLog.With(utils.LogRestOp, op.Method, utils.LogRestModel, op.Model)
 		SetTenant := session.SetTenant(op.Tenant)
 		SetTenant(c.AviSession)
 		SetVersion := session.SetVersion(op.Version)
 		SetVersion(c.AviSession)
+		span := utils.StartSpan(string(op.Method)+" "+op.Model, parentSpan, trace.SpanKindClient, attribute.String(utils.LogKey, key),
+			attribute.String(utils.LogRestOp, string(op.Method)), attribute.String(utils.LogRestModel, op.Model),
+			attribute.String(utils.LogTenant, op.Tenant), semconv.HTTPURLKey.String(op.Path))
+		start := time.Now()
 		switch op.Method {
 		case utils.RestPost:
 			op.Err = c.AviSession.Post(op.Path, op.Obj, &op.Response)
@@ -264,21 +278,27 @@ func AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp) error {
 		case utils.RestDelete:
 			op.Err = c.AviSession.Delete(op.Path)
 		default:
-			utils.AviLog.Errorf("Unknown RestOp %v", op.Method)
+			log.Errorf("Unknown RestOp %v", op.Method)
 			op.Err = fmt.Errorf("Unknown RestOp %v", op.Method)
 		}
+		utils.ObserveAviRestOperation(op.Model, op.Method, op.Err, start)
 		if op.Err != nil {
-			utils.AviLog.Warnf(`RestOp method %v path %v tenant %v Obj %s returned err %s with response %s`,
+			span.RecordError(op.Err)
+			span.SetStatus(codes.Error, op.Err.Error())
+		}
+		span.End()
+		if op.Err != nil {
+			log.Warnf(`RestOp method %v path %v tenant %v Obj %s returned err %s with response %s`,
 				op.Method, op.Path, op.Tenant, utils.Stringify(op.Obj), utils.Stringify(op.Err), utils.Stringify(op.Response))
 			// Wrap the error into a websync error.
 			err := &utils.WebSyncError{Err: op.Err, Operation: string(op.Method)}
 			aviErr, ok := op.Err.(session.AviError)
 			if !ok {
-				utils.AviLog.Warnf("Error in rest operation is not of type AviError, err: %v, %T", op.Err, op.Err)
+				log.Warnf("Error in rest operation is not of type AviError, err: %v, %T", op.Err, op.Err)
 			} else if op.Model == "VsVip" && op.Method == utils.RestPut {
-				utils.AviLog.Debugf("Error in rest operation for VsVip Put request.")
+				log.Debugf("Error in rest operation for VsVip Put request.")
 			} else if aviErr.HttpStatusCode == 404 && op.Method == utils.RestDelete {
-				utils.AviLog.Warnf("Error during rest operation: %v, object of type %s not found in the controller. Ignoring err: %v", op.Method, op.Model, op.Err)
+				log.Warnf("Error during rest operation: %v, object of type %s not found in the controller. Ignoring err: %v", op.Method, op.Model, op.Err)
 				continue
 			} else if !isErrorRetryable(aviErr.HttpStatusCode, *aviErr.Message) {
 				if op.Method != utils.RestPost {
@@ -294,7 +314,7 @@ func AviRestOperate(c *clients.AviClient, rest_ops []*utils.RestOp) error {
 			}
 			return err
 		} else {
-			utils.AviLog.Debugf(`RestOp method %v path %v tenant %v response %v`,
+			log.Debugf(`RestOp method %v path %v tenant %v response %v`,
 				op.Method, op.Path, op.Tenant, utils.Stringify(op.Response))
 		}
 	}
@@ -22,16 +22,20 @@ import (
 
 func DequeueFastRetry(vsKey string) {
 	utils.AviLog.Infof("Retrieved the key for fast retry: %s", vsKey)
+	utils.IncRetryCount(lib.FAST_RETRY_LAYER)
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	modelName := lib.GetTenant() + "/" + vsKey
+	utils.TraceEnqueue(utils.GraphLayer, modelName, utils.GetActiveSpan(lib.FAST_RETRY_LAYER, vsKey))
 	nodes.PublishKeyToRestLayer(modelName, "retry", sharedQueue)
 
 }
 
 func DequeueSlowRetry(vsKey string) {
 	utils.AviLog.Infof("Retrieved the key for slow retry: %s", vsKey)
+	utils.IncRetryCount(lib.SLOW_RETRY_LAYER)
 	sharedQueue := utils.SharedWorkQueue().GetQueueByName(utils.GraphLayer)
 	modelName := lib.GetTenant() + "/" + vsKey
+	utils.TraceEnqueue(utils.GraphLayer, modelName, utils.GetActiveSpan(lib.SLOW_RETRY_LAYER, vsKey))
 	nodes.PublishKeyToRestLayer(modelName, "retry", sharedQueue)
 
 }
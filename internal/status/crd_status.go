@@ -17,9 +17,11 @@ package status
 import (
 	"context"
 	"encoding/json"
+	"reflect"
 	"strings"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
@@ -44,8 +46,18 @@ func UpdateHostRuleStatus(key string, hr *akov1alpha1.HostRule, updateStatus Upd
 		}
 	}
 
+	// The fqdns a rejected HostRule was applied to are cleared, the fqdns of an accepted
+	// HostRule are updated once the HostRule is applied.
+	hrStatus := map[string]interface{}{
+		"status": updateStatus.Status,
+		"error":  updateStatus.Error,
+		"fqdns":  nil,
+	}
+	if updateStatus.Status == lib.StatusAccepted {
+		hrStatus["fqdns"] = hr.Status.Fqdns
+	}
 	patchPayload, _ := json.Marshal(map[string]interface{}{
-		"status": akov1alpha1.HostRuleStatus(updateStatus),
+		"status": hrStatus,
 	})
 
 	_, err := lib.GetCRDClientset().AkoV1alpha1().HostRules(hr.Namespace).Patch(context.TODO(), hr.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
@@ -65,6 +77,43 @@ func UpdateHostRuleStatus(key string, hr *akov1alpha1.HostRule, updateStatus Upd
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the hostrule %s/%s status %+v", key, hr.Namespace, hr.Name, utils.Stringify(updateStatus))
 }
 
+// UpdateHostRuleFqdnsStatus updates the fqdns the HostRule is applied to in the HostRule status.
+func UpdateHostRuleFqdnsStatus(key, namespace, name string, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 3 {
+			utils.AviLog.Errorf("key: %s, msg: UpdateHostRuleFqdnsStatus retried 3 times, aborting", key)
+			return
+		}
+	}
+
+	hr, err := lib.GetCRDInformers().HostRuleInformer.Lister().HostRules(namespace).Get(name)
+	if err != nil {
+		utils.AviLog.Debugf("key: %s, msg: hostrule %s/%s not found: %v", key, namespace, name, err)
+		return
+	}
+	fqdns := objects.SharedCRDLister().GetHostruleAppliedFqdns(namespace + "/" + name)
+	if hr.Status.Status != lib.StatusAccepted || reflect.DeepEqual(hr.Status.Fqdns, fqdns) {
+		return
+	}
+
+	// fqdns is set to null in the merge patch when the HostRule is not applied to any fqdn.
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": map[string]interface{}{
+			"fqdns": fqdns,
+		},
+	})
+	_, err = lib.GetCRDClientset().AkoV1alpha1().HostRules(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: %d there was an error in updating the hostrule fqdns: %+v", key, retry, err)
+		UpdateHostRuleFqdnsStatus(key, namespace, name, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the hostrule %s/%s fqdns %v", key, namespace, name, fqdns)
+}
+
 // UpdateHTTPRuleStatus HttpRule status updates
 func UpdateHTTPRuleStatus(key string, rr *akov1alpha1.HTTPRule, updateStatus UpdateCRDStatusOptions, retryNum ...int) {
 	retry := 0
@@ -128,3 +177,35 @@ func UpdateAviInfraSettingStatus(key string, infraSetting *akov1alpha1.AviInfraS
 
 	utils.AviLog.Infof("key: %s, msg: Successfully updated the aviinfrasetting %s status %+v", key, infraSetting.Name, utils.Stringify(updateStatus))
 }
+
+// UpdateL4RuleStatus L4Rule status updates
+func UpdateL4RuleStatus(key string, l4Rule *akov1alpha1.L4Rule, updateStatus UpdateCRDStatusOptions, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 3 {
+			utils.AviLog.Errorf("key: %s, msg: UpdateL4RuleStatus retried 3 times, aborting", key)
+			return
+		}
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": akov1alpha1.L4RuleStatus(updateStatus),
+	})
+
+	_, err := lib.GetCRDClientset().AkoV1alpha1().L4Rules(l4Rule.Namespace).Patch(context.TODO(), l4Rule.Name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	if err != nil {
+		utils.AviLog.Errorf("key: %s, msg: %d there was an error in updating the l4rule status: %+v", key, retry, err)
+		updatedL4Rule, err := lib.GetCRDClientset().AkoV1alpha1().L4Rules(l4Rule.Namespace).Get(context.TODO(), l4Rule.Name, metav1.GetOptions{})
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: l4rule not found %v", key, err)
+			if strings.Contains(err.Error(), utils.K8S_ETIMEDOUT) {
+				UpdateL4RuleStatus(key, updatedL4Rule, updateStatus, retry+1)
+			}
+			return
+		}
+		UpdateL4RuleStatus(key, updatedL4Rule, updateStatus, retry+1)
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the l4rule %s/%s status %+v", key, l4Rule.Namespace, l4Rule.Name, utils.Stringify(updateStatus))
+}
@@ -0,0 +1,377 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	avicache "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	routev1 "github.com/openshift/api/route/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// OperStatusAnnotation holds the oper status of the virtualservices and pools of a Service, Ingress or HostRule.
+	OperStatusAnnotation = "ako.vmware.com/oper-status"
+
+	// RouteOperStatusUp is the condition set on the route ingress entries admitted by AKO, which is True
+	// when the virtualservices and pools of the route are up on the Avi controller.
+	RouteOperStatusUp routev1.RouteIngressConditionType = "AviOperStatusUp"
+
+	operStatusUpReason   = "OperUp"
+	operStatusDownReason = "OperDown"
+)
+
+type PoolOperStatus struct {
+	Pool      string `json:"pool"`
+	State     string `json:"state"`
+	ServersUp int32  `json:"serversUp"`
+	Servers   int32  `json:"servers"`
+}
+
+type VSOperStatus struct {
+	VirtualService string           `json:"virtualService"`
+	State          string           `json:"state"`
+	Reason         []string         `json:"reason,omitempty"`
+	Pools          []PoolOperStatus `json:"pools,omitempty"`
+}
+
+// OperStatusObject is the kubernetes object the oper status is published on, ObjType is one of
+// utils.Service, utils.Ingress, utils.OshiftRoute and lib.HostRule.
+type OperStatusObject struct {
+	ObjType       string
+	NamespaceName string
+}
+
+// OperStatuses collects the oper status of the virtualservices, and of their pools, for each kubernetes object.
+type OperStatuses map[OperStatusObject]map[string]*VSOperStatus
+
+// Add records the oper status of the virtualservice for the object, along with the oper status of the pool if set.
+func (o OperStatuses) Add(obj OperStatusObject, vs *avicache.AviOperStatusCache, pool *avicache.AviOperStatusCache) {
+	if _, ok := o[obj]; !ok {
+		o[obj] = make(map[string]*VSOperStatus)
+	}
+	vsStatus, ok := o[obj][vs.Name]
+	if !ok {
+		vsStatus = &VSOperStatus{
+			VirtualService: vs.Name,
+			State:          vs.State,
+			Reason:         vs.Reason,
+		}
+		o[obj][vs.Name] = vsStatus
+	}
+	if pool == nil {
+		return
+	}
+	for _, poolStatus := range vsStatus.Pools {
+		if poolStatus.Pool == pool.Name {
+			return
+		}
+	}
+	vsStatus.Pools = append(vsStatus.Pools, PoolOperStatus{
+		Pool:      pool.Name,
+		State:     pool.State,
+		ServersUp: pool.NumServersUp,
+		Servers:   pool.NumServers,
+	})
+}
+
+// get returns the oper statuses of the object sorted by the virtualservice and pool names.
+func (o OperStatuses) get(obj OperStatusObject) []VSOperStatus {
+	var vsStatuses []VSOperStatus
+	for _, vsStatus := range o[obj] {
+		pools := append([]PoolOperStatus{}, vsStatus.Pools...)
+		sort.Slice(pools, func(i, j int) bool {
+			return pools[i].Pool < pools[j].Pool
+		})
+		vsStatuses = append(vsStatuses, VSOperStatus{
+			VirtualService: vsStatus.VirtualService,
+			State:          vsStatus.State,
+			Reason:         vsStatus.Reason,
+			Pools:          pools,
+		})
+	}
+	sort.Slice(vsStatuses, func(i, j int) bool {
+		return vsStatuses[i].VirtualService < vsStatuses[j].VirtualService
+	})
+	return vsStatuses
+}
+
+// OperStatusOptions holds the oper statuses to be published on a kubernetes object through the status queue,
+// ObjType is one of utils.Service, utils.Ingress, utils.OshiftRoute and lib.HostRule.
+type OperStatusOptions struct {
+	ObjType    string
+	VSStatuses []VSOperStatus
+}
+
+// UpdateOperStatuses publishes the oper statuses of the Services, Ingresses and HostRules as the oper status
+// annotation, and of the Routes as the AviOperStatusUp condition, to the status queue. The objects are read from
+// the informer caches, and only the objects whose annotation or condition is out of date are published. The
+// annotation and condition are removed from the objects which no longer have a virtualservice.
+func UpdateOperStatuses(operStatuses OperStatuses) {
+	informers := utils.GetInformers()
+	if !lib.GetLayer7Only() {
+		svcs, err := informers.ServiceInformer.Lister().List(labels.Everything())
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the services for oper status update: %v", err)
+		}
+		for _, svc := range svcs {
+			publishAnnotationOperStatus(utils.Service, svc, operStatuses)
+		}
+	}
+	if informers.RouteInformer != nil {
+		routes, err := informers.RouteInformer.Lister().List(labels.Everything())
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the routes for oper status update: %v", err)
+		}
+		for _, route := range routes {
+			vsStatuses := operStatuses.get(OperStatusObject{utils.OshiftRoute, route.Namespace + "/" + route.Name})
+			if setRouteOperStatusCondition(route.DeepCopy(), vsStatuses) {
+				publishOperStatus(utils.OshiftRoute, route.Namespace, route.Name, vsStatuses)
+			}
+		}
+	} else {
+		ingresses, err := informers.IngressInformer.Lister().List(labels.Everything())
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the ingresses for oper status update: %v", err)
+		}
+		for _, ing := range ingresses {
+			publishAnnotationOperStatus(utils.Ingress, ing, operStatuses)
+		}
+	}
+	if lib.GetHostRuleEnabled() {
+		hostRules, err := lib.GetCRDInformers().HostRuleInformer.Lister().List(labels.Everything())
+		if err != nil {
+			utils.AviLog.Warnf("Could not get the hostrules for oper status update: %v", err)
+		}
+		for _, hr := range hostRules {
+			publishAnnotationOperStatus(lib.HostRule, hr, operStatuses)
+		}
+	}
+	utils.AviLog.Infof("Oper status syncing completed")
+}
+
+// publishAnnotationOperStatus publishes the oper status of the object to the status queue, if the oper status
+// annotation of the object is out of date.
+func publishAnnotationOperStatus(objType string, obj metav1.Object, operStatuses OperStatuses) {
+	vsStatuses := operStatuses.get(OperStatusObject{objType, obj.GetNamespace() + "/" + obj.GetName()})
+	if getOperStatusAnnotationPayload(obj.GetAnnotations(), vsStatuses) == nil {
+		return
+	}
+	publishOperStatus(objType, obj.GetNamespace(), obj.GetName(), vsStatuses)
+}
+
+func publishOperStatus(objType, namespace, name string, vsStatuses []VSOperStatus) {
+	statusOption := StatusOptions{
+		ObjType:   lib.OperStatus,
+		Op:        lib.UpdateStatus,
+		ObjName:   name,
+		Namespace: namespace,
+		Key:       objType + "/" + namespace + "/" + name,
+		Oper: &OperStatusOptions{
+			ObjType:    objType,
+			VSStatuses: vsStatuses,
+		},
+	}
+	PublishToStatusQueue(namespace+"/"+name, statusOption)
+}
+
+// UpdateObjectOperStatus updates the oper status annotation of the Service, Ingress or HostRule, or the oper status
+// condition of the Route, from the status queue.
+func UpdateObjectOperStatus(key, objType, namespace, name string, vsStatuses []VSOperStatus) {
+	var err error
+	switch objType {
+	case utils.Service:
+		err = updateSvcOperStatus(namespace, name, vsStatuses)
+	case utils.Ingress:
+		err = updateIngressOperStatus(namespace, name, vsStatuses)
+	case lib.HostRule:
+		err = updateHostRuleOperStatus(namespace, name, vsStatuses)
+	case utils.OshiftRoute:
+		err = updateRouteOperStatus(namespace, name, vsStatuses)
+	default:
+		return
+	}
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error in updating the oper status: %v", key, err)
+		return
+	}
+	utils.AviLog.Debugf("key: %s, msg: updated the oper status", key)
+}
+
+func updateSvcOperStatus(namespace, name string, vsStatuses []VSOperStatus) error {
+	svc, err := utils.GetInformers().ServiceInformer.Lister().Services(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	payload := getOperStatusAnnotationPayload(svc.Annotations, vsStatuses)
+	if payload == nil {
+		return nil
+	}
+	_, err = utils.GetInformers().ClientSet.CoreV1().Services(namespace).Patch(context.TODO(), name,
+		types.MergePatchType, payload, metav1.PatchOptions{})
+	return err
+}
+
+func updateIngressOperStatus(namespace, name string, vsStatuses []VSOperStatus) error {
+	ing, err := utils.GetInformers().IngressInformer.Lister().Ingresses(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	payload := getOperStatusAnnotationPayload(ing.Annotations, vsStatuses)
+	if payload == nil {
+		return nil
+	}
+	_, err = utils.PatchIngress(utils.GetInformers().ClientSet, namespace, name, types.MergePatchType, payload)
+	return err
+}
+
+func updateHostRuleOperStatus(namespace, name string, vsStatuses []VSOperStatus) error {
+	hr, err := lib.GetCRDInformers().HostRuleInformer.Lister().HostRules(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	payload := getOperStatusAnnotationPayload(hr.Annotations, vsStatuses)
+	if payload == nil {
+		return nil
+	}
+	_, err = lib.GetCRDClientset().AkoV1alpha1().HostRules(namespace).Patch(context.TODO(), name,
+		types.MergePatchType, payload, metav1.PatchOptions{})
+	return err
+}
+
+func updateRouteOperStatus(namespace, name string, vsStatuses []VSOperStatus) error {
+	route, err := utils.GetInformers().RouteInformer.Lister().Routes(namespace).Get(name)
+	if err != nil {
+		return nil
+	}
+	mRoute := route.DeepCopy()
+	if !setRouteOperStatusCondition(mRoute, vsStatuses) {
+		return nil
+	}
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": mRoute.Status,
+	})
+	_, err = utils.GetInformers().OshiftClient.RouteV1().Routes(namespace).Patch(context.TODO(), name,
+		types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// getOperStatusAnnotationPayload returns the merge patch for the oper status annotation, which removes the
+// annotation if there are no oper statuses. nil is returned if the annotation is already up to date.
+func getOperStatusAnnotationPayload(annotations map[string]string, vsStatuses []VSOperStatus) []byte {
+	var value *string
+	if len(vsStatuses) > 0 {
+		statusBytes, _ := json.Marshal(vsStatuses)
+		statusStr := string(statusBytes)
+		value = &statusStr
+	}
+
+	oldValue, found := annotations[OperStatusAnnotation]
+	if (value == nil && !found) || (value != nil && found && oldValue == *value) {
+		return nil
+	}
+
+	payload, _ := json.Marshal(map[string]interface{}{
+		"metadata": map[string]map[string]*string{
+			"annotations": {
+				OperStatusAnnotation: value,
+			},
+		},
+	})
+	return payload
+}
+
+// setRouteOperStatusCondition sets the oper status condition on the route ingress entries of AKO, and returns
+// true if the status of the route is changed.
+func setRouteOperStatusCondition(mRoute *routev1.Route, vsStatuses []VSOperStatus) bool {
+	var updated bool
+	for i := range mRoute.Status.Ingress {
+		rtIngress := &mRoute.Status.Ingress[i]
+		if rtIngress.RouterName != lib.AKOUser {
+			continue
+		}
+
+		var oldCondition *routev1.RouteIngressCondition
+		conditions := make([]routev1.RouteIngressCondition, 0, len(rtIngress.Conditions))
+		for j := range rtIngress.Conditions {
+			if rtIngress.Conditions[j].Type == RouteOperStatusUp {
+				oldCondition = &rtIngress.Conditions[j]
+				continue
+			}
+			conditions = append(conditions, rtIngress.Conditions[j])
+		}
+
+		if len(vsStatuses) == 0 {
+			if oldCondition != nil {
+				rtIngress.Conditions = conditions
+				updated = true
+			}
+			continue
+		}
+
+		condition := getRouteOperStatusCondition(vsStatuses)
+		if oldCondition != nil && oldCondition.Status == condition.Status &&
+			oldCondition.Reason == condition.Reason && oldCondition.Message == condition.Message {
+			continue
+		}
+		if oldCondition != nil && oldCondition.Status == condition.Status {
+			condition.LastTransitionTime = oldCondition.LastTransitionTime
+		}
+		rtIngress.Conditions = append(conditions, condition)
+		updated = true
+	}
+	return updated
+}
+
+func getRouteOperStatusCondition(vsStatuses []VSOperStatus) routev1.RouteIngressCondition {
+	now := metav1.Now()
+	condition := routev1.RouteIngressCondition{
+		Type:               RouteOperStatusUp,
+		Status:             corev1.ConditionTrue,
+		Reason:             operStatusUpReason,
+		LastTransitionTime: &now,
+	}
+
+	var messages []string
+	for _, vsStatus := range vsStatuses {
+		if vsStatus.State != avicache.OperStateUp {
+			condition.Status = corev1.ConditionFalse
+		}
+		messages = append(messages, fmt.Sprintf("virtualservice %s is %s", vsStatus.VirtualService, vsStatus.State))
+		for _, poolStatus := range vsStatus.Pools {
+			if poolStatus.State != avicache.OperStateUp {
+				condition.Status = corev1.ConditionFalse
+			}
+			messages = append(messages, fmt.Sprintf("pool %s is %s with %d/%d servers up",
+				poolStatus.Pool, poolStatus.State, poolStatus.ServersUp, poolStatus.Servers))
+		}
+	}
+	if condition.Status == corev1.ConditionFalse {
+		condition.Reason = operStatusDownReason
+	}
+	condition.Message = strings.Join(messages, ", ")
+	return condition
+}
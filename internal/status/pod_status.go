@@ -0,0 +1,83 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package status
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+)
+
+// UpdatePodReadinessGates sets the readiness gate condition managed by AKO to True, for the pods backing
+// the servers of the pool, once the pod IP is added to all the pools it is a server of.
+func UpdatePodReadinessGates(key, pool string) {
+	podInformer := utils.GetInformers().PodInformer
+	if podInformer == nil {
+		return
+	}
+
+	for _, ip := range objects.SharedPoolServerLister().GetServers(pool) {
+		podObjs, err := podInformer.Informer().GetIndexer().ByIndex(lib.PodIPIndex, ip)
+		if err != nil {
+			utils.AviLog.Warnf("key: %s, msg: error in getting pods with IP %s: %v", key, ip, err)
+			continue
+		}
+		for _, podObj := range podObjs {
+			pod, ok := podObj.(*corev1.Pod)
+			if !ok || !lib.IsPodWaitingForReadinessGate(pod) {
+				continue
+			}
+			if !objects.SharedPoolServerLister().IsServerAdded(ip) {
+				utils.AviLog.Debugf("key: %s, msg: pod %s/%s is not yet added to all pools", key, pod.Namespace, pod.Name)
+				continue
+			}
+			setPodReadinessGate(key, pod)
+		}
+	}
+}
+
+func setPodReadinessGate(key string, pod *corev1.Pod) {
+	pod = pod.DeepCopy()
+	condition := corev1.PodCondition{
+		Type:               lib.PodReadinessGateConditionType,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             "PoolServerAdded",
+		Message:            "Pod is added as a server to all the Avi pools",
+	}
+
+	var foundCondition bool
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == lib.PodReadinessGateConditionType {
+			pod.Status.Conditions[i] = condition
+			foundCondition = true
+			break
+		}
+	}
+	if !foundCondition {
+		pod.Status.Conditions = append(pod.Status.Conditions, condition)
+	}
+
+	if _, err := utils.GetInformers().ClientSet.CoreV1().Pods(pod.Namespace).UpdateStatus(context.TODO(), pod, metav1.UpdateOptions{}); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: error in updating the readiness gate of pod %s/%s: %v", key, pod.Namespace, pod.Name, err)
+		return
+	}
+	utils.AviLog.Infof("key: %s, msg: set readiness gate %s of pod %s/%s to True", key, lib.PodReadinessGateConditionType, pod.Namespace, pod.Name)
+}
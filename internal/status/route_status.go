@@ -275,7 +275,8 @@ func routeStatusCheck(key string, oldStatus []routev1.RouteIngress, hostname str
 }
 
 func updateRouteObject(mRoute *routev1.Route, updateOption UpdateOptions, retryNum ...int) error {
-	if updateOption.Vip == "" {
+	vips := getVips(updateOption)
+	if len(vips) == 0 {
 		return nil
 	}
 	if updateOption.ServiceMetadata.InsecureEdgeTermAllow {
@@ -296,8 +297,15 @@ func updateRouteObject(mRoute *routev1.Route, updateOption UpdateOptions, retryN
 	oldRouteStatus := mRoute.Status.DeepCopy()
 
 	// If we find a hostname in the present update, let's first remove it from the existing status.
+	// The oper status conditions of the hostnames are retained till the next oper status sync.
+	operConditions := make(map[string]routev1.RouteIngressCondition)
 	for i := len(mRoute.Status.Ingress) - 1; i >= 0; i-- {
 		if utils.HasElem(hostnames, mRoute.Status.Ingress[i].Host) {
+			for _, condition := range mRoute.Status.Ingress[i].Conditions {
+				if condition.Type == RouteOperStatusUp && mRoute.Status.Ingress[i].RouterName == lib.AKOUser {
+					operConditions[mRoute.Status.Ingress[i].Host] = condition
+				}
+			}
 			mRoute.Status.Ingress = append(mRoute.Status.Ingress[:i], mRoute.Status.Ingress[i+1:]...)
 		}
 	}
@@ -306,7 +314,7 @@ func updateRouteObject(mRoute *routev1.Route, updateOption UpdateOptions, retryN
 	for _, host := range hostnames {
 		now := metav1.Now()
 		condition := routev1.RouteIngressCondition{
-			Message:            updateOption.Vip,
+			Message:            strings.Join(vips, ","),
 			Status:             corev1.ConditionTrue,
 			LastTransitionTime: &now,
 			Type:               routev1.RouteAdmitted,
@@ -318,6 +326,9 @@ func updateRouteObject(mRoute *routev1.Route, updateOption UpdateOptions, retryN
 				condition,
 			},
 		}
+		if operCondition, ok := operConditions[host]; ok {
+			rtIngress.Conditions = append(rtIngress.Conditions, operCondition)
+		}
 		mRoute.Status.Ingress = append(mRoute.Status.Ingress, rtIngress)
 	}
 
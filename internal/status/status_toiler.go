@@ -27,14 +27,30 @@ type StatusOptions struct {
 	Namespace string
 	Key       string
 	Options   *UpdateOptions
+	Route     *SvcApiRouteStatusOptions
+	Oper      *OperStatusOptions
 }
 
 func PublishToStatusQueue(key string, statusOption StatusOptions) {
+	if lib.IsDryRun() && isVSStatus(statusOption.ObjType) {
+		utils.AviLog.Debugf("key: %s, msg: dry run, skipping the %s status update of %s", statusOption.Key, statusOption.ObjType, key)
+		return
+	}
 	statusQueue := utils.SharedWorkQueue().GetQueueByName(utils.StatusQueue)
 	bkt := utils.Bkt(key, statusQueue.NumWorkers)
 	statusQueue.Workqueue[bkt].AddRateLimited(statusOption)
 }
 
+// isVSStatus returns true for the objects whose status reports the virtualservices on the Avi controller,
+// these are not updated in the dry run mode as the virtualservices are not created.
+func isVSStatus(objType string) bool {
+	switch objType {
+	case utils.L4LBService, utils.Ingress, utils.OshiftRoute, lib.Gateway, lib.SERVICES_API, lib.OperStatus:
+		return true
+	}
+	return false
+}
+
 func DequeueStatus(objIntf interface{}) error {
 	obj, ok := objIntf.(StatusOptions)
 	if !ok {
@@ -72,6 +88,22 @@ func DequeueStatus(objIntf interface{}) error {
 		} else if obj.Op == lib.DeleteStatus {
 			DeleteSvcApiGatewayStatusAddress(obj.Options.Key, obj.Options.ServiceMetadata)
 		}
+	case lib.HTTPRoute, lib.TCPRoute, lib.UDPRoute, lib.TLSRoute:
+		if obj.Op == lib.UpdateStatus {
+			UpdateSvcApiRouteStatus(obj.Key, obj.ObjType, obj.Namespace, obj.ObjName, obj.Route.RouteStatus, obj.Route.GwNamespace, obj.Route.GwName, obj.Route.Condition)
+		}
+	case lib.PodReadinessGate:
+		if obj.Op == lib.UpdateStatus {
+			UpdatePodReadinessGates(obj.Key, obj.Namespace+"/"+obj.ObjName)
+		}
+	case lib.HostRule:
+		if obj.Op == lib.UpdateStatus {
+			UpdateHostRuleFqdnsStatus(obj.Key, obj.Namespace, obj.ObjName)
+		}
+	case lib.OperStatus:
+		if obj.Op == lib.UpdateStatus {
+			UpdateObjectOperStatus(obj.Key, obj.Oper.ObjType, obj.Namespace, obj.ObjName, obj.Oper.VSStatuses)
+		}
 	case lib.NPLService:
 		if obj.Op == lib.UpdateStatus {
 			UpdateNPLAnnotation(obj.Key, obj.Namespace, obj.ObjName)
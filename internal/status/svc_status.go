@@ -50,7 +50,8 @@ func UpdateL4LBStatus(options []UpdateOptions, bulk bool) {
 		key, svcMetadata := option.Key, option.ServiceMetadata
 		if service := serviceMap[option.IngSvc]; service != nil {
 			oldServiceStatus := service.Status.LoadBalancer.DeepCopy()
-			if option.Vip == "" {
+			vips := getVips(option)
+			if len(vips) == 0 {
 				// nothing to do here
 				continue
 			}
@@ -59,12 +60,17 @@ func UpdateL4LBStatus(options []UpdateOptions, bulk bool) {
 			if len(svcMetadata.HostNames) > 0 {
 				svcHostname = svcMetadata.HostNames[0]
 			}
+			var lbIngress []corev1.LoadBalancerIngress
+			for _, vip := range vips {
+				lbIngress = append(lbIngress, corev1.LoadBalancerIngress{
+					IP:       vip,
+					Hostname: svcHostname,
+				})
+			}
 			service.Status = corev1.ServiceStatus{
 				LoadBalancer: corev1.LoadBalancerStatus{
-					Ingress: []corev1.LoadBalancerIngress{{
-						IP:       option.Vip,
-						Hostname: svcHostname,
-					}}}}
+					Ingress: lbIngress,
+				}}
 
 			sameStatus := compareLBStatus(oldServiceStatus, &service.Status.LoadBalancer)
 			var updatedSvc *corev1.Service
@@ -19,6 +19,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 
 	svcapiv1alpha1 "sigs.k8s.io/service-apis/apis/v1alpha1"
@@ -27,6 +28,7 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -61,6 +63,12 @@ func UpdateSvcApiGatewayStatusAddress(options []UpdateOptions, bulk bool) {
 				Type:   "Ready",
 				Status: metav1.ConditionTrue,
 			})
+			// The listeners which are not realised on the virtualservice are not ready.
+			if lib.IsSvcApiL7Gateway(gw) {
+				UpdateSvcApiL7GatewayListenerConditions(option.Key, gw, gwStatus)
+			} else {
+				UpdateSvcApiL4GatewayListenerConditions(option.Key, gw, gwStatus)
+			}
 			UpdateSvcApiGatewayStatusObject(option.Key, gw, gwStatus)
 			delete(gatewayMap, option.IngSvc)
 		}
@@ -250,13 +258,13 @@ func UpdateSvcApiGatewayStatusGWCondition(key string, gwStatus *svcapiv1alpha1.G
 }
 
 // supported ListenerConditionType
-// PortConflict, InvalidRoutes, UnsupportedProtocol, *Serviceable
+// PortConflict, InvalidRoutes, UnsupportedProtocol, InvalidCertificateRef, *Serviceable
 // pass portString as empty string for updating status in all ports
 func UpdateSvcApiGatewayStatusListenerConditions(key string, gwStatus *svcapiv1alpha1.GatewayStatus, portString string, updateStatus *UpdateSvcApiGWStatusConditionOptions) {
 	utils.AviLog.Debugf("key: %s, msg: Updating Gateway status listener condition port: %s %v", key, portString, utils.Stringify(updateStatus))
 	for port, condition := range gwStatus.Listeners {
 		notFound := true
-		if portString == "" {
+		if portString == "" || strconv.Itoa(int(condition.Port)) == portString {
 			for i, portCondition := range condition.Conditions {
 				if updateStatus.Type == "Ready" && updateStatus.Type != string(portCondition.Type) && updateStatus.Status == metav1.ConditionTrue {
 					gwStatus.Listeners[port].Conditions[i].Status = metav1.ConditionFalse
@@ -286,7 +294,7 @@ func UpdateSvcApiGatewayStatusListenerConditions(key string, gwStatus *svcapiv1a
 
 	// in case of a positive error listenerCondition Update we need to mark the
 	// gateway Condition back from Ready to Pending
-	badTypes := []string{"PortConflict", "InvalidRoutes", "UnsupportedProtocol"}
+	badTypes := []string{"PortConflict", "InvalidRoutes", "UnsupportedProtocol", "InvalidCertificateRef"}
 	if utils.HasElem(badTypes, updateStatus.Type) {
 		UpdateSvcApiGatewayStatusGWCondition(key, gwStatus, &UpdateSvcApiGWStatusConditionOptions{
 			Type:   "Pending",
@@ -416,3 +424,250 @@ func compareSvcApiGatewayStatuses(old, new *svcapiv1alpha1.GatewayStatus) bool {
 
 	return reflect.DeepEqual(oldStatus, newStatus)
 }
+
+// UpdateSvcApiL7GatewayListenerConditions updates the listener conditions of a gateway which selects HTTPRoutes,
+// for the listeners which cannot be realised on the L7 virtualservice.
+func UpdateSvcApiL7GatewayListenerConditions(key string, gw *svcapiv1alpha1.Gateway, gwStatus *svcapiv1alpha1.GatewayStatus) {
+	portProtocols := make(map[svcapiv1alpha1.PortNumber]svcapiv1alpha1.ProtocolType)
+	for _, listener := range gw.Spec.Listeners {
+		port := strconv.Itoa(int(listener.Port))
+		if !lib.IsSvcApiRouteListener(listener) || listener.Routes.Kind != lib.HTTPRoute {
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "InvalidRoutes",
+				Status: metav1.ConditionTrue,
+				Reason: fmt.Sprintf("Services and other kinds of routes cannot be selected along with %ss in the same gateway", lib.HTTPRoute),
+			})
+			continue
+		}
+		if listener.Protocol != svcapiv1alpha1.HTTPProtocolType && listener.Protocol != svcapiv1alpha1.HTTPSProtocolType {
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "UnsupportedProtocol",
+				Status: metav1.ConditionTrue,
+				Reason: fmt.Sprintf("Protocol %s is not supported for %ss", listener.Protocol, lib.HTTPRoute),
+			})
+			continue
+		}
+		if protocol, ok := portProtocols[listener.Port]; ok && protocol != listener.Protocol {
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "PortConflict",
+				Status: metav1.ConditionTrue,
+				Reason: fmt.Sprintf("Port %s is used with protocols %s and %s", port, protocol, listener.Protocol),
+			})
+			continue
+		}
+		portProtocols[listener.Port] = listener.Protocol
+		if listener.Protocol != svcapiv1alpha1.HTTPSProtocolType {
+			continue
+		}
+		if listener.TLS != nil && listener.TLS.Mode == svcapiv1alpha1.TLSModePassthrough {
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "UnsupportedProtocol",
+				Status: metav1.ConditionTrue,
+				Reason: fmt.Sprintf("TLS mode %s is not supported for %ss", svcapiv1alpha1.TLSModePassthrough, lib.HTTPRoute),
+			})
+			continue
+		}
+		if listener.TLS == nil || !lib.IsSvcApiSecretRef(listener.TLS.CertificateRef) {
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "InvalidCertificateRef",
+				Status: metav1.ConditionTrue,
+				Reason: "HTTPS listener requires a certificateRef of kind Secret",
+			})
+		}
+	}
+}
+
+// UpdateSvcApiL4GatewayListenerConditions updates the listener conditions of a L4 gateway, for the listeners of
+// TCPRoutes, UDPRoutes and TLSRoutes which cannot be realised on the virtualservice. The listeners of a gateway
+// with TLS passthrough listeners can only select TLSRoutes.
+func UpdateSvcApiL4GatewayListenerConditions(key string, gw *svcapiv1alpha1.Gateway, gwStatus *svcapiv1alpha1.GatewayStatus) {
+	isPassthrough := lib.IsSvcApiPassthroughGateway(gw)
+	for _, listener := range gw.Spec.Listeners {
+		port := strconv.Itoa(int(listener.Port))
+		if lib.IsSvcApiRouteListener(listener) && !lib.IsSvcApiL4RouteListener(listener) {
+			reason := fmt.Sprintf("Protocol %s is not supported for %ss", listener.Protocol, listener.Routes.Kind)
+			if listener.Routes.Kind == lib.TLSRoute && listener.Protocol == svcapiv1alpha1.TLSProtocolType {
+				reason = fmt.Sprintf("Only the TLS mode %s is supported for %ss", svcapiv1alpha1.TLSModePassthrough, lib.TLSRoute)
+			}
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "UnsupportedProtocol",
+				Status: metav1.ConditionTrue,
+				Reason: reason,
+			})
+			continue
+		}
+		if isPassthrough && listener.Routes.Kind != lib.TLSRoute {
+			UpdateSvcApiGatewayStatusListenerConditions(key, gwStatus, port, &UpdateSvcApiGWStatusConditionOptions{
+				Type:   "InvalidRoutes",
+				Status: metav1.ConditionTrue,
+				Reason: fmt.Sprintf("Only %ss can be selected in a gateway with TLS passthrough listeners", lib.TLSRoute),
+			})
+		}
+	}
+}
+
+// UpdateSvcApiRouteGatewayCondition sets the condition reported for the gateway in the status of a route.
+// A nil condition removes the gateway from the route status. Returns true if the route status is modified.
+func UpdateSvcApiRouteGatewayCondition(routeStatus *svcapiv1alpha1.RouteStatus, gwNamespace, gwName string, condition *metav1.Condition) bool {
+	for i, gwStatus := range routeStatus.Gateways {
+		if gwStatus.GatewayRef.Namespace != gwNamespace || gwStatus.GatewayRef.Name != gwName {
+			continue
+		}
+		if condition == nil {
+			routeStatus.Gateways = append(routeStatus.Gateways[:i], routeStatus.Gateways[i+1:]...)
+			return true
+		}
+		if existing := meta.FindStatusCondition(gwStatus.Conditions, condition.Type); existing != nil &&
+			existing.Status == condition.Status && existing.Reason == condition.Reason && existing.Message == condition.Message &&
+			existing.ObservedGeneration == condition.ObservedGeneration {
+			return false
+		}
+		meta.SetStatusCondition(&routeStatus.Gateways[i].Conditions, *condition)
+		// SetStatusCondition does not update the observedGeneration of an existing condition.
+		meta.FindStatusCondition(routeStatus.Gateways[i].Conditions, condition.Type).ObservedGeneration = condition.ObservedGeneration
+		return true
+	}
+
+	if condition == nil {
+		return false
+	}
+	gwStatus := svcapiv1alpha1.RouteGatewayStatus{
+		GatewayRef: svcapiv1alpha1.GatewayReference{
+			Namespace: gwNamespace,
+			Name:      gwName,
+		},
+	}
+	meta.SetStatusCondition(&gwStatus.Conditions, *condition)
+	routeStatus.Gateways = append(routeStatus.Gateways, gwStatus)
+	return true
+}
+
+// SvcApiRouteStatusOptions is the Admitted condition reported for a gateway in the status of a route, along with
+// the status of the route in the informer cache.
+type SvcApiRouteStatusOptions struct {
+	RouteStatus *svcapiv1alpha1.RouteStatus
+	GwNamespace string
+	GwName      string
+	Condition   *metav1.Condition
+}
+
+// UpdateSvcApiHTTPRouteStatus publishes the Admitted condition reported for the gateway in the HTTPRoute status.
+// A nil condition removes the gateway from the HTTPRoute status.
+func UpdateSvcApiHTTPRouteStatus(key string, route *svcapiv1alpha1.HTTPRoute, gwNamespace, gwName string, condition *metav1.Condition) {
+	PublishSvcApiRouteStatus(key, lib.HTTPRoute, route.Namespace, route.Name, &route.Status.RouteStatus, gwNamespace, gwName, condition)
+}
+
+// PublishSvcApiRouteStatus publishes the Admitted condition reported for the gateway in the status of the route of
+// the kind to the status queue, which updates the route only in the leader AKO. Nothing is published if the
+// condition is unchanged.
+func PublishSvcApiRouteStatus(key, kind, namespace, name string, routeStatus *svcapiv1alpha1.RouteStatus, gwNamespace, gwName string, condition *metav1.Condition) {
+	if !UpdateSvcApiRouteGatewayCondition(routeStatus.DeepCopy(), gwNamespace, gwName, condition) {
+		return
+	}
+	PublishToStatusQueue(namespace+"/"+name, StatusOptions{
+		ObjType:   kind,
+		Op:        lib.UpdateStatus,
+		Namespace: namespace,
+		ObjName:   name,
+		Key:       key,
+		Route: &SvcApiRouteStatusOptions{
+			RouteStatus: routeStatus,
+			GwNamespace: gwNamespace,
+			GwName:      gwName,
+			Condition:   condition,
+		},
+	})
+}
+
+// UpdateSvcApiRouteStatus updates the Admitted condition reported for the gateway in the status of the route of
+// the kind. routeStatus is the status of the route in the informer cache, which avoids fetching the route when
+// the condition is unchanged. A nil condition removes the gateway from the route status.
+func UpdateSvcApiRouteStatus(key, kind, namespace, name string, routeStatus *svcapiv1alpha1.RouteStatus, gwNamespace, gwName string, condition *metav1.Condition, retryNum ...int) {
+	retry := 0
+	if len(retryNum) > 0 {
+		retry = retryNum[0]
+		if retry >= 5 {
+			utils.AviLog.Errorf("key: %s, msg: UpdateSvcApiRouteStatus retried 5 times, aborting", key)
+			return
+		}
+	}
+
+	if !UpdateSvcApiRouteGatewayCondition(routeStatus.DeepCopy(), gwNamespace, gwName, condition) {
+		return
+	}
+
+	// The status of a route is updated by each of its gateways, so the latest route is fetched
+	// in order to not overwrite the conditions reported by the other gateways.
+	latestStatus, err := getSvcApiRouteStatus(kind, namespace, name)
+	if err != nil {
+		utils.AviLog.Warnf("key: %s, msg: %s not found %v", key, kind, err)
+		return
+	}
+	newStatus := latestStatus.DeepCopy()
+	if !UpdateSvcApiRouteGatewayCondition(newStatus, gwNamespace, gwName, condition) {
+		return
+	}
+	if newStatus.Gateways == nil {
+		newStatus.Gateways = []svcapiv1alpha1.RouteGatewayStatus{}
+	}
+
+	patchPayload, _ := json.Marshal(map[string]interface{}{
+		"status": newStatus,
+	})
+	if err = patchSvcApiRouteStatus(kind, namespace, name, patchPayload); err != nil {
+		utils.AviLog.Warnf("key: %s, msg: %d there was an error in updating the %s status: %+v", key, retry, kind, err)
+		UpdateSvcApiRouteStatus(key, kind, namespace, name, latestStatus, gwNamespace, gwName, condition, retry+1)
+		return
+	}
+
+	utils.AviLog.Infof("key: %s, msg: Successfully updated the %s %s/%s status %+v", key, kind, namespace, name, utils.Stringify(newStatus))
+}
+
+func getSvcApiRouteStatus(kind, namespace, name string) (*svcapiv1alpha1.RouteStatus, error) {
+	client := lib.GetServicesAPIClientset().NetworkingV1alpha1()
+	switch kind {
+	case lib.HTTPRoute:
+		route, err := client.HTTPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &route.Status.RouteStatus, nil
+	case lib.TCPRoute:
+		route, err := client.TCPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &route.Status.RouteStatus, nil
+	case lib.UDPRoute:
+		route, err := client.UDPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &route.Status.RouteStatus, nil
+	case lib.TLSRoute:
+		route, err := client.TLSRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		return &route.Status.RouteStatus, nil
+	}
+	return nil, fmt.Errorf("unsupported route kind %s", kind)
+}
+
+func patchSvcApiRouteStatus(kind, namespace, name string, patchPayload []byte) error {
+	client := lib.GetServicesAPIClientset().NetworkingV1alpha1()
+	var err error
+	switch kind {
+	case lib.HTTPRoute:
+		_, err = client.HTTPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.TCPRoute:
+		_, err = client.TCPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.UDPRoute:
+		_, err = client.UDPRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	case lib.TLSRoute:
+		_, err = client.TLSRoutes(namespace).Patch(context.TODO(), name, types.MergePatchType, patchPayload, metav1.PatchOptions{}, "status")
+	default:
+		err = fmt.Errorf("unsupported route kind %s", kind)
+	}
+	return err
+}
@@ -16,6 +16,7 @@ package api
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"time"
@@ -28,8 +29,10 @@ import (
 
 type ApiServer struct {
 	http.Server
-	Port   string
-	Models []models.ApiModel
+	Port     string
+	Models   []models.ApiModel
+	CertFile string
+	KeyFile  string
 }
 
 type ApiServerInterface interface {
@@ -77,6 +80,8 @@ func (a *ApiServer) initModels() {
 	// add common models in ApiServer
 	genericModels := []models.ApiModel{
 		models.RestStatus,
+		models.Metrics,
+		models.DryRun,
 	}
 	a.Models = append(a.Models, genericModels...)
 
@@ -105,10 +110,39 @@ func NewServer(port string, models []models.ApiModel) *ApiServer {
 	return s
 }
 
+// NewTLSServer returns an API server that serves only the given models over TLS,
+// with the certificate and key present in certFile and keyFile. The certificate is
+// reloaded once certFile is modified.
+func NewTLSServer(port, certFile, keyFile string, models []models.ApiModel) *ApiServer {
+	s := &ApiServer{
+		Server: http.Server{
+			Addr:         ":" + port,
+			ReadTimeout:  10 * time.Second,
+			WriteTimeout: 10 * time.Second,
+			TLSConfig:    &tls.Config{GetCertificate: newCertReloader(certFile, keyFile).GetCertificate},
+		},
+		CertFile: certFile,
+		KeyFile:  keyFile,
+	}
+	s.Models = models
+	for _, model := range s.Models {
+		model.InitModel()
+	}
+	s.Handler = s.SetRouter()
+
+	return s
+}
+
 func (a *ApiServer) InitApi() {
 	go func() {
+		var err error
 		utils.AviLog.Infof("Starting API server at %s", a.Server.Addr)
-		err := a.ListenAndServe()
+		if a.CertFile != "" {
+			// the certificate is served by the GetCertificate callback of the TLSConfig.
+			err = a.ListenAndServeTLS("", "")
+		} else {
+			err = a.ListenAndServe()
+		}
 		if err != nil {
 			utils.AviLog.Infof("API server shutdown: %v", err)
 		}
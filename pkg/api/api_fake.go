@@ -31,6 +31,8 @@ func (a *FakeApiServer) initModels() {
 	// add common models in ApiServer
 	genericModels := []models.ApiModel{
 		models.RestStatus,
+		models.Metrics,
+		models.DryRun,
 	}
 	a.Models = append(a.Models, genericModels...)
 
@@ -15,22 +15,36 @@
 package api
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/json"
+	"encoding/pem"
+	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestMain(m *testing.M) {
-	akoApi := &ApiServer{
-		Port:   "12345",
-		Models: []models.ApiModel{},
-	}
-
-	go akoApi.InitApi()
+	akoApi := NewServer("12345", []models.ApiModel{})
+	akoApi.InitApi()
+	time.Sleep(100 * time.Millisecond)
 
 	os.Exit(m.Run())
 }
@@ -56,3 +70,176 @@ func TestApiServerStatusModel(t *testing.T) {
 		t.Fail()
 	}
 }
+
+// TestApiServerMetricsModel tests that the MetricsModel serves the AKO collectors
+func TestApiServerMetricsModel(t *testing.T) {
+	utils.IncRetryCount("FastRetryLayer")
+
+	resp, err := http.Get("http://localhost:12345/metrics")
+	if err != nil {
+		t.Fatalf("error in fetching metrics: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error in reading metrics response: %v", err)
+	}
+
+	if !strings.Contains(string(body), `ako_retry_total{layer="FastRetryLayer"} 1`) {
+		t.Fatalf("retry count not found in metrics response: %s", string(body))
+	}
+}
+
+// TestApiServerDryRunModel tests that the DryRunModel serves the recorded rest operations per VS key
+func TestApiServerDryRunModel(t *testing.T) {
+	models.DryRun.RecordRestOps("admin/cluster--Shared-L7-0", []models.PlannedRestOp{
+		{Method: "POST", Path: "/api/pool/", Model: "Pool", Name: "cluster--foo.com_foo-default-foo", Tenant: "admin"},
+	})
+
+	resp, err := http.Get("http://localhost:12345/api/dryrun/admin/cluster--Shared-L7-0")
+	if err != nil {
+		t.Fatalf("error in fetching dry run plan: %v", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("error in reading dry run response: %v", err)
+	}
+
+	var plan []models.PlannedRestOp
+	if err = json.Unmarshal(body, &plan); err != nil {
+		t.Fatalf("error in unmarshalling dry run response: %v", err)
+	}
+	if len(plan) != 1 || plan[0].Method != "POST" || plan[0].Model != "Pool" {
+		t.Fatalf("unexpected dry run plan: %s", string(body))
+	}
+
+	resp, err = http.Get("http://localhost:12345/api/dryrun/admin/unknown")
+	if err != nil {
+		t.Fatalf("error in fetching dry run plan: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 for unknown VS key, got %d", resp.StatusCode)
+	}
+}
+
+// TestApiServerValidatingWebhookModel tests that the ValidatingWebhookModel denies the requests rejected by the validator
+// registered for the kind, and allows the rest.
+func TestApiServerValidatingWebhookModel(t *testing.T) {
+	webhook := &models.ValidatingWebhookModel{
+		Validators: map[string]models.AdmissionValidator{
+			"HostRule": func(req *admissionv1beta1.AdmissionRequest) error {
+				if req.Name == "invalid" {
+					return fmt.Errorf("duplicate fqdn foo.com found in default/valid")
+				}
+				return nil
+			},
+		},
+	}
+	webhookApi := NewServer("12346", []models.ApiModel{webhook})
+	webhookApi.InitApi()
+	time.Sleep(100 * time.Millisecond)
+
+	review := func(kind, name string) *admissionv1beta1.AdmissionResponse {
+		request := admissionv1beta1.AdmissionReview{
+			Request: &admissionv1beta1.AdmissionRequest{
+				UID:       types.UID(name),
+				Kind:      metav1.GroupVersionKind{Group: "ako.vmware.com", Version: "v1alpha1", Kind: kind},
+				Name:      name,
+				Namespace: "default",
+				Operation: admissionv1beta1.Create,
+			},
+		}
+		payload, _ := json.Marshal(request)
+		resp, err := http.Post("http://localhost:12346"+models.ValidatingWebhookRoute, "application/json", bytes.NewReader(payload))
+		if err != nil {
+			t.Fatalf("error in posting admission review: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("error in reading admission review response: %v", err)
+		}
+		var response admissionv1beta1.AdmissionReview
+		if err = json.Unmarshal(body, &response); err != nil || response.Response == nil {
+			t.Fatalf("error in unmarshalling admission review response: %v, %s", err, string(body))
+		}
+		if response.Response.UID != types.UID(name) {
+			t.Fatalf("expected UID %s in admission response, got %s", name, response.Response.UID)
+		}
+		return response.Response
+	}
+
+	if response := review("HostRule", "valid"); !response.Allowed {
+		t.Fatalf("expected valid HostRule to be allowed")
+	}
+	response := review("HostRule", "invalid")
+	if response.Allowed || response.Result == nil || !strings.Contains(response.Result.Message, "duplicate fqdn") {
+		t.Fatalf("expected invalid HostRule to be denied, got %+v", response)
+	}
+	if response := review("HTTPRule", "invalid"); !response.Allowed {
+		t.Fatalf("expected HTTPRule without a validator to be allowed")
+	}
+}
+
+// writeTestCertificate writes a self signed certificate with the serial number and its key to certFile and keyFile
+func writeTestCertificate(t *testing.T, certFile, keyFile string, serial int64, modTime time.Time) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("error in generating key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "ako-webhook"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("error in creating certificate: %v", err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err = ioutil.WriteFile(certFile, certPEM, 0600); err != nil {
+		t.Fatalf("error in writing certificate: %v", err)
+	}
+	if err = ioutil.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatalf("error in writing key: %v", err)
+	}
+	os.Chtimes(certFile, modTime, modTime)
+}
+
+// TestTLSServerReloadsCertificate tests that the TLS server serves the rotated certificate without a restart
+func TestTLSServerReloadsCertificate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ako-webhook")
+	if err != nil {
+		t.Fatalf("error in creating temp dir: %v", err)
+	}
+	defer os.RemoveAll(dir)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeTestCertificate(t, certFile, keyFile, 1, time.Now().Add(-time.Minute))
+
+	tlsApi := NewTLSServer("12347", certFile, keyFile, []models.ApiModel{models.RestStatus})
+	tlsApi.InitApi()
+	defer tlsApi.ShutDown()
+	time.Sleep(100 * time.Millisecond)
+
+	servedSerial := func() int64 {
+		conn, err := tls.Dial("tcp", "localhost:12347", &tls.Config{InsecureSkipVerify: true})
+		if err != nil {
+			t.Fatalf("error in connecting to the TLS server: %v", err)
+		}
+		defer conn.Close()
+		return conn.ConnectionState().PeerCertificates[0].SerialNumber.Int64()
+	}
+	if serial := servedSerial(); serial != 1 {
+		t.Fatalf("expected certificate with serial 1, got %d", serial)
+	}
+
+	writeTestCertificate(t, certFile, keyFile, 2, time.Now())
+	if serial := servedSerial(); serial != 2 {
+		t.Fatalf("expected the rotated certificate with serial 2, got %d", serial)
+	}
+}
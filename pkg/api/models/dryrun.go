@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// maximum number of planned operations retained per VS key
+const maxPlannedOpsPerKey = 100
+
+// PlannedRestOp is a rest operation that AKO would have sent to the Avi controller.
+type PlannedRestOp struct {
+	Method    string      `json:"method"`
+	Path      string      `json:"path"`
+	Model     string      `json:"model"`
+	Name      string      `json:"name,omitempty"`
+	Tenant    string      `json:"tenant"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
+
+var DryRun *DryRunModel
+var dryrunonce sync.Once
+
+// DryRunModel implements ApiModel, and holds the rest operations recorded in the dry run mode, per VS key.
+type DryRunModel struct {
+	plans    map[string][]PlannedRestOp
+	planLock sync.RWMutex
+}
+
+func (a *DryRunModel) InitModel() {
+	dryrunonce.Do(func() {
+		DryRun = &DryRunModel{
+			plans: make(map[string][]PlannedRestOp),
+		}
+	})
+}
+
+func (a *DryRunModel) ApiOperationMap() []OperationMap {
+	var operationMapList []OperationMap
+
+	getAll := OperationMap{
+		Route:  "/api/dryrun",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			utils.Respond(w, DryRun.GetPlans())
+		},
+	}
+
+	getOne := OperationMap{
+		Route:  "/api/dryrun/{namespace}/{name}",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			vars := mux.Vars(r)
+			plan, ok := DryRun.GetPlan(vars["namespace"] + "/" + vars["name"])
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			utils.Respond(w, plan)
+		},
+	}
+
+	operationMapList = append(operationMapList, getAll, getOne)
+	return operationMapList
+}
+
+// RecordRestOps appends the operations to the plan of the VS key, keeping only the latest maxPlannedOpsPerKey entries.
+func (a *DryRunModel) RecordRestOps(vsKey string, ops []PlannedRestOp) {
+	if a == nil {
+		return
+	}
+	a.planLock.Lock()
+	defer a.planLock.Unlock()
+	plan := append(a.plans[vsKey], ops...)
+	if len(plan) > maxPlannedOpsPerKey {
+		plan = plan[len(plan)-maxPlannedOpsPerKey:]
+	}
+	a.plans[vsKey] = plan
+}
+
+func (a *DryRunModel) GetPlan(vsKey string) ([]PlannedRestOp, bool) {
+	a.planLock.RLock()
+	defer a.planLock.RUnlock()
+	plan, ok := a.plans[vsKey]
+	return plan, ok
+}
+
+func (a *DryRunModel) GetPlans() map[string][]PlannedRestOp {
+	a.planLock.RLock()
+	defer a.planLock.RUnlock()
+	plans := make(map[string][]PlannedRestOp, len(a.plans))
+	for vsKey, plan := range a.plans {
+		plans[vsKey] = plan
+	}
+	return plans
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var Metrics *MetricsModel
+var metricsonce sync.Once
+
+// MetricsModel implements ApiModel, and exposes the collectors in utils.MetricsRegistry
+// in the prometheus exposition format.
+type MetricsModel struct {
+	handler http.Handler
+}
+
+func (a *MetricsModel) InitModel() {
+	metricsonce.Do(func() {
+		Metrics = &MetricsModel{
+			handler: promhttp.HandlerFor(utils.MetricsRegistry, promhttp.HandlerOpts{}),
+		}
+	})
+}
+
+func (a *MetricsModel) ApiOperationMap() []OperationMap {
+	var operationMapList []OperationMap
+
+	get := OperationMap{
+		Route:  "/metrics",
+		Method: "GET",
+		Handler: func(w http.ResponseWriter, r *http.Request) {
+			Metrics.handler.ServeHTTP(w, r)
+		},
+	}
+
+	operationMapList = append(operationMapList, get)
+	return operationMapList
+}
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ValidatingWebhookRoute is the route on which the admission reviews are served.
+const ValidatingWebhookRoute = "/validate"
+
+// AdmissionValidator validates the object in an admission request, a non nil error denies the request.
+type AdmissionValidator func(req *admissionv1beta1.AdmissionRequest) error
+
+// ValidatingWebhookModel implements ApiModel, and serves the admission reviews sent by the kubernetes
+// API server to a ValidatingAdmissionWebhook. The requests are validated by the validator registered
+// for the kind of the object, requests for the kinds without a validator are allowed.
+type ValidatingWebhookModel struct {
+	Validators map[string]AdmissionValidator
+}
+
+func (a *ValidatingWebhookModel) InitModel() {}
+
+func (a *ValidatingWebhookModel) ApiOperationMap() []OperationMap {
+	var operationMapList []OperationMap
+
+	validate := OperationMap{
+		Route:   ValidatingWebhookRoute,
+		Method:  "POST",
+		Handler: a.serveAdmissionReview,
+	}
+
+	operationMapList = append(operationMapList, validate)
+	return operationMapList
+}
+
+func (a *ValidatingWebhookModel) serveAdmissionReview(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error in reading the request body: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The AdmissionReview is the same in admission.k8s.io/v1 and v1beta1, the apiVersion of the request
+	// is retained in the response.
+	review := &admissionv1beta1.AdmissionReview{}
+	if err := json.Unmarshal(body, review); err != nil || review.Request == nil {
+		http.Error(w, fmt.Sprintf("invalid admission review: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	review.Response = a.Review(review.Request)
+	review.Request = nil
+	utils.Respond(w, review)
+}
+
+// Review runs the validator registered for the kind of the object in the admission request.
+func (a *ValidatingWebhookModel) Review(req *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	response := &admissionv1beta1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+
+	validator, ok := a.Validators[req.Kind.Kind]
+	if !ok {
+		return response
+	}
+
+	if err := validator(req); err != nil {
+		utils.AviLog.Warnf("Denied %s of %s %s/%s: %v", req.Operation, req.Kind.Kind, req.Namespace, req.Name, err)
+		response.Allowed = false
+		response.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: err.Error(),
+			Code:    http.StatusUnprocessableEntity,
+		}
+	}
+	return response
+}
@@ -44,17 +44,45 @@ type HostRuleVirtualHost struct {
 	EnableVirtualHost  *bool              `json:"enableVirtualHost,omitempty"`
 	ErrorPageProfile   string             `json:"errorPageProfile,omitempty"`
 	Fqdn               string             `json:"fqdn,omitempty"`
+	FqdnType           FqdnType           `json:"fqdnType,omitempty"`
 	HTTPPolicy         HostRuleHTTPPolicy `json:"httpPolicy,omitempty"`
 	Gslb               HostRuleGSLB       `json:"gslb,omitempty"`
+	SourceRanges       []string           `json:"sourceRanges,omitempty"`
 	TLS                HostRuleTLS        `json:"tls,omitempty"`
 	WAFPolicy          string             `json:"wafPolicy,omitempty"`
 }
 
+// FqdnType is the way the fqdn of a HostRule is matched with the hosts
+type FqdnType string
+
+const (
+	// FqdnTypeExact matches the hosts equal to the fqdn
+	FqdnTypeExact FqdnType = "Exact"
+	// FqdnTypeWildcard matches the hosts with a single label in place of
+	// the leading * of the fqdn, e.g. *.apps.example.com
+	FqdnTypeWildcard FqdnType = "Wildcard"
+	// FqdnTypeRegex matches the hosts matching the fqdn as a regular expression
+	FqdnTypeRegex FqdnType = "Regex"
+)
+
 // HostRuleTLS holds secure host specific properties
 type HostRuleTLS struct {
 	SSLKeyCertificate HostRuleSecret `json:"sslKeyCertificate,omitempty"`
 	SSLProfile        string         `json:"sslProfile,omitempty"`
 	Termination       string         `json:"termination,omitempty"`
+	// ClientCertificate enables the validation of the client certificates
+	ClientCertificate HostRuleClientCertificate `json:"clientCertificate,omitempty"`
+}
+
+// HostRuleClientCertificate holds the settings for validating the client certificates
+// with the CA certificates in a Kubernetes Secret
+type HostRuleClientCertificate struct {
+	// CASecret is the Secret in the namespace of the HostRule, with the CA certificates in the ca.crt key
+	CASecret string `json:"caSecret,omitempty"`
+	// Mode is Require or Request, defaults to Require
+	Mode string `json:"mode,omitempty"`
+	// ForwardDNHeader is the request header carrying the subject DN of the client certificate to the backends
+	ForwardDNHeader string `json:"forwardDNHeader,omitempty"`
 }
 
 // HostRuleSecret is required to provide distinction between Avi SSLKeyCertificate
@@ -79,6 +107,8 @@ type HostRuleGSLB struct {
 type HostRuleStatus struct {
 	Status string `json:"status,omitempty"`
 	Error  string `json:"error,omitempty"`
+	// Fqdns are the hosts the HostRule is currently applied to
+	Fqdns []string `json:"fqdns,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
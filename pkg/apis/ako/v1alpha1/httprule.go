@@ -44,6 +44,41 @@ type HTTPRulePaths struct {
 	TLS                    HTTPRuleTLS      `json:"tls,omitempty"`
 	HealthMonitors         []string         `json:"healthMonitors,omitempty"`
 	ApplicationPersistence string           `json:"applicationPersistence,omitempty"`
+	RequestHeaders         HTTPRuleHeaders  `json:"requestHeaders,omitempty"`
+	ResponseHeaders        HTTPRuleHeaders  `json:"responseHeaders,omitempty"`
+	Rewrite                HTTPRuleRewrite  `json:"rewrite,omitempty"`
+	// +optional
+	Redirect *HTTPRuleRedirect `json:"redirect,omitempty"`
+	// +optional
+	RateLimit                         *HTTPRuleRateLimit `json:"rateLimit,omitempty"`
+	MaxConcurrentConnectionsPerServer int32              `json:"maxConcurrentConnectionsPerServer,omitempty"`
+}
+
+// HTTPRuleHeaders holds the headers to add, replace or remove for a path
+type HTTPRuleHeaders struct {
+	Add     []HTTPRuleHeader `json:"add,omitempty"`
+	Replace []HTTPRuleHeader `json:"replace,omitempty"`
+	Remove  []string         `json:"remove,omitempty"`
+}
+
+// HTTPRuleHeader is a header name and value
+type HTTPRuleHeader struct {
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// HTTPRuleRewrite replaces the target prefix of the request path with Prefix
+type HTTPRuleRewrite struct {
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// HTTPRuleRedirect redirects the requests of a path, unset fields are retained from the request
+type HTTPRuleRedirect struct {
+	Protocol   string `json:"protocol,omitempty"`
+	Host       string `json:"host,omitempty"`
+	Port       int32  `json:"port,omitempty"`
+	Path       string `json:"path,omitempty"`
+	StatusCode int    `json:"statusCode,omitempty"`
 }
 
 // HTTPRuleLBPolicy holds a path/pool's load balancer policies
@@ -53,6 +88,18 @@ type HTTPRuleLBPolicy struct {
 	HostHeader string `json:"hostHeader,omitempty"`
 }
 
+// HTTPRuleRateLimit limits the rate of the requests of a path to Count requests every Period seconds,
+// either for each client IP or for all the clients
+type HTTPRuleRateLimit struct {
+	Count       int32  `json:"count,omitempty"`
+	Period      int32  `json:"period,omitempty"`
+	Burst       int32  `json:"burst,omitempty"`
+	PerClientIP bool   `json:"perClientIP,omitempty"`
+	Action      string `json:"action,omitempty"`
+	// +optional
+	Redirect *HTTPRuleRedirect `json:"redirect,omitempty"`
+}
+
 // HTTPRuleTLS holds secure path/pool specific properties
 type HTTPRuleTLS struct {
 	Type          string `json:"type,omitempty"`
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package v1alpha1
+
+import metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// L4Rule is a top-level type
+type L4Rule struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +optional
+	Status L4RuleStatus `json:"status,omitempty"`
+
+	Spec L4RuleSpec `json:"spec,omitempty"`
+}
+
+// L4RuleSpec consists of the main L4Rule settings, applied on the
+// virtualservice and pools of the Services referring to the L4Rule
+type L4RuleSpec struct {
+	ApplicationProfile string         `json:"applicationProfile,omitempty"`
+	NetworkProfile     string         `json:"networkProfile,omitempty"`
+	AnalyticsProfile   string         `json:"analyticsProfile,omitempty"`
+	LoadBalancerPolicy L4RuleLBPolicy `json:"loadBalancerPolicy,omitempty"`
+	HealthMonitors     []string       `json:"healthMonitors,omitempty"`
+}
+
+// L4RuleLBPolicy holds the load balancer policies of the Service pools
+type L4RuleLBPolicy struct {
+	Algorithm string `json:"algorithm,omitempty"`
+	Hash      string `json:"hash,omitempty"`
+}
+
+// L4RuleStatus holds the status of the L4Rule
+type L4RuleStatus struct {
+	Status string `json:"status,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// L4RuleList has the list of L4Rule objects
+type L4RuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	// +optional
+	metav1.ListMeta `json:"metadata,omitempty"`
+
+	Items []L4Rule `json:"items"`
+}
@@ -54,6 +54,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&HTTPRuleList{},
 		&AviInfraSetting{},
 		&AviInfraSettingList{},
+		&L4Rule{},
+		&L4RuleList{},
 	)
 
 	scheme.AddKnownTypes(
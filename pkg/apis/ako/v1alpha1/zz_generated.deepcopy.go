@@ -227,6 +227,53 @@ func (in *HTTPRule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleHeader) DeepCopyInto(out *HTTPRuleHeader) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleHeader.
+func (in *HTTPRuleHeader) DeepCopy() *HTTPRuleHeader {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleHeader)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleHeaders) DeepCopyInto(out *HTTPRuleHeaders) {
+	*out = *in
+	if in.Add != nil {
+		in, out := &in.Add, &out.Add
+		*out = make([]HTTPRuleHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Replace != nil {
+		in, out := &in.Replace, &out.Replace
+		*out = make([]HTTPRuleHeader, len(*in))
+		copy(*out, *in)
+	}
+	if in.Remove != nil {
+		in, out := &in.Remove, &out.Remove
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleHeaders.
+func (in *HTTPRuleHeaders) DeepCopy() *HTTPRuleHeaders {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleHeaders)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleLBPolicy) DeepCopyInto(out *HTTPRuleLBPolicy) {
 	*out = *in
@@ -286,6 +333,19 @@ func (in *HTTPRulePaths) DeepCopyInto(out *HTTPRulePaths) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	in.RequestHeaders.DeepCopyInto(&out.RequestHeaders)
+	in.ResponseHeaders.DeepCopyInto(&out.ResponseHeaders)
+	out.Rewrite = in.Rewrite
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(HTTPRuleRedirect)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(HTTPRuleRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -299,6 +359,59 @@ func (in *HTTPRulePaths) DeepCopy() *HTTPRulePaths {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleRateLimit) DeepCopyInto(out *HTTPRuleRateLimit) {
+	*out = *in
+	if in.Redirect != nil {
+		in, out := &in.Redirect, &out.Redirect
+		*out = new(HTTPRuleRedirect)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleRateLimit.
+func (in *HTTPRuleRateLimit) DeepCopy() *HTTPRuleRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleRedirect) DeepCopyInto(out *HTTPRuleRedirect) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleRedirect.
+func (in *HTTPRuleRedirect) DeepCopy() *HTTPRuleRedirect {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleRedirect)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPRuleRewrite) DeepCopyInto(out *HTTPRuleRewrite) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPRuleRewrite.
+func (in *HTTPRuleRewrite) DeepCopy() *HTTPRuleRewrite {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPRuleRewrite)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPRuleSpec) DeepCopyInto(out *HTTPRuleSpec) {
 	*out = *in
@@ -359,7 +472,7 @@ func (in *HostRule) DeepCopyInto(out *HostRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	in.Spec.DeepCopyInto(&out.Spec)
 	return
 }
@@ -382,6 +495,22 @@ func (in *HostRule) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HostRuleClientCertificate) DeepCopyInto(out *HostRuleClientCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HostRuleClientCertificate.
+func (in *HostRuleClientCertificate) DeepCopy() *HostRuleClientCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(HostRuleClientCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleGSLB) DeepCopyInto(out *HostRuleGSLB) {
 	*out = *in
@@ -488,6 +617,11 @@ func (in *HostRuleSpec) DeepCopy() *HostRuleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleStatus) DeepCopyInto(out *HostRuleStatus) {
 	*out = *in
+	if in.Fqdns != nil {
+		in, out := &in.Fqdns, &out.Fqdns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -505,6 +639,7 @@ func (in *HostRuleStatus) DeepCopy() *HostRuleStatus {
 func (in *HostRuleTLS) DeepCopyInto(out *HostRuleTLS) {
 	*out = *in
 	out.SSLKeyCertificate = in.SSLKeyCertificate
+	out.ClientCertificate = in.ClientCertificate
 	return
 }
 
@@ -518,6 +653,121 @@ func (in *HostRuleTLS) DeepCopy() *HostRuleTLS {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4Rule) DeepCopyInto(out *L4Rule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Status = in.Status
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4Rule.
+func (in *L4Rule) DeepCopy() *L4Rule {
+	if in == nil {
+		return nil
+	}
+	out := new(L4Rule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *L4Rule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleLBPolicy) DeepCopyInto(out *L4RuleLBPolicy) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleLBPolicy.
+func (in *L4RuleLBPolicy) DeepCopy() *L4RuleLBPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleLBPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleList) DeepCopyInto(out *L4RuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]L4Rule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleList.
+func (in *L4RuleList) DeepCopy() *L4RuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *L4RuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleSpec) DeepCopyInto(out *L4RuleSpec) {
+	*out = *in
+	out.LoadBalancerPolicy = in.LoadBalancerPolicy
+	if in.HealthMonitors != nil {
+		in, out := &in.HealthMonitors, &out.HealthMonitors
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleSpec.
+func (in *L4RuleSpec) DeepCopy() *L4RuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *L4RuleStatus) DeepCopyInto(out *L4RuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new L4RuleStatus.
+func (in *L4RuleStatus) DeepCopy() *L4RuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(L4RuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 	*out = *in
@@ -533,6 +783,11 @@ func (in *HostRuleVirtualHost) DeepCopyInto(out *HostRuleVirtualHost) {
 	}
 	in.HTTPPolicy.DeepCopyInto(&out.HTTPPolicy)
 	out.Gslb = in.Gslb
+	if in.SourceRanges != nil {
+		in, out := &in.SourceRanges, &out.SourceRanges
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	out.TLS = in.TLS
 	return
 }
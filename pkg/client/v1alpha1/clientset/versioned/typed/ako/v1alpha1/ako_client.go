@@ -29,6 +29,7 @@ type AkoV1alpha1Interface interface {
 	AviInfraSettingsGetter
 	HTTPRulesGetter
 	HostRulesGetter
+	L4RulesGetter
 }
 
 // AkoV1alpha1Client is used to interact with features provided by the ako.vmware.com group.
@@ -48,6 +49,10 @@ func (c *AkoV1alpha1Client) HostRules(namespace string) HostRuleInterface {
 	return newHostRules(c, namespace)
 }
 
+func (c *AkoV1alpha1Client) L4Rules(namespace string) L4RuleInterface {
+	return newL4Rules(c, namespace)
+}
+
 // NewForConfig creates a new AkoV1alpha1Client for the given config.
 func NewForConfig(c *rest.Config) (*AkoV1alpha1Client, error) {
 	config := *c
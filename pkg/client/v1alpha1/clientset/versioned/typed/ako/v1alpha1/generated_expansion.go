@@ -23,3 +23,5 @@ type AviInfraSettingExpansion interface{}
 type HTTPRuleExpansion interface{}
 
 type HostRuleExpansion interface{}
+
+type L4RuleExpansion interface{}
@@ -30,6 +30,8 @@ type Interface interface {
 	HTTPRules() HTTPRuleInformer
 	// HostRules returns a HostRuleInformer.
 	HostRules() HostRuleInformer
+	// L4Rules returns a L4RuleInformer.
+	L4Rules() L4RuleInformer
 }
 
 type version struct {
@@ -57,3 +59,8 @@ func (v *version) HTTPRules() HTTPRuleInformer {
 func (v *version) HostRules() HostRuleInformer {
 	return &hostRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// L4Rules returns a L4RuleInformer.
+func (v *version) L4Rules() L4RuleInformer {
+	return &l4RuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
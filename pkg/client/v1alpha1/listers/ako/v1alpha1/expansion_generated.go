@@ -37,3 +37,11 @@ type HostRuleListerExpansion interface{}
 // HostRuleNamespaceListerExpansion allows custom methods to be added to
 // HostRuleNamespaceLister.
 type HostRuleNamespaceListerExpansion interface{}
+
+// L4RuleListerExpansion allows custom methods to be added to
+// L4RuleLister.
+type L4RuleListerExpansion interface{}
+
+// L4RuleNamespaceListerExpansion allows custom methods to be added to
+// L4RuleNamespaceLister.
+type L4RuleNamespaceListerExpansion interface{}
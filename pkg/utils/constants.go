@@ -17,6 +17,7 @@ package utils
 const (
 	GraphLayer                    = "GraphLayer"
 	ObjectIngestionLayer          = "ObjectIngestionLayer"
+	RestLayer                     = "RestLayer"
 	StatusQueue                   = "StatusQueue"
 	LeastConnection               = "LB_ALGORITHM_LEAST_CONNECTIONS"
 	RandomConnection              = "RANDOM_CONN"
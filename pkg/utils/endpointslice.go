@@ -0,0 +1,92 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	EndpointSliceAPIVersion = "discovery.k8s.io/v1beta1"
+)
+
+var endpointSliceEnabled *bool
+
+// SetEndpointSliceEnabled discovers whether the cluster serves the discovery.k8s.io/v1beta1 EndpointSlice API
+// (k8s 1.17+). The endpoints of the Services are tracked using the EndpointSlices when available, and using
+// the core Endpoints otherwise.
+func SetEndpointSliceEnabled(kc kubernetes.Interface) {
+	if endpointSliceEnabled != nil {
+		return
+	}
+
+	var isPresent bool
+	resources, err := kc.Discovery().ServerResourcesForGroupVersion(EndpointSliceAPIVersion)
+	if err != nil {
+		AviLog.Infof("%s not found/enabled on cluster, using v1/Endpoints: %v", EndpointSliceAPIVersion, err)
+	} else {
+		for _, resource := range resources.APIResources {
+			if resource.Name == "endpointslices" {
+				isPresent = true
+				break
+			}
+		}
+	}
+	if isPresent {
+		AviLog.Infof("Using %s/EndpointSlice", EndpointSliceAPIVersion)
+	}
+	endpointSliceEnabled = &isPresent
+}
+
+func GetEndpointSliceEnabled() bool {
+	return endpointSliceEnabled != nil && *endpointSliceEnabled
+}
+
+// IsEndpointReady returns true if the endpoint is ready to receive new connections. An unknown ready condition
+// is interpreted as ready, and an endpoint which is terminating is never ready.
+func IsEndpointReady(conditions discoveryv1beta1.EndpointConditions) bool {
+	if conditions.Terminating != nil && *conditions.Terminating {
+		return false
+	}
+	return conditions.Ready == nil || *conditions.Ready
+}
+
+// IsEndpointServingTerminating returns true if the endpoint is terminating, but still serves its existing
+// connections. An unknown serving condition defers to the ready condition.
+func IsEndpointServingTerminating(conditions discoveryv1beta1.EndpointConditions) bool {
+	if conditions.Terminating == nil || !*conditions.Terminating {
+		return false
+	}
+	if conditions.Serving != nil {
+		return *conditions.Serving
+	}
+	return conditions.Ready != nil && *conditions.Ready
+}
+
+// GetEndpointNodeName returns the node of the endpoint, from its nodeName and else from its
+// kubernetes.io/hostname topology.
+func GetEndpointNodeName(endpoint discoveryv1beta1.Endpoint) string {
+	if endpoint.NodeName != nil {
+		return *endpoint.NodeName
+	}
+	return endpoint.Topology[corev1.LabelHostname]
+}
+
+// GetEndpointZone returns the zone of the endpoint, from its topology.kubernetes.io/zone topology.
+func GetEndpointZone(endpoint discoveryv1beta1.Endpoint) string {
+	return endpoint.Topology[corev1.LabelZoneFailureDomainStable]
+}
@@ -17,11 +17,20 @@ package utils
 import (
 	"context"
 
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 )
 
+const (
+	IngressAPIVersionV1      = "networking.k8s.io/v1"
+	IngressAPIVersionV1beta1 = "networking.k8s.io/v1beta1"
+)
+
 var ingressClassEnabled *bool
+var ingressAPIVersion string
 
 func SetIngressClassEnabled(kc kubernetes.Interface) {
 	if ingressClassEnabled != nil {
@@ -46,3 +55,180 @@ func SetIngressClassEnabled(kc kubernetes.Interface) {
 func GetIngressClassEnabled() bool {
 	return *ingressClassEnabled
 }
+
+// SetIngressAPIVersion discovers whether the cluster serves the networking.k8s.io/v1 Ingress API (k8s 1.19+),
+// and falls back to networking.k8s.io/v1beta1 otherwise. The Ingress and IngressClass objects are always
+// handed out as networking.k8s.io/v1 objects, the v1beta1 objects are converted by the informers and the client helpers.
+func SetIngressAPIVersion(kc kubernetes.Interface) {
+	if ingressAPIVersion != "" {
+		return
+	}
+
+	ingressAPIVersion = IngressAPIVersionV1beta1
+	resources, err := kc.Discovery().ServerResourcesForGroupVersion(IngressAPIVersionV1)
+	if err != nil {
+		AviLog.Infof("%s not found/enabled on cluster, using %s/Ingress: %v", IngressAPIVersionV1, IngressAPIVersionV1beta1, err)
+		return
+	}
+	for _, resource := range resources.APIResources {
+		if resource.Name == "ingresses" {
+			ingressAPIVersion = IngressAPIVersionV1
+			break
+		}
+	}
+	AviLog.Infof("Using %s/Ingress", ingressAPIVersion)
+}
+
+func GetIngressAPIVersion() string {
+	return ingressAPIVersion
+}
+
+func isIngressV1Enabled() bool {
+	return ingressAPIVersion == IngressAPIVersionV1
+}
+
+// GetIngress fetches the Ingress from the api server using the discovered Ingress API version.
+func GetIngress(kc kubernetes.Interface, namespace, name string) (*networkingv1.Ingress, error) {
+	if isIngressV1Enabled() {
+		return kc.NetworkingV1().Ingresses(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	ing, err := kc.NetworkingV1beta1().Ingresses(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return IngressV1beta1ToV1(ing), nil
+}
+
+// ListIngresses lists the Ingresses from the api server using the discovered Ingress API version.
+func ListIngresses(kc kubernetes.Interface, namespace string, opts metav1.ListOptions) (*networkingv1.IngressList, error) {
+	if isIngressV1Enabled() {
+		return kc.NetworkingV1().Ingresses(namespace).List(context.TODO(), opts)
+	}
+	ingList, err := kc.NetworkingV1beta1().Ingresses(namespace).List(context.TODO(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return ingressListV1beta1ToV1(ingList), nil
+}
+
+// PatchIngress patches the Ingress using the discovered Ingress API version. The metadata and status of the
+// Ingress have the same schema in both the API versions, so the patch payload is version independent.
+func PatchIngress(kc kubernetes.Interface, namespace, name string, pt types.PatchType, data []byte, subresources ...string) (*networkingv1.Ingress, error) {
+	if isIngressV1Enabled() {
+		return kc.NetworkingV1().Ingresses(namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}, subresources...)
+	}
+	ing, err := kc.NetworkingV1beta1().Ingresses(namespace).Patch(context.TODO(), name, pt, data, metav1.PatchOptions{}, subresources...)
+	if err != nil {
+		return nil, err
+	}
+	return IngressV1beta1ToV1(ing), nil
+}
+
+// GetIngressClass fetches the IngressClass from the api server using the discovered Ingress API version.
+func GetIngressClass(kc kubernetes.Interface, name string) (*networkingv1.IngressClass, error) {
+	if isIngressV1Enabled() {
+		return kc.NetworkingV1().IngressClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	}
+	ingClass, err := kc.NetworkingV1beta1().IngressClasses().Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return IngressClassV1beta1ToV1(ingClass), nil
+}
+
+// ListIngressClasses lists the IngressClasses from the api server using the discovered Ingress API version.
+func ListIngressClasses(kc kubernetes.Interface, opts metav1.ListOptions) (*networkingv1.IngressClassList, error) {
+	if isIngressV1Enabled() {
+		return kc.NetworkingV1().IngressClasses().List(context.TODO(), opts)
+	}
+	ingClassList, err := kc.NetworkingV1beta1().IngressClasses().List(context.TODO(), opts)
+	if err != nil {
+		return nil, err
+	}
+	return ingressClassListV1beta1ToV1(ingClassList), nil
+}
+
+// IngressV1beta1ToV1 converts a networking.k8s.io/v1beta1 Ingress to the networking.k8s.io/v1 Ingress.
+func IngressV1beta1ToV1(in *networkingv1beta1.Ingress) *networkingv1.Ingress {
+	out := &networkingv1.Ingress{
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: in.Spec.IngressClassName,
+			DefaultBackend:   ingressBackendV1beta1ToV1(in.Spec.Backend),
+		},
+		Status: networkingv1.IngressStatus{
+			LoadBalancer: *in.Status.LoadBalancer.DeepCopy(),
+		},
+	}
+	for _, tls := range in.Spec.TLS {
+		out.Spec.TLS = append(out.Spec.TLS, networkingv1.IngressTLS{
+			Hosts:      tls.Hosts,
+			SecretName: tls.SecretName,
+		})
+	}
+	for _, rule := range in.Spec.Rules {
+		outRule := networkingv1.IngressRule{Host: rule.Host}
+		if rule.HTTP != nil {
+			outRule.HTTP = &networkingv1.HTTPIngressRuleValue{}
+			for _, path := range rule.HTTP.Paths {
+				// The pathType is required in networking.k8s.io/v1, and defaults to ImplementationSpecific
+				// in networking.k8s.io/v1beta1.
+				pathType := networkingv1.PathTypeImplementationSpecific
+				if path.PathType != nil {
+					pathType = networkingv1.PathType(*path.PathType)
+				}
+				outPath := networkingv1.HTTPIngressPath{Path: path.Path, PathType: &pathType}
+				if backend := ingressBackendV1beta1ToV1(&path.Backend); backend != nil {
+					outPath.Backend = *backend
+				}
+				outRule.HTTP.Paths = append(outRule.HTTP.Paths, outPath)
+			}
+		}
+		out.Spec.Rules = append(out.Spec.Rules, outRule)
+	}
+	return out
+}
+
+func ingressBackendV1beta1ToV1(in *networkingv1beta1.IngressBackend) *networkingv1.IngressBackend {
+	if in == nil {
+		return nil
+	}
+	out := &networkingv1.IngressBackend{Resource: in.Resource}
+	if in.ServiceName != "" {
+		out.Service = &networkingv1.IngressServiceBackend{
+			Name: in.ServiceName,
+			Port: networkingv1.ServiceBackendPort{
+				Number: in.ServicePort.IntVal,
+				Name:   in.ServicePort.StrVal,
+			},
+		}
+	}
+	return out
+}
+
+func ingressListV1beta1ToV1(in *networkingv1beta1.IngressList) *networkingv1.IngressList {
+	out := &networkingv1.IngressList{ListMeta: in.ListMeta}
+	for i := range in.Items {
+		out.Items = append(out.Items, *IngressV1beta1ToV1(&in.Items[i]))
+	}
+	return out
+}
+
+// IngressClassV1beta1ToV1 converts a networking.k8s.io/v1beta1 IngressClass to the networking.k8s.io/v1 IngressClass.
+func IngressClassV1beta1ToV1(in *networkingv1beta1.IngressClass) *networkingv1.IngressClass {
+	return &networkingv1.IngressClass{
+		ObjectMeta: *in.ObjectMeta.DeepCopy(),
+		Spec: networkingv1.IngressClassSpec{
+			Controller: in.Spec.Controller,
+			Parameters: in.Spec.Parameters.DeepCopy(),
+		},
+	}
+}
+
+func ingressClassListV1beta1ToV1(in *networkingv1beta1.IngressClassList) *networkingv1.IngressClassList {
+	out := &networkingv1.IngressClassList{ListMeta: in.ListMeta}
+	for i := range in.Items {
+		out.Items = append(out.Items, *IngressClassV1beta1ToV1(&in.Items[i]))
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	netinformers "k8s.io/client-go/informers/networking/v1"
+	"k8s.io/client-go/kubernetes"
+	netlisters "k8s.io/client-go/listers/networking/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// v1beta1IngressInformer watches the networking.k8s.io/v1beta1 Ingresses, and stores them in the
+// informer cache as networking.k8s.io/v1 Ingresses. This is used on clusters older than k8s 1.19.
+type v1beta1IngressInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+func newV1beta1IngressInformer(cs kubernetes.Interface, namespace string, resync time.Duration) netinformers.IngressInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			ingList, err := cs.NetworkingV1beta1().Ingresses(namespace).List(context.TODO(), options)
+			if err != nil {
+				return nil, err
+			}
+			return ingressListV1beta1ToV1(ingList), nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			w, err := cs.NetworkingV1beta1().Ingresses(namespace).Watch(context.TODO(), options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+				if ing, ok := in.Object.(*networkingv1beta1.Ingress); ok {
+					in.Object = IngressV1beta1ToV1(ing)
+				}
+				return in, true
+			}), nil
+		},
+	}
+	informer := cache.NewSharedIndexInformer(lw, &networkingv1.Ingress{}, resync,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	return &v1beta1IngressInformer{informer: informer}
+}
+
+func (i *v1beta1IngressInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *v1beta1IngressInformer) Lister() netlisters.IngressLister {
+	return netlisters.NewIngressLister(i.informer.GetIndexer())
+}
+
+// v1beta1IngressClassInformer watches the networking.k8s.io/v1beta1 IngressClasses, and stores them in the
+// informer cache as networking.k8s.io/v1 IngressClasses.
+type v1beta1IngressClassInformer struct {
+	informer cache.SharedIndexInformer
+}
+
+func newV1beta1IngressClassInformer(cs kubernetes.Interface, resync time.Duration) netinformers.IngressClassInformer {
+	lw := &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			ingClassList, err := cs.NetworkingV1beta1().IngressClasses().List(context.TODO(), options)
+			if err != nil {
+				return nil, err
+			}
+			return ingressClassListV1beta1ToV1(ingClassList), nil
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			w, err := cs.NetworkingV1beta1().IngressClasses().Watch(context.TODO(), options)
+			if err != nil {
+				return nil, err
+			}
+			return watch.Filter(w, func(in watch.Event) (watch.Event, bool) {
+				if ingClass, ok := in.Object.(*networkingv1beta1.IngressClass); ok {
+					in.Object = IngressClassV1beta1ToV1(ingClass)
+				}
+				return in, true
+			}), nil
+		},
+	}
+	informer := cache.NewSharedIndexInformer(lw, &networkingv1.IngressClass{}, resync, cache.Indexers{})
+	return &v1beta1IngressClassInformer{informer: informer}
+}
+
+func (i *v1beta1IngressClassInformer) Informer() cache.SharedIndexInformer {
+	return i.informer
+}
+
+func (i *v1beta1IngressClassInformer) Lister() netlisters.IngressClassLister {
+	return netlisters.NewIngressClassLister(i.informer.GetIndexer())
+}
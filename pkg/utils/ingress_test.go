@@ -0,0 +1,155 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"testing"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func v1beta1Ingress(paths ...networkingv1beta1.HTTPIngressPath) *networkingv1beta1.Ingress {
+	return &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "foo"},
+		Spec: networkingv1beta1.IngressSpec{
+			Rules: []networkingv1beta1.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: networkingv1beta1.IngressRuleValue{
+					HTTP: &networkingv1beta1.HTTPIngressRuleValue{Paths: paths},
+				},
+			}},
+		},
+	}
+}
+
+func TestIngressV1beta1ToV1ServicePort(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	ing := v1beta1Ingress(
+		networkingv1beta1.HTTPIngressPath{
+			Path:    "/foo",
+			Backend: networkingv1beta1.IngressBackend{ServiceName: "avisvc", ServicePort: intstr.FromInt(8080)},
+		},
+		networkingv1beta1.HTTPIngressPath{
+			Path:    "/bar",
+			Backend: networkingv1beta1.IngressBackend{ServiceName: "avisvc", ServicePort: intstr.FromString("http")},
+		},
+	)
+	ing.Spec.Backend = &networkingv1beta1.IngressBackend{ServiceName: "defaultsvc", ServicePort: intstr.FromString("https")}
+	ing.Spec.TLS = []networkingv1beta1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "my-secret"}}
+	ing.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.250.250.10", Hostname: "foo.com"}}
+
+	out := IngressV1beta1ToV1(ing)
+	g.Expect(out.Namespace).To(gomega.Equal("default"))
+	g.Expect(out.Name).To(gomega.Equal("foo"))
+	g.Expect(out.Spec.DefaultBackend.Service).To(gomega.Equal(&networkingv1.IngressServiceBackend{
+		Name: "defaultsvc",
+		Port: networkingv1.ServiceBackendPort{Name: "https"},
+	}))
+	g.Expect(out.Spec.TLS).To(gomega.Equal([]networkingv1.IngressTLS{{Hosts: []string{"foo.com"}, SecretName: "my-secret"}}))
+	g.Expect(out.Status.LoadBalancer.Ingress).To(gomega.Equal(ing.Status.LoadBalancer.Ingress))
+
+	g.Expect(out.Spec.Rules).To(gomega.HaveLen(1))
+	g.Expect(out.Spec.Rules[0].Host).To(gomega.Equal("foo.com"))
+	paths := out.Spec.Rules[0].HTTP.Paths
+	g.Expect(paths).To(gomega.HaveLen(2))
+	// A port number is converted to the number of the service port, and a port name to its name.
+	g.Expect(paths[0].Path).To(gomega.Equal("/foo"))
+	g.Expect(paths[0].Backend.Service).To(gomega.Equal(&networkingv1.IngressServiceBackend{
+		Name: "avisvc",
+		Port: networkingv1.ServiceBackendPort{Number: 8080},
+	}))
+	g.Expect(paths[1].Path).To(gomega.Equal("/bar"))
+	g.Expect(paths[1].Backend.Service).To(gomega.Equal(&networkingv1.IngressServiceBackend{
+		Name: "avisvc",
+		Port: networkingv1.ServiceBackendPort{Name: "http"},
+	}))
+}
+
+func TestIngressV1beta1ToV1ResourceBackend(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	apiGroup := "k8s.example.com"
+	resource := &corev1.TypedLocalObjectReference{APIGroup: &apiGroup, Kind: "StorageBucket", Name: "static-assets"}
+	ing := v1beta1Ingress(networkingv1beta1.HTTPIngressPath{
+		Path:    "/static",
+		Backend: networkingv1beta1.IngressBackend{Resource: resource},
+	})
+	ing.Spec.Backend = &networkingv1beta1.IngressBackend{Resource: resource}
+
+	out := IngressV1beta1ToV1(ing)
+	g.Expect(out.Spec.DefaultBackend.Service).To(gomega.BeNil())
+	g.Expect(out.Spec.DefaultBackend.Resource).To(gomega.Equal(resource))
+	backend := out.Spec.Rules[0].HTTP.Paths[0].Backend
+	g.Expect(backend.Service).To(gomega.BeNil())
+	g.Expect(backend.Resource).To(gomega.Equal(resource))
+}
+
+func TestIngressV1beta1ToV1PathType(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	exact, prefix := networkingv1beta1.PathTypeExact, networkingv1beta1.PathTypePrefix
+	backend := networkingv1beta1.IngressBackend{ServiceName: "avisvc", ServicePort: intstr.FromInt(8080)}
+	ing := v1beta1Ingress(
+		networkingv1beta1.HTTPIngressPath{Path: "/foo", PathType: &exact, Backend: backend},
+		networkingv1beta1.HTTPIngressPath{Path: "/bar", PathType: &prefix, Backend: backend},
+		networkingv1beta1.HTTPIngressPath{Path: "/baz", Backend: backend},
+	)
+	ing.Spec.Rules = append(ing.Spec.Rules, networkingv1beta1.IngressRule{Host: "bar.com"})
+
+	out := IngressV1beta1ToV1(ing)
+	paths := out.Spec.Rules[0].HTTP.Paths
+	g.Expect(*paths[0].PathType).To(gomega.Equal(networkingv1.PathTypeExact))
+	g.Expect(*paths[1].PathType).To(gomega.Equal(networkingv1.PathTypePrefix))
+	// The pathType is required in v1, and defaults to ImplementationSpecific in v1beta1.
+	g.Expect(*paths[2].PathType).To(gomega.Equal(networkingv1.PathTypeImplementationSpecific))
+	// The conversion does not share the pathType of the v1beta1 Ingress.
+	g.Expect(paths[0].PathType).NotTo(gomega.BeIdenticalTo(ing.Spec.Rules[0].HTTP.Paths[0].PathType))
+	// A rule without http paths is converted without paths.
+	g.Expect(out.Spec.Rules[1].Host).To(gomega.Equal("bar.com"))
+	g.Expect(out.Spec.Rules[1].HTTP).To(gomega.BeNil())
+}
+
+func TestSetIngressAPIVersion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	defer func(version string) { ingressAPIVersion = version }(ingressAPIVersion)
+
+	// networking.k8s.io/v1beta1 is used if the cluster does not serve networking.k8s.io/v1.
+	ingressAPIVersion = ""
+	SetIngressAPIVersion(k8sfake.NewSimpleClientset())
+	g.Expect(GetIngressAPIVersion()).To(gomega.Equal(IngressAPIVersionV1beta1))
+
+	// networking.k8s.io/v1 without the ingresses resource, as in k8s 1.18 which serves only the NetworkPolicy.
+	kc := k8sfake.NewSimpleClientset()
+	kc.Resources = []*metav1.APIResourceList{{
+		GroupVersion: IngressAPIVersionV1,
+		APIResources: []metav1.APIResource{{Name: "networkpolicies", Kind: "NetworkPolicy"}},
+	}}
+	ingressAPIVersion = ""
+	SetIngressAPIVersion(kc)
+	g.Expect(GetIngressAPIVersion()).To(gomega.Equal(IngressAPIVersionV1beta1))
+
+	kc.Resources[0].APIResources = append(kc.Resources[0].APIResources, metav1.APIResource{Name: "ingresses", Kind: "Ingress"})
+	ingressAPIVersion = ""
+	SetIngressAPIVersion(kc)
+	g.Expect(GetIngressAPIVersion()).To(gomega.Equal(IngressAPIVersionV1))
+
+	// The discovered version is retained.
+	SetIngressAPIVersion(k8sfake.NewSimpleClientset())
+	g.Expect(GetIngressAPIVersion()).To(gomega.Equal(IngressAPIVersionV1))
+}
@@ -32,6 +32,22 @@ const (
 	ErrorLevel = zapcore.ErrorLevel
 )
 
+// Fields attached to the logs of the ingestion, graph and rest layers, in the JSON log format.
+const (
+	LogKey            = "key"
+	LogLayer          = "layer"
+	LogObjectKind     = "kind"
+	LogNamespace      = "namespace"
+	LogName           = "name"
+	LogVirtualService = "vs"
+	LogRestOp         = "restOp"
+	LogRestModel      = "model"
+	LogTenant         = "tenant"
+)
+
+// JSONLogFormat is the value of LOG_FORMAT, which switches the logs from the console format to JSON.
+const JSONLogFormat = "json"
+
 var LogLevelMap = map[string]zapcore.Level{
 	"DEBUG": DebugLevel,
 	"INFO":  InfoLevel,
@@ -44,6 +60,18 @@ type AviLogger struct {
 	logger *zap.Logger // sugar is obtained from this logger
 	// Sugaring a Logger is quite inexpensive, so it's reasonable for a single application to use both Loggers and SugaredLoggers, converting between them on the boundaries of performance-sensitive code.
 	atom zap.AtomicLevel
+	// jsonFormat is set if the logs are encoded as JSON, the fields are attached to the logs only in this format.
+	jsonFormat bool
+}
+
+// With returns a logger which attaches the key value pairs as fields to the logs, in the JSON log format.
+// The logger is returned as is in the console format, where the messages already carry the key.
+func (aviLogger *AviLogger) With(args ...interface{}) *AviLogger {
+	if !aviLogger.jsonFormat {
+		return aviLogger
+	}
+	sugar := aviLogger.sugar.With(args...)
+	return &AviLogger{sugar, sugar.Desugar(), aviLogger.atom, aviLogger.jsonFormat}
 }
 
 func (aviLogger *AviLogger) Infof(template string, args ...interface{}) {
@@ -121,26 +149,17 @@ func init() {
 	var err error
 
 	usePVC := os.Getenv("USE_PVC")
-
-	encoderCfg := zap.NewProductionEncoderConfig()
-	encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder // colored capital case LEVEL
-	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder        // format 2020-05-08T03:26:08.943+0530
-	encoderCfg.EncodeCaller = zapcore.ShortCallerEncoder      // caller format package_name/filename.go
+	jsonFormat := os.Getenv("LOG_FORMAT") == JSONLogFormat
 
 	if usePVC != "true" {
-		logger := zap.New(zapcore.NewCore(
-			zapcore.NewConsoleEncoder(encoderCfg),
+		AviLog = newAviLogger(zapcore.NewCore(
+			newLogEncoder(jsonFormat, true),
 			zapcore.Lock(os.Stdout),
 			atom,
-		))
-
-		logger = logger.WithOptions(zap.AddCaller(), zap.AddCallerSkip(1))
-		sugar := logger.Sugar()
-		AviLog = AviLogger{sugar, logger, atom}
+		), atom, jsonFormat)
 		return
 	}
 
-	encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder
 	logpath = getFileName()
 	file, err = os.OpenFile(logpath,
 		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -159,15 +178,35 @@ func init() {
 		MaxAge:     28,  // days
 		Compress:   true,
 	})
-	core := zapcore.NewCore(zapcore.NewConsoleEncoder(encoderCfg),
+	core := zapcore.NewCore(newLogEncoder(jsonFormat, false),
 		w,
 		level,
 	)
 
-	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
-	sugar := logger.Sugar()
-	defer sugar.Sync()
-	AviLog = AviLogger{sugar, logger, atom}
+	AviLog = newAviLogger(core, atom, jsonFormat)
+	defer AviLog.sugar.Sync()
 
 	return
 }
+
+// newLogEncoder returns the encoder of the logs, in the JSON or the console format. The levels are colored
+// only in the console format, when the logs are not written to a file.
+func newLogEncoder(jsonFormat, colorLevel bool) zapcore.Encoder {
+	encoderCfg := zap.NewProductionEncoderConfig()
+	encoderCfg.EncodeLevel = zapcore.CapitalLevelEncoder // capital case LEVEL
+	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder   // format 2020-05-08T03:26:08.943+0530
+	encoderCfg.EncodeCaller = zapcore.ShortCallerEncoder // caller format package_name/filename.go
+	if jsonFormat {
+		return zapcore.NewJSONEncoder(encoderCfg)
+	}
+	if colorLevel {
+		encoderCfg.EncodeLevel = zapcore.CapitalColorLevelEncoder // colored capital case LEVEL
+	}
+	return zapcore.NewConsoleEncoder(encoderCfg)
+}
+
+// newAviLogger returns the logger writing to the core, the caller of the AviLogger methods is logged.
+func newAviLogger(core zapcore.Core, atom zap.AtomicLevel, jsonFormat bool) AviLogger {
+	logger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
+	return AviLogger{logger.Sugar(), logger, atom, jsonFormat}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/onsi/gomega"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func newTestLogger(buf *bytes.Buffer, jsonFormat bool) AviLogger {
+	atom := zap.NewAtomicLevel()
+	atom.SetLevel(InfoLevel)
+	return newAviLogger(zapcore.NewCore(newLogEncoder(jsonFormat, false), zapcore.AddSync(buf), atom), atom, jsonFormat)
+}
+
+// decodeLogLines returns the JSON objects of the log lines written to the buffer.
+func decodeLogLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var lines []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		fields := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(line), &fields); err != nil {
+			t.Fatalf("log line %q is not valid JSON: %v", line, err)
+		}
+		lines = append(lines, fields)
+	}
+	return lines
+}
+
+func TestJSONLogFormat(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, true)
+
+	key := "Ingress/default/foo"
+	logger.With(LogKey, key, LogLayer, ObjectIngestionLayer).Infof("key: %s, msg: starting graph Sync", key)
+	logger.Debugf("key: %s, msg: not logged at the info level", key)
+
+	lines := decodeLogLines(t, &buf)
+	g.Expect(lines).To(gomega.HaveLen(1))
+	g.Expect(lines[0]["level"]).To(gomega.Equal("INFO"))
+	g.Expect(lines[0]["msg"]).To(gomega.Equal("key: Ingress/default/foo, msg: starting graph Sync"))
+	g.Expect(lines[0][LogKey]).To(gomega.Equal(key))
+	g.Expect(lines[0][LogLayer]).To(gomega.Equal(ObjectIngestionLayer))
+	g.Expect(lines[0]["caller"]).To(gomega.ContainSubstring("utils/log_test.go"))
+	g.Expect(lines[0]).To(gomega.HaveKey("ts"))
+}
+
+func TestJSONLogFormatWith(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, true)
+
+	// The fields of a child logger are added to those of its parent, and are not attached to the parent.
+	modelLog := logger.With(LogKey, "admin/cluster--foo", LogLayer, RestLayer)
+	opLog := modelLog.With(LogRestOp, RestPost, LogRestModel, "Pool")
+	opLog.Warnf("rest operation failed")
+	modelLog.Infof("rest layer sync completed")
+	logger.Infof("no fields")
+
+	lines := decodeLogLines(t, &buf)
+	g.Expect(lines).To(gomega.HaveLen(3))
+	g.Expect(lines[0]["level"]).To(gomega.Equal("WARN"))
+	g.Expect(lines[0][LogKey]).To(gomega.Equal("admin/cluster--foo"))
+	g.Expect(lines[0][LogLayer]).To(gomega.Equal(RestLayer))
+	g.Expect(lines[0][LogRestOp]).To(gomega.Equal(string(RestPost)))
+	g.Expect(lines[0][LogRestModel]).To(gomega.Equal("Pool"))
+
+	g.Expect(lines[1][LogKey]).To(gomega.Equal("admin/cluster--foo"))
+	g.Expect(lines[1]).NotTo(gomega.HaveKey(LogRestOp))
+	g.Expect(lines[1]).NotTo(gomega.HaveKey(LogRestModel))
+
+	g.Expect(lines[2]).NotTo(gomega.HaveKey(LogKey))
+	g.Expect(lines[2]).NotTo(gomega.HaveKey(LogLayer))
+
+	// The level set on the parent applies to the child loggers.
+	buf.Reset()
+	logger.SetLevel("WARN")
+	opLog.Infof("not logged at the warn level")
+	g.Expect(buf.Len()).To(gomega.Equal(0))
+}
+
+func TestConsoleLogFormatWith(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf, false)
+
+	// The fields are not attached in the console format, where the messages already carry the key.
+	child := logger.With(LogKey, "admin/cluster--foo", LogLayer, RestLayer)
+	g.Expect(child).To(gomega.BeIdenticalTo(&logger))
+	child.Infof("key: %s, msg: start rest layer sync.", "admin/cluster--foo")
+
+	line := buf.String()
+	g.Expect(line).To(gomega.ContainSubstring("INFO"))
+	g.Expect(line).To(gomega.ContainSubstring("utils/log_test.go"))
+	g.Expect(line).To(gomega.ContainSubstring("key: admin/cluster--foo, msg: start rest layer sync."))
+	g.Expect(line).NotTo(gomega.ContainSubstring(RestLayer))
+	g.Expect(line).NotTo(gomega.HavePrefix("{"))
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	metricsNamespace = "ako"
+
+	MetricsResultSuccess = "success"
+	MetricsResultFailure = "failure"
+)
+
+// MetricsRegistry holds all the collectors exposed by the /metrics api.
+var MetricsRegistry = prometheus.NewRegistry()
+
+var (
+	workqueueDepth = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "depth",
+		Help:      "Current number of keys waiting in the workqueue.",
+	}, []string{"queue"})
+
+	workqueueAdds = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "adds_total",
+		Help:      "Total number of keys added to the workqueue.",
+	}, []string{"queue"})
+
+	workqueueLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "queue_duration_seconds",
+		Help:      "Time a key spends in the workqueue before it is dequeued.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"queue"})
+
+	workqueueWorkDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "work_duration_seconds",
+		Help:      "Time taken to process a key after it is dequeued.",
+		Buckets:   prometheus.ExponentialBuckets(0.001, 4, 10),
+	}, []string{"queue"})
+
+	workqueueRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "workqueue",
+		Name:      "retries_total",
+		Help:      "Total number of rate limited re-adds to the workqueue.",
+	}, []string{"queue"})
+
+	aviRestOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "avi_rest",
+		Name:      "operations_total",
+		Help:      "Total number of rest operations sent to the Avi controller.",
+	}, []string{"object_type", "method", "result"})
+
+	aviRestLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "avi_rest",
+		Name:      "operation_duration_seconds",
+		Help:      "Latency of rest operations sent to the Avi controller.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"object_type", "method"})
+
+	retryCount = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "retry",
+		Name:      "total",
+		Help:      "Total number of keys processed by the retry layers.",
+	}, []string{"layer"})
+
+	fullSyncDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "full_sync",
+		Name:      "duration_seconds",
+		Help:      "Time taken by each full sync run.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 12),
+	}, []string{"type"})
+)
+
+func init() {
+	MetricsRegistry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		workqueueDepth,
+		workqueueAdds,
+		workqueueLatency,
+		workqueueWorkDuration,
+		workqueueRetries,
+		aviRestOperations,
+		aviRestLatency,
+		retryCount,
+		fullSyncDuration,
+	)
+	// The provider has to be set before any of the workqueues get created.
+	workqueue.SetProvider(workqueueMetricsProvider{})
+}
+
+// ObserveAviRestOperation records the result and latency of a single rest operation.
+func ObserveAviRestOperation(objType string, method RestMethod, err error, start time.Time) {
+	result := MetricsResultSuccess
+	if err != nil {
+		result = MetricsResultFailure
+	}
+	aviRestOperations.WithLabelValues(objType, string(method), result).Inc()
+	aviRestLatency.WithLabelValues(objType, string(method)).Observe(time.Since(start).Seconds())
+}
+
+// IncRetryCount increments the number of keys processed by the given retry layer.
+func IncRetryCount(layer string) {
+	retryCount.WithLabelValues(layer).Inc()
+}
+
+// ObserveFullSyncDuration records the time taken by a full sync run of the given type.
+func ObserveFullSyncDuration(syncType string, start time.Time) {
+	fullSyncDuration.WithLabelValues(syncType).Observe(time.Since(start).Seconds())
+}
+
+// workqueueMetricsProvider implements workqueue.MetricsProvider. Each WorkerQueue is made up of
+// NumWorkers rate limiting queues sharing the same name, so the metrics are aggregated per layer.
+type workqueueMetricsProvider struct{}
+
+func (workqueueMetricsProvider) NewDepthMetric(name string) workqueue.GaugeMetric {
+	return workqueueDepth.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewAddsMetric(name string) workqueue.CounterMetric {
+	return workqueueAdds.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewLatencyMetric(name string) workqueue.HistogramMetric {
+	return workqueueLatency.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewWorkDurationMetric(name string) workqueue.HistogramMetric {
+	return workqueueWorkDuration.WithLabelValues(name)
+}
+
+func (workqueueMetricsProvider) NewRetriesMetric(name string) workqueue.CounterMetric {
+	return workqueueRetries.WithLabelValues(name)
+}
+
+// The unfinished work and longest running processor gauges are set per queue instance, and would
+// overwrite each other for the queues of a layer, hence they are not exported.
+func (workqueueMetricsProvider) NewUnfinishedWorkSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopSettableGauge{}
+}
+
+func (workqueueMetricsProvider) NewLongestRunningProcessorSecondsMetric(name string) workqueue.SettableGaugeMetric {
+	return noopSettableGauge{}
+}
+
+type noopSettableGauge struct{}
+
+func (noopSettableGauge) Set(float64) {}
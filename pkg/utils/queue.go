@@ -78,6 +78,9 @@ func NewWorkQueue(num_workers uint32, workerQueueName string, slowSyncTime ...in
 	}
 	for i := uint32(0); i < num_workers; i++ {
 		queue.Workqueue[i] = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), fmt.Sprintf("avi-%s", workerQueueName))
+		if workerQueueName == ObjectIngestionLayer && IsTracingEnabled() {
+			queue.Workqueue[i] = &tracingQueue{queue.Workqueue[i], workerQueueName}
+		}
 	}
 	return queue
 }
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/util/workqueue"
+)
+
+const (
+	TRACING_OTLP_ENDPOINT = "TRACING_OTLP_ENDPOINT"
+	TRACING_FILE          = "TRACING_FILE"
+
+	tracingServiceName   = "ako"
+	tracingExportTimeout = 10 * time.Second
+)
+
+var tracerProvider *sdktrace.TracerProvider
+var tracerProviderOnce sync.Once
+
+// getTracerProvider returns the provider of the tracer, which exports the spans to the OTLP endpoint and
+// the file set in the environment. nil is returned if neither is set.
+func getTracerProvider() *sdktrace.TracerProvider {
+	tracerProviderOnce.Do(func() {
+		endpoint := os.Getenv(TRACING_OTLP_ENDPOINT)
+		file := os.Getenv(TRACING_FILE)
+		if endpoint == "" && file == "" {
+			return
+		}
+		attributes := []attribute.KeyValue{semconv.ServiceNameKey.String(tracingServiceName)}
+		if podName := os.Getenv("POD_NAME"); podName != "" {
+			attributes = append(attributes, semconv.K8SPodNameKey.String(podName))
+		}
+		options := []sdktrace.TracerProviderOption{
+			sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, attributes...)),
+		}
+		if endpoint != "" {
+			exporter, err := newOTLPExporter(endpoint)
+			if err != nil {
+				AviLog.Warnf("Error in creating the exporter of the spans to the endpoint %s: %v", endpoint, err)
+			} else {
+				options = append(options, sdktrace.WithBatcher(exporter))
+			}
+		}
+		if file != "" {
+			f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+			if err != nil {
+				AviLog.Warnf("Error in opening the trace file %s: %v", file, err)
+			} else if exporter, err := stdouttrace.New(stdouttrace.WithWriter(f)); err != nil {
+				AviLog.Warnf("Error in creating the exporter of the spans to the file %s: %v", file, err)
+			} else {
+				options = append(options, sdktrace.WithBatcher(exporter))
+			}
+		}
+		if len(options) == 1 {
+			return
+		}
+		AviLog.Infof("Tracing enabled, exporting spans to endpoint: %q, file: %q", endpoint, file)
+		tracerProvider = sdktrace.NewTracerProvider(options...)
+	})
+	return tracerProvider
+}
+
+// newOTLPExporter returns the exporter of the spans to the OTLP/HTTP endpoint of a collector, such as
+// http://otel-collector:4318. The /v1/traces path is used if the endpoint has no path.
+func newOTLPExporter(endpoint string) (sdktrace.SpanExporter, error) {
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	path := strings.TrimSuffix(endpointURL.Path, "/")
+	if !strings.HasSuffix(path, "/v1/traces") {
+		path += "/v1/traces"
+	}
+	options := []otlptracehttp.Option{
+		otlptracehttp.WithEndpoint(endpointURL.Host),
+		otlptracehttp.WithURLPath(path),
+		otlptracehttp.WithTimeout(tracingExportTimeout),
+	}
+	if endpointURL.Scheme != "https" {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	return otlptracehttp.New(context.Background(), options...)
+}
+
+func getTracer() trace.Tracer {
+	if provider := getTracerProvider(); provider != nil {
+		return provider.Tracer(tracingServiceName)
+	}
+	return trace.NewNoopTracerProvider().Tracer(tracingServiceName)
+}
+
+// IsTracingEnabled returns true if the spans are exported to an OTLP endpoint or a file.
+func IsTracingEnabled() bool {
+	return getTracerProvider() != nil
+}
+
+// FlushSpans exports the spans which have ended, and waits for the export to complete.
+func FlushSpans() {
+	if provider := getTracerProvider(); provider != nil {
+		if err := provider.ForceFlush(context.Background()); err != nil {
+			AviLog.Warnf("Error in exporting the spans: %v", err)
+		}
+	}
+}
+
+// StartSpan starts a span, as a child of the parent if set, or of a new trace.
+func StartSpan(name string, parent trace.Span, kind trace.SpanKind, attributes ...attribute.KeyValue) trace.Span {
+	ctx := context.Background()
+	if parent != nil {
+		ctx = trace.ContextWithSpan(ctx, parent)
+	}
+	_, span := getTracer().Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attributes...))
+	return span
+}
+
+// keySpans tracks the spans of the keys, which are passed across the layers as strings through the workqueues.
+type keySpans struct {
+	lock sync.Mutex
+	// queue name -> key -> span started when the key was added to the queue
+	queued map[string]map[string]trace.Span
+	// queue name -> key -> span of the processing of the key dequeued from the queue
+	active map[string]map[string]trace.Span
+}
+
+var traceKeySpans = &keySpans{
+	queued: make(map[string]map[string]trace.Span),
+	active: make(map[string]map[string]trace.Span),
+}
+
+// TraceEnqueue starts the span of the key added to the queue, as a child of the parent span if set. The span
+// covers the time the key waits in the queue and is processed. If the key is already waiting in the queue,
+// the span of the earlier addition is retained, as the workqueue processes the key once for both.
+func TraceEnqueue(queueName, key string, parent trace.Span) {
+	if !IsTracingEnabled() {
+		return
+	}
+	traceKeySpans.lock.Lock()
+	defer traceKeySpans.lock.Unlock()
+	if _, ok := traceKeySpans.queued[queueName][key]; ok {
+		return
+	}
+	if traceKeySpans.queued[queueName] == nil {
+		traceKeySpans.queued[queueName] = make(map[string]trace.Span)
+	}
+	traceKeySpans.queued[queueName][key] = StartSpan(queueName, parent, trace.SpanKindInternal,
+		attribute.String(LogKey, key), attribute.String(LogLayer, queueName))
+}
+
+// keySpan is the span of the processing of a key, which ends the span of the key in the queue as well.
+type keySpan struct {
+	trace.Span
+	queueSpan trace.Span
+	queueName string
+	key       string
+}
+
+func (s *keySpan) End(options ...trace.SpanEndOption) {
+	traceKeySpans.lock.Lock()
+	if traceKeySpans.active[s.queueName][s.key] == s {
+		delete(traceKeySpans.active[s.queueName], s.key)
+	}
+	traceKeySpans.lock.Unlock()
+	s.Span.End(options...)
+	if s.queueSpan != nil {
+		s.queueSpan.End(options...)
+	}
+}
+
+// StartKeySpan starts the span of the processing of the key dequeued from the queue, as a child of the span of
+// the key in the queue. The span is the active span of the key, till it is ended.
+func StartKeySpan(queueName, key, name string) trace.Span {
+	if !IsTracingEnabled() {
+		return trace.SpanFromContext(context.Background())
+	}
+	traceKeySpans.lock.Lock()
+	defer traceKeySpans.lock.Unlock()
+	queueSpan := traceKeySpans.queued[queueName][key]
+	delete(traceKeySpans.queued[queueName], key)
+
+	span := &keySpan{
+		Span:      StartSpan(name, queueSpan, trace.SpanKindInternal, attribute.String(LogKey, key), attribute.String(LogLayer, queueName)),
+		queueSpan: queueSpan,
+		queueName: queueName,
+		key:       key,
+	}
+	if traceKeySpans.active[queueName] == nil {
+		traceKeySpans.active[queueName] = make(map[string]trace.Span)
+	}
+	traceKeySpans.active[queueName][key] = span
+	return span
+}
+
+// GetActiveSpan returns the span of the key being processed from the queue, nil if there is none.
+func GetActiveSpan(queueName, key string) trace.Span {
+	traceKeySpans.lock.Lock()
+	defer traceKeySpans.lock.Unlock()
+	return traceKeySpans.active[queueName][key]
+}
+
+// tracingQueue starts the span of the keys added to the queue, so that the trace of an object starts when
+// the informer event handlers add its key to the queue.
+type tracingQueue struct {
+	workqueue.RateLimitingInterface
+	name string
+}
+
+func (q *tracingQueue) Add(item interface{}) {
+	if key, ok := item.(string); ok {
+		TraceEnqueue(q.name, key, nil)
+	}
+	q.RateLimitingInterface.Add(item)
+}
+
+func (q *tracingQueue) AddAfter(item interface{}, duration time.Duration) {
+	if key, ok := item.(string); ok {
+		TraceEnqueue(q.name, key, nil)
+	}
+	q.RateLimitingInterface.AddAfter(item, duration)
+}
+
+func (q *tracingQueue) AddRateLimited(item interface{}) {
+	if key, ok := item.(string); ok {
+		TraceEnqueue(q.name, key, nil)
+	}
+	q.RateLimitingInterface.AddRateLimited(item)
+}
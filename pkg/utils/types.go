@@ -23,7 +23,8 @@ import (
 	oshiftinformers "github.com/openshift/client-go/route/informers/externalversions/route/v1"
 	avimodels "github.com/vmware/alb-sdk/go/models"
 	coreinformers "k8s.io/client-go/informers/core/v1"
-	netinformers "k8s.io/client-go/informers/networking/v1beta1"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1beta1"
+	netinformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
@@ -71,6 +72,7 @@ type Informers struct {
 	ConfigMapInformer    coreinformers.ConfigMapInformer
 	ServiceInformer      coreinformers.ServiceInformer
 	EpInformer           coreinformers.EndpointsInformer
+	EpSliceInformer      discoveryinformers.EndpointSliceInformer
 	PodInformer          coreinformers.PodInformer
 	NSInformer           coreinformers.NamespaceInformer
 	SecretInformer       coreinformers.SecretInformer
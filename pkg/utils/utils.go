@@ -31,7 +31,7 @@ import (
 	oshiftclientset "github.com/openshift/client-go/route/clientset/versioned"
 	oshiftinformers "github.com/openshift/client-go/route/informers/externalversions"
 	corev1 "k8s.io/api/core/v1"
-	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	networkingv1 "k8s.io/api/networking/v1"
 	k8sruntime "k8s.io/apimachinery/pkg/runtime"
 	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/tools/cache"
@@ -43,7 +43,7 @@ var runtimeScheme = k8sruntime.NewScheme()
 func init() {
 	//Setting the package-wide version
 	CtrlVersion = os.Getenv("CTRL_VERSION")
-	networkingv1beta1.AddToScheme(runtimeScheme)
+	networkingv1.AddToScheme(runtimeScheme)
 }
 
 func IsV4(addr string) bool {
@@ -107,7 +107,7 @@ func CrudHashKey(obj_type string, obj interface{}) string {
 		ns = svc.Namespace
 		name = svc.Name
 	case "Ingress":
-		ing := obj.(*networkingv1beta1.Ingress)
+		ing := obj.(*networkingv1.Ingress)
 		ns = ing.Namespace
 		name = ing.Name
 	default:
@@ -162,12 +162,15 @@ func instantiateInformers(kubeClient KubeClientIntf, registeredInformers []strin
 	akoNS := GetAKONamespace()
 
 	SetIngressClassEnabled(cs)
+	SetIngressAPIVersion(cs)
+	SetEndpointSliceEnabled(cs)
 
 	akoNSInformerFactory = kubeinformers.NewSharedInformerFactoryWithOptions(cs, InformerDefaultResync, kubeinformers.WithNamespace(akoNS))
 	AviLog.Infof("Initializing configmap informer in %v", akoNS)
 
 	informers := &Informers{}
 	informers.KubeClientIntf = kubeClient
+	informers.IngressVersion = GetIngressAPIVersion()
 	for _, informer := range registeredInformers {
 		switch informer {
 		case ServiceInformer:
@@ -177,7 +180,11 @@ func instantiateInformers(kubeClient KubeClientIntf, registeredInformers []strin
 		case PodInformer:
 			informers.PodInformer = kubeInformerFactory.Core().V1().Pods()
 		case EndpointInformer:
-			informers.EpInformer = kubeInformerFactory.Core().V1().Endpoints()
+			if GetEndpointSliceEnabled() {
+				informers.EpSliceInformer = kubeInformerFactory.Discovery().V1beta1().EndpointSlices()
+			} else {
+				informers.EpInformer = kubeInformerFactory.Core().V1().Endpoints()
+			}
 		case SecretInformer:
 			if akoNSBoundInformer {
 				informers.SecretInformer = akoNSInformerFactory.Core().V1().Secrets()
@@ -189,10 +196,18 @@ func instantiateInformers(kubeClient KubeClientIntf, registeredInformers []strin
 		case ConfigMapInformer:
 			informers.ConfigMapInformer = akoNSInformerFactory.Core().V1().ConfigMaps()
 		case IngressInformer:
-			informers.IngressInformer = kubeInformerFactory.Networking().V1beta1().Ingresses()
+			if informers.IngressVersion == IngressAPIVersionV1 {
+				informers.IngressInformer = kubeInformerFactory.Networking().V1().Ingresses()
+			} else {
+				informers.IngressInformer = newV1beta1IngressInformer(cs, namespace, InformerDefaultResync)
+			}
 		case IngressClassInformer:
 			if GetIngressClassEnabled() {
-				informers.IngressClassInformer = kubeInformerFactory.Networking().V1beta1().IngressClasses()
+				if informers.IngressVersion == IngressAPIVersionV1 {
+					informers.IngressClassInformer = kubeInformerFactory.Networking().V1().IngressClasses()
+				} else {
+					informers.IngressClassInformer = newV1beta1IngressClassInformer(cs, InformerDefaultResync)
+				}
 			}
 		case RouteInformer:
 			if ocs != nil {
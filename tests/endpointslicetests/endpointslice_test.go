@@ -0,0 +1,429 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package endpointslicetests
+
+import (
+	"context"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1beta1 "k8s.io/api/discovery/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var ctrl *k8s.AviController
+
+func TestMain(m *testing.M) {
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+
+	KubeClient = k8sfake.NewSimpleClientset()
+	// Serve the EndpointSlice API from the fake discovery client, so that the endpoints are tracked using EndpointSlices.
+	KubeClient.Resources = []*metav1.APIResourceList{{
+		GroupVersion: utils.EndpointSliceAPIVersion,
+		APIResources: []metav1.APIResource{{Name: "endpointslices", Kind: "EndpointSlice", Namespaced: true}},
+	}}
+	CRDClient = crdfake.NewSimpleClientset()
+	lib.SetCRDClientset(CRDClient)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers(CRDClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	cloudObj.NSIpamDNS = []string{"avi.internal", ".com"}
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+type fakeEndpoint struct {
+	ip          string
+	node        string
+	ready       *bool
+	serving     *bool
+	terminating *bool
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func getEndpointSlice(ns, name, svcName string, endpoints []fakeEndpoint) *discoveryv1beta1.EndpointSlice {
+	portName := "foo0"
+	port := int32(8080)
+	protocol := corev1.ProtocolTCP
+	epSlice := &discoveryv1beta1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: ns,
+			Name:      name,
+			Labels:    map[string]string{discoveryv1beta1.LabelServiceName: svcName},
+		},
+		AddressType: discoveryv1beta1.AddressTypeIPv4,
+		Ports:       []discoveryv1beta1.EndpointPort{{Name: &portName, Port: &port, Protocol: &protocol}},
+	}
+	for _, ep := range endpoints {
+		endpoint := discoveryv1beta1.Endpoint{
+			Addresses:  []string{ep.ip},
+			Conditions: discoveryv1beta1.EndpointConditions{Ready: ep.ready, Serving: ep.serving, Terminating: ep.terminating},
+		}
+		if ep.node != "" {
+			endpoint.Topology = map[string]string{corev1.LabelHostname: ep.node}
+		}
+		epSlice.Endpoints = append(epSlice.Endpoints, endpoint)
+	}
+	return epSlice
+}
+
+func createEndpointSlice(t *testing.T, epSlice *discoveryv1beta1.EndpointSlice) {
+	if _, err := KubeClient.DiscoveryV1beta1().EndpointSlices(epSlice.Namespace).Create(context.TODO(), epSlice, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating EndpointSlice: %v", err)
+	}
+}
+
+func updateEndpointSlice(t *testing.T, epSlice *discoveryv1beta1.EndpointSlice) {
+	epSlice.ResourceVersion = "2"
+	if _, err := KubeClient.DiscoveryV1beta1().EndpointSlices(epSlice.Namespace).Update(context.TODO(), epSlice, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating EndpointSlice: %v", err)
+	}
+}
+
+func deleteEndpointSlice(t *testing.T, ns, name string) {
+	if err := KubeClient.DiscoveryV1beta1().EndpointSlices(ns).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting EndpointSlice: %v", err)
+	}
+}
+
+// getPoolServers returns the sorted server IPs and the server nodes of the first pool of the VS in the model.
+func getPoolServers(modelName string) ([]string, map[string]string) {
+	var servers []string
+	serverNodes := make(map[string]string)
+	if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 1 && len(nodes[0].PoolRefs) == 1 {
+			for _, server := range nodes[0].PoolRefs[0].Servers {
+				servers = append(servers, *server.Ip.Addr)
+				serverNodes[*server.Ip.Addr] = server.ServerNode
+			}
+		}
+	}
+	sort.Strings(servers)
+	return servers, serverNodes
+}
+
+// getDisabledPoolServers returns the sorted IPs of the disabled servers of the first pool of the VS in the model.
+func getDisabledPoolServers(modelName string) []string {
+	var servers []string
+	if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 1 && len(nodes[0].PoolRefs) == 1 {
+			for _, server := range nodes[0].PoolRefs[0].Servers {
+				if server.Disabled {
+					servers = append(servers, *server.Ip.Addr)
+				}
+			}
+		}
+	}
+	sort.Strings(servers)
+	return servers
+}
+
+func TestL4ServiceWithEndpointSlices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, corev1.ServiceTypeLoadBalancer, false)
+
+	// The endpoints of a Service are split across slices, the not ready endpoints are not added to the pool.
+	epSlice1 := getEndpointSlice(integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC+"-abc", integrationtest.SINGLEPORTSVC, []fakeEndpoint{
+		{ip: "1.1.1.1", node: "node1", ready: boolPtr(true)},
+		{ip: "1.1.1.2", node: "node2", ready: boolPtr(false)},
+	})
+	epSlice2 := getEndpointSlice(integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC+"-def", integrationtest.SINGLEPORTSVC, []fakeEndpoint{
+		{ip: "1.1.1.3", node: "node3"},
+	})
+	createEndpointSlice(t, epSlice1)
+	createEndpointSlice(t, epSlice2)
+
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(integrationtest.SINGLEPORTMODEL)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.3"}))
+	_, serverNodes := getPoolServers(integrationtest.SINGLEPORTMODEL)
+	g.Expect(serverNodes["1.1.1.1"]).To(gomega.Equal("node1"))
+	g.Expect(serverNodes["1.1.1.3"]).To(gomega.Equal("node3"))
+
+	// The endpoint becomes ready.
+	epSlice1.Endpoints[1].Conditions.Ready = boolPtr(true)
+	updateEndpointSlice(t, epSlice1)
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(integrationtest.SINGLEPORTMODEL)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}))
+
+	// The endpoints of the deleted slice are removed from the pool.
+	deleteEndpointSlice(t, integrationtest.NAMESPACE, epSlice2.Name)
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(integrationtest.SINGLEPORTMODEL)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2"}))
+
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	deleteEndpointSlice(t, integrationtest.NAMESPACE, epSlice1.Name)
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: "cluster--red-ns-testsvc"}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+}
+
+func TestIngressWithEndpointSlices(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, "default", "avisvc", corev1.ServiceTypeClusterIP, false)
+	epSlice := getEndpointSlice("default", "avisvc-abc", "avisvc", []fakeEndpoint{
+		{ip: "1.1.1.1", ready: boolPtr(true)},
+	})
+	createEndpointSlice(t, epSlice)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(modelName)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1"}))
+
+	// The slice updates are applied to the pools of the Ingresses using the Service.
+	epSlice.Endpoints = append(epSlice.Endpoints, discoveryv1beta1.Endpoint{
+		Addresses:  []string{"1.1.1.2"},
+		Conditions: discoveryv1beta1.EndpointConditions{Ready: boolPtr(true)},
+	})
+	updateEndpointSlice(t, epSlice)
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(modelName)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2"}))
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) == 1 {
+				return len(nodes[0].PoolRefs)
+			}
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(0))
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.DelSVC(t, "default", "avisvc")
+	deleteEndpointSlice(t, "default", epSlice.Name)
+}
+
+func TestL4ServiceWithTerminatingEndpoints(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, corev1.ServiceTypeLoadBalancer, false)
+
+	// The terminating endpoints which are still serving are added as disabled servers, the other
+	// terminating endpoints are not added to the pool.
+	epSlice := getEndpointSlice(integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC+"-abc", integrationtest.SINGLEPORTSVC, []fakeEndpoint{
+		{ip: "1.1.1.1", node: "node1", ready: boolPtr(true), serving: boolPtr(true), terminating: boolPtr(false)},
+		{ip: "1.1.1.2", node: "node2", ready: boolPtr(false), serving: boolPtr(true), terminating: boolPtr(true)},
+		{ip: "1.1.1.3", node: "node3", ready: boolPtr(false), serving: boolPtr(false), terminating: boolPtr(true)},
+		{ip: "1.1.1.4", node: "node4", terminating: boolPtr(true)},
+	})
+	createEndpointSlice(t, epSlice)
+
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(integrationtest.SINGLEPORTMODEL)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2"}))
+	g.Expect(getDisabledPoolServers(integrationtest.SINGLEPORTMODEL)).To(gomega.Equal([]string{"1.1.1.2"}))
+	_, serverNodes := getPoolServers(integrationtest.SINGLEPORTMODEL)
+	g.Expect(serverNodes["1.1.1.2"]).To(gomega.Equal("node2"))
+
+	// The endpoint stops serving, and is removed from the pool.
+	epSlice.Endpoints[1].Conditions.Serving = boolPtr(false)
+	updateEndpointSlice(t, epSlice)
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(integrationtest.SINGLEPORTMODEL)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1"}))
+	g.Expect(getDisabledPoolServers(integrationtest.SINGLEPORTMODEL)).To(gomega.BeEmpty())
+
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	deleteEndpointSlice(t, integrationtest.NAMESPACE, epSlice.Name)
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: "cluster--red-ns-testsvc"}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+}
+
+// getDrainDeadlines returns the drain deadlines of the servers of the first pool of the VS in the model, along
+// with the key of the pool.
+func getDrainDeadlines(modelName string) (map[string]time.Time, cache.NamespaceName) {
+	deadlines := make(map[string]time.Time)
+	var poolKey cache.NamespaceName
+	if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 1 && len(nodes[0].PoolRefs) == 1 {
+			pool := nodes[0].PoolRefs[0]
+			poolKey = cache.NamespaceName{Namespace: pool.Tenant, Name: pool.Name}
+			for _, server := range pool.Servers {
+				if server.DrainDeadline != nil {
+					deadlines[*server.Ip.Addr] = *server.DrainDeadline
+				}
+			}
+		}
+	}
+	return deadlines, poolKey
+}
+
+func TestL4ServiceWithTerminatingEndpointsAndServerDrain(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	os.Setenv(lib.SERVER_DRAIN_TIMEOUT, "60")
+	defer os.Unsetenv(lib.SERVER_DRAIN_TIMEOUT)
+
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, corev1.ServiceTypeLoadBalancer, false)
+
+	epSlice := getEndpointSlice(integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC+"-abc", integrationtest.SINGLEPORTSVC, []fakeEndpoint{
+		{ip: "1.1.1.1", node: "node1", ready: boolPtr(true), serving: boolPtr(true), terminating: boolPtr(false)},
+		{ip: "1.1.1.2", node: "node2", ready: boolPtr(false), serving: boolPtr(true), terminating: boolPtr(true)},
+	})
+	createEndpointSlice(t, epSlice)
+
+	// The drain of the terminating endpoint starts while it is still serving.
+	g.Eventually(func() []string {
+		return getDisabledPoolServers(integrationtest.SINGLEPORTMODEL)
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.2"}))
+	deadlines, poolKey := getDrainDeadlines(integrationtest.SINGLEPORTMODEL)
+	g.Expect(deadlines).To(gomega.HaveLen(1))
+	deadline := deadlines["1.1.1.2"]
+	g.Eventually(func() time.Time {
+		return cache.SharedAviObjCache().GetPoolServers(poolKey)["1.1.1.2"].DrainDeadline
+	}, 30*time.Second).Should(gomega.BeTemporally("==", deadline))
+
+	// The endpoint is removed from the slice, and keeps draining until the same deadline.
+	epSlice.Endpoints = epSlice.Endpoints[:1]
+	epSlice.Endpoints = append(epSlice.Endpoints, discoveryv1beta1.Endpoint{
+		Addresses:  []string{"1.1.1.3"},
+		Conditions: discoveryv1beta1.EndpointConditions{Ready: boolPtr(true)},
+	})
+	updateEndpointSlice(t, epSlice)
+	g.Eventually(func() []string {
+		servers, _ := getPoolServers(integrationtest.SINGLEPORTMODEL)
+		return servers
+	}, 30*time.Second).Should(gomega.Equal([]string{"1.1.1.1", "1.1.1.2", "1.1.1.3"}))
+	g.Expect(getDisabledPoolServers(integrationtest.SINGLEPORTMODEL)).To(gomega.Equal([]string{"1.1.1.2"}))
+	deadlines, _ = getDrainDeadlines(integrationtest.SINGLEPORTMODEL)
+	g.Expect(deadlines["1.1.1.2"]).To(gomega.BeTemporally("==", deadline))
+
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	deleteEndpointSlice(t, integrationtest.NAMESPACE, epSlice.Name)
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: "cluster--red-ns-testsvc"}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+}
@@ -27,6 +27,7 @@ import (
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
 	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	utils "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
@@ -492,3 +493,53 @@ func TestHTTPRuleCreateDeleteForEvh(t *testing.T) {
 
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
+
+func TestHTTPRuleRedirectForInsecureEvh(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-EVH-0"
+	rrname := "samplerr-foo"
+	SetUpIngressForCacheSyncCheck(t, false, false, modelName)
+
+	rrCreate := &akov1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: rrname},
+		Spec: akov1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []akov1alpha1.HTTPRulePaths{{
+				Target:   "/foo",
+				Redirect: &akov1alpha1.HTTPRuleRedirect{Host: "bar.com"},
+			}},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Create(context.TODO(), rrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+
+	getHTTPRulePolicy := func() *avinodes.AviHttpPolicySetNode {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviEvhVS()
+			if len(nodes) > 0 && len(nodes[0].EvhNodes) > 0 {
+				for _, policy := range nodes[0].EvhNodes[0].HttpPolicyRefs {
+					if policy.Name == lib.GetHTTPRulePolicy(nodes[0].EvhNodes[0].Name) {
+						return policy
+					}
+				}
+			}
+		}
+		return nil
+	}
+	g.Eventually(getHTTPRulePolicy, 25*time.Second).ShouldNot(gomega.BeNil())
+	policy := getHTTPRulePolicy()
+	g.Expect(policy.HppMap).To(gomega.HaveLen(2))
+	// the redirect retains the protocol of the insecure host.
+	g.Expect(policy.HppMap[0].Redirect).To(gomega.Equal(&avinodes.AviHostPathRedirect{
+		Protocol:   "HTTP",
+		Host:       "bar.com",
+		StatusCode: lib.STATUS_REDIRECT,
+	}))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(getHTTPRulePolicy, 25*time.Second).Should(gomega.BeNil())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
@@ -16,16 +16,27 @@ package ingresstests
 
 import (
 	"context"
+	"encoding/json"
+	"os"
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	akov1alpha1 "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/apis/ako/v1alpha1"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
 	"github.com/onsi/gomega"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestCreateDeleteHostRule(t *testing.T) {
@@ -489,6 +500,172 @@ func TestValidToInvalidHostSwitch(t *testing.T) {
 
 // HttpRule tests
 
+func TestHostRuleSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	hrCreate := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hrCreate.Spec.VirtualHost.SourceRanges = []string{"10.10.0.0/16"}
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Create(context.TODO(), hrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	policyName := lib.GetSourceRangesPolicy("cluster--foo.com")
+	getSourceRangesPolicy := func() *avinodes.AviHttpPolicySetNode {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].SniNodes) > 0 {
+				for _, policy := range nodes[0].SniNodes[0].HttpPolicyRefs {
+					if policy.Name == policyName {
+						return policy
+					}
+				}
+			}
+		}
+		return nil
+	}
+	g.Eventually(getSourceRangesPolicy, 25*time.Second).ShouldNot(gomega.BeNil())
+	policy := getSourceRangesPolicy()
+	g.Expect(policy.SecurityRules).To(gomega.HaveLen(1))
+	g.Expect(policy.SecurityRules[0].Action).To(gomega.Equal(lib.CLOSE_CONNECTION))
+	g.Expect(policy.SecurityRules[0].MatchCriteria).To(gomega.Equal(lib.IS_NOT_IN))
+	g.Expect(policy.SecurityRules[0].SourceRanges).To(gomega.Equal([]string{"10.10.0.0/16"}))
+
+	// an invalid source range rejects the hostrule, the policy of the accepted hostrule is retained.
+	hrUpdate := hrCreate.DeepCopy()
+	hrUpdate.Spec.VirtualHost.SourceRanges = []string{"10.10.0.0"}
+	hrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+	g.Expect(getSourceRangesPolicy()).ShouldNot(gomega.BeNil())
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(getSourceRangesPolicy, 25*time.Second).Should(gomega.BeNil())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHostRuleClientCertificate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	caSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "client-ca"},
+		Data:       map[string][]byte{"ca.crt": []byte("-----BEGIN CERTIFICATE-----")},
+	}
+	if _, err := KubeClient.CoreV1().Secrets("default").Create(context.TODO(), caSecret, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Secret: %v", err)
+	}
+
+	hrCreate := integrationtest.FakeHostRule{
+		Name:      hrname,
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	hrCreate.Spec.VirtualHost.TLS.ClientCertificate = akov1alpha1.HostRuleClientCertificate{
+		CASecret:        "client-ca",
+		ForwardDNHeader: "X-Client-DN",
+	}
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Create(context.TODO(), hrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	getSniNode := func() *avinodes.AviVsNode {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].SniNodes) > 0 {
+				return nodes[0].SniNodes[0]
+			}
+		}
+		return nil
+	}
+	getClientCertMode := func() string {
+		if node := getSniNode(); node != nil && node.ClientCertProfile != nil {
+			return node.ClientCertProfile.Mode
+		}
+		return ""
+	}
+	g.Eventually(getClientCertMode, 25*time.Second).Should(gomega.Equal(lib.SSL_CLIENT_CERTIFICATE_REQUIRE))
+	sniNode := getSniNode()
+	g.Expect(sniNode.ClientCertProfile.Name).To(gomega.Equal("cluster--foo.com"))
+	g.Expect(sniNode.ClientCertProfile.DNHeader).To(gomega.Equal("X-Client-DN"))
+	g.Expect(sniNode.ClientCertProfile.PkiProfile.CACert).To(gomega.Equal("-----BEGIN CERTIFICATE-----"))
+	g.Expect(sniNode.AppProfileRef).To(gomega.Equal("/api/applicationprofile?name=cluster--foo.com"))
+
+	// the avi application profile is created and cached for the virtual host.
+	mcache := cache.SharedAviObjCache()
+	appProfileKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 25*time.Second).Should(gomega.BeTrue())
+
+	hrUpdate := hrCreate.DeepCopy()
+	hrUpdate.Spec.VirtualHost.TLS.ClientCertificate.Mode = lib.ClientCertModeRequest
+	hrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(getClientCertMode, 25*time.Second).Should(gomega.Equal(lib.SSL_CLIENT_CERTIFICATE_REQUEST))
+
+	// the virtualhost is disabled when the CA Secret goes away.
+	if err := KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "client-ca", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Secret: %v", err)
+	}
+	g.Eventually(func() bool {
+		if node := getSniNode(); node != nil && node.Enabled != nil {
+			return *node.Enabled
+		}
+		return true
+	}, 25*time.Second).Should(gomega.BeFalse())
+	g.Expect(getSniNode().ClientCertProfile).To(gomega.BeNil())
+
+	// clientCertificate can not be used along with applicationProfile.
+	hrUpdate = hrUpdate.DeepCopy()
+	hrUpdate.Spec.VirtualHost.ApplicationProfile = "thisisaviref-appprof"
+	hrUpdate.ResourceVersion = "3"
+	if _, err := CRDClient.AkoV1alpha1().HostRules("default").Update(context.TODO(), hrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HostRule: %v", err)
+	}
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	g.Eventually(func() bool {
+		_, found := mcache.AppProfileCache.AviCacheGet(appProfileKey)
+		return found
+	}, 25*time.Second).Should(gomega.BeFalse())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestHTTPRuleCreateDelete(t *testing.T) {
 	// ingress secure foo.com/foo /bar
 	// create httprule /foo, nothing happens
@@ -551,6 +728,261 @@ func TestHTTPRuleCreateDelete(t *testing.T) {
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
 
+func TestHTTPRuleHeadersRewriteRedirect(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	rrCreate := &akov1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: rrname},
+		Spec: akov1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []akov1alpha1.HTTPRulePaths{{
+				Target: "/foo",
+				RequestHeaders: akov1alpha1.HTTPRuleHeaders{
+					Add:    []akov1alpha1.HTTPRuleHeader{{Name: "X-Forwarded-Prefix", Value: "/foo"}},
+					Remove: []string{"X-Debug"},
+				},
+				ResponseHeaders: akov1alpha1.HTTPRuleHeaders{
+					Replace: []akov1alpha1.HTTPRuleHeader{{Name: "Server", Value: "avi"}},
+				},
+				Rewrite: akov1alpha1.HTTPRuleRewrite{Prefix: "/"},
+			}, {
+				Target:   "/foo/old",
+				Redirect: &akov1alpha1.HTTPRuleRedirect{Path: "/foo/new", StatusCode: 301},
+			}},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Create(context.TODO(), rrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	policyName := lib.GetHTTPRulePolicy("cluster--foo.com")
+	getHTTPRulePolicy := func() *avinodes.AviHttpPolicySetNode {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].SniNodes) > 0 {
+				for _, policy := range nodes[0].SniNodes[0].HttpPolicyRefs {
+					if policy.Name == policyName {
+						return policy
+					}
+				}
+			}
+		}
+		return nil
+	}
+	g.Eventually(getHTTPRulePolicy, 25*time.Second).ShouldNot(gomega.BeNil())
+	policy := getHTTPRulePolicy()
+
+	// the longer target /foo/old is matched before /foo.
+	g.Expect(policy.HppMap).To(gomega.HaveLen(4))
+	g.Expect(policy.HppMap[0].Path).To(gomega.Equal([]string{"/foo/old"}))
+	g.Expect(policy.HppMap[0].MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(policy.HppMap[1].Path).To(gomega.Equal([]string{"/foo/old/"}))
+	g.Expect(policy.HppMap[1].MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(policy.HppMap[1].Redirect).To(gomega.Equal(&avinodes.AviHostPathRedirect{
+		Protocol:   "HTTPS",
+		Path:       "/foo/new",
+		StatusCode: "HTTP_REDIRECT_STATUS_CODE_301",
+	}))
+	g.Expect(policy.HppMap[3].Path).To(gomega.Equal([]string{"/foo/"}))
+	g.Expect(policy.HppMap[3].RewritePath).To(gomega.Equal(&avinodes.AviHostPathRewrite{StripPrefix: "/foo", Prefix: "/"}))
+	g.Expect(policy.HppMap[3].HdrAction).To(gomega.Equal([]avinodes.AviHostPathHdrAction{
+		{Action: "HTTP_ADD_HDR", Name: "X-Forwarded-Prefix", Value: "/foo"},
+		{Action: "HTTP_REMOVE_HDR", Name: "X-Debug"},
+	}))
+	g.Expect(policy.ResponseRules).To(gomega.HaveLen(2))
+	g.Expect(policy.ResponseRules[1].Path).To(gomega.Equal([]string{"/foo/"}))
+	g.Expect(policy.ResponseRules[1].HdrAction).To(gomega.Equal([]avinodes.AviHostPathHdrAction{
+		{Action: "HTTP_REPLACE_HDR", Name: "Server", Value: "avi"},
+	}))
+
+	mcache := cache.SharedAviObjCache()
+	policyKey := cache.NamespaceName{Namespace: "admin", Name: policyName}
+	g.Eventually(func() bool {
+		_, found := mcache.HTTPPolicyCache.AviCacheGet(policyKey)
+		return found
+	}, 25*time.Second).Should(gomega.BeTrue())
+
+	// a redirect along with a rewrite rejects the httprule, the policy of the accepted httprule is retained.
+	rrUpdate := rrCreate.DeepCopy()
+	rrUpdate.Spec.Paths[1].Rewrite.Prefix = "/bar"
+	rrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+	g.Expect(getHTTPRulePolicy()).ShouldNot(gomega.BeNil())
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(getHTTPRulePolicy, 25*time.Second).Should(gomega.BeNil())
+	g.Eventually(func() bool {
+		_, found := mcache.HTTPPolicyCache.AviCacheGet(policyKey)
+		return found
+	}, 25*time.Second).Should(gomega.BeFalse())
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestHTTPRuleRateLimitAndConnectionLimit(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	rrname := "samplerr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	rrCreate := &akov1alpha1.HTTPRule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: rrname},
+		Spec: akov1alpha1.HTTPRuleSpec{
+			Fqdn: "foo.com",
+			Paths: []akov1alpha1.HTTPRulePaths{{
+				Target: "/foo",
+				RateLimit: &akov1alpha1.HTTPRuleRateLimit{
+					Count:       10,
+					Burst:       20,
+					PerClientIP: true,
+					Action:      "Drop",
+				},
+				MaxConcurrentConnectionsPerServer: 100,
+			}},
+		},
+	}
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Create(context.TODO(), rrCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	policyName := lib.GetHTTPRulePolicy("cluster--foo.com")
+	getSniNode := func() *avinodes.AviVsNode {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].SniNodes) > 0 {
+				return nodes[0].SniNodes[0]
+			}
+		}
+		return nil
+	}
+	getPolicy := func() *avinodes.AviHttpPolicySetNode {
+		if node := getSniNode(); node != nil {
+			for _, policy := range node.HttpPolicyRefs {
+				if policy.Name == policyName {
+					return policy
+				}
+			}
+		}
+		return nil
+	}
+	getRateLimit := func() *avinodes.AviRateLimit {
+		if policy := getPolicy(); policy != nil && len(policy.SecurityRules) == 1 {
+			return policy.SecurityRules[0].RateLimit
+		}
+		return nil
+	}
+	isRateLimited := func(path string) bool {
+		policy := getPolicy()
+		for _, rule := range policy.SecurityRules {
+			for _, sg := range policy.StringGroupRefs {
+				if rule.MatchCriteria != lib.REGEX_MATCH || rule.StringGroup != sg.Name {
+					continue
+				}
+				for _, value := range sg.Values {
+					if regexp.MustCompile(value).MatchString(path) {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	g.Eventually(getRateLimit, 25*time.Second).Should(gomega.Equal(&avinodes.AviRateLimit{
+		Count:       10,
+		Period:      1,
+		BurstSize:   20,
+		PerClientIP: true,
+		Action:      lib.RL_ACTION_DROP_CONN,
+	}))
+	g.Expect(getSniNode().PoolRefs[0].MaxConcurrentConns).To(gomega.Equal(int32(100)))
+	// a single rule matches all the paths of the target, so that their requests share the same rate limiter.
+	policy := getPolicy()
+	g.Expect(policy.StringGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(policy.SecurityRules[0].StringGroup).To(gomega.Equal(policy.StringGroupRefs[0].Name))
+	// the target is matched as a Prefix path, the sibling paths with the same prefix are not rate limited.
+	g.Expect(isRateLimited("/foo")).To(gomega.BeTrue())
+	g.Expect(isRateLimited("/foo/")).To(gomega.BeTrue())
+	g.Expect(isRateLimited("/foo/bar")).To(gomega.BeTrue())
+	g.Expect(isRateLimited("/foobar")).To(gomega.BeFalse())
+
+	mcache := cache.SharedAviObjCache()
+	policyKey := cache.NamespaceName{Namespace: "admin", Name: policyName}
+	getPolicyChecksum := func() string {
+		if policyCache, found := mcache.HTTPPolicyCache.AviCacheGet(policyKey); found {
+			return policyCache.(*cache.AviHTTPPolicyCache).CloudConfigCksum
+		}
+		return ""
+	}
+	g.Eventually(getPolicyChecksum, 25*time.Second).ShouldNot(gomega.BeEmpty())
+	oldChecksum := getPolicyChecksum()
+	sgKey := cache.NamespaceName{Namespace: "admin", Name: policy.StringGroupRefs[0].Name}
+	g.Eventually(func() bool {
+		_, found := mcache.StringGroupCache.AviCacheGet(sgKey)
+		return found
+	}, 25*time.Second).Should(gomega.BeTrue())
+
+	// the checksum of the cached policy follows the updated rate limit.
+	rrUpdate := rrCreate.DeepCopy()
+	rrUpdate.Spec.Paths[0].RateLimit.Action = "TooManyRequests"
+	rrUpdate.ResourceVersion = "2"
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		if rateLimit := getRateLimit(); rateLimit != nil {
+			return rateLimit.StatusCode
+		}
+		return ""
+	}, 25*time.Second).Should(gomega.Equal(lib.STATUS_TOO_MANY_REQUESTS))
+	g.Eventually(getPolicyChecksum, 25*time.Second).ShouldNot(gomega.Equal(oldChecksum))
+
+	// a redirect action without a redirect rejects the httprule.
+	rrUpdate = rrUpdate.DeepCopy()
+	rrUpdate.Spec.Paths[0].RateLimit.Action = "Redirect"
+	rrUpdate.ResourceVersion = "3"
+	if _, err := CRDClient.AkoV1alpha1().HTTPRules("default").Update(context.TODO(), rrUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRule: %v", err)
+	}
+	g.Eventually(func() string {
+		httprule, _ := CRDClient.AkoV1alpha1().HTTPRules("default").Get(context.TODO(), rrname, metav1.GetOptions{})
+		return httprule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Rejected"))
+
+	integrationtest.TeardownHTTPRule(t, rrname)
+	g.Eventually(getRateLimit, 25*time.Second).Should(gomega.BeNil())
+	g.Eventually(func() bool {
+		_, found := mcache.StringGroupCache.AviCacheGet(sgKey)
+		return found
+	}, 25*time.Second).Should(gomega.BeFalse())
+	g.Eventually(func() int32 {
+		if node := getSniNode(); node != nil && len(node.PoolRefs) > 0 {
+			return node.PoolRefs[0].MaxConcurrentConns
+		}
+		return -1
+	}, 25*time.Second).Should(gomega.Equal(int32(0)))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
 func TestHTTPRuleHostSwitch(t *testing.T) {
 	// ingress foo.com/foo voo.com/foo
 	// hr1: foo.com (secure), hr2: voo.com (insecure)
@@ -635,3 +1067,189 @@ func TestHTTPRuleHostSwitch(t *testing.T) {
 	integrationtest.TeardownHTTPRule(t, rrnameFoo)
 	TearDownIngressForCacheSyncCheck(t, modelName)
 }
+
+func admissionRequestFor(t *testing.T, kind, version string, obj metav1.Object, object interface{}) *admissionv1beta1.AdmissionRequest {
+	raw, err := json.Marshal(object)
+	if err != nil {
+		t.Fatalf("error in marshalling %s: %v", kind, err)
+	}
+	return &admissionv1beta1.AdmissionRequest{
+		UID:       types.UID(obj.GetNamespace() + "/" + obj.GetName()),
+		Kind:      metav1.GroupVersionKind{Version: version, Kind: kind},
+		Name:      obj.GetName(),
+		Namespace: obj.GetNamespace(),
+		Operation: admissionv1beta1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+}
+
+func TestValidatingWebhookHostRuleAndIngress(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	hrname := "samplehr-foo"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+	integrationtest.SetupHostRule(t, hrname, "foo.com", true)
+	g.Eventually(func() string {
+		hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		return hostrule.Status.Status
+	}, 10*time.Second).Should(gomega.Equal("Accepted"))
+
+	webhook := k8s.NewValidatingWebhookModel()
+
+	// the accepted hostrule is allowed to be updated with the same fqdn
+	hostrule, _ := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+	response := webhook.Review(admissionRequestFor(t, lib.HostRule, "v1alpha1", hostrule, hostrule))
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	// another hostrule with the same fqdn is denied
+	duplicateHR := integrationtest.FakeHostRule{
+		Name:      "samplehr-foo-duplicate",
+		Namespace: "default",
+		Fqdn:      "foo.com",
+	}.HostRule()
+	response = webhook.Review(admissionRequestFor(t, lib.HostRule, "v1alpha1", duplicateHR, duplicateHR))
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Result.Message).To(gomega.ContainSubstring("duplicate fqdn foo.com found in default/samplehr-foo"))
+
+	// a hostrule with a ref not present on the controller is denied
+	badRefHR := integrationtest.FakeHostRule{
+		Name:      "samplehr-voo",
+		Namespace: "default",
+		Fqdn:      "voo.com",
+		WafPolicy: "thisisBADaviref",
+	}.HostRule()
+	response = webhook.Review(admissionRequestFor(t, lib.HostRule, "v1alpha1", badRefHR, badRefHR))
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+
+	// an ingress using the host/path of foo-with-targets is allowed by default, and denied with
+	// webhookDenyDuplicateHostPath, while a different path is allowed
+	conflictingIngress := (integrationtest.FakeIngress{
+		Name:        "foo-conflict",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	response = webhook.Review(admissionRequestFor(t, utils.Ingress, "v1beta1", conflictingIngress, conflictingIngress))
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	os.Setenv(lib.WEBHOOK_DENY_DUP_HOSTPATH, "true")
+	defer os.Unsetenv(lib.WEBHOOK_DENY_DUP_HOSTPATH)
+	response = webhook.Review(admissionRequestFor(t, utils.Ingress, "v1beta1", conflictingIngress, conflictingIngress))
+	g.Expect(response.Allowed).To(gomega.BeFalse())
+	g.Expect(response.Result.Message).To(gomega.ContainSubstring("hostpath foo.com/foo is already used by ingress default/foo-with-targets"))
+
+	nonConflictingIngress := (integrationtest.FakeIngress{
+		Name:        "foo-conflict",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Paths:       []string{"/bar"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	response = webhook.Review(admissionRequestFor(t, utils.Ingress, "v1beta1", nonConflictingIngress, nonConflictingIngress))
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+
+	// an update of foo-with-targets is allowed, even if the hostpath store still has the host/path
+	// of an ingress that has been deleted
+	avinodes.SharedHostNameLister().SaveHostPathStore("foo.com", "/foo", "default/foo-deleted")
+	ownerIngress := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		Paths:       []string{"/foo"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	updateRequest := admissionRequestFor(t, utils.Ingress, "v1beta1", ownerIngress, ownerIngress)
+	updateRequest.Operation = admissionv1beta1.Update
+	response = webhook.Review(updateRequest)
+	g.Expect(response.Allowed).To(gomega.BeTrue())
+	avinodes.SharedHostNameLister().RemoveHostPathStore("foo.com", "/foo", "default/foo-deleted")
+
+	sniVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com"}
+	integrationtest.TeardownHostRule(t, g, sniVSKey, hrname)
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
+
+func TestWildcardAndRegexHostRule(t *testing.T) {
+	// Exact, Wildcard and Regex hostrules matching foo.com are applied in this order of precedence
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpIngressForCacheSyncCheck(t, true, true, modelName)
+
+	setupHostRule := func(hrname, fqdn string, fqdnType akov1alpha1.FqdnType, wafPolicy string) {
+		hostrule := integrationtest.FakeHostRule{
+			Name:              hrname,
+			Namespace:         "default",
+			Fqdn:              fqdn,
+			SslKeyCertificate: "thisisaviref-sslkey",
+			WafPolicy:         wafPolicy,
+		}.HostRule()
+		hostrule.Spec.VirtualHost.FqdnType = fqdnType
+		if _, err := CRDClient.AkoV1alpha1().HostRules("default").Create(context.TODO(), hostrule, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding HostRule: %v", err)
+		}
+	}
+	getHostRuleStatus := func(hrname string) akov1alpha1.HostRuleStatus {
+		hostrule, err := CRDClient.AkoV1alpha1().HostRules("default").Get(context.TODO(), hrname, metav1.GetOptions{})
+		if err != nil {
+			return akov1alpha1.HostRuleStatus{}
+		}
+		return hostrule.Status
+	}
+	getWafPolicyRef := func() string {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 1 && len(nodes[0].SniNodes) == 1 {
+			return nodes[0].SniNodes[0].WafPolicyRef
+		}
+		return ""
+	}
+
+	setupHostRule("samplehr-regex", `f.+\.com`, akov1alpha1.FqdnTypeRegex, "thisisaviref-waf-regex")
+	g.Eventually(getWafPolicyRef, 20*time.Second).Should(gomega.Equal("/api/wafpolicy?name=thisisaviref-waf-regex"))
+	g.Eventually(func() []string {
+		return getHostRuleStatus("samplehr-regex").Fqdns
+	}, 20*time.Second).Should(gomega.Equal([]string{"foo.com"}))
+
+	// the fqdnType of a fqdn starting with *. defaults to Wildcard
+	setupHostRule("samplehr-wildcard", "*.com", "", "thisisaviref-waf-wildcard")
+	g.Eventually(getWafPolicyRef, 20*time.Second).Should(gomega.Equal("/api/wafpolicy?name=thisisaviref-waf-wildcard"))
+	g.Eventually(func() []string {
+		return getHostRuleStatus("samplehr-wildcard").Fqdns
+	}, 20*time.Second).Should(gomega.Equal([]string{"foo.com"}))
+	g.Eventually(func() []string {
+		return getHostRuleStatus("samplehr-regex").Fqdns
+	}, 20*time.Second).Should(gomega.BeEmpty())
+
+	setupHostRule("samplehr-foo", "foo.com", "", "thisisaviref-waf")
+	g.Eventually(getWafPolicyRef, 20*time.Second).Should(gomega.Equal("/api/wafpolicy?name=thisisaviref-waf"))
+	g.Eventually(func() []string {
+		return getHostRuleStatus("samplehr-foo").Fqdns
+	}, 20*time.Second).Should(gomega.Equal([]string{"foo.com"}))
+
+	// invalid wildcard and regex fqdns are rejected
+	setupHostRule("samplehr-badwildcard", "foo.*.com", akov1alpha1.FqdnTypeWildcard, "thisisaviref-waf")
+	setupHostRule("samplehr-badregex", "f(.com", akov1alpha1.FqdnTypeRegex, "thisisaviref-waf")
+	g.Eventually(func() string {
+		return getHostRuleStatus("samplehr-badwildcard").Status
+	}, 20*time.Second).Should(gomega.Equal(lib.StatusRejected))
+	g.Eventually(func() string {
+		return getHostRuleStatus("samplehr-badregex").Status
+	}, 20*time.Second).Should(gomega.Equal(lib.StatusRejected))
+	integrationtest.TearDownHostRuleWithNoVerif(t, g, "samplehr-badwildcard")
+	integrationtest.TearDownHostRuleWithNoVerif(t, g, "samplehr-badregex")
+
+	// deleting the hostrules falls back to the hostrule with the next precedence
+	integrationtest.TearDownHostRuleWithNoVerif(t, g, "samplehr-foo")
+	g.Eventually(getWafPolicyRef, 20*time.Second).Should(gomega.Equal("/api/wafpolicy?name=thisisaviref-waf-wildcard"))
+	integrationtest.TearDownHostRuleWithNoVerif(t, g, "samplehr-wildcard")
+	g.Eventually(getWafPolicyRef, 20*time.Second).Should(gomega.Equal("/api/wafpolicy?name=thisisaviref-waf-regex"))
+	integrationtest.TearDownHostRuleWithNoVerif(t, g, "samplehr-regex")
+	g.Eventually(getWafPolicyRef, 20*time.Second).Should(gomega.Equal(""))
+
+	TearDownIngressForCacheSyncCheck(t, modelName)
+}
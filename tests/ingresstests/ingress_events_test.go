@@ -0,0 +1,119 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// setupFakeEventRecorder replaces the AKO event recorder, since the events sent to the fake clientset
+// through the broadcaster sink are rejected for the namespace mismatch.
+func setupFakeEventRecorder(g *gomega.WithT) (*record.FakeRecorder, func()) {
+	// wait for the controller to set up its recorder, so that it does not overwrite the fake one.
+	g.Eventually(func() bool {
+		return lib.GetEventRecorder() != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+	recorder := lib.GetEventRecorder()
+	fakeRecorder := record.NewFakeRecorder(100)
+	lib.SetEventRecorder(fakeRecorder)
+	return fakeRecorder, func() { lib.SetEventRecorder(recorder) }
+}
+
+func waitForEvent(g *gomega.WithT, recorder *record.FakeRecorder, reason string) {
+	g.Eventually(func() bool {
+		for {
+			select {
+			case event := <-recorder.Events:
+				if strings.Contains(event, reason) {
+					return true
+				}
+			default:
+				return false
+			}
+		}
+	}, 30*time.Second).Should(gomega.Equal(true))
+}
+
+func TestInvalidHostnameEvent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	recorder, restoreRecorder := setupFakeEventRecorder(g)
+	defer restoreRecorder()
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-invalid-host",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.org"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+
+	waitForEvent(g, recorder, corev1.EventTypeWarning+" "+lib.InvalidHostname)
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "foo-invalid-host", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestSecretNotFoundEvent(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	recorder, restoreRecorder := setupFakeEventRecorder(g)
+	defer restoreRecorder()
+
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-missing-secret",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+		TlsSecretDNS: map[string][]string{
+			"missing-secret": {"foo.com"},
+		},
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+
+	waitForEvent(g, recorder, corev1.EventTypeWarning+" "+lib.SecretNotFound)
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "foo-missing-secret", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	TearDownTestForIngress(t, modelName)
+}
@@ -0,0 +1,230 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingresstests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	networking "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func pathTypeIngress(name string, tlsSecretDNS map[string][]string) *networking.Ingress {
+	ingress := (integrationtest.FakeIngress{
+		Name:         name,
+		Namespace:    "default",
+		DnsNames:     []string{"foo.com"},
+		Ips:          []string{"8.8.8.8"},
+		Paths:        []string{"/foo", "/bar"},
+		HostNames:    []string{"v1"},
+		TlsSecretDNS: tlsSecretDNS,
+		ServiceName:  "avisvc",
+	}).IngressMultiPath()
+	exact, prefix := networking.PathTypeExact, networking.PathTypePrefix
+	ingress.Spec.Rules[0].HTTP.Paths[0].PathType = &exact
+	ingress.Spec.Rules[0].HTTP.Paths[1].PathType = &prefix
+	return ingress
+}
+
+func TestSniHttpPolicyPathType(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	integrationtest.AddSecret("my-secret", "default", "tlsCert", "tlsKey")
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := pathTypeIngress("ingress-pathtype", map[string][]string{"my-secret": {"foo.com"}})
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) == 1 && len(nodes[0].SniNodes) == 1 {
+				return len(nodes[0].SniNodes[0].HttpPolicyRefs)
+			}
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	for _, policy := range nodes[0].SniNodes[0].HttpPolicyRefs {
+		switch policy.Name {
+		case "cluster--default-foo.com_foo-ingress-pathtype":
+			g.Expect(policy.HppMap).To(gomega.HaveLen(1))
+			g.Expect(policy.HppMap[0].MatchCriteria).To(gomega.Equal("EQUALS"))
+			g.Expect(policy.HppMap[0].Path).To(gomega.Equal([]string{"/foo"}))
+		case "cluster--default-foo.com_bar-ingress-pathtype":
+			g.Expect(policy.HppMap).To(gomega.HaveLen(2))
+			g.Expect(policy.HppMap[0].MatchCriteria).To(gomega.Equal("EQUALS"))
+			g.Expect(policy.HppMap[0].Path).To(gomega.Equal([]string{"/bar"}))
+			g.Expect(policy.HppMap[1].MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+			g.Expect(policy.HppMap[1].Path).To(gomega.Equal([]string{"/bar/"}))
+			g.Expect(policy.HppMap[1].PoolGroup).To(gomega.Equal(policy.HppMap[0].PoolGroup))
+		default:
+			t.Fatalf("unexpected http policy %s", policy.Name)
+		}
+	}
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "ingress-pathtype", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	KubeClient.CoreV1().Secrets("default").Delete(context.TODO(), "my-secret", metav1.DeleteOptions{})
+	VerifySNIIngressDeletion(t, g, aviModel, 0)
+
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestShardVSExactPathPolicy(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := pathTypeIngress("ingress-exact", nil)
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	exactPolicyName := lib.GetL7ExactPathPolicy(lib.GetL7PoolName("foo.com/foo", "default", "ingress-exact", ""))
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			for _, policy := range nodes[0].HttpPolicyRefs {
+				if policy.Name == exactPolicyName {
+					return true
+				}
+			}
+		}
+		return false
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].PoolRefs).To(gomega.HaveLen(2))
+	var exactPolicies int
+	for _, policy := range nodes[0].HttpPolicyRefs {
+		if policy.Name != exactPolicyName {
+			continue
+		}
+		exactPolicies++
+		g.Expect(policy.HppMap).To(gomega.HaveLen(1))
+		g.Expect(policy.HppMap[0].Host).To(gomega.Equal([]string{"foo.com"}))
+		g.Expect(policy.HppMap[0].Path).To(gomega.Equal([]string{"/foo/"}))
+		g.Expect(policy.HppMap[0].LocalRspStatusCode).To(gomega.Equal(lib.STATUS_NOT_FOUND))
+	}
+	g.Expect(exactPolicies).To(gomega.Equal(1))
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "ingress-exact", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	VerifyIngressDeletion(t, g, aviModel, 0)
+	g.Eventually(func() int {
+		_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		return len(nodes[0].HttpPolicyRefs)
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	TearDownTestForIngress(t, modelName)
+}
+
+func TestShardVSExactPathPolicyWithSubPathIngress(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	SetUpTestForIngress(t, modelName)
+
+	ingrFake := pathTypeIngress("ingress-exact", nil)
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	subPathIngress := (integrationtest.FakeIngress{
+		Name:        "ingress-subpath",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		Paths:       []string{"/foo/bar"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	prefix := networking.PathTypePrefix
+	subPathIngress.Spec.Rules[0].HTTP.Paths[0].PathType = &prefix
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), subPathIngress, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	exactPolicyName := lib.GetL7ExactPathPolicy(lib.GetL7PoolName("foo.com/foo", "default", "ingress-exact", ""))
+	subPathPoolName := lib.GetL7PoolName("foo.com/foo/bar", "default", "ingress-subpath", "")
+	getExactPolicy := func() *avinodes.AviHttpPolicySetNode {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			for _, policy := range nodes[0].HttpPolicyRefs {
+				if policy.Name == exactPolicyName {
+					return policy
+				}
+			}
+		}
+		return nil
+	}
+	// The sub paths claimed by the other Ingress are switched to its pool ahead of the 404 rule.
+	g.Eventually(func() int {
+		if policy := getExactPolicy(); policy != nil {
+			return len(policy.HppMap)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(3))
+
+	policy := getExactPolicy()
+	g.Expect(policy.HppMap[0].MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(policy.HppMap[0].Path).To(gomega.Equal([]string{"/foo/bar/"}))
+	g.Expect(policy.HppMap[0].Pool).To(gomega.Equal(subPathPoolName))
+	g.Expect(policy.HppMap[1].MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(policy.HppMap[1].Path).To(gomega.Equal([]string{"/foo/bar"}))
+	g.Expect(policy.HppMap[1].Pool).To(gomega.Equal(subPathPoolName))
+	g.Expect(policy.HppMap[2].MatchCriteria).To(gomega.Equal("BEGINS_WITH"))
+	g.Expect(policy.HppMap[2].Path).To(gomega.Equal([]string{"/foo/"}))
+	g.Expect(policy.HppMap[2].LocalRspStatusCode).To(gomega.Equal(lib.STATUS_NOT_FOUND))
+
+	// Once the other Ingress is removed, all the sub paths of the Exact path are served a 404 again.
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "ingress-subpath", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() int {
+		if policy := getExactPolicy(); policy != nil {
+			return len(policy.HppMap)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	g.Expect(getExactPolicy().HppMap[0].Path).To(gomega.Equal([]string{"/foo/"}))
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "ingress-exact", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	VerifyIngressDeletion(t, g, aviModel, 0)
+	g.Eventually(getExactPolicy, 30*time.Second).Should(gomega.BeNil())
+
+	TearDownTestForIngress(t, modelName)
+}
@@ -2647,6 +2647,88 @@ func TestL7WrongSubDomainMultiSNI(t *testing.T) {
 	TearDownTestForIngress(t, modelName)
 }
 
+func TestShardVSSizeConfigMapChange(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	modelName := "admin/cluster--Shared-L7-0"
+	dedicatedModelName := "admin/cluster--foo.com-dedicated"
+	SetUpTestForIngress(t, modelName, dedicatedModelName)
+
+	ingrFake := (integrationtest.FakeIngress{
+		Name:        "foo-with-targets",
+		Namespace:   "default",
+		DnsNames:    []string{"foo.com"},
+		Ips:         []string{"8.8.8.8"},
+		HostNames:   []string{"v1"},
+		ServiceName: "avisvc",
+	}).Ingress()
+	if _, err := KubeClient.NetworkingV1beta1().Ingresses("default").Create(context.TODO(), ingrFake, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs)
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(1))
+	mcache := cache.SharedAviObjCache()
+	sharedVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	dedicatedVSKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--foo.com-dedicated"}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(sharedVSKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(true))
+
+	// the hostname moves to a dedicated VS, and the shared VS is removed, without restarting AKO
+	integrationtest.UpdateConfigMap(t, KubeClient, map[string]string{"shardVSSize": "DEDICATED"})
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(dedicatedModelName)
+		return found && aviModel != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 30*time.Second).Should(gomega.Equal(false))
+	g.Expect(lib.GetshardSize()).To(gomega.Equal(uint32(0)))
+	// the shared VS is deleted from the controller as well
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(sharedVSKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(dedicatedVSKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	// and moves back to the shared VS with the original shard size
+	integrationtest.UpdateConfigMap(t, KubeClient, map[string]string{"shardVSSize": "LARGE"})
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(modelName)
+		return found && aviModel != nil
+	}, 30*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(dedicatedModelName)
+		return found && aviModel != nil
+	}, 30*time.Second).Should(gomega.Equal(false))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(dedicatedVSKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(sharedVSKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	g.Expect(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs).To(gomega.HaveLen(1))
+
+	if err := KubeClient.NetworkingV1beta1().Ingresses("default").Delete(context.TODO(), "foo-with-targets", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	VerifyIngressDeletion(t, g, aviModel, 0)
+	TearDownTestForIngress(t, modelName, dedicatedModelName)
+}
+
 func TestClusterRuntimeUpSinceChange(t *testing.T) {
 	g := gomega.NewGomegaWithT(t)
 	onBootup := true
@@ -0,0 +1,270 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package ingressv1tests
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var ctrl *k8s.AviController
+
+// TestMain runs the suite against a cluster which serves the networking.k8s.io/v1 Ingress API, so that the
+// Ingresses and IngressClasses are read and patched as networking.k8s.io/v1 objects.
+func TestMain(m *testing.M) {
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+
+	KubeClient = k8sfake.NewSimpleClientset()
+	KubeClient.Resources = []*metav1.APIResourceList{{
+		GroupVersion: utils.IngressAPIVersionV1,
+		APIResources: []metav1.APIResource{
+			{Name: "ingresses", Kind: "Ingress", Namespaced: true},
+			{Name: "ingressclasses", Kind: "IngressClass"},
+		},
+	}}
+	CRDClient = crdfake.NewSimpleClientset()
+	lib.SetCRDClientset(CRDClient)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers(CRDClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	cloudObj.NSIpamDNS = []string{"avi.internal", ".com"}
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	addDefaultIngressClass()
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+func addDefaultIngressClass() {
+	ingressClass := &networkingv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: integrationtest.DefaultIngressClass,
+			Annotations: map[string]string{
+				lib.DefaultIngressClassAnnotation: "true",
+			},
+		},
+		Spec: networkingv1.IngressClassSpec{
+			Controller: lib.AviIngressController,
+		},
+	}
+	KubeClient.NetworkingV1().IngressClasses().Create(context.TODO(), ingressClass, metav1.CreateOptions{})
+}
+
+func setUpTestForIngress(t *testing.T, modelName string) {
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.CreateSVC(t, "default", "avisvc", corev1.ServiceTypeClusterIP, false)
+	integrationtest.CreateEP(t, "default", "avisvc", false, false, "1.1.1")
+}
+
+func tearDownTestForIngress(t *testing.T, modelName string) {
+	objects.SharedAviGraphLister().Delete(modelName)
+	integrationtest.DelSVC(t, "default", "avisvc")
+	integrationtest.DelEP(t, "default", "avisvc")
+}
+
+// v1Ingress returns an Ingress for foo.com, with the /foo path to the service port number 8080 and the
+// /bar path to the same service port, by its name foo0.
+func v1Ingress(name string) *networkingv1.Ingress {
+	prefix, exact := networkingv1.PathTypePrefix, networkingv1.PathTypeExact
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+		Spec: networkingv1.IngressSpec{
+			Rules: []networkingv1.IngressRule{{
+				Host: "foo.com",
+				IngressRuleValue: networkingv1.IngressRuleValue{
+					HTTP: &networkingv1.HTTPIngressRuleValue{
+						Paths: []networkingv1.HTTPIngressPath{
+							{
+								Path:     "/foo",
+								PathType: &prefix,
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+									Name: "avisvc",
+									Port: networkingv1.ServiceBackendPort{Number: 8080},
+								}},
+							},
+							{
+								Path:     "/bar",
+								PathType: &exact,
+								Backend: networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{
+									Name: "avisvc",
+									Port: networkingv1.ServiceBackendPort{Name: "foo0"},
+								}},
+							},
+						},
+					},
+				},
+			}},
+		},
+	}
+}
+
+func TestIngressV1APIVersion(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(utils.GetIngressAPIVersion()).To(gomega.Equal(utils.IngressAPIVersionV1))
+}
+
+func TestIngressV1ServicePortNumberAndName(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	setUpTestForIngress(t, modelName)
+
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), v1Ingress("ingress-v1"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) == 1 {
+				return len(nodes[0].PoolRefs)
+			}
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	poolNames := make(map[string]bool)
+	for _, pool := range nodes[0].PoolRefs {
+		poolNames[pool.Name] = true
+		// Both the backends resolve to the port 8080 of the service, and its endpoints.
+		g.Expect(pool.Port).To(gomega.Equal(int32(8080)))
+		g.Expect(pool.Servers).To(gomega.HaveLen(1))
+		g.Expect(*pool.Servers[0].Ip.Addr).To(gomega.Equal("1.1.1.1"))
+	}
+	g.Expect(poolNames).To(gomega.HaveKey(lib.GetL7PoolName("foo.com/foo", "default", "ingress-v1", "")))
+	g.Expect(poolNames).To(gomega.HaveKey(lib.GetL7PoolName("foo.com/bar", "default", "ingress-v1", "")))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "ingress-v1", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	g.Eventually(func() int {
+		return len(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs)
+	}, 10*time.Second).Should(gomega.Equal(0))
+
+	tearDownTestForIngress(t, modelName)
+}
+
+func TestIngressV1StatusUpdate(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/cluster--Shared-L7-0"
+	setUpTestForIngress(t, modelName)
+
+	if _, err := KubeClient.NetworkingV1().Ingresses("default").Create(context.TODO(), v1Ingress("ingress-v1-status"), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	integrationtest.PollForCompletion(t, modelName, 5)
+
+	// The status is patched on the networking.k8s.io/v1 Ingress.
+	g.Eventually(func() int {
+		ingress, err := KubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "ingress-v1-status", metav1.GetOptions{})
+		if err != nil {
+			return 0
+		}
+		return len(ingress.Status.LoadBalancer.Ingress)
+	}, 30*time.Second).Should(gomega.Equal(1))
+	ingress, _ := KubeClient.NetworkingV1().Ingresses("default").Get(context.TODO(), "ingress-v1-status", metav1.GetOptions{})
+	g.Expect(ingress.Status.LoadBalancer.Ingress[0].IP).To(gomega.Equal("10.250.250.10"))
+	g.Expect(ingress.Status.LoadBalancer.Ingress[0].Hostname).To(gomega.Equal("foo.com"))
+
+	if err := KubeClient.NetworkingV1().Ingresses("default").Delete(context.TODO(), "ingress-v1-status", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("Couldn't DELETE the Ingress %v", err)
+	}
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--Shared-L7-0"}
+	g.Eventually(func() bool {
+		vsCache, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return true
+		}
+		return len(vsCache.(*cache.AviVsCache).PoolKeyCollection) == 0
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	tearDownTestForIngress(t, modelName)
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2019-2020 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package integrationtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/api/models"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getDryRunOps(vsKey cache.NamespaceName, method, model string) []models.PlannedRestOp {
+	var ops []models.PlannedRestOp
+	plan, _ := models.DryRun.GetPlan(vsKey.Namespace + "/" + vsKey.Name)
+	for _, op := range plan {
+		if op.Method == method && op.Model == model {
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// TestL4ServiceDryRun verifies that in the dry run mode the rest operations of a Service of type LoadBalancer are
+// recorded instead of being sent to the controller, that the avi object cache is populated from the synthesized
+// responses so that the subsequent updates are planned against the dry run uuids, and that the Service status
+// is not updated.
+func TestL4ServiceDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lib.SetDryRun(true)
+	defer lib.SetDryRun(false)
+
+	// the static VIP is present in the synthesized vsvip response, which would otherwise be set in the Service status
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := ConstructService(NAMESPACE, SINGLEPORTSVC, corev1.ServiceTypeLoadBalancer, false, make(map[string]string))
+	svcExample.Spec.LoadBalancerIP = "10.10.10.10"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+	vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	vsCacheObj := vsCache.(*cache.AviVsCache)
+	g.Expect(vsCacheObj.Uuid).To(gomega.HavePrefix("virtualservice-dryrun-"))
+	g.Expect(vsCacheObj.VSVipKeyCollection).To(gomega.HaveLen(1))
+	g.Expect(vsCacheObj.PoolKeyCollection).To(gomega.HaveLen(1))
+	poolCache, found := mcache.PoolCache.AviCacheGet(vsCacheObj.PoolKeyCollection[0])
+	g.Expect(found).To(gomega.Equal(true))
+	poolUuid := poolCache.(*cache.AviPoolCache).Uuid
+	g.Expect(poolUuid).To(gomega.HavePrefix("pool-dryrun-"))
+
+	g.Expect(getDryRunOps(vsKey, "POST", "VsVip")).To(gomega.HaveLen(1))
+	g.Expect(getDryRunOps(vsKey, "POST", "Pool")).To(gomega.HaveLen(1))
+	g.Expect(getDryRunOps(vsKey, "POST", "VirtualService")).To(gomega.HaveLen(1))
+
+	g.Consistently(func() int {
+		svc, err := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), SINGLEPORTSVC, metav1.GetOptions{})
+		if err != nil {
+			return -1
+		}
+		return len(svc.Status.LoadBalancer.Ingress)
+	}, 3*time.Second).Should(gomega.Equal(0))
+
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: SINGLEPORTSVC},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.2.3.14"}, {IP: "1.2.3.24"}},
+			Ports:     []corev1.EndpointPort{{Name: "foo", Port: 8080, Protocol: "TCP"}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Error in updating the Endpoint: %v", err)
+	}
+	// the pool is updated in place, using the uuid from the synthesized response
+	g.Eventually(func() []models.PlannedRestOp {
+		return getDryRunOps(vsKey, "PUT", "Pool")
+	}, 10*time.Second).ShouldNot(gomega.BeEmpty())
+	g.Expect(getDryRunOps(vsKey, "PUT", "Pool")[0].Path).To(gomega.Equal("/api/pool/" + poolUuid))
+	g.Expect(getDryRunOps(vsKey, "POST", "Pool")).To(gomega.HaveLen(1))
+
+	TearDownTestForSvcLB(t, g)
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.Equal(false))
+	g.Expect(getDryRunOps(vsKey, "DELETE", "VirtualService")).To(gomega.HaveLen(1))
+	_, found = mcache.PoolCache.AviCacheGet(vsCacheObj.PoolKeyCollection[0])
+	g.Expect(found).To(gomega.Equal(false))
+}
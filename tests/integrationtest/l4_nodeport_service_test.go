@@ -267,3 +267,131 @@ func TestMultiPortL4SvcNodePort(t *testing.T) {
 
 	TearDownTestForSvcLBMultiport(t, g)
 }
+
+// TestL4SvcNodePortLocalTrafficPolicyAndAffinity tests L4 service with externalTrafficPolicy Local and ClientIP session affinity
+func TestL4SvcNodePortLocalTrafficPolicyAndAffinity(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+	svcName := "testsvc-local"
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, svcName)
+
+	SetNodePortMode()
+	defer SetClusterIPMode()
+	CreateNode(t, "testNodeLocal1", "10.1.1.2")
+	defer DeleteNode(t, "testNodeLocal1")
+	CreateNode(t, "testNodeLocal2", "10.1.1.3")
+	defer DeleteNode(t, "testNodeLocal2")
+
+	svcExample := (FakeService{
+		Name:         svcName,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo0", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080, NodePort: 31031}},
+	}).Service()
+	timeoutSeconds := int32(600)
+	svcExample.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeLocal
+	svcExample.Spec.HealthCheckNodePort = 32000
+	svcExample.Spec.SessionAffinity = corev1.ServiceAffinityClientIP
+	svcExample.Spec.SessionAffinityConfig = &corev1.SessionAffinityConfig{
+		ClientIP: &corev1.ClientIPConfig{TimeoutSeconds: &timeoutSeconds},
+	}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	nodeName := "testNodeLocal1"
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: svcName},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.1.1.1", NodeName: &nodeName}},
+			Ports:     []corev1.EndpointPort{{Name: "foo0", Port: 8080, Protocol: "TCP"}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Create(context.TODO(), epExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+
+	// only the node running the endpoint is added as a pool server.
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].PoolRefs) > 0 {
+				return len(nodes[0].PoolRefs[0].Servers)
+			}
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(modelName)
+	pool := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0]
+	g.Expect(*pool.Servers[0].Ip.Addr).To(gomega.Equal("10.1.1.2"))
+	g.Expect(pool.HealthMonitorNode).NotTo(gomega.BeNil())
+	g.Expect(pool.HealthMonitorNode.Name).To(gomega.Equal(pool.Name))
+	g.Expect(pool.HealthMonitorNode.MonitorPort).To(gomega.Equal(int32(32000)))
+	g.Expect(pool.PersistenceProfile).NotTo(gomega.BeNil())
+	g.Expect(pool.PersistenceProfile.Name).To(gomega.Equal(pool.Name))
+	g.Expect(pool.PersistenceProfile.Timeout).To(gomega.Equal(int32(10)))
+
+	poolChildKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: pool.Name}
+	g.Eventually(func() bool {
+		_, found := mcache.PersistenceCache.AviCacheGet(poolChildKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(poolChildKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+
+	// the pool server follows the endpoint to the other node.
+	nodeName = "testNodeLocal2"
+	epExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Endpoint: %v", err)
+	}
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].PoolRefs) > 0 && len(nodes[0].PoolRefs[0].Servers) == 1 {
+				return *nodes[0].PoolRefs[0].Servers[0].Ip.Addr
+			}
+		}
+		return ""
+	}, 10*time.Second).Should(gomega.Equal("10.1.1.3"))
+
+	// with the Cluster policy and without affinity, all nodes are added and the persistence profile and health monitor are removed.
+	svcExample.Spec.ExternalTrafficPolicy = corev1.ServiceExternalTrafficPolicyTypeCluster
+	svcExample.Spec.HealthCheckNodePort = 0
+	svcExample.Spec.SessionAffinity = corev1.ServiceAffinityNone
+	svcExample.Spec.SessionAffinityConfig = nil
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].PoolRefs) > 0 {
+				return len(nodes[0].PoolRefs[0].Servers)
+			}
+		}
+		return 0
+	}, 10*time.Second).Should(gomega.Equal(2))
+	_, aviModel = objects.SharedAviGraphLister().Get(modelName)
+	pool = aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0]
+	g.Expect(pool.HealthMonitorNode).To(gomega.BeNil())
+	g.Expect(pool.PersistenceProfile).To(gomega.BeNil())
+	g.Eventually(func() bool {
+		_, found := mcache.PersistenceCache.AviCacheGet(poolChildKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(false))
+	g.Eventually(func() bool {
+		_, found := mcache.HealthMonitorCache.AviCacheGet(poolChildKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(false))
+
+	DelSVC(t, NAMESPACE, svcName)
+	DelEP(t, NAMESPACE, svcName)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, svcName)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(false))
+}
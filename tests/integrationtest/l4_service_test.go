@@ -23,6 +23,7 @@ import (
 	"os"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/rest"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
 
@@ -394,6 +396,217 @@ func TestAviSvcUpdateEndpoint(t *testing.T) {
 	TearDownTestForSvcLB(t, g)
 }
 
+func TestAviSvcUpdateEndpointWithServerDrain(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, SINGLEPORTSVC)
+	// The servers of a pool left over from an earlier test would be drained.
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().PoolCache.AviCacheGet(cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s--8080", NAMESPACE, SINGLEPORTSVC)})
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+	// The drain timeout is rounded up to whole minutes, the graceful disable timeout unit of the pools.
+	os.Setenv(lib.SERVER_DRAIN_TIMEOUT, "30")
+	defer os.Unsetenv(lib.SERVER_DRAIN_TIMEOUT)
+	g.Expect(lib.GetServerDrainTimeout()).To(gomega.Equal(time.Minute))
+
+	SetUpTestForSvcLB(t)
+
+	var poolKey cache.NamespaceName
+	getServers := func() []avinodes.AviPoolMetaServer {
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			pool := aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0]
+			poolKey = cache.NamespaceName{Namespace: pool.Tenant, Name: pool.Name}
+			return pool.Servers
+		}
+		return nil
+	}
+	g.Eventually(getServers, 5*time.Second).Should(gomega.HaveLen(1))
+
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: SINGLEPORTSVC},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{{IP: "1.2.3.14"}, {IP: "1.2.3.24"}},
+			Ports:     []corev1.EndpointPort{{Name: "foo", Port: 8080, Protocol: "TCP"}},
+		}},
+	}
+	epExample.ResourceVersion = "2"
+	drainStart := time.Now()
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Error in updating the Endpoint: %v", err)
+	}
+
+	// the removed server is disabled in the pool until the drain deadline
+	g.Eventually(getServers, 5*time.Second).Should(gomega.HaveLen(3))
+	var deadline time.Time
+	for _, server := range getServers() {
+		g.Expect(server.Disabled).To(gomega.Equal(*server.Ip.Addr == "1.1.1.1"))
+		if server.Disabled {
+			g.Expect(server.DrainDeadline).NotTo(gomega.BeNil())
+			deadline = *server.DrainDeadline
+		}
+	}
+	g.Expect(deadline).To(gomega.BeTemporally(">=", drainStart.Add(time.Minute)))
+	g.Expect(deadline).To(gomega.BeTemporally("<", drainStart.Add(time.Minute+5*time.Second)))
+
+	// the drain deadline is stored on the pool server in the controller, and read back into the pool cache
+	g.Eventually(func() time.Time {
+		return cache.SharedAviObjCache().GetPoolServers(poolKey)["1.1.1.1"].DrainDeadline
+	}, 5*time.Second).Should(gomega.BeTemporally("==", deadline))
+	g.Expect(cache.SharedAviObjCache().GetPoolServers(poolKey)["1.1.1.1"].Enabled).To(gomega.BeFalse())
+
+	// the server is deleted once the deadline in the pool cache expires
+	poolCache, _ := cache.SharedAviObjCache().PoolCache.AviCacheGet(poolKey)
+	poolCacheObj := *poolCache.(*cache.AviPoolCache)
+	poolCacheObj.Servers = map[string]cache.AviPoolServerCache{
+		"1.1.1.1": {Enabled: false, DrainDeadline: time.Now().Add(-time.Second)},
+	}
+	cache.SharedAviObjCache().PoolCache.AviCacheAdd(poolKey, &poolCacheObj)
+	ingestionQueue := utils.SharedWorkQueue().GetQueueByName(utils.ObjectIngestionLayer)
+	ingestionQueue.Workqueue[utils.Bkt(NAMESPACE, ingestionQueue.NumWorkers)].AddRateLimited(utils.Endpoints + "/" + NAMESPACE + "/" + SINGLEPORTSVC)
+
+	g.Eventually(getServers, 10*time.Second).Should(gomega.HaveLen(2))
+	for _, server := range getServers() {
+		g.Expect(server.Disabled).To(gomega.BeFalse())
+	}
+
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestAviSvcServerRatioByZone(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := fmt.Sprintf("%s/cluster--%s-%s", AVINAMESPACE, NAMESPACE, SINGLEPORTSVC)
+	os.Setenv(lib.ZONE_SERVER_RATIO, `{"zone-a":4,"zone-c":30}`)
+	defer os.Unsetenv(lib.ZONE_SERVER_RATIO)
+
+	zones := map[string]string{"testNodeZoneA": "zone-a", "testNodeZoneB": "zone-b", "testNodeZoneC": "zone-c"}
+	for nodeName, zone := range zones {
+		nodeExample := (FakeNode{Name: nodeName, PodCIDR: "10.244.0.0/24", Version: "1", NodeIP: "10.1.1.1"}).Node()
+		nodeExample.Labels = map[string]string{corev1.LabelZoneFailureDomainStable: zone}
+		if _, err := KubeClient.CoreV1().Nodes().Create(context.TODO(), nodeExample, metav1.CreateOptions{}); err != nil {
+			t.Fatalf("error in adding Node: %v", err)
+		}
+	}
+
+	SetUpTestForSvcLB(t)
+
+	nodeA, nodeB, nodeC := "testNodeZoneA", "testNodeZoneB", "testNodeZoneC"
+	epExample := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Namespace: NAMESPACE, Name: SINGLEPORTSVC},
+		Subsets: []corev1.EndpointSubset{{
+			Addresses: []corev1.EndpointAddress{
+				{IP: "1.2.3.14", NodeName: &nodeA},
+				{IP: "1.2.3.24", NodeName: &nodeB},
+				{IP: "1.2.3.34", NodeName: &nodeC},
+			},
+			Ports: []corev1.EndpointPort{{Name: "foo", Port: 8080, Protocol: "TCP"}},
+		}},
+	}
+	epExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Endpoints(NAMESPACE).Update(context.TODO(), epExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("Error in updating the Endpoint: %v", err)
+	}
+
+	// The ratio of zone-c is out of range, and zone-b has no ratio, their servers get the default ratio.
+	g.Eventually(func() map[string]int32 {
+		ratios := make(map[string]int32)
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			for _, server := range aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].PoolRefs[0].Servers {
+				ratios[*server.Ip.Addr] = server.Ratio
+			}
+		}
+		return ratios
+	}, 5*time.Second).Should(gomega.Equal(map[string]int32{"1.2.3.14": 4, "1.2.3.24": 0, "1.2.3.34": 0}))
+
+	TearDownTestForSvcLB(t, g)
+	for nodeName := range zones {
+		if err := KubeClient.CoreV1().Nodes().Delete(context.TODO(), nodeName, metav1.DeleteOptions{}); err != nil {
+			t.Fatalf("error in deleting Node: %v", err)
+		}
+	}
+}
+
+func TestAviSvcOperStatus(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	SetUpTestForSvcLB(t)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)}
+	var vsCacheObj *cache.AviVsCache
+	g.Eventually(func() int {
+		vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		if !found {
+			return 0
+		}
+		vsCacheObj = vsCache.(*cache.AviVsCache)
+		return len(vsCacheObj.PoolKeyCollection)
+	}, 10*time.Second).Should(gomega.Equal(1))
+	poolCache, found := mcache.PoolCache.AviCacheGet(vsCacheObj.PoolKeyCollection[0])
+	g.Expect(found).To(gomega.Equal(true))
+	poolCacheObj := poolCache.(*cache.AviPoolCache)
+
+	inventories := map[string]string{
+		"virtualservice-inventory": fmt.Sprintf(`{"count": 1, "results": [{"config": {"name": "%s", "uuid": "%s"}, "runtime": {"oper_status": {"state": "OPER_UP"}}}]}`,
+			vsCacheObj.Name, vsCacheObj.Uuid),
+		"pool-inventory": fmt.Sprintf(`{"count": 1, "results": [{"config": {"name": "%s", "uuid": "%s"}, "runtime": {"oper_status": {"state": "OPER_DOWN", "reason": ["No servers up"]}, "num_servers": 1, "num_servers_up": 0}}]}`,
+			poolCacheObj.Name, poolCacheObj.Uuid),
+	}
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		for inventory, resp := range inventories {
+			if r.Method == "GET" && strings.Contains(url, inventory) {
+				w.WriteHeader(http.StatusOK)
+				fmt.Fprintln(w, resp)
+				return
+			}
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	restlayer := rest.NewRestOperations(mcache, cache.SharedAVIClients())
+	g.Expect(mcache.AviOperStatusPopulate(cache.SharedAVIClients().AviClient[0], utils.CloudName)).To(gomega.Succeed())
+	restlayer.SyncObjectOperStatuses()
+
+	// The oper status is published through the status queue, and read back from the informer cache.
+	g.Eventually(func() string {
+		svc, err := utils.GetInformers().ServiceInformer.Lister().Services(NAMESPACE).Get(SINGLEPORTSVC)
+		if err != nil {
+			return ""
+		}
+		return svc.Annotations[status.OperStatusAnnotation]
+	}, 10*time.Second).ShouldNot(gomega.BeEmpty())
+	svc, err := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), SINGLEPORTSVC, metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	var operStatuses []status.VSOperStatus
+	g.Expect(json.Unmarshal([]byte(svc.Annotations[status.OperStatusAnnotation]), &operStatuses)).To(gomega.Succeed())
+	g.Expect(operStatuses).To(gomega.Equal([]status.VSOperStatus{{
+		VirtualService: vsCacheObj.Name,
+		State:          "OPER_UP",
+		Pools: []status.PoolOperStatus{{
+			Pool:      poolCacheObj.Name,
+			State:     "OPER_DOWN",
+			ServersUp: 0,
+			Servers:   1,
+		}},
+	}}))
+
+	// The annotation is removed once the virtualservice is no longer reported.
+	inventories = map[string]string{
+		"virtualservice-inventory": `{"count": 0, "results": []}`,
+		"pool-inventory":           `{"count": 0, "results": []}`,
+	}
+	g.Expect(mcache.AviOperStatusPopulate(cache.SharedAVIClients().AviClient[0], utils.CloudName)).To(gomega.Succeed())
+	restlayer.SyncObjectOperStatuses()
+
+	g.Eventually(func() map[string]string {
+		svc, _ := KubeClient.CoreV1().Services(NAMESPACE).Get(context.TODO(), SINGLEPORTSVC, metav1.GetOptions{})
+		return svc.Annotations
+	}, 10*time.Second).ShouldNot(gomega.HaveKey(status.OperStatusAnnotation))
+
+	TearDownTestForSvcLB(t, g)
+}
+
 // Rest Cache sync tests
 
 func TestCreateServiceLBCacheSync(t *testing.T) {
@@ -689,6 +902,213 @@ func TestAviSvcCreationWithStaticIP(t *testing.T) {
 	TearDownTestForSvcLB(t, g)
 }
 
+func TestAviSvcCreationIPv6(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	settingName := "infra-setting-v6"
+	staticIP := "2002::1234:abcd:ffff:c0a8:101"
+
+	settingCreate := (FakeAviInfraSetting{
+		Name:        settingName,
+		SeGroupName: "thisisaviref-seGroup",
+		Networks:    []string{"thisisaviref-networkName"},
+	}).AviInfraSetting()
+	settingCreate.Spec.Network.VipNetworks[0].V6Cidr = "2002::1234:abcd:ffff:c0a8:100/120"
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().AviInfraSettings().Create(context.TODO(), settingCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding AviInfraSetting: %v", err)
+	}
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := (FakeService{
+		Name:           SINGLEPORTSVC,
+		Namespace:      NAMESPACE,
+		Type:           corev1.ServiceTypeLoadBalancer,
+		LoadBalancerIP: staticIP,
+		ServicePorts:   []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol}
+	svcExample.Annotations = map[string]string{lib.InfraSettingNameAnnotation: settingName}
+	_, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].VSVIPRefs) > 0 {
+				return nodes[0].VSVIPRefs[0].IPType
+			}
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal(lib.IPTypeV6Only))
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].VSVIPRefs[0].IPAddress).Should(gomega.Equal(""))
+	g.Expect(nodes[0].VSVIPRefs[0].IPv6Address).Should(gomega.Equal(staticIP))
+	g.Expect(nodes[0].VSVIPRefs[0].VipNetworks[0].V6Cidr).Should(gomega.Equal("2002::1234:abcd:ffff:c0a8:100/120"))
+
+	TeardownAviInfraSetting(t, settingName)
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestAviSvcCreationDualStack(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	settingName := "infra-setting-dualstack"
+
+	settingCreate := (FakeAviInfraSetting{
+		Name:        settingName,
+		SeGroupName: "thisisaviref-seGroup",
+		Networks:    []string{"thisisaviref-networkName"},
+	}).AviInfraSetting()
+	settingCreate.Spec.Network.VipNetworks[0].V6Cidr = "2002::1234:abcd:ffff:c0a8:100/120"
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().AviInfraSettings().Create(context.TODO(), settingCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding AviInfraSetting: %v", err)
+	}
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	requireDualStack := corev1.IPFamilyPolicyRequireDualStack
+	svcExample := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv4Protocol, corev1.IPv6Protocol}
+	svcExample.Spec.IPFamilyPolicy = &requireDualStack
+	svcExample.Annotations = map[string]string{lib.InfraSettingNameAnnotation: settingName}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].VSVIPRefs) > 0 {
+				return nodes[0].VSVIPRefs[0].IPType
+			}
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal(lib.IPTypeV4V6))
+
+	// a SingleStack Service only gets its primary family.
+	singleStack := corev1.IPFamilyPolicySingleStack
+	svcExample.Spec.IPFamilies = []corev1.IPFamily{corev1.IPv6Protocol, corev1.IPv4Protocol}
+	svcExample.Spec.IPFamilyPolicy = &singleStack
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 && len(nodes[0].VSVIPRefs) > 0 {
+				return nodes[0].VSVIPRefs[0].IPType
+			}
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal(lib.IPTypeV6Only))
+
+	TeardownAviInfraSetting(t, settingName)
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestAviInfraSettingInvalidV6Cidr(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	settingName := "infra-setting-invalid-v6"
+
+	settingCreate := (FakeAviInfraSetting{
+		Name:        settingName,
+		SeGroupName: "thisisaviref-seGroup",
+		Networks:    []string{"thisisaviref-networkName"},
+	}).AviInfraSetting()
+	settingCreate.Spec.Network.VipNetworks[0].V6Cidr = "10.10.10.0/24"
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().AviInfraSettings().Create(context.TODO(), settingCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding AviInfraSetting: %v", err)
+	}
+
+	// a v4 CIDR in the v6Cidr field is rejected.
+	g.Eventually(func() string {
+		setting, _ := lib.GetCRDClientset().AkoV1alpha1().AviInfraSettings().Get(context.TODO(), settingName, metav1.GetOptions{})
+		return setting.Status.Status
+	}, 40*time.Second).Should(gomega.Equal("Rejected"))
+
+	settingCreate.Spec.Network.VipNetworks[0].V6Cidr = "2002::1234:abcd:ffff:c0a8:100/120"
+	settingCreate.ResourceVersion = "2"
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().AviInfraSettings().Update(context.TODO(), settingCreate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating AviInfraSetting: %v", err)
+	}
+	g.Eventually(func() string {
+		setting, _ := lib.GetCRDClientset().AkoV1alpha1().AviInfraSettings().Get(context.TODO(), settingName, metav1.GetOptions{})
+		return setting.Status.Status
+	}, 40*time.Second).Should(gomega.Equal("Accepted"))
+
+	TeardownAviInfraSetting(t, settingName)
+}
+
+func TestAviSvcCreationSourceRanges(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	mcache := cache.SharedAviObjCache()
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Spec.LoadBalancerSourceRanges = []string{"10.10.0.0/16", "10.20.0.0"}
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	g.Eventually(func() int {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+			if len(nodes) > 0 {
+				return len(nodes[0].NSPolicyRefs)
+			}
+		}
+		return 0
+	}, 40*time.Second).Should(gomega.Equal(1))
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NSPolicyRefs[0].Name).To(gomega.Equal(vsName))
+	g.Expect(nodes[0].NSPolicyRefs[0].SourceRanges).To(gomega.Equal([]string{"10.10.0.0/16"}))
+
+	g.Eventually(func() int {
+		if vsCache, found := mcache.VsCacheMeta.AviCacheGet(vsKey); found {
+			return len(vsCache.(*cache.AviVsCache).NSPolicyCollection)
+		}
+		return 0
+	}, 40*time.Second).Should(gomega.Equal(1))
+	nspKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	_, found := mcache.NSPolicyCache.AviCacheGet(nspKey)
+	g.Expect(found).To(gomega.Equal(true))
+
+	// removing the source ranges deletes the network security policy.
+	svcExample.Spec.LoadBalancerSourceRanges = nil
+	svcExample.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Services(NAMESPACE).Update(context.TODO(), svcExample, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Service: %v", err)
+	}
+	g.Eventually(func() bool {
+		_, found := mcache.NSPolicyCache.AviCacheGet(nspKey)
+		return found
+	}, 40*time.Second).Should(gomega.Equal(false))
+	vsCache, _ := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(vsCache.(*cache.AviVsCache).NSPolicyCollection).To(gomega.HaveLen(0))
+
+	TearDownTestForSvcLB(t, g)
+}
+
 // Infra CRD tests via service annotation
 
 func TestWithInfraSettingStatusUpdates(t *testing.T) {
@@ -1002,3 +1422,245 @@ func TestBootupServiceLBStatusPersistence(t *testing.T) {
 
 	TearDownTestForSvcLB(t, g)
 }
+
+// L4Rule CRD tests via service annotation
+
+func TestL4RuleCreateAndDelete(t *testing.T) {
+	// create svcLB with l4rule annotation, create l4rule
+	// check for Accepted status, check layer 2 model for l4rule settings
+	// delete l4rule, fallback to defaults
+
+	g := gomega.NewGomegaWithT(t)
+	l4RuleName := "l4-rule"
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Annotations = map[string]string{lib.L4RuleNameAnnotation: l4RuleName}
+	_, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	SetupL4Rule(t, l4RuleName, NAMESPACE)
+
+	g.Eventually(func() string {
+		l4Rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), l4RuleName, metav1.GetOptions{})
+		return l4Rule.Status.Status
+	}, 15*time.Second).Should(gomega.Equal("Accepted"))
+
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].ApplicationProfile
+			}
+		}
+		return ""
+	}, 35*time.Second).Should(gomega.Equal("thisisaviref-l4appprof"))
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+	g.Expect(nodes[0].NetworkProfile).Should(gomega.Equal("thisisaviref-networkprof"))
+	g.Expect(nodes[0].AnalyticsProfileRef).Should(gomega.Equal("/api/analyticsprofile?name=thisisaviref-analyticsprof"))
+	g.Expect(nodes[0].PoolRefs).Should(gomega.HaveLen(1))
+	g.Expect(nodes[0].PoolRefs[0].LbAlgorithm).Should(gomega.Equal("LB_ALGORITHM_CONSISTENT_HASH"))
+	g.Expect(nodes[0].PoolRefs[0].LbAlgorithmHash).Should(gomega.Equal("LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS"))
+	g.Expect(nodes[0].PoolRefs[0].HealthMonitors).Should(gomega.HaveLen(2))
+	g.Expect(nodes[0].PoolRefs[0].HealthMonitors[0]).Should(gomega.Equal("/api/healthmonitor?name=thisisaviref-hm2"))
+	g.Expect(nodes[0].PoolRefs[0].HealthMonitors[1]).Should(gomega.Equal("/api/healthmonitor?name=thisisaviref-hm1"))
+
+	TeardownL4Rule(t, l4RuleName, NAMESPACE)
+
+	// defaults to the L4 application profile, tcp fast path and controller defaults for the pool.
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].ApplicationProfile == utils.DEFAULT_L4_APP_PROFILE &&
+					nodes[0].NetworkProfile == utils.TCP_NW_FAST_PATH &&
+					nodes[0].AnalyticsProfileRef == "" &&
+					len(nodes[0].PoolRefs) == 1 &&
+					nodes[0].PoolRefs[0].LbAlgorithm == "" &&
+					len(nodes[0].PoolRefs[0].HealthMonitors) == 0
+			}
+		}
+		return false
+	}, 35*time.Second).Should(gomega.Equal(true))
+
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestL4RuleWithInvalidRefs(t *testing.T) {
+	// create svcLB with l4rule annotation, create l4rule with a HTTP application profile
+	// check for Rejected status, check layer 2 for defaults
+	// update to a L4 application profile, check for Accepted status and layer 2 model
+
+	g := gomega.NewGomegaWithT(t)
+	l4RuleName := "l4-rule"
+
+	objects.SharedAviGraphLister().Delete(SINGLEPORTMODEL)
+	svcExample := (FakeService{
+		Name:         SINGLEPORTSVC,
+		Namespace:    NAMESPACE,
+		Type:         corev1.ServiceTypeLoadBalancer,
+		ServicePorts: []Serviceport{{PortName: "foo1", Protocol: "TCP", PortNumber: 8080, TargetPort: 8080}},
+	}).Service()
+	svcExample.Annotations = map[string]string{lib.L4RuleNameAnnotation: l4RuleName}
+	_, err := KubeClient.CoreV1().Services(NAMESPACE).Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in creating Service: %v", err)
+	}
+	CreateEP(t, NAMESPACE, SINGLEPORTSVC, false, false, "1.1.1")
+	PollForCompletion(t, SINGLEPORTMODEL, 5)
+
+	l4RuleCreate := (FakeL4Rule{
+		Name:               l4RuleName,
+		Namespace:          NAMESPACE,
+		ApplicationProfile: "thisisaviref-appprof",
+		NetworkProfile:     "thisisaviref-networkprof",
+	}).L4Rule()
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Create(context.TODO(), l4RuleCreate, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding L4Rule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		l4Rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), l4RuleName, metav1.GetOptions{})
+		return l4Rule.Status.Status
+	}, 15*time.Second).Should(gomega.Equal("Rejected"))
+	l4Rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), l4RuleName, metav1.GetOptions{})
+	g.Expect(l4Rule.Status.Error).Should(gomega.ContainSubstring("must be of type: APPLICATION_PROFILE_TYPE_L4"))
+
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].ApplicationProfile == utils.DEFAULT_L4_APP_PROFILE &&
+					nodes[0].NetworkProfile == utils.TCP_NW_FAST_PATH
+			}
+		}
+		return false
+	}, 35*time.Second).Should(gomega.Equal(true))
+
+	l4RuleUpdate := (FakeL4Rule{
+		Name:               l4RuleName,
+		Namespace:          NAMESPACE,
+		ApplicationProfile: "thisisaviref-l4appprof",
+		NetworkProfile:     "thisisaviref-networkprof",
+	}).L4Rule()
+	l4RuleUpdate.ResourceVersion = "2"
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().L4Rules(NAMESPACE).Update(context.TODO(), l4RuleUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating L4Rule: %v", err)
+	}
+
+	g.Eventually(func() string {
+		l4Rule, _ := CRDClient.AkoV1alpha1().L4Rules(NAMESPACE).Get(context.TODO(), l4RuleName, metav1.GetOptions{})
+		return l4Rule.Status.Status
+	}, 15*time.Second).Should(gomega.Equal("Accepted"))
+
+	g.Eventually(func() bool {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+				return nodes[0].ApplicationProfile == "thisisaviref-l4appprof" &&
+					nodes[0].NetworkProfile == "thisisaviref-networkprof"
+			}
+		}
+		return false
+	}, 35*time.Second).Should(gomega.Equal(true))
+
+	TeardownL4Rule(t, l4RuleName, NAMESPACE)
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestL4ServiceWithLayer7OnlyConfigMapChange(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	SetUpTestForSvcLB(t)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+
+	// the L4 virtualservice gets deleted once layer7Only is enabled, without restarting AKO
+	UpdateConfigMap(t, KubeClient, map[string]string{lib.LAYER7_ONLY: "true"})
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+		return found && aviModel != nil
+	}, 30*time.Second).Should(gomega.Equal(false))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+
+	// and gets recreated once layer7Only is disabled again
+	UpdateConfigMap(t, KubeClient, map[string]string{lib.LAYER7_ONLY: "false"})
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+	g.Expect(lib.GetLayer7Only()).To(gomega.Equal(false))
+
+	UpdateConfigMap(t, KubeClient, nil)
+	TearDownTestForSvcLB(t, g)
+}
+
+func TestServiceEngineGroupConfigMapChange(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	vsName := fmt.Sprintf("cluster--%s-%s", NAMESPACE, SINGLEPORTSVC)
+	var vsDeleted int32
+	AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "DELETE" && strings.Contains(r.URL.EscapedPath(), "/api/virtualservice/virtualservice-"+vsName+"-") {
+			atomic.StoreInt32(&vsDeleted, 1)
+		}
+		NormalControllerServer(w, r)
+	})
+	defer ResetMiddleware()
+
+	SetUpTestForSvcLB(t)
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: AVINAMESPACE, Name: vsName}
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 15*time.Second).Should(gomega.Equal(true))
+	_, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL)
+	g.Expect(aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].ServiceEngineGroup).To(gomega.Equal("Default-Group"))
+
+	// the virtualservice is deleted and recreated in the new SE group, without restarting AKO
+	UpdateConfigMap(t, KubeClient, map[string]string{"serviceEngineGroupName": "SEG-2"})
+	g.Eventually(func() bool {
+		return atomic.LoadInt32(&vsDeleted) == 1
+	}, 30*time.Second).Should(gomega.Equal(true))
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].ServiceEngineGroup
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal("SEG-2"))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+	g.Expect(lib.GetSEGName()).To(gomega.Equal("SEG-2"))
+
+	atomic.StoreInt32(&vsDeleted, 0)
+	UpdateConfigMap(t, KubeClient, map[string]string{"serviceEngineGroupName": "Default-Group"})
+	g.Eventually(func() string {
+		if found, aviModel := objects.SharedAviGraphLister().Get(SINGLEPORTMODEL); found && aviModel != nil {
+			return aviModel.(*avinodes.AviObjectGraph).GetAviVS()[0].ServiceEngineGroup
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal("Default-Group"))
+	g.Expect(atomic.LoadInt32(&vsDeleted)).To(gomega.Equal(int32(1)))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(true))
+
+	TearDownTestForSvcLB(t, g)
+}
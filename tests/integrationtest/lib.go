@@ -93,6 +93,19 @@ func AddConfigMap(client *k8sfake.Clientset) {
 	client.CoreV1().ConfigMaps(utils.GetAKONamespace()).Create(context.TODO(), aviCM, metav1.CreateOptions{})
 }
 
+// UpdateConfigMap replaces the data of the AKO configmap, to simulate an edit of the configmap while AKO is running.
+func UpdateConfigMap(t *testing.T, client *k8sfake.Clientset, data map[string]string) {
+	aviCM, err := client.CoreV1().ConfigMaps(utils.GetAKONamespace()).Get(context.TODO(), lib.AviConfigMap, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("error in getting configmap: %v", err)
+	}
+	aviCM.Data = data
+	aviCM.ResourceVersion = aviCM.ResourceVersion + "1"
+	if _, err = client.CoreV1().ConfigMaps(utils.GetAKONamespace()).Update(context.TODO(), aviCM, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating configmap: %v", err)
+	}
+}
+
 func AddDefaultIngressClass() {
 	aviIngressClass := &networking.IngressClass{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1025,10 +1038,13 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 			// handle sni child, fill in vs parent ref
 			if vsType := resp["type"]; vsType == "VS_TYPE_VH_CHILD" {
 				parentVSName := strings.Split(resp["vh_parent_vs_uuid"].(string), "name=")[1]
-				shardVSNum = strings.Split(parentVSName, "cluster--Shared-L7-")[1]
-
 				resp["vh_parent_vs_ref"] = fmt.Sprintf("https://localhost/api/virtualservice/virtualservice-%s-%s#%s", parentVSName, RANDOMUUID, parentVSName)
-				vipAddress = fmt.Sprintf("%s.1%s", addrPrefix, shardVSNum)
+				if strings.Contains(parentVSName, "cluster--Shared-L7-") {
+					shardVSNum = strings.Split(parentVSName, "cluster--Shared-L7-")[1]
+					vipAddress = fmt.Sprintf("%s.1%s", addrPrefix, shardVSNum)
+				} else {
+					vipAddress = "10.250.250.250"
+				}
 
 			} else if strings.Contains(rName, "Shared-L7-EVH-") {
 				shardVSNum = strings.Split(rName, "Shared-L7-EVH-")[1]
@@ -1091,7 +1107,11 @@ func NormalControllerServer(w http.ResponseWriter, r *http.Request, args ...stri
 
 	} else if r.Method == "GET" && strings.Contains(r.URL.RawQuery, "aviref") {
 		// block to handle
-		if strings.Contains(r.URL.RawQuery, "thisisaviref") {
+		if strings.Contains(r.URL.RawQuery, "thisisaviref-l4") {
+			w.WriteHeader(http.StatusOK)
+			data, _ := ioutil.ReadFile(fmt.Sprintf("%s/crd_l4_mock.json", mockFilePath))
+			w.Write(data)
+		} else if strings.Contains(r.URL.RawQuery, "thisisaviref") {
 			w.WriteHeader(http.StatusOK)
 			data, _ := ioutil.ReadFile(fmt.Sprintf("%s/crd_mock.json", mockFilePath))
 			w.Write(data)
@@ -1454,3 +1474,58 @@ func TeardownAviInfraSetting(t *testing.T, infraSettingName string) {
 		t.Fatalf("error in deleting AviInfraSetting: %v", err)
 	}
 }
+
+// L4Rule lib functions
+type FakeL4Rule struct {
+	Name               string
+	Namespace          string
+	ApplicationProfile string
+	NetworkProfile     string
+	AnalyticsProfile   string
+	Algorithm          string
+	Hash               string
+	HealthMonitors     []string
+}
+
+func (rr FakeL4Rule) L4Rule() *akov1alpha1.L4Rule {
+	l4Rule := &akov1alpha1.L4Rule{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: rr.Namespace,
+			Name:      rr.Name,
+		},
+		Spec: akov1alpha1.L4RuleSpec{
+			ApplicationProfile: rr.ApplicationProfile,
+			NetworkProfile:     rr.NetworkProfile,
+			AnalyticsProfile:   rr.AnalyticsProfile,
+			LoadBalancerPolicy: akov1alpha1.L4RuleLBPolicy{
+				Algorithm: rr.Algorithm,
+				Hash:      rr.Hash,
+			},
+			HealthMonitors: rr.HealthMonitors,
+		},
+	}
+
+	return l4Rule
+}
+
+func SetupL4Rule(t *testing.T, l4RuleName, namespace string) {
+	l4Rule := FakeL4Rule{
+		Name:               l4RuleName,
+		Namespace:          namespace,
+		ApplicationProfile: "thisisaviref-l4appprof",
+		NetworkProfile:     "thisisaviref-networkprof",
+		AnalyticsProfile:   "thisisaviref-analyticsprof",
+		Algorithm:          "LB_ALGORITHM_CONSISTENT_HASH",
+		Hash:               "LB_ALGORITHM_CONSISTENT_HASH_SOURCE_IP_ADDRESS",
+		HealthMonitors:     []string{"thisisaviref-hm2", "thisisaviref-hm1"},
+	}
+	if _, err := lib.GetCRDClientset().AkoV1alpha1().L4Rules(namespace).Create(context.TODO(), l4Rule.L4Rule(), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding L4Rule: %v", err)
+	}
+}
+
+func TeardownL4Rule(t *testing.T, l4RuleName, namespace string) {
+	if err := lib.GetCRDClientset().AkoV1alpha1().L4Rules(namespace).Delete(context.TODO(), l4RuleName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting L4Rule: %v", err)
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
 
 	"github.com/onsi/gomega"
+	"github.com/vmware/alb-sdk/go/models"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -201,3 +203,96 @@ func TestMultiNodeAdd(t *testing.T) {
 	}
 	g.Expect(len(nodeIPMap)).To(gomega.Equal(0))
 }
+
+func TestDualStackNodeAdd(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/global"
+	nodeName := "testNodeDualStack"
+	nodeip := "10.1.1.3"
+	nodeipv6 := "2002::10:1:1:3"
+	objects.SharedAviGraphLister().Delete(modelName)
+	nodeExample := (FakeNode{
+		Name:    nodeName,
+		PodCIDR: "10.244.3.0/24",
+		Version: "1",
+		NodeIP:  nodeip,
+	}).Node()
+	nodeExample.Spec.PodCIDRs = []string{"10.244.3.0/24", "fd00:10:244:3::/64"}
+	nodeExample.Status.Addresses = append(nodeExample.Status.Addresses, corev1.NodeAddress{Type: "InternalIP", Address: nodeipv6})
+
+	_, err := KubeClient.CoreV1().Nodes().Create(context.TODO(), nodeExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Node: %v", err)
+	}
+
+	PollForCompletion(t, modelName, 5)
+	var routes []*models.StaticRoute
+	g.Eventually(func() int {
+		routes = nil
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			for _, vrf := range aviModel.(*avinodes.AviObjectGraph).GetAviVRF() {
+				for _, staticRoute := range vrf.StaticRoutes {
+					if *staticRoute.NextHop.Addr == nodeip || *staticRoute.NextHop.Addr == nodeipv6 {
+						routes = append(routes, staticRoute)
+					}
+				}
+			}
+		}
+		return len(routes)
+	}, 10*time.Second).Should(gomega.Equal(2))
+
+	// the v6 pod CIDR is routed via the v6 address of the node.
+	g.Expect(*routes[0].Prefix.IPAddr.Addr).To(gomega.Equal("10.244.3.0"))
+	g.Expect(*routes[0].Prefix.IPAddr.Type).To(gomega.Equal("V4"))
+	g.Expect(*routes[0].NextHop.Addr).To(gomega.Equal(nodeip))
+	g.Expect(*routes[0].NextHop.Type).To(gomega.Equal("V4"))
+	g.Expect(*routes[1].Prefix.IPAddr.Addr).To(gomega.Equal("fd00:10:244:3::"))
+	g.Expect(*routes[1].Prefix.IPAddr.Type).To(gomega.Equal("V6"))
+	g.Expect(*routes[1].Prefix.Mask).To(gomega.Equal(int32(64)))
+	g.Expect(*routes[1].NextHop.Addr).To(gomega.Equal(nodeipv6))
+	g.Expect(*routes[1].NextHop.Type).To(gomega.Equal("V6"))
+
+	if err := KubeClient.CoreV1().Nodes().Delete(context.TODO(), nodeName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Node: %v", err)
+	}
+}
+
+func TestV4OnlyNodeSkipsV6PodCIDR(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	modelName := "admin/global"
+	nodeName := "testNodeV4Only"
+	nodeip := "10.1.1.4"
+	objects.SharedAviGraphLister().Delete(modelName)
+	nodeExample := (FakeNode{
+		Name:    nodeName,
+		PodCIDR: "10.244.4.0/24",
+		Version: "1",
+		NodeIP:  nodeip,
+	}).Node()
+	nodeExample.Spec.PodCIDRs = []string{"10.244.4.0/24", "fd00:10:244:4::/64"}
+
+	_, err := KubeClient.CoreV1().Nodes().Create(context.TODO(), nodeExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Node: %v", err)
+	}
+
+	PollForCompletion(t, modelName, 5)
+	var prefixes []string
+	g.Eventually(func() []string {
+		prefixes = nil
+		if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+			for _, vrf := range aviModel.(*avinodes.AviObjectGraph).GetAviVRF() {
+				for _, staticRoute := range vrf.StaticRoutes {
+					if *staticRoute.NextHop.Addr == nodeip {
+						prefixes = append(prefixes, *staticRoute.Prefix.IPAddr.Addr)
+					}
+				}
+			}
+		}
+		return prefixes
+	}, 10*time.Second).Should(gomega.Equal([]string{"10.244.4.0"}))
+
+	if err := KubeClient.CoreV1().Nodes().Delete(context.TODO(), nodeName, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting Node: %v", err)
+	}
+}
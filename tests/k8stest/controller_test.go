@@ -23,6 +23,7 @@ import (
 
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/status"
 	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
 	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
 
@@ -30,6 +31,7 @@ import (
 	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	k8sfake "k8s.io/client-go/kubernetes/fake"
 
@@ -286,6 +288,68 @@ func TestIngressNoUpdate(t *testing.T) {
 	waitAndverify(t, "")
 }
 
+// An update of only the oper status annotation, which is set by AKO, should not add the service key to ingestion queue
+func TestSvcOperStatusNoUpdate(t *testing.T) {
+	svcExample := &corev1.Service{
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "red-ns",
+			Name:      "testsvc-operstatus",
+		},
+	}
+	_, err := kubeClient.CoreV1().Services("red-ns").Create(context.TODO(), svcExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Service: %v", err)
+	}
+	waitAndverify(t, "L4LBService/red-ns/testsvc-operstatus")
+
+	payload := []byte(`{"metadata":{"annotations":{"` + status.OperStatusAnnotation + `":"[{\"virtualService\":\"vs\",\"state\":\"OPER_UP\"}]"}}}`)
+	_, err = kubeClient.CoreV1().Services("red-ns").Patch(context.TODO(), "testsvc-operstatus", types.MergePatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		t.Fatalf("error in patching Service: %v", err)
+	}
+	waitAndverify(t, "")
+
+	payload = []byte(`{"metadata":{"annotations":{"` + lib.InfraSettingNameAnnotation + `":"infra"}}}`)
+	_, err = kubeClient.CoreV1().Services("red-ns").Patch(context.TODO(), "testsvc-operstatus", types.MergePatchType, payload, metav1.PatchOptions{})
+	if err != nil {
+		t.Fatalf("error in patching Service: %v", err)
+	}
+	waitAndverify(t, "L4LBService/red-ns/testsvc-operstatus")
+}
+
+// An update of only the oper status annotation, which is set by AKO, should not add the ingress key to ingestion queue
+func TestIngressOperStatusNoUpdate(t *testing.T) {
+	ingrExample := &networkingv1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "red-ns",
+			Name:      "testingr-operstatus",
+		},
+		Spec: networkingv1beta1.IngressSpec{
+			Backend: &networkingv1beta1.IngressBackend{
+				ServiceName: "testsvc",
+			},
+		},
+	}
+	_, err := kubeClient.NetworkingV1beta1().Ingresses("red-ns").Create(context.TODO(), ingrExample, metav1.CreateOptions{})
+	if err != nil {
+		t.Fatalf("error in adding Ingress: %v", err)
+	}
+	waitAndverify(t, "Ingress/red-ns/testingr-operstatus")
+
+	ingrExample.Annotations = map[string]string{
+		status.OperStatusAnnotation: `[{"virtualService":"vs","state":"OPER_UP"}]`,
+	}
+	ingrExample.ResourceVersion = "2"
+	_, err = kubeClient.NetworkingV1beta1().Ingresses("red-ns").Update(context.TODO(), ingrExample, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatalf("error in updating Ingress: %v", err)
+	}
+	waitAndverify(t, "")
+}
+
 func TestNode(t *testing.T) {
 	nodeExample := &corev1.Node{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,250 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package leaderelectiontests
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+
+// the identity of the other AKO replica, which holds the lease when the test starts
+const otherReplica = "ako-0"
+
+// a pool in the avi mock objects which is not referred by any virtualservice
+const stalePoolUUID = "pool-11a38043-e51e-4c93-8187-b390d7d81abd"
+
+var aviWriteCount, aviVSGetCount, stalePoolDeleteCount, svcStatusWriteCount int32
+
+func TestMain(m *testing.M) {
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("SERVICE_TYPE", "ClusterIP")
+	os.Setenv("AUTO_L4_FQDN", "disable")
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+	os.Setenv(lib.LEADER_ELECTION, "true")
+	os.Setenv("POD_NAME", "ako-1")
+
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	lib.SetCRDClientset(CRDClient)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	// the lease is held by the other replica, this replica starts as a standby
+	leaseDuration := int32(15)
+	now := metav1.NewMicroTime(time.Now())
+	otherHolder := otherReplica
+	lease := &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: lib.AKOLeaseName, Namespace: utils.GetAKONamespace()},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &otherHolder,
+			LeaseDurationSeconds: &leaseDuration,
+			AcquireTime:          &now,
+			RenewTime:            &now,
+		},
+	}
+	KubeClient.CoordinationV1().Leases(utils.GetAKONamespace()).Create(context.TODO(), lease, metav1.CreateOptions{})
+	KubeClient.PrependReactor("patch", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if action.GetSubresource() == "status" {
+			atomic.AddInt32(&svcStatusWriteCount, 1)
+		}
+		return false, nil, nil
+	})
+	lib.SetAKOIsLeader(false)
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers(CRDClient)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+	integrationtest.AddMiddleware(func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.EscapedPath()
+		if r.Method == "GET" && strings.Trim(url, "/") == "api/virtualservice" {
+			atomic.AddInt32(&aviVSGetCount, 1)
+		} else if r.Method != "GET" && !strings.Contains(url, "login") {
+			atomic.AddInt32(&aviWriteCount, 1)
+			if r.Method == "DELETE" && strings.HasSuffix(url, "/api/pool/"+stalePoolUUID) {
+				atomic.AddInt32(&stalePoolDeleteCount, 1)
+			}
+		}
+		integrationtest.NormalControllerServer(w, r)
+	})
+
+	ctrl := k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+// renewLease renews the lease on behalf of the other replica till stopCh is closed, and then releases it
+// the way a replica does when it shuts down.
+func renewLease(t *testing.T, stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+	leases := KubeClient.CoordinationV1().Leases(utils.GetAKONamespace())
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		lease, err := leases.Get(context.TODO(), lib.AKOLeaseName, metav1.GetOptions{})
+		if err != nil {
+			t.Errorf("error in getting the lease: %v", err)
+			return
+		}
+		select {
+		case <-stopCh:
+			holder, leaseDuration := "", int32(1)
+			lease.Spec.HolderIdentity = &holder
+			lease.Spec.LeaseDurationSeconds = &leaseDuration
+			if _, err = leases.Update(context.TODO(), lease, metav1.UpdateOptions{}); err != nil {
+				t.Errorf("error in releasing the lease: %v", err)
+			}
+			return
+		case <-ticker.C:
+			now := metav1.NewMicroTime(time.Now())
+			lease.Spec.RenewTime = &now
+			if _, err = leases.Update(context.TODO(), lease, metav1.UpdateOptions{}); err != nil {
+				t.Errorf("error in renewing the lease: %v", err)
+				return
+			}
+		}
+	}
+}
+
+func TestStandbyAndTakeover(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	stopRenewCh, renewDoneCh := make(chan struct{}), make(chan struct{})
+	go renewLease(t, stopRenewCh, renewDoneCh)
+
+	mcache := cache.SharedAviObjCache()
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: "cluster--red-ns-testsvc"}
+	atomic.StoreInt32(&aviWriteCount, 0)
+	atomic.StoreInt32(&svcStatusWriteCount, 0)
+	atomic.StoreInt32(&stalePoolDeleteCount, 0)
+
+	// the standby builds the model of the service, but does not sync it to the controller, or update the
+	// status of the service.
+	integrationtest.CreateSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, corev1.ServiceTypeLoadBalancer, false)
+	integrationtest.CreateEP(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, false, false, "1.1.1")
+	g.Eventually(func() bool {
+		found, aviModel := objects.SharedAviGraphLister().Get(integrationtest.SINGLEPORTMODEL)
+		return found && aviModel != nil
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Consistently(func() int32 {
+		return atomic.LoadInt32(&aviWriteCount)
+	}, 5*time.Second).Should(gomega.BeZero())
+	g.Expect(lib.AKOIsLeader()).To(gomega.BeFalse())
+	g.Expect(atomic.LoadInt32(&svcStatusWriteCount)).To(gomega.BeZero())
+	_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+	g.Expect(found).To(gomega.BeFalse())
+	vsGetCount := atomic.LoadInt32(&aviVSGetCount)
+
+	// once the other replica releases the lease, this replica takes over, refreshes the avi object cache,
+	// cleans up the stale objects and syncs the models built as a standby.
+	close(stopRenewCh)
+	<-renewDoneCh
+	g.Eventually(lib.AKOIsLeader, 20*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() int32 {
+		return atomic.LoadInt32(&aviVSGetCount)
+	}, 10*time.Second).Should(gomega.BeNumerically(">", vsGetCount))
+	g.Eventually(func() int32 {
+		return atomic.LoadInt32(&stalePoolDeleteCount)
+	}, 10*time.Second).Should(gomega.Equal(int32(1)))
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeTrue())
+	g.Eventually(func() int32 {
+		return atomic.LoadInt32(&svcStatusWriteCount)
+	}, 10*time.Second).Should(gomega.BeNumerically(">", 0))
+	g.Eventually(func() int {
+		svc, _ := KubeClient.CoreV1().Services(integrationtest.NAMESPACE).Get(context.TODO(), integrationtest.SINGLEPORTSVC, metav1.GetOptions{})
+		return len(svc.Status.LoadBalancer.Ingress)
+	}, 10*time.Second).Should(gomega.Equal(1))
+
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	integrationtest.DelEP(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	g.Eventually(func() bool {
+		_, found := mcache.VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+}
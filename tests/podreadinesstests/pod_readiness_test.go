@@ -0,0 +1,268 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package podreadinesstests
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var ctrl *k8s.AviController
+
+const (
+	defaultPodName = "test-pod"
+	defaultPodIP   = "192.168.32.10"
+	defaultPort    = 8080
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+	os.Setenv(lib.ENABLE_POD_READINESS_GATE, "true")
+
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	lib.SetCRDClientset(CRDClient)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+		utils.PodInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers(CRDClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	cloudObj.NSIpamDNS = []string{"avi.internal", ".com"}
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+func getTestPod(containersReady bool) *corev1.Pod {
+	containersReadyStatus := corev1.ConditionFalse
+	if containersReady {
+		containersReadyStatus = corev1.ConditionTrue
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      defaultPodName,
+			Namespace: integrationtest.NAMESPACE,
+			Labels:    map[string]string{"app": "readiness"},
+		},
+		Spec: corev1.PodSpec{
+			ReadinessGates: []corev1.PodReadinessGate{{ConditionType: lib.PodReadinessGateConditionType}},
+		},
+		Status: corev1.PodStatus{
+			PodIP: defaultPodIP,
+			Conditions: []corev1.PodCondition{
+				{Type: corev1.ContainersReady, Status: containersReadyStatus},
+				{Type: corev1.PodReady, Status: corev1.ConditionFalse},
+			},
+		},
+	}
+}
+
+// setUpNotReadyEndpoint creates the Service and its Endpoints, with the pod in the not ready addresses.
+func setUpNotReadyEndpoint(t *testing.T) {
+	selectors := map[string]string{"app": "readiness"}
+	integrationtest.CreateServiceWithSelectors(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC, corev1.ServiceTypeLoadBalancer, false, selectors)
+	ep := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: integrationtest.NAMESPACE,
+			Name:      integrationtest.SINGLEPORTSVC,
+		},
+		Subsets: []corev1.EndpointSubset{{
+			NotReadyAddresses: []corev1.EndpointAddress{{
+				IP: defaultPodIP,
+				TargetRef: &corev1.ObjectReference{
+					Kind:      utils.Pod,
+					Namespace: integrationtest.NAMESPACE,
+					Name:      defaultPodName,
+				},
+			}},
+			Ports: []corev1.EndpointPort{{Name: "foo0", Port: defaultPort, Protocol: "TCP"}},
+		}},
+	}
+	if _, err := KubeClient.CoreV1().Endpoints(integrationtest.NAMESPACE).Create(context.TODO(), ep, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Endpoint: %v", err)
+	}
+}
+
+func tearDown(t *testing.T, g *gomega.WithT) {
+	objects.SharedAviGraphLister().Delete(integrationtest.SINGLEPORTMODEL)
+	integrationtest.DelSVC(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	integrationtest.DelEP(t, integrationtest.NAMESPACE, integrationtest.SINGLEPORTSVC)
+	KubeClient.CoreV1().Pods(integrationtest.NAMESPACE).Delete(context.TODO(), defaultPodName, metav1.DeleteOptions{})
+	vsKey := cache.NamespaceName{Namespace: integrationtest.AVINAMESPACE, Name: "cluster--red-ns-testsvc"}
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 30*time.Second).Should(gomega.Equal(false))
+}
+
+func getPoolServers() []string {
+	var servers []string
+	if found, aviModel := objects.SharedAviGraphLister().Get(integrationtest.SINGLEPORTMODEL); found && aviModel != nil {
+		nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS()
+		if len(nodes) == 1 && len(nodes[0].PoolRefs) == 1 {
+			for _, server := range nodes[0].PoolRefs[0].Servers {
+				servers = append(servers, *server.Ip.Addr)
+			}
+		}
+	}
+	return servers
+}
+
+func getPoolServerReadyCondition() corev1.ConditionStatus {
+	pod, err := KubeClient.CoreV1().Pods(integrationtest.NAMESPACE).Get(context.TODO(), defaultPodName, metav1.GetOptions{})
+	if err != nil {
+		return ""
+	}
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == lib.PodReadinessGateConditionType {
+			return condition.Status
+		}
+	}
+	return ""
+}
+
+// TestPodReadinessGateSetAfterPoolServerAdded verifies that a pod waiting only for the readiness gate
+// is added to the pool, after which the readiness gate is set to True.
+func TestPodReadinessGateSetAfterPoolServerAdded(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	if _, err := KubeClient.CoreV1().Pods(integrationtest.NAMESPACE).Create(context.TODO(), getTestPod(true), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Pod: %v", err)
+	}
+	setUpNotReadyEndpoint(t)
+
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.ConsistOf(defaultPodIP))
+	g.Eventually(getPoolServerReadyCondition, 20*time.Second).Should(gomega.Equal(corev1.ConditionTrue))
+
+	tearDown(t, g)
+}
+
+// TestPodReadinessGateWaitsForContainersReady verifies that a pod is added to the pool and its readiness gate
+// is set to True only after its containers are ready.
+func TestPodReadinessGateWaitsForContainersReady(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+
+	if _, err := KubeClient.CoreV1().Pods(integrationtest.NAMESPACE).Create(context.TODO(), getTestPod(false), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Pod: %v", err)
+	}
+	setUpNotReadyEndpoint(t)
+	integrationtest.PollForCompletion(t, integrationtest.SINGLEPORTMODEL, 5)
+
+	g.Consistently(getPoolServers, 3*time.Second).Should(gomega.BeEmpty())
+	g.Expect(getPoolServerReadyCondition()).To(gomega.BeEmpty())
+
+	pod := getTestPod(true)
+	pod.ResourceVersion = "2"
+	if _, err := KubeClient.CoreV1().Pods(integrationtest.NAMESPACE).Update(context.TODO(), pod, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating Pod: %v", err)
+	}
+
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.ConsistOf(defaultPodIP))
+	g.Eventually(getPoolServerReadyCondition, 20*time.Second).Should(gomega.Equal(corev1.ConditionTrue))
+
+	tearDown(t, g)
+}
+
+// TestPodReadinessGateNotSetInDryRun verifies that the readiness gate of a pod is not set in the dry run mode,
+// in which the pod is added to the pool in the model, but not on the controller.
+func TestPodReadinessGateNotSetInDryRun(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	lib.SetDryRun(true)
+	defer lib.SetDryRun(false)
+
+	if _, err := KubeClient.CoreV1().Pods(integrationtest.NAMESPACE).Create(context.TODO(), getTestPod(true), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in creating Pod: %v", err)
+	}
+	setUpNotReadyEndpoint(t)
+
+	g.Eventually(getPoolServers, 10*time.Second).Should(gomega.ConsistOf(defaultPodIP))
+	g.Consistently(getPoolServerReadyCondition, 5*time.Second).Should(gomega.BeEmpty())
+
+	tearDown(t, g)
+}
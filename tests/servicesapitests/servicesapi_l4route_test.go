@@ -0,0 +1,372 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package servicesapitests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+
+	"github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servicesapi "sigs.k8s.io/service-apis/apis/v1alpha1"
+)
+
+// L4 route lib functions
+func routeListener(port servicesapi.PortNumber, protocol servicesapi.ProtocolType, kind string) servicesapi.Listener {
+	listener := servicesapi.Listener{
+		Port:     port,
+		Protocol: protocol,
+		Routes: servicesapi.RouteBindingSelector{
+			Kind: kind,
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"app": "foo"},
+			},
+		},
+	}
+	if protocol == servicesapi.TLSProtocolType {
+		listener.TLS = &servicesapi.GatewayTLSConfig{Mode: servicesapi.TLSModePassthrough}
+	}
+	return listener
+}
+
+func SetupRouteGateway(t *testing.T, gwname, namespace, gwclass string, listeners ...servicesapi.Listener) {
+	gateway := &servicesapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      gwname,
+		},
+		Spec: servicesapi.GatewaySpec{
+			GatewayClassName: gwclass,
+			Listeners:        listeners,
+		},
+	}
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().Gateways(namespace).Create(context.TODO(), gateway, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Gateway: %v", err)
+	}
+}
+
+func routeForwardTo(backends map[string]int32) []servicesapi.RouteForwardTo {
+	var forwardTo []servicesapi.RouteForwardTo
+	for svcName, weight := range backends {
+		forwardTo = append(forwardTo, servicesapi.RouteForwardTo{
+			ServiceName: &[]string{svcName}[0],
+			Port:        8080,
+			Weight:      weight,
+		})
+	}
+	return forwardTo
+}
+
+func routeMeta(name, namespace string, created time.Time) metav1.ObjectMeta {
+	return metav1.ObjectMeta{
+		Namespace:         namespace,
+		Name:              name,
+		Labels:            map[string]string{"app": "foo"},
+		CreationTimestamp: metav1.NewTime(created),
+	}
+}
+
+func SetupTCPRoute(t *testing.T, name, namespace string, created time.Time, backends map[string]int32) {
+	route := &servicesapi.TCPRoute{
+		ObjectMeta: routeMeta(name, namespace, created),
+		Spec: servicesapi.TCPRouteSpec{
+			Rules: []servicesapi.TCPRouteRule{{ForwardTo: routeForwardTo(backends)}},
+		},
+	}
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().TCPRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding TCPRoute: %v", err)
+	}
+}
+
+func SetupUDPRoute(t *testing.T, name, namespace string, backends map[string]int32) {
+	route := &servicesapi.UDPRoute{
+		ObjectMeta: routeMeta(name, namespace, time.Now()),
+		Spec: servicesapi.UDPRouteSpec{
+			Rules: []servicesapi.UDPRouteRule{{ForwardTo: routeForwardTo(backends)}},
+		},
+	}
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().UDPRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding UDPRoute: %v", err)
+	}
+}
+
+func SetupTLSRoute(t *testing.T, name, namespace string, sni string, backends map[string]int32) {
+	route := &servicesapi.TLSRoute{
+		ObjectMeta: routeMeta(name, namespace, time.Now()),
+		Spec: servicesapi.TLSRouteSpec{
+			Rules: []servicesapi.TLSRouteRule{{
+				Matches:   []servicesapi.TLSRouteMatch{{SNIs: []servicesapi.Hostname{servicesapi.Hostname(sni)}}},
+				ForwardTo: routeForwardTo(backends),
+			}},
+		},
+	}
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().TLSRoutes(namespace).Create(context.TODO(), route, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding TLSRoute: %v", err)
+	}
+}
+
+func getRouteAdmittedCondition(kind, namespace, name, gwNamespace, gwName string) *metav1.Condition {
+	var routeStatus servicesapi.RouteStatus
+	client := SvcAPIClient.NetworkingV1alpha1()
+	switch kind {
+	case lib.TCPRoute:
+		route, err := client.TCPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		routeStatus = route.Status.RouteStatus
+	case lib.UDPRoute:
+		route, err := client.UDPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		routeStatus = route.Status.RouteStatus
+	case lib.TLSRoute:
+		route, err := client.TLSRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+		if err != nil {
+			return nil
+		}
+		routeStatus = route.Status.RouteStatus
+	}
+	for _, gwStatus := range routeStatus.Gateways {
+		if gwStatus.GatewayRef.Namespace == gwNamespace && gwStatus.GatewayRef.Name == gwName {
+			return meta.FindStatusCondition(gwStatus.Conditions, string(servicesapi.ConditionRouteAdmitted))
+		}
+	}
+	return nil
+}
+
+func TestServicesAPITCPUDPRouteBestCase(t *testing.T) {
+	// create gwclass, gw with a TCP listener selecting TCPRoutes and a UDP listener selecting UDPRoutes
+	// create 2 svcs, a tcproute with weighted backends and a udproute
+	// check the L4 policy rules selecting the poolgroups, the poolgroup ratios and the route status
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns := "avi-lb", "my-route-gateway", "default"
+	modelName := "admin/cluster--default-my-route-gateway"
+
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Backends(t, ns, "l4svc1", "l4svc2")
+	SetupRouteGateway(t, gatewayName, ns, gwClassName,
+		routeListener(8081, servicesapi.TCPProtocolType, lib.TCPRoute),
+		routeListener(8082, servicesapi.UDPProtocolType, lib.UDPRoute))
+	SetupTCPRoute(t, "foo-tcp", ns, time.Now(), map[string]int32{"l4svc1": 20, "l4svc2": 80})
+	SetupUDPRoute(t, "foo-udp", ns, map[string]int32{"l4svc1": 1})
+
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil && len(vsNode.L4PolicyRefs) == 1 {
+			return len(vsNode.L4PolicyRefs[0].PortPool)
+		}
+		return 0
+	}, 40*time.Second).Should(gomega.Equal(2))
+
+	vsNode := getGatewayVS(modelName)
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(2))
+	g.Expect(vsNode.NetworkProfile).To(gomega.Equal(utils.MIXED_NET_PROFILE))
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(2))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(3))
+	pgs := map[string]int{}
+	for i, pg := range vsNode.PoolGroupRefs {
+		pgs[pg.Name] = i
+	}
+	for _, portPool := range vsNode.L4PolicyRefs[0].PortPool {
+		g.Expect(portPool.Pool).To(gomega.BeEmpty())
+		g.Expect(pgs).To(gomega.HaveKey(portPool.PoolGroup))
+		pg := vsNode.PoolGroupRefs[pgs[portPool.PoolGroup]]
+		if portPool.Port == 8081 {
+			g.Expect(portPool.Protocol).To(gomega.Equal(utils.TCP))
+			g.Expect(pg.Members).To(gomega.HaveLen(2))
+			for _, member := range pg.Members {
+				if strings.Contains(*member.PoolRef, "l4svc1") {
+					g.Expect(*member.Ratio).To(gomega.Equal(int32(20)))
+				} else {
+					g.Expect(*member.Ratio).To(gomega.Equal(int32(80)))
+				}
+			}
+		} else {
+			g.Expect(portPool.Port).To(gomega.Equal(uint32(8082)))
+			g.Expect(portPool.Protocol).To(gomega.Equal(utils.UDP))
+			g.Expect(pg.Members).To(gomega.HaveLen(1))
+		}
+	}
+	for _, pool := range vsNode.PoolRefs {
+		g.Expect(pool.Servers).To(gomega.HaveLen(1))
+	}
+
+	g.Eventually(func() string {
+		if condition := getRouteAdmittedCondition(lib.TCPRoute, ns, "foo-tcp", ns, gatewayName); condition != nil {
+			return condition.Message
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal("Admitted by listeners TCP/8081"))
+	g.Eventually(func() string {
+		if condition := getRouteAdmittedCondition(lib.UDPRoute, ns, "foo-udp", ns, gatewayName); condition != nil {
+			return condition.Message
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal("Admitted by listeners UDP/8082"))
+
+	// The L4 policy selecting the poolgroups is created on the controller.
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().L4PolicyCache.AviCacheGet(cache.NamespaceName{Namespace: "admin", Name: "cluster--default-my-route-gateway"})
+		return found
+	}, 40*time.Second).Should(gomega.BeTrue())
+
+	// Deleting the tcproute removes the rule of the TCP listener.
+	if err := SvcAPIClient.NetworkingV1alpha1().TCPRoutes(ns).Delete(context.TODO(), "foo-tcp", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting TCPRoute: %v", err)
+	}
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil && len(vsNode.L4PolicyRefs) == 1 {
+			return len(vsNode.L4PolicyRefs[0].PortPool)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+
+	if err := SvcAPIClient.NetworkingV1alpha1().UDPRoutes(ns).Delete(context.TODO(), "foo-udp", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting UDPRoute: %v", err)
+	}
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownL7Backends(t, ns, "l4svc1", "l4svc2")
+}
+
+func TestServicesAPITCPRouteConflict(t *testing.T) {
+	// create gwclass, gw with a TCP listener and 2 tcproutes selected by the listener
+	// check that the older tcproute is admitted, and the newer tcproute is not
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns := "avi-lb", "my-route-gateway", "default"
+	modelName := "admin/cluster--default-my-route-gateway"
+
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Backends(t, ns, "l4svc1", "l4svc2")
+	SetupRouteGateway(t, gatewayName, ns, gwClassName, routeListener(8081, servicesapi.TCPProtocolType, lib.TCPRoute))
+	SetupTCPRoute(t, "new-tcp", ns, time.Now(), map[string]int32{"l4svc2": 1})
+	SetupTCPRoute(t, "old-tcp", ns, time.Now().Add(-time.Hour), map[string]int32{"l4svc1": 1})
+
+	g.Eventually(func() string {
+		if condition := getRouteAdmittedCondition(lib.TCPRoute, ns, "new-tcp", ns, gatewayName); condition != nil {
+			return condition.Reason
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal("RouteConflict"))
+	g.Eventually(func() metav1.ConditionStatus {
+		if condition := getRouteAdmittedCondition(lib.TCPRoute, ns, "old-tcp", ns, gatewayName); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}, 30*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+
+	vsNode := getGatewayVS(modelName)
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolRefs[0].ServiceMetadata.NamespaceServiceName).To(gomega.Equal([]string{"default/l4svc1"}))
+
+	// The newer route is admitted once the older route is deleted.
+	if err := SvcAPIClient.NetworkingV1alpha1().TCPRoutes(ns).Delete(context.TODO(), "old-tcp", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting TCPRoute: %v", err)
+	}
+	g.Eventually(func() metav1.ConditionStatus {
+		if condition := getRouteAdmittedCondition(lib.TCPRoute, ns, "new-tcp", ns, gatewayName); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}, 30*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+
+	if err := SvcAPIClient.NetworkingV1alpha1().TCPRoutes(ns).Delete(context.TODO(), "new-tcp", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting TCPRoute: %v", err)
+	}
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownL7Backends(t, ns, "l4svc1", "l4svc2")
+}
+
+func TestServicesAPITLSRoutePassthrough(t *testing.T) {
+	// create gwclass, gw with a TLS passthrough listener selecting TLSRoutes and a TCP listener, svc and a tlsroute
+	// check the datascript selecting the poolgroup of the SNI, and that the TCP listener is not realised
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns := "avi-lb", "my-route-gateway", "default"
+	modelName := "admin/cluster--default-my-route-gateway"
+
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Backends(t, ns, "l4svc1")
+	SetupRouteGateway(t, gatewayName, ns, gwClassName,
+		routeListener(443, servicesapi.TLSProtocolType, lib.TLSRoute),
+		routeListener(8081, servicesapi.TCPProtocolType, lib.TCPRoute))
+	SetupTLSRoute(t, "foo-tls", ns, "foo.avi.com", map[string]int32{"l4svc1": 1})
+
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil && len(vsNode.HTTPDSrefs) == 1 {
+			return len(vsNode.HTTPDSrefs[0].PoolGroupRefs)
+		}
+		return 0
+	}, 40*time.Second).Should(gomega.Equal(1))
+
+	vsNode := getGatewayVS(modelName)
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(443)))
+	g.Expect(vsNode.PortProto[0].Protocol).To(gomega.Equal(utils.TCP))
+	g.Expect(vsNode.NetworkProfile).To(gomega.Equal(utils.DEFAULT_TCP_NW_PROFILE))
+	g.Expect(vsNode.L4PolicyRefs).To(gomega.HaveLen(0))
+	g.Expect(vsNode.HTTPDSrefs[0].PoolGroupRefs).To(gomega.Equal([]string{"cluster--default-my-route-gateway--foo.avi.com"}))
+	g.Expect(vsNode.HTTPDSrefs[0].Script).To(gomega.ContainSubstring(`pg_name = "cluster--default-my-route-gateway--"..sname`))
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.PoolGroupRefs[0].Name).To(gomega.Equal("cluster--default-my-route-gateway--foo.avi.com"))
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.VSVIPRefs[0].FQDNs).To(gomega.ContainElement("foo.avi.com"))
+
+	g.Eventually(func() string {
+		if condition := getRouteAdmittedCondition(lib.TLSRoute, ns, "foo-tls", ns, gatewayName); condition != nil {
+			return condition.Message
+		}
+		return ""
+	}, 30*time.Second).Should(gomega.Equal("Admitted by listeners TLS/443"))
+
+	// The TCP listener cannot be combined with the TLS passthrough listener.
+	g.Eventually(func() bool {
+		gw, _ := SvcAPIClient.NetworkingV1alpha1().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		for _, listener := range gw.Status.Listeners {
+			if listener.Port == 8081 {
+				return meta.IsStatusConditionTrue(listener.Conditions, "InvalidRoutes")
+			}
+		}
+		return false
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	if err := SvcAPIClient.NetworkingV1alpha1().TLSRoutes(ns).Delete(context.TODO(), "foo-tls", metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting TLSRoute: %v", err)
+	}
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil {
+			return len(vsNode.HTTPDSrefs) + len(vsNode.PoolGroupRefs)
+		}
+		return -1
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownL7Backends(t, ns, "l4svc1")
+}
@@ -0,0 +1,492 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package servicesapitests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	avinodes "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/nodes"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/objects"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	servicesapi "sigs.k8s.io/service-apis/apis/v1alpha1"
+)
+
+// HTTPRoute lib functions
+type FakeHTTPRoute struct {
+	Name      string
+	Namespace string
+	Labels    map[string]string
+	Hostnames []string
+	Rules     []FakeHTTPRouteRule
+}
+
+type FakeHTTPRouteRule struct {
+	Path       string
+	PathType   servicesapi.PathMatchType
+	Headers    map[string]string
+	AddHeaders map[string]string
+	DelHeaders []string
+	Backends   map[string]int32
+}
+
+func (route FakeHTTPRoute) HTTPRoute() *servicesapi.HTTPRoute {
+	var rules []servicesapi.HTTPRouteRule
+	for _, fakeRule := range route.Rules {
+		rule := servicesapi.HTTPRouteRule{
+			Matches: []servicesapi.HTTPRouteMatch{{
+				Path: servicesapi.HTTPPathMatch{
+					Type:  fakeRule.PathType,
+					Value: fakeRule.Path,
+				},
+			}},
+		}
+		if len(fakeRule.Headers) > 0 {
+			rule.Matches[0].Headers = &servicesapi.HTTPHeaderMatch{
+				Type:   servicesapi.HeaderMatchExact,
+				Values: fakeRule.Headers,
+			}
+		}
+		if len(fakeRule.AddHeaders) > 0 || len(fakeRule.DelHeaders) > 0 {
+			rule.Filters = []servicesapi.HTTPRouteFilter{{
+				Type: servicesapi.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &servicesapi.HTTPRequestHeaderFilter{
+					Add:    fakeRule.AddHeaders,
+					Remove: fakeRule.DelHeaders,
+				},
+			}}
+		}
+		for svcName, weight := range fakeRule.Backends {
+			rule.ForwardTo = append(rule.ForwardTo, servicesapi.HTTPRouteForwardTo{
+				ServiceName: &[]string{svcName}[0],
+				Port:        8080,
+				Weight:      weight,
+			})
+		}
+		rules = append(rules, rule)
+	}
+
+	var hostnames []servicesapi.Hostname
+	for _, hostname := range route.Hostnames {
+		hostnames = append(hostnames, servicesapi.Hostname(hostname))
+	}
+
+	return &servicesapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: route.Namespace,
+			Name:      route.Name,
+			Labels:    route.Labels,
+		},
+		Spec: servicesapi.HTTPRouteSpec{
+			Hostnames: hostnames,
+			Rules:     rules,
+		},
+	}
+}
+
+func SetupHTTPRoute(t *testing.T, route FakeHTTPRoute) {
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().HTTPRoutes(route.Namespace).Create(context.TODO(), route.HTTPRoute(), metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding HTTPRoute: %v", err)
+	}
+}
+
+func UpdateHTTPRoute(t *testing.T, route FakeHTTPRoute, resourceVersion string) {
+	routeUpdate := route.HTTPRoute()
+	routeUpdate.ResourceVersion = resourceVersion
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().HTTPRoutes(route.Namespace).Update(context.TODO(), routeUpdate, metav1.UpdateOptions{}); err != nil {
+		t.Fatalf("error in updating HTTPRoute: %v", err)
+	}
+}
+
+func TeardownHTTPRoute(t *testing.T, name, namespace string) {
+	if err := lib.GetServicesAPIClientset().NetworkingV1alpha1().HTTPRoutes(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil {
+		t.Fatalf("error in deleting HTTPRoute: %v", err)
+	}
+}
+
+// SetupL7Gateway creates a gateway with a HTTP listener on port 80 and a HTTPS listener on port 443,
+// both selecting the HTTPRoutes with the app: foo label.
+func SetupL7Gateway(t *testing.T, gwname, namespace, gwclass, secretName string) {
+	routes := servicesapi.RouteBindingSelector{
+		Kind: lib.HTTPRoute,
+		Selector: metav1.LabelSelector{
+			MatchLabels: map[string]string{"app": "foo"},
+		},
+	}
+	gateway := &servicesapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      gwname,
+		},
+		Spec: servicesapi.GatewaySpec{
+			GatewayClassName: gwclass,
+			Listeners: []servicesapi.Listener{{
+				Port:     80,
+				Protocol: servicesapi.HTTPProtocolType,
+				Routes:   routes,
+			}, {
+				Port:     443,
+				Protocol: servicesapi.HTTPSProtocolType,
+				TLS: &servicesapi.GatewayTLSConfig{
+					CertificateRef: servicesapi.LocalObjectReference{
+						Group: "core",
+						Kind:  "Secret",
+						Name:  secretName,
+					},
+				},
+				Routes: routes,
+			}},
+		},
+	}
+	if _, err := lib.GetServicesAPIClientset().NetworkingV1alpha1().Gateways(namespace).Create(context.TODO(), gateway, metav1.CreateOptions{}); err != nil {
+		t.Fatalf("error in adding Gateway: %v", err)
+	}
+}
+
+func SetupL7Backends(t *testing.T, namespace string, svcNames ...string) {
+	for i, svcName := range svcNames {
+		integrationtest.CreateSVC(t, namespace, svcName, corev1.ServiceTypeClusterIP, false)
+		integrationtest.CreateEP(t, namespace, svcName, false, false, fmt.Sprintf("1.1.%d", i+1))
+	}
+}
+
+func TeardownL7Backends(t *testing.T, namespace string, svcNames ...string) {
+	for _, svcName := range svcNames {
+		integrationtest.DelSVC(t, namespace, svcName)
+		integrationtest.DelEP(t, namespace, svcName)
+	}
+}
+
+func getHTTPRouteAdmittedCondition(namespace, name, gwNamespace, gwName string) *metav1.Condition {
+	route, err := SvcAPIClient.NetworkingV1alpha1().HTTPRoutes(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	for _, gwStatus := range route.Status.Gateways {
+		if gwStatus.GatewayRef.Namespace == gwNamespace && gwStatus.GatewayRef.Name == gwName {
+			return meta.FindStatusCondition(gwStatus.Conditions, string(servicesapi.ConditionRouteAdmitted))
+		}
+	}
+	return nil
+}
+
+func getGatewayVS(modelName string) *avinodes.AviVsNode {
+	if found, aviModel := objects.SharedAviGraphLister().Get(modelName); found && aviModel != nil {
+		if nodes := aviModel.(*avinodes.AviObjectGraph).GetAviVS(); len(nodes) > 0 {
+			return nodes[0]
+		}
+	}
+	return nil
+}
+
+func TestServicesAPIHTTPRouteBestCase(t *testing.T) {
+	// create gwclass, secret, gw with HTTP and HTTPS listeners, 2 svcs and a httproute
+	// check the parent VS ports and policies, the SNI child, the poolgroup ratios
+	// check the httproute and gateway status, delete the httproute and the gateway
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns, secretName := "avi-lb", "my-l7-gateway", "default", "my-l7-secret"
+	modelName := "admin/cluster--default-my-l7-gateway"
+
+	integrationtest.AddSecret(secretName, ns, "tlsCert", "tlsKey")
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Backends(t, ns, "l7svc1", "l7svc2")
+	SetupL7Gateway(t, gatewayName, ns, gwClassName, secretName)
+	SetupHTTPRoute(t, FakeHTTPRoute{
+		Name:      "foo-route",
+		Namespace: ns,
+		Labels:    map[string]string{"app": "foo"},
+		Hostnames: []string{"foo.avi.com"},
+		Rules: []FakeHTTPRouteRule{{
+			Path:       "/foo",
+			PathType:   servicesapi.PathMatchPrefix,
+			Headers:    map[string]string{"version": "v2"},
+			AddHeaders: map[string]string{"x-gateway": "avi"},
+			DelHeaders: []string{"x-debug"},
+			Backends:   map[string]int32{"l7svc1": 20, "l7svc2": 80},
+		}},
+	})
+
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil {
+			return len(vsNode.SniNodes)
+		}
+		return 0
+	}, 40*time.Second).Should(gomega.Equal(1))
+
+	vsNode := getGatewayVS(modelName)
+	g.Expect(vsNode.PortProto).To(gomega.HaveLen(2))
+	g.Expect(vsNode.PortProto[0].Port).To(gomega.Equal(int32(80)))
+	g.Expect(vsNode.PortProto[1].Port).To(gomega.Equal(int32(443)))
+	g.Expect(vsNode.PortProto[1].EnableSSL).To(gomega.BeTrue())
+	g.Expect(vsNode.ServiceMetadata.Gateway).To(gomega.Equal("default/my-l7-gateway"))
+	g.Expect(vsNode.VSVIPRefs[0].FQDNs).To(gomega.ContainElement("foo.avi.com"))
+
+	// Prefix /foo matches /foo and /foo/ on the HTTP listener.
+	g.Expect(vsNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(2))
+	rule := vsNode.HttpPolicyRefs[0].HppMap[0]
+	g.Expect(rule.Host).To(gomega.Equal([]string{"foo.avi.com"}))
+	g.Expect(rule.Port).To(gomega.Equal(uint32(80)))
+	g.Expect(rule.MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(rule.Path).To(gomega.Equal([]string{"/foo"}))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[1].Path).To(gomega.Equal([]string{"/foo/"}))
+	g.Expect(rule.HdrMatch).To(gomega.Equal([]avinodes.AviHostPathHdrMatch{{Name: "version", MatchCriteria: "HDR_EQUALS", Values: []string{"v2"}}}))
+	g.Expect(rule.HdrAction).To(gomega.Equal([]avinodes.AviHostPathHdrAction{
+		{Action: "HTTP_ADD_HDR", Name: "x-gateway", Value: "avi"},
+		{Action: "HTTP_REMOVE_HDR", Name: "x-debug"},
+	}))
+	g.Expect(vsNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(rule.PoolGroup).To(gomega.Equal(vsNode.PoolGroupRefs[0].Name))
+	g.Expect(vsNode.PoolGroupRefs[0].Members).To(gomega.HaveLen(2))
+	ratios := map[string]int32{}
+	for _, member := range vsNode.PoolGroupRefs[0].Members {
+		ratios[*member.PoolRef] = *member.Ratio
+	}
+	g.Expect(vsNode.PoolRefs).To(gomega.HaveLen(2))
+	for _, pool := range vsNode.PoolRefs {
+		g.Expect(pool.Servers).To(gomega.HaveLen(1))
+		if pool.ServiceMetadata.NamespaceServiceName[0] == "default/l7svc1" {
+			g.Expect(ratios["/api/pool?name="+pool.Name]).To(gomega.Equal(int32(20)))
+		} else {
+			g.Expect(ratios["/api/pool?name="+pool.Name]).To(gomega.Equal(int32(80)))
+		}
+	}
+
+	// The HTTPS listener is realised as a SNI child, with the rules matching on all the ports.
+	sniNode := vsNode.SniNodes[0]
+	g.Expect(sniNode.VHDomainNames).To(gomega.Equal([]string{"foo.avi.com"}))
+	g.Expect(sniNode.SSLKeyCertRefs).To(gomega.HaveLen(1))
+	g.Expect(sniNode.SSLKeyCertRefs[0].Cert).To(gomega.Equal([]byte("tlsCert")))
+	g.Expect(sniNode.HttpPolicyRefs).To(gomega.HaveLen(1))
+	g.Expect(sniNode.HttpPolicyRefs[0].HppMap).To(gomega.HaveLen(2))
+	g.Expect(sniNode.HttpPolicyRefs[0].HppMap[0].Port).To(gomega.Equal(uint32(0)))
+	g.Expect(sniNode.PoolGroupRefs).To(gomega.HaveLen(1))
+	g.Expect(sniNode.PoolRefs).To(gomega.HaveLen(2))
+
+	g.Eventually(func() metav1.ConditionStatus {
+		if condition := getHTTPRouteAdmittedCondition(ns, "foo-route", ns, gatewayName); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}, 30*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+	condition := getHTTPRouteAdmittedCondition(ns, "foo-route", ns, gatewayName)
+	g.Expect(condition.Message).To(gomega.Equal("Admitted by listeners HTTP/80, HTTPS/443"))
+
+	g.Eventually(func() string {
+		gw, _ := SvcAPIClient.NetworkingV1alpha1().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		if len(gw.Status.Addresses) > 0 {
+			return gw.Status.Addresses[0].Value
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal("10.250.250.250"))
+
+	// Deleting the route removes the policies and the SNI child.
+	TeardownHTTPRoute(t, "foo-route", ns)
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil {
+			return len(vsNode.HttpPolicyRefs) + len(vsNode.SniNodes) + len(vsNode.PoolGroupRefs)
+		}
+		return -1
+	}, 30*time.Second).Should(gomega.Equal(0))
+
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownL7Backends(t, ns, "l7svc1", "l7svc2")
+	integrationtest.DeleteSecret(secretName, ns)
+}
+
+func TestServicesAPIHTTPRouteLabelUpdate(t *testing.T) {
+	// create gwclass, gw, svc and a httproute not selected by the gateway listeners
+	// update the httproute labels, check that the rule is added to the parent VS
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns, secretName := "avi-lb", "my-l7-gateway", "default", "my-l7-secret"
+	modelName := "admin/cluster--default-my-l7-gateway"
+
+	integrationtest.AddSecret(secretName, ns, "tlsCert", "tlsKey")
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Backends(t, ns, "l7svc1")
+	SetupL7Gateway(t, gatewayName, ns, gwClassName, secretName)
+	route := FakeHTTPRoute{
+		Name:      "bar-route",
+		Namespace: ns,
+		Labels:    map[string]string{"app": "bar"},
+		Rules: []FakeHTTPRouteRule{{
+			Path:     "/bar",
+			PathType: servicesapi.PathMatchExact,
+			Backends: map[string]int32{"l7svc1": 1},
+		}},
+	}
+	SetupHTTPRoute(t, route)
+
+	g.Eventually(func() bool {
+		return getGatewayVS(modelName) != nil
+	}, 40*time.Second).Should(gomega.BeTrue())
+	g.Consistently(func() int {
+		return len(getGatewayVS(modelName).HttpPolicyRefs)
+	}, 5*time.Second).Should(gomega.Equal(0))
+	g.Expect(getHTTPRouteAdmittedCondition(ns, "bar-route", ns, gatewayName)).To(gomega.BeNil())
+
+	route.Labels = map[string]string{"app": "foo"}
+	UpdateHTTPRoute(t, route, "2")
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil && len(vsNode.HttpPolicyRefs) > 0 {
+			return len(vsNode.HttpPolicyRefs[0].HppMap)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+
+	// A route without hostnames is served by the HTTP listener only, since the HTTPS listener has no hostname.
+	vsNode := getGatewayVS(modelName)
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Host).To(gomega.BeEmpty())
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].MatchCriteria).To(gomega.Equal("EQUALS"))
+	g.Expect(vsNode.HttpPolicyRefs[0].HppMap[0].Path).To(gomega.Equal([]string{"/bar"}))
+	g.Expect(vsNode.SniNodes).To(gomega.HaveLen(0))
+	g.Eventually(func() metav1.ConditionStatus {
+		if condition := getHTTPRouteAdmittedCondition(ns, "bar-route", ns, gatewayName); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}, 30*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+
+	// Removing the label unbinds the route from the gateway, and removes the gateway from the route status.
+	route.Labels = map[string]string{"app": "bar"}
+	UpdateHTTPRoute(t, route, "3")
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil {
+			return len(vsNode.HttpPolicyRefs)
+		}
+		return -1
+	}, 30*time.Second).Should(gomega.Equal(0))
+	g.Eventually(func() *metav1.Condition {
+		return getHTTPRouteAdmittedCondition(ns, "bar-route", ns, gatewayName)
+	}, 30*time.Second).Should(gomega.BeNil())
+
+	TeardownHTTPRoute(t, "bar-route", ns)
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownL7Backends(t, ns, "l7svc1")
+	integrationtest.DeleteSecret(secretName, ns)
+}
+
+func TestServicesAPIHTTPRouteNotAdmitted(t *testing.T) {
+	// create gwclass, gw, svc and a httproute with a RegularExpression path match
+	// check the httproute status, fix the path match and check the route is admitted
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns, secretName := "avi-lb", "my-l7-gateway", "default", "my-l7-secret"
+	modelName := "admin/cluster--default-my-l7-gateway"
+
+	integrationtest.AddSecret(secretName, ns, "tlsCert", "tlsKey")
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Backends(t, ns, "l7svc1")
+	SetupL7Gateway(t, gatewayName, ns, gwClassName, secretName)
+	route := FakeHTTPRoute{
+		Name:      "regex-route",
+		Namespace: ns,
+		Labels:    map[string]string{"app": "foo"},
+		Rules: []FakeHTTPRouteRule{{
+			Path:     "/foo/.*",
+			PathType: servicesapi.PathMatchRegularExpression,
+			Backends: map[string]int32{"l7svc1": 1},
+		}},
+	}
+	SetupHTTPRoute(t, route)
+
+	g.Eventually(func() string {
+		if condition := getHTTPRouteAdmittedCondition(ns, "regex-route", ns, gatewayName); condition != nil && condition.Status == metav1.ConditionFalse {
+			return condition.Reason
+		}
+		return ""
+	}, 40*time.Second).Should(gomega.Equal("InvalidRoute"))
+	g.Expect(getGatewayVS(modelName).HttpPolicyRefs).To(gomega.HaveLen(0))
+
+	route.Rules[0].Path = "/foo"
+	route.Rules[0].PathType = servicesapi.PathMatchPrefix
+	UpdateHTTPRoute(t, route, "2")
+	g.Eventually(func() metav1.ConditionStatus {
+		if condition := getHTTPRouteAdmittedCondition(ns, "regex-route", ns, gatewayName); condition != nil {
+			return condition.Status
+		}
+		return metav1.ConditionUnknown
+	}, 30*time.Second).Should(gomega.Equal(metav1.ConditionTrue))
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil && len(vsNode.HttpPolicyRefs) > 0 {
+			return len(vsNode.HttpPolicyRefs[0].HppMap)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(2))
+
+	TeardownHTTPRoute(t, "regex-route", ns)
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+	TeardownL7Backends(t, ns, "l7svc1")
+	integrationtest.DeleteSecret(secretName, ns)
+}
+
+func TestServicesAPIL7GatewayInvalidCertificateRef(t *testing.T) {
+	// create gwclass, gw with a HTTPS listener without a certificateRef
+	// check the listener condition, the HTTPS port is not added to the VS
+	g := gomega.NewGomegaWithT(t)
+
+	gwClassName, gatewayName, ns := "avi-lb", "my-l7-gateway", "default"
+	modelName := "admin/cluster--default-my-l7-gateway"
+
+	SetupGatewayClass(t, gwClassName, lib.SvcApiAviGatewayController, "")
+	SetupL7Gateway(t, gatewayName, ns, gwClassName, "")
+
+	g.Eventually(func() bool {
+		gw, _ := SvcAPIClient.NetworkingV1alpha1().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		for _, listener := range gw.Status.Listeners {
+			if listener.Port == 443 {
+				condition := meta.FindStatusCondition(listener.Conditions, "InvalidCertificateRef")
+				return condition != nil && condition.Status == metav1.ConditionTrue
+			}
+		}
+		return false
+	}, 30*time.Second).Should(gomega.BeTrue())
+
+	g.Eventually(func() int {
+		if vsNode := getGatewayVS(modelName); vsNode != nil {
+			return len(vsNode.PortProto)
+		}
+		return 0
+	}, 30*time.Second).Should(gomega.Equal(1))
+	g.Expect(getGatewayVS(modelName).PortProto[0].Port).To(gomega.Equal(int32(80)))
+
+	// The listener condition is retained once the gateway address is published.
+	g.Eventually(func() int {
+		gw, _ := SvcAPIClient.NetworkingV1alpha1().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+		return len(gw.Status.Addresses)
+	}, 30*time.Second).Should(gomega.Equal(1))
+	gw, _ := SvcAPIClient.NetworkingV1alpha1().Gateways(ns).Get(context.TODO(), gatewayName, metav1.GetOptions{})
+	g.Expect(meta.IsStatusConditionTrue(gw.Status.Listeners[1].Conditions, "InvalidCertificateRef")).To(gomega.BeTrue())
+
+	TeardownGateway(t, gatewayName, ns)
+	VerifyGatewayVSNodeDeletion(g, modelName)
+	TeardownGatewayClass(t, gwClassName)
+}
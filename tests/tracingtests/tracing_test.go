@@ -0,0 +1,231 @@
+/*
+ * Copyright 2021 VMware, Inc.
+ * All Rights Reserved.
+* Licensed under the Apache License, Version 2.0 (the "License");
+* you may not use this file except in compliance with the License.
+* You may obtain a copy of the License at
+*   http://www.apache.org/licenses/LICENSE-2.0
+* Unless required by applicable law or agreed to in writing, software
+* distributed under the License is distributed on an "AS IS" BASIS,
+* WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+* See the License for the specific language governing permissions and
+* limitations under the License.
+*/
+
+package tracingtests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/cache"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/k8s"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/internal/lib"
+	crdfake "github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/client/v1alpha1/clientset/versioned/fake"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/pkg/utils"
+	"github.com/vmware/load-balancer-and-ingress-services-for-kubernetes/tests/integrationtest"
+
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+)
+
+var KubeClient *k8sfake.Clientset
+var CRDClient *crdfake.Clientset
+var ctrl *k8s.AviController
+var traceFile string
+
+func TestMain(m *testing.M) {
+	traceDir, err := ioutil.TempDir("", "ako-traces")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(traceDir)
+	traceFile = filepath.Join(traceDir, "traces.json")
+	os.Setenv(utils.TRACING_FILE, traceFile)
+
+	os.Setenv("INGRESS_API", "extensionv1")
+	os.Setenv("VIP_NETWORK_LIST", `[{"networkName":"net123"}]`)
+	os.Setenv("CLUSTER_NAME", "cluster")
+	os.Setenv("CLOUD_NAME", "CLOUD_VCENTER")
+	os.Setenv("SEG_NAME", "Default-Group")
+	os.Setenv("NODE_NETWORK_LIST", `[{"networkName":"net123","cidrs":["10.79.168.0/22"]}]`)
+	os.Setenv("POD_NAMESPACE", utils.AKO_DEFAULT_NS)
+	os.Setenv("SHARD_VS_SIZE", "LARGE")
+
+	KubeClient = k8sfake.NewSimpleClientset()
+	CRDClient = crdfake.NewSimpleClientset()
+	lib.SetCRDClientset(CRDClient)
+	data := map[string][]byte{
+		"username": []byte("admin"),
+		"password": []byte("admin"),
+	}
+	object := metav1.ObjectMeta{Name: "avi-secret", Namespace: utils.GetAKONamespace()}
+	secret := &corev1.Secret{Data: data, ObjectMeta: object}
+	KubeClient.CoreV1().Secrets(utils.GetAKONamespace()).Create(context.TODO(), secret, metav1.CreateOptions{})
+
+	registeredInformers := []string{
+		utils.ServiceInformer,
+		utils.EndpointInformer,
+		utils.IngressInformer,
+		utils.IngressClassInformer,
+		utils.SecretInformer,
+		utils.NSInformer,
+		utils.NodeInformer,
+		utils.ConfigMapInformer,
+	}
+	utils.NewInformers(utils.KubeClientIntf{ClientSet: KubeClient}, registeredInformers)
+	informers := k8s.K8sinformers{Cs: KubeClient}
+	k8s.NewCRDInformers(CRDClient)
+
+	mcache := cache.SharedAviObjCache()
+	cloudObj := &cache.AviCloudPropertyCache{Name: "Default-Cloud", VType: "mock"}
+	cloudObj.NSIpamDNS = []string{"avi.internal", ".com"}
+	mcache.CloudKeyCache.AviCacheAdd("Default-Cloud", cloudObj)
+
+	integrationtest.InitializeFakeAKOAPIServer()
+	integrationtest.NewAviFakeClientInstance(KubeClient)
+	defer integrationtest.AviFakeClientInstance.Close()
+
+	ctrl = k8s.SharedAviController()
+	stopCh := utils.SetupSignalHandler()
+	ctrlCh := make(chan struct{})
+	quickSyncCh := make(chan struct{})
+	waitGroupMap := make(map[string]*sync.WaitGroup)
+	wgIngestion := &sync.WaitGroup{}
+	waitGroupMap["ingestion"] = wgIngestion
+	wgFastRetry := &sync.WaitGroup{}
+	waitGroupMap["fastretry"] = wgFastRetry
+	wgSlowRetry := &sync.WaitGroup{}
+	waitGroupMap["slowretry"] = wgSlowRetry
+	wgGraph := &sync.WaitGroup{}
+	waitGroupMap["graph"] = wgGraph
+	wgStatus := &sync.WaitGroup{}
+	waitGroupMap["status"] = wgStatus
+
+	integrationtest.AddConfigMap(KubeClient)
+	integrationtest.PollForSyncStart(ctrl, 10)
+
+	ctrl.HandleConfigMap(informers, ctrlCh, stopCh, quickSyncCh)
+	integrationtest.KubeClient = KubeClient
+	integrationtest.AddDefaultIngressClass()
+
+	go ctrl.InitController(informers, registeredInformers, ctrlCh, stopCh, quickSyncCh, waitGroupMap)
+	os.Exit(m.Run())
+}
+
+// exportedSpan is a span written to the trace file by the stdout exporter of OpenTelemetry.
+type exportedSpan struct {
+	Name        string
+	SpanContext struct {
+		TraceID string
+		SpanID  string
+	}
+	Parent struct {
+		TraceID string
+		SpanID  string
+	}
+	Attributes []struct {
+		Key   string
+		Value struct {
+			Value interface{}
+		}
+	}
+}
+
+func (s exportedSpan) attribute(key string) string {
+	for _, attribute := range s.Attributes {
+		if attribute.Key == key {
+			value, _ := attribute.Value.Value.(string)
+			return value
+		}
+	}
+	return ""
+}
+
+// getExportedSpans flushes the spans and returns the spans in the trace file, keyed by the span id.
+func getExportedSpans(t *testing.T) map[string]exportedSpan {
+	utils.FlushSpans()
+	spans := make(map[string]exportedSpan)
+	f, err := os.Open(traceFile)
+	if err != nil {
+		return spans
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var span exportedSpan
+		if err := json.Unmarshal(scanner.Bytes(), &span); err != nil {
+			t.Fatalf("error in parsing the trace file: %v", err)
+		}
+		spans[span.SpanContext.SpanID] = span
+	}
+	return spans
+}
+
+// getSpanChain returns the names of the ancestors of the span, from the parent to the root, and the root span.
+func getSpanChain(spans map[string]exportedSpan, span exportedSpan) ([]string, exportedSpan) {
+	var names []string
+	for {
+		parent, ok := spans[span.Parent.SpanID]
+		if !ok || parent.SpanContext.TraceID != span.SpanContext.TraceID {
+			break
+		}
+		names = append(names, parent.Name)
+		span = parent
+	}
+	return names, span
+}
+
+func TestServiceTraceFromEventToRestCall(t *testing.T) {
+	g := gomega.NewGomegaWithT(t)
+	g.Expect(utils.IsTracingEnabled()).To(gomega.BeTrue())
+
+	svcName := "tracesvc"
+	vsKey := cache.NamespaceName{Namespace: "admin", Name: "cluster--default-" + svcName}
+	integrationtest.CreateSVC(t, "default", svcName, corev1.ServiceTypeLoadBalancer, false)
+	integrationtest.CreateEP(t, "default", svcName, false, false, "1.1.1")
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 20*time.Second).Should(gomega.BeTrue())
+
+	epExample, err := KubeClient.CoreV1().Endpoints("default").Get(context.TODO(), svcName, metav1.GetOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+	epExample.Subsets[0].Addresses = append(epExample.Subsets[0].Addresses, corev1.EndpointAddress{IP: "1.1.1.99"})
+	epExample.ResourceVersion = "2"
+	_, err = KubeClient.CoreV1().Endpoints("default").Update(context.TODO(), epExample, metav1.UpdateOptions{})
+	g.Expect(err).NotTo(gomega.HaveOccurred())
+
+	// The rest call updating the pool is traced back to the informer event of the Endpoints.
+	g.Eventually(func() []string {
+		spans := getExportedSpans(t)
+		for _, span := range spans {
+			if span.Name != "PUT Pool" || span.attribute(utils.LogKey) != vsKey.Namespace+"/"+vsKey.Name {
+				continue
+			}
+			chain, root := getSpanChain(spans, span)
+			if root.attribute(utils.LogKey) != utils.Endpoints+"/default/"+svcName {
+				continue
+			}
+			return chain
+		}
+		return nil
+	}, 20*time.Second).Should(gomega.Equal([]string{"DequeueNodes", utils.GraphLayer, "DequeueIngestion", utils.ObjectIngestionLayer}))
+
+	integrationtest.DelSVC(t, "default", svcName)
+	integrationtest.DelEP(t, "default", svcName)
+	g.Eventually(func() bool {
+		_, found := cache.SharedAviObjCache().VsCacheMeta.AviCacheGet(vsKey)
+		return found
+	}, 10*time.Second).Should(gomega.BeFalse())
+}